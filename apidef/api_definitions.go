@@ -25,6 +25,7 @@ import (
 	"github.com/TykTechnologies/graphql-go-tools/pkg/execution/datasource"
 
 	"github.com/TykTechnologies/tyk/internal/service/gojsonschema"
+	"github.com/TykTechnologies/tyk/internal/xsd"
 
 	"github.com/TykTechnologies/tyk/regexp"
 
@@ -365,6 +366,21 @@ type ValidatePathMeta struct {
 	ErrorResponseCode int `bson:"error_response_code" json:"error_response_code"`
 }
 
+// ValidateXMLMeta configures the validation of the XML request bodies of an endpoint against an XML schema.
+type ValidateXMLMeta struct {
+	Disabled bool   `bson:"disabled" json:"disabled"`
+	Path     string `bson:"path" json:"path"`
+	Method   string `bson:"method" json:"method"`
+	// Schema is an XML schema, or a document containing XML schemas such as the types of a WSDL document.
+	Schema string `bson:"schema" json:"schema"`
+	// Element is the name of the element the body, or the body of its SOAP envelope, must contain.
+	// Any element declared by the schema is accepted when it's empty.
+	Element     string      `bson:"element" json:"element,omitempty"`
+	SchemaCache *xsd.Schema `bson:"-" json:"-"`
+	// Allows override of default 422 Unprocessible Entity response code for validation errors.
+	ErrorResponseCode int `bson:"error_response_code" json:"error_response_code"`
+}
+
 type ValidateRequestMeta struct {
 	Enabled bool   `bson:"enabled" json:"enabled"`
 	Path    string `bson:"path" json:"path"`
@@ -410,6 +426,7 @@ type ExtendedPathsSet struct {
 	TrackEndpoints          []TrackEndpointMeta   `bson:"track_endpoints" json:"track_endpoints,omitempty"`
 	DoNotTrackEndpoints     []TrackEndpointMeta   `bson:"do_not_track_endpoints" json:"do_not_track_endpoints,omitempty"`
 	ValidateJSON            []ValidatePathMeta    `bson:"validate_json" json:"validate_json,omitempty"`
+	ValidateXML             []ValidateXMLMeta     `bson:"validate_xml" json:"validate_xml,omitempty"`
 	ValidateRequest         []ValidateRequestMeta `bson:"validate_request" json:"validate_request,omitempty"`
 	Internal                []InternalMeta        `bson:"internal" json:"internal,omitempty"`
 	GoPlugin                []GoPluginMeta        `bson:"go_plugin" json:"go_plugin,omitempty"`
@@ -425,6 +442,7 @@ func (e *ExtendedPathsSet) Clear() {
 		TransformJQ:         e.TransformJQ,
 		TransformJQResponse: e.TransformJQResponse,
 		PersistGraphQL:      e.PersistGraphQL,
+		ValidateXML:         e.ValidateXML,
 	}
 }
 
@@ -784,6 +802,12 @@ type APIDefinition struct {
 	// SecurityRequirements stores all OAS security requirements (auto-populated from OpenAPI description import)
 	// When len(SecurityRequirements) > 1, OR logic is automatically applied
 	SecurityRequirements [][]string `json:"security_requirements,omitempty" bson:"security_requirements,omitempty"`
+
+	// Schedule contains the activation schedule and maintenance windows of the API.
+	Schedule *ActivationSchedule `bson:"schedule,omitempty" json:"schedule,omitempty"`
+
+	// GRPCTranscoding configures the transcoding of HTTP/JSON requests to a gRPC upstream.
+	GRPCTranscoding *GRPCTranscoding `bson:"grpc_transcoding,omitempty" json:"grpc_transcoding,omitempty"`
 }
 
 type JWK struct {
@@ -1719,6 +1743,14 @@ type WebHookHandlerConf struct {
 	HeaderList map[string]string `bson:"header_map" json:"header_map"`
 	// The cool-down for the event so it does not trigger again (in seconds).
 	EventTimeout int64 `bson:"event_timeout" json:"event_timeout"`
+	// SigningSecret is the secret used to sign deliveries per the Standard Webhooks specification.
+	// Secrets prefixed with `whsec_` are base64 decoded.
+	SigningSecret string `bson:"signing_secret,omitempty" json:"signing_secret,omitempty"`
+	// MaxRetries is the number of retries for a failed delivery. When set, deliveries are
+	// persisted to the outbox and retried with exponential backoff.
+	MaxRetries int `bson:"max_retries,omitempty" json:"max_retries,omitempty"`
+	// RetryInterval is the delay before the first retry (in seconds), doubled on each retry.
+	RetryInterval int64 `bson:"retry_interval,omitempty" json:"retry_interval,omitempty"`
 }
 
 // Scan scans WebHookHandlerConf from `any` in.
@@ -1772,3 +1804,80 @@ func (l *LogEventHandlerConf) Scan(in any) error {
 	*l = *conf
 	return nil
 }
+
+// CloudEventsHandlerConf represents the configuration for a CloudEvents event handler.
+// The handler delivers events as CloudEvents 1.0 structured JSON envelopes to a message broker.
+type CloudEventsHandlerConf struct {
+	// Disabled indicates whether the handler is inactive.
+	Disabled bool `bson:"disabled" json:"disabled"`
+	// ID is the optional unique identifier for the event handler.
+	ID string `bson:"id" json:"id"`
+	// Name is the name of the event handler.
+	Name string `bson:"name" json:"name"`
+	// Source is the CloudEvents `source` attribute, defaults to `/tyk/gateway`.
+	Source string `bson:"source" json:"source"`
+	// Transport is the protocol binding used for delivery, one of `kafka`, `nats`, `amqp` or `http`.
+	Transport string `bson:"transport" json:"transport"`
+	// Kafka holds the Kafka transport configuration.
+	Kafka CloudEventsKafkaConf `bson:"kafka" json:"kafka"`
+	// NATS holds the NATS transport configuration.
+	NATS CloudEventsNATSConf `bson:"nats" json:"nats"`
+	// AMQP holds the AMQP 0.9.1 transport configuration.
+	AMQP CloudEventsAMQPConf `bson:"amqp" json:"amqp"`
+	// HTTP holds the HTTP transport configuration.
+	HTTP CloudEventsHTTPConf `bson:"http" json:"http"`
+	// QueueSize is the maximum number of events waiting for delivery, defaults to 1000.
+	// Events fired while the queue is full are written to the dead-letter file.
+	QueueSize int `bson:"queue_size" json:"queue_size"`
+	// MaxRetries is the number of delivery retries before an event is dead-lettered, defaults to 3.
+	MaxRetries int `bson:"max_retries" json:"max_retries"`
+	// RetryInterval is the delay in seconds before the first retry, doubled on each retry. Defaults to 1.
+	RetryInterval int64 `bson:"retry_interval" json:"retry_interval"`
+	// DeadLetterPath is the file undeliverable events are appended to, as newline-delimited JSON.
+	// If empty, undeliverable events are only logged.
+	DeadLetterPath string `bson:"dead_letter_path" json:"dead_letter_path"`
+}
+
+// CloudEventsKafkaConf configures the Kafka binding of the CloudEvents event handler.
+type CloudEventsKafkaConf struct {
+	// Brokers is the list of Kafka broker addresses.
+	Brokers []string `bson:"brokers" json:"brokers"`
+	// Topic is the topic events are produced to.
+	Topic string `bson:"topic" json:"topic"`
+}
+
+// CloudEventsNATSConf configures the NATS binding of the CloudEvents event handler.
+type CloudEventsNATSConf struct {
+	// URL is the NATS server URL.
+	URL string `bson:"url" json:"url"`
+	// Subject is the subject events are published to.
+	Subject string `bson:"subject" json:"subject"`
+}
+
+// CloudEventsAMQPConf configures the AMQP 0.9.1 binding of the CloudEvents event handler.
+type CloudEventsAMQPConf struct {
+	// URL is the AMQP broker URL.
+	URL string `bson:"url" json:"url"`
+	// Exchange is the exchange events are published to. An empty value uses the default exchange.
+	Exchange string `bson:"exchange" json:"exchange"`
+	// RoutingKey is the routing key events are published with.
+	RoutingKey string `bson:"routing_key" json:"routing_key"`
+}
+
+// CloudEventsHTTPConf configures the HTTP binding of the CloudEvents event handler.
+type CloudEventsHTTPConf struct {
+	// URL is the endpoint events are POSTed to.
+	URL string `bson:"url" json:"url"`
+	// Headers are additional headers to set on the request.
+	Headers map[string]string `bson:"headers" json:"headers"`
+}
+
+// Scan extracts data from the input into the CloudEventsHandlerConf struct by performing type conversion.
+func (c *CloudEventsHandlerConf) Scan(in any) error {
+	conf, err := reflect.Cast[CloudEventsHandlerConf](in)
+	if err != nil {
+		return err
+	}
+	*c = *conf
+	return nil
+}
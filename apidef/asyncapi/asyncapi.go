@@ -0,0 +1,259 @@
+// Package asyncapi converts AsyncAPI documents to Tyk Streams configurations and back.
+//
+// AsyncAPI 2.x and 3.0 documents are parsed into the AsyncAPI 3.0 model. Each operation of
+// the document becomes a stream: the operations the application sends are read from the
+// broker of their channel and delivered to HTTP consumers over server-sent events and
+// WebSocket, and the operations the application receives are published over HTTP and written
+// to the broker. The Kafka, MQTT and AMQP 0.9 protocols are supported as brokers, channels
+// without a broker are passed through the gateway.
+package asyncapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/oasdiff/yaml"
+)
+
+// Version is the AsyncAPI version of the exported documents.
+const Version = "3.0.0"
+
+const (
+	// ActionSend is the action of the operations the application sends to its consumers.
+	ActionSend = "send"
+	// ActionReceive is the action of the operations the application receives from its producers.
+	ActionReceive = "receive"
+)
+
+var (
+	// ErrUnsupportedVersion is returned for documents that aren't AsyncAPI 2.x or 3.x documents.
+	ErrUnsupportedVersion = errors.New("unsupported AsyncAPI version, 2.x and 3.x are supported")
+	// ErrNoOperations is returned when a document has no operations to import.
+	ErrNoOperations = errors.New("the AsyncAPI document has no operations")
+)
+
+// Document is an AsyncAPI 3.0 document, limited to the fields used by Tyk Streams.
+type Document struct {
+	AsyncAPI   string                `json:"asyncapi"`
+	Info       Info                  `json:"info"`
+	Servers    map[string]*Server    `json:"servers,omitempty"`
+	Channels   map[string]*Channel   `json:"channels,omitempty"`
+	Operations map[string]*Operation `json:"operations,omitempty"`
+}
+
+// Info is the metadata of the API.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server is a message broker or a gateway endpoint.
+type Server struct {
+	Host        string `json:"host"`
+	Protocol    string `json:"protocol"`
+	Pathname    string `json:"pathname,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// URL returns the URL of the server.
+func (s *Server) URL() string {
+	return s.Protocol + "://" + s.Host + s.Pathname
+}
+
+// Channel is an addressable component the messages are exchanged on, a topic or queue of a broker.
+type Channel struct {
+	Address     string                 `json:"address,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Servers     []Reference            `json:"servers,omitempty"`
+	Bindings    map[string]interface{} `json:"bindings,omitempty"`
+
+	// HTTP holds the paths the channel is served on by the gateway.
+	HTTP *HTTPPaths `json:"x-tyk-http,omitempty"`
+}
+
+// HTTPPaths are the paths of a stream, relative to the listen path of the API.
+type HTTPPaths struct {
+	// Path is the path messages are published to with HTTP POST requests.
+	Path string `json:"path,omitempty"`
+	// StreamPath is the path messages are consumed from as server-sent events.
+	StreamPath string `json:"streamPath,omitempty"`
+	// WSPath is the path messages are consumed from over WebSocket.
+	WSPath string `json:"wsPath,omitempty"`
+}
+
+// Operation is a message sent or received by the application on a channel.
+type Operation struct {
+	Action      string    `json:"action"`
+	Channel     Reference `json:"channel"`
+	Summary     string    `json:"summary,omitempty"`
+	Description string    `json:"description,omitempty"`
+}
+
+// Reference is a JSON reference to a component of the document.
+type Reference struct {
+	Ref string `json:"$ref"`
+}
+
+func ref(kind, name string) Reference {
+	name = strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+	return Reference{Ref: "#/" + kind + "/" + name}
+}
+
+// name returns the name of the referenced component of the given kind.
+func (r Reference) name(kind string) (string, bool) {
+	name, ok := strings.CutPrefix(r.Ref, "#/"+kind+"/")
+	if !ok {
+		return "", false
+	}
+
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~1", "/"), "~0", "~"), true
+}
+
+// Parse parses an AsyncAPI 2.x or 3.x document in JSON or YAML.
+func Parse(data []byte) (*Document, error) {
+	data, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var header struct {
+		AsyncAPI string `json:"asyncapi"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasPrefix(header.AsyncAPI, "3."):
+		var doc Document
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		return &doc, nil
+	case strings.HasPrefix(header.AsyncAPI, "2."):
+		var doc documentV2
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		return doc.convert(), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedVersion, header.AsyncAPI)
+	}
+}
+
+// documentV2 is an AsyncAPI 2.x document.
+type documentV2 struct {
+	Info     Info                  `json:"info"`
+	Servers  map[string]*serverV2  `json:"servers"`
+	Channels map[string]*channelV2 `json:"channels"`
+}
+
+type serverV2 struct {
+	URL         string `json:"url"`
+	Protocol    string `json:"protocol"`
+	Description string `json:"description"`
+}
+
+type channelV2 struct {
+	Description string                 `json:"description"`
+	Servers     []string               `json:"servers"`
+	Bindings    map[string]interface{} `json:"bindings"`
+	Subscribe   *operationV2           `json:"subscribe"`
+	Publish     *operationV2           `json:"publish"`
+}
+
+type operationV2 struct {
+	OperationID string `json:"operationId"`
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+}
+
+// convert converts the document to the AsyncAPI 3.0 model. The channels the consumers
+// subscribe to are sent by the application, the channels they publish to are received.
+func (d *documentV2) convert() *Document {
+	doc := &Document{
+		AsyncAPI:   Version,
+		Info:       d.Info,
+		Servers:    map[string]*Server{},
+		Channels:   map[string]*Channel{},
+		Operations: map[string]*Operation{},
+	}
+
+	for name, server := range d.Servers {
+		doc.Servers[name] = server.convert()
+	}
+
+	for _, address := range sortedKeys(d.Channels) {
+		channel := d.Channels[address]
+
+		converted := &Channel{
+			Address:     address,
+			Description: channel.Description,
+			Bindings:    channel.Bindings,
+		}
+		for _, server := range channel.Servers {
+			converted.Servers = append(converted.Servers, ref("servers", server))
+		}
+		doc.Channels[address] = converted
+
+		for _, op := range []struct {
+			action    string
+			operation *operationV2
+		}{
+			{ActionSend, channel.Subscribe},
+			{ActionReceive, channel.Publish},
+		} {
+			if op.operation == nil {
+				continue
+			}
+
+			id := op.operation.OperationID
+			if id == "" {
+				id = address + "." + op.action
+			}
+
+			doc.Operations[id] = &Operation{
+				Action:      op.action,
+				Channel:     ref("channels", address),
+				Summary:     op.operation.Summary,
+				Description: op.operation.Description,
+			}
+		}
+	}
+
+	return doc
+}
+
+// convert splits the URL of the server into its host and pathname.
+func (s *serverV2) convert() *Server {
+	server := &Server{Protocol: s.Protocol, Description: s.Description}
+
+	rawURL := s.URL
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "//" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		server.Host = s.URL
+		return server
+	}
+
+	server.Host = u.Host
+	server.Pathname = strings.TrimSuffix(u.Path, "/")
+	return server
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,165 @@
+package asyncapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const userEventsV2 = `asyncapi: 2.6.0
+info:
+  title: User events
+  version: 1.2.0
+servers:
+  production:
+    url: broker-1:9092
+    protocol: kafka
+  replica:
+    url: broker-2:9092
+    protocol: kafka
+  mqtt:
+    url: mqtt://mqtt.example.com:1883
+    protocol: mqtt
+channels:
+  user/signedup:
+    servers: [production, replica]
+    subscribe:
+      operationId: userSignedUp
+      summary: A user signed up.
+  user/commands:
+    servers: [mqtt]
+    publish: {}
+`
+
+const userEventsV3 = `{
+  "asyncapi": "3.0.0",
+  "info": {"title": "User events", "version": "1.2.0"},
+  "servers": {
+    "rabbit": {"host": "rabbit.example.com:5672", "protocol": "amqp", "pathname": "/vhost"},
+    "secure": {"host": "kafka.example.com:9093", "protocol": "kafka-secure"},
+    "gateway": {"host": "api.example.com", "protocol": "https"}
+  },
+  "channels": {
+    "orders": {
+      "address": "orders.created",
+      "servers": [{"$ref": "#/servers/rabbit"}],
+      "x-tyk-http": {"streamPath": "/orders/sse"}
+    },
+    "payments": {
+      "address": "payments",
+      "servers": [{"$ref": "#/servers/rabbit"}],
+      "bindings": {"amqp": {"exchange": {"name": "billing"}}}
+    },
+    "audit": {"address": "audit", "servers": [{"$ref": "#/servers/secure"}]},
+    "chat": {"address": "chat/{room}", "servers": [{"$ref": "#/servers/gateway"}]}
+  },
+  "operations": {
+    "onOrderCreated": {"action": "send", "channel": {"$ref": "#/channels/orders"}},
+    "pay": {"action": "receive", "channel": {"$ref": "#/channels/payments"}},
+    "audit": {"action": "send", "channel": {"$ref": "#/channels/audit"}},
+    "chat": {"action": "receive", "channel": {"$ref": "#/channels/chat"}}
+  }
+}`
+
+func TestParse_V2(t *testing.T) {
+	doc, err := Parse([]byte(userEventsV2))
+	require.NoError(t, err)
+
+	assert.Equal(t, Version, doc.AsyncAPI)
+	assert.Equal(t, Info{Title: "User events", Version: "1.2.0"}, doc.Info)
+	assert.Equal(t, &Server{Host: "mqtt.example.com:1883", Protocol: "mqtt"}, doc.Servers["mqtt"])
+	assert.Equal(t, "user/signedup", doc.Channels["user/signedup"].Address)
+	assert.Equal(t, &Operation{Action: ActionSend, Channel: Reference{Ref: "#/channels/user~1signedup"}, Summary: "A user signed up."},
+		doc.Operations["userSignedUp"])
+	assert.Equal(t, ActionReceive, doc.Operations["user/commands.receive"].Action)
+
+	streams, err := doc.Streams()
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"userSignedUp": map[string]interface{}{
+			"input": map[string]interface{}{"kafka": map[string]interface{}{
+				"addresses": []interface{}{"broker-1:9092", "broker-2:9092"},
+				"topics":    []interface{}{"user/signedup"},
+			}},
+			"output": map[string]interface{}{"http_server": map[string]interface{}{
+				"stream_path": "/user-signedup/stream",
+				"ws_path":     "/user-signedup/ws",
+			}},
+		},
+		"user/commands.receive": map[string]interface{}{
+			"input": map[string]interface{}{"http_server": map[string]interface{}{
+				"path":          "/user-commands",
+				"allowed_verbs": []interface{}{"POST"},
+			}},
+			"output": map[string]interface{}{"mqtt": map[string]interface{}{
+				"urls":  []interface{}{"tcp://mqtt.example.com:1883"},
+				"topic": "user/commands",
+			}},
+		},
+	}, streams)
+}
+
+func TestParse_V3(t *testing.T) {
+	doc, err := Parse([]byte(userEventsV3))
+	require.NoError(t, err)
+
+	streams, err := doc.Streams()
+	require.NoError(t, err)
+	require.Len(t, streams, 4)
+
+	stream := func(name string) map[string]interface{} {
+		return streams[name].(map[string]interface{})
+	}
+
+	assert.Equal(t, map[string]interface{}{"amqp_0_9": map[string]interface{}{
+		"urls":  []interface{}{"amqp://rabbit.example.com:5672/vhost"},
+		"queue": "orders.created",
+	}}, stream("onOrderCreated")["input"])
+	assert.Equal(t, map[string]interface{}{"http_server": map[string]interface{}{
+		"stream_path": "/orders/sse",
+		"ws_path":     "/orders.created/ws",
+	}}, stream("onOrderCreated")["output"], "x-tyk-http overrides the derived paths")
+
+	assert.Equal(t, map[string]interface{}{"amqp_0_9": map[string]interface{}{
+		"urls":     []interface{}{"amqp://rabbit.example.com:5672/vhost"},
+		"exchange": "billing",
+		"key":      "payments",
+	}}, stream("pay")["output"])
+
+	assert.Equal(t, map[string]interface{}{"kafka": map[string]interface{}{
+		"addresses": []interface{}{"kafka.example.com:9093"},
+		"topics":    []interface{}{"audit"},
+		"tls":       map[string]interface{}{"enabled": true},
+	}}, stream("audit")["input"])
+
+	assert.Equal(t, map[string]interface{}{
+		"input": map[string]interface{}{"http_server": map[string]interface{}{
+			"path":          "/chat-room",
+			"allowed_verbs": []interface{}{"POST"},
+		}},
+		"output": map[string]interface{}{"http_server": map[string]interface{}{
+			"stream_path": "/chat-room/stream",
+			"ws_path":     "/chat-room/ws",
+		}},
+	}, stream("chat"), "channels without a broker are passed through")
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse([]byte(`{"asyncapi": "1.2.0"}`))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	_, err = Parse([]byte(`asyncapi: [`))
+	assert.Error(t, err)
+
+	doc, err := Parse([]byte(`{"asyncapi": "3.0.0", "info": {"title": "empty", "version": "1"}}`))
+	require.NoError(t, err)
+	_, err = doc.Streams()
+	assert.ErrorIs(t, err, ErrNoOperations)
+
+	doc, err = Parse([]byte(`{"asyncapi": "3.0.0", "operations": {"op": {"action": "send", "channel": {"$ref": "#/channels/missing"}}}}`))
+	require.NoError(t, err)
+	_, err = doc.Streams()
+	assert.Error(t, err)
+}
@@ -0,0 +1,282 @@
+package asyncapi
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/TykTechnologies/tyk/apidef/oas"
+)
+
+// The servers of the exported documents the consumers connect to.
+const (
+	ServerGateway   = "gateway"
+	ServerGatewayWS = "gateway-ws"
+)
+
+// ErrNoStreams is returned when an API has no streams with an http_server input or output to export.
+var ErrNoStreams = errors.New("the API has no streams served over HTTP")
+
+// The default paths of the http_server input and output.
+const (
+	defaultInputPath    = "/post"
+	defaultStreamPath   = "/get/stream"
+	defaultOutputWSPath = "/get/ws"
+)
+
+// component is an input or output of a stream.
+type component struct {
+	name   string
+	config map[string]interface{}
+}
+
+// Export builds the AsyncAPI 3.0 document of a Tyk Streams API. Each stream with an http_server
+// input or output is exported as an operation, on a channel addressed with the topic or queue of
+// its broker, or served by the gateway when the stream has no broker. The gateway paths of the
+// channels are set with x-tyk-http.
+//
+// host is the host the gateway is reached on, the custom domain of the API is used when enabled.
+func Export(api *oas.OAS, host string) (*Document, error) {
+	xTykStreaming := api.GetTykStreamingExtension()
+	if xTykStreaming == nil || len(xTykStreaming.Streams) == 0 {
+		return nil, ErrNoStreams
+	}
+
+	listenPath := ""
+	if xTykAPIGateway := api.GetTykExtension(); xTykAPIGateway != nil {
+		listenPath = strings.TrimSuffix(xTykAPIGateway.Server.ListenPath.Value, "/")
+		if domain := xTykAPIGateway.Server.CustomDomain; domain != nil && domain.Enabled && domain.Name != "" {
+			host = domain.Name
+		}
+	}
+
+	doc := &Document{
+		AsyncAPI:   Version,
+		Servers:    map[string]*Server{},
+		Channels:   map[string]*Channel{},
+		Operations: map[string]*Operation{},
+	}
+
+	if api.Info != nil {
+		doc.Info = Info{Title: api.Info.Title, Version: api.Info.Version, Description: api.Info.Description}
+	}
+
+	websocket := false
+	for _, name := range sortedKeys(xTykStreaming.Streams) {
+		config, ok := xTykStreaming.Streams[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		inputs, outputs := components(config["input"], "inputs"), components(config["output"], "outputs")
+		httpInput, httpOutput := find(inputs, componentHTTPServer), find(outputs, componentHTTPServer)
+
+		var (
+			action     string
+			brokerSide *component
+			paths      HTTPPaths
+		)
+
+		switch {
+		case httpOutput != nil:
+			action, brokerSide = ActionSend, findBroker(inputs)
+		case httpInput != nil:
+			action, brokerSide = ActionReceive, findBroker(outputs)
+		default:
+			continue
+		}
+
+		if httpInput != nil {
+			paths.Path = stringValue(httpInput.config, "path", defaultInputPath)
+		}
+		if httpOutput != nil {
+			paths.StreamPath = stringValue(httpOutput.config, "stream_path", defaultStreamPath)
+			paths.WSPath = stringValue(httpOutput.config, "ws_path", defaultOutputWSPath)
+			websocket = true
+		}
+
+		channel := &Channel{HTTP: &paths}
+		if brokerSide != nil {
+			channel.Address, channel.Bindings = brokerAddress(brokerSide, action)
+			for _, server := range doc.addBrokerServers(brokerSide) {
+				channel.Servers = append(channel.Servers, ref("servers", server))
+			}
+		} else {
+			channel.Address = paths.Path
+			channel.Servers = []Reference{ref("servers", ServerGateway)}
+			if paths.WSPath != "" {
+				channel.Servers = append(channel.Servers, ref("servers", ServerGatewayWS))
+			}
+		}
+
+		doc.Channels[name] = channel
+		doc.Operations[name] = &Operation{Action: action, Channel: ref("channels", name)}
+	}
+
+	if len(doc.Operations) == 0 {
+		return nil, ErrNoStreams
+	}
+
+	doc.Servers[ServerGateway] = &Server{Host: host, Protocol: "http", Pathname: listenPath}
+	if websocket {
+		doc.Servers[ServerGatewayWS] = &Server{Host: host, Protocol: "ws", Pathname: listenPath}
+	}
+
+	return doc, nil
+}
+
+// components returns the components of a stream input or output, and of its broker.
+func components(value interface{}, brokerKey string) []component {
+	config, _ := value.(map[string]interface{})
+
+	var result []component
+	for name, componentConfig := range config {
+		if name == "broker" {
+			broker, _ := componentConfig.(map[string]interface{})
+			list, _ := broker[brokerKey].([]interface{})
+			for _, item := range list {
+				result = append(result, components(item, brokerKey)...)
+			}
+			continue
+		}
+
+		if c, ok := componentConfig.(map[string]interface{}); ok {
+			result = append(result, component{name: name, config: c})
+		}
+	}
+
+	return result
+}
+
+func find(components []component, name string) *component {
+	for i := range components {
+		if components[i].name == name {
+			return &components[i]
+		}
+	}
+	return nil
+}
+
+func findBroker(components []component) *component {
+	for _, name := range []string{componentKafka, componentMQTT, componentAMQP} {
+		if c := find(components, name); c != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// brokerAddress returns the topic or queue of a broker input or output, and the AMQP binding
+// of the exchange the messages are published to with a routing key.
+func brokerAddress(c *component, action string) (string, map[string]interface{}) {
+	switch {
+	case c.name == componentAMQP && action == ActionSend:
+		return stringValue(c.config, "queue", ""), nil
+	case c.name == componentAMQP:
+		exchange, key := stringValue(c.config, "exchange", ""), stringValue(c.config, "key", "")
+		if key == "" {
+			return exchange, nil
+		}
+		return key, map[string]interface{}{
+			"amqp": map[string]interface{}{"exchange": map[string]interface{}{"name": exchange}},
+		}
+	case action == ActionSend:
+		topics := stringList(c.config["topics"])
+		if len(topics) == 0 {
+			return "", nil
+		}
+		return topics[0], nil
+	default:
+		return stringValue(c.config, "topic", ""), nil
+	}
+}
+
+// addBrokerServers adds the servers of a broker, named after their protocol, and returns their names.
+func (d *Document) addBrokerServers(c *component) []string {
+	var servers []*Server
+	switch c.name {
+	case componentKafka:
+		protocol := "kafka"
+		if tls, _ := c.config["tls"].(map[string]interface{}); tls["enabled"] == true {
+			protocol = "kafka-secure"
+		}
+		for _, address := range stringList(c.config["addresses"]) {
+			for _, host := range strings.Split(address, ",") {
+				servers = append(servers, &Server{Host: strings.TrimSpace(host), Protocol: protocol})
+			}
+		}
+	default:
+		for _, rawURL := range stringList(c.config["urls"]) {
+			if server := brokerServer(c.name, rawURL); server != nil {
+				servers = append(servers, server)
+			}
+		}
+	}
+
+	var names []string
+	for _, server := range servers {
+		names = append(names, d.addServer(server))
+	}
+	return names
+}
+
+// brokerServer returns the server of an MQTT or AMQP URL.
+func brokerServer(name, rawURL string) *Server {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+
+	protocol := u.Scheme
+	if name == componentMQTT {
+		protocol = "mqtt"
+		if u.Scheme == "ssl" || u.Scheme == "tls" || u.Scheme == "mqtts" {
+			protocol = "secure-mqtt"
+		}
+	}
+
+	return &Server{Host: u.Host, Protocol: protocol, Pathname: strings.TrimSuffix(u.Path, "/")}
+}
+
+// addServer adds a server named after its protocol, and returns the name of the server.
+// Existing servers are reused.
+func (d *Document) addServer(server *Server) string {
+	for name, existing := range d.Servers {
+		if *existing == *server {
+			return name
+		}
+	}
+
+	name := server.Protocol
+	for i := 2; d.Servers[name] != nil; i++ {
+		name = server.Protocol + "-" + strconv.Itoa(i)
+	}
+
+	d.Servers[name] = server
+	return name
+}
+
+func stringValue(config map[string]interface{}, key, defaultValue string) string {
+	if value, ok := config[key].(string); ok && value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func stringList(value interface{}) []string {
+	var result []string
+	switch v := value.(type) {
+	case string:
+		result = append(result, v)
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+	case []string:
+		result = v
+	}
+	return result
+}
@@ -0,0 +1,132 @@
+package asyncapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef/oas"
+)
+
+func streamsAPI(t *testing.T, streams string) *oas.OAS {
+	t.Helper()
+
+	api := &oas.OAS{T: openapi3.T{Info: &openapi3.Info{Title: "User events", Version: "1.2.0"}}}
+	api.SetTykExtension(&oas.XTykAPIGateway{Server: oas.Server{ListenPath: oas.ListenPath{Value: "/events/"}}})
+
+	var xTykStreaming oas.XTykStreaming
+	require.NoError(t, json.Unmarshal([]byte(streams), &xTykStreaming.Streams))
+	api.SetTykStreamingExtension(&xTykStreaming)
+
+	return api
+}
+
+func TestExport(t *testing.T) {
+	api := streamsAPI(t, `{
+		"signups": {
+			"input": {"kafka": {"addresses": ["broker-1:9092,broker-2:9092"], "topics": ["user.signedup"]}},
+			"output": {"http_server": {"stream_path": "/signups/sse"}}
+		},
+		"commands": {
+			"input": {"http_server": {"path": "/commands"}},
+			"output": {"amqp_0_9": {"urls": ["amqps://rabbit.example.com/vhost"], "exchange": "users", "key": "commands"}}
+		},
+		"chat": {
+			"input": {"broker": {"inputs": [{"http_server": {"path": "/chat"}}]}},
+			"output": {"http_server": {"ws_path": "/chat/ws"}}
+		},
+		"internal": {
+			"input": {"kafka": {"addresses": ["broker-1:9092"], "topics": ["a"]}},
+			"output": {"kafka": {"addresses": ["broker-1:9092"], "topic": "b"}}
+		}
+	}`)
+
+	doc, err := Export(api, "gateway.example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, Version, doc.AsyncAPI)
+	assert.Equal(t, Info{Title: "User events", Version: "1.2.0"}, doc.Info)
+	assert.Equal(t, map[string]*Server{
+		"gateway":    {Host: "gateway.example.com", Protocol: "http", Pathname: "/events"},
+		"gateway-ws": {Host: "gateway.example.com", Protocol: "ws", Pathname: "/events"},
+		"kafka":      {Host: "broker-1:9092", Protocol: "kafka"},
+		"kafka-2":    {Host: "broker-2:9092", Protocol: "kafka"},
+		"amqps":      {Host: "rabbit.example.com", Protocol: "amqps", Pathname: "/vhost"},
+	}, doc.Servers)
+
+	assert.NotContains(t, doc.Operations, "internal", "streams without an http_server aren't exported")
+
+	assert.Equal(t, &Operation{Action: ActionSend, Channel: Reference{Ref: "#/channels/signups"}}, doc.Operations["signups"])
+	assert.Equal(t, &Channel{
+		Address: "user.signedup",
+		Servers: []Reference{{Ref: "#/servers/kafka"}, {Ref: "#/servers/kafka-2"}},
+		HTTP:    &HTTPPaths{StreamPath: "/signups/sse", WSPath: "/get/ws"},
+	}, doc.Channels["signups"])
+
+	assert.Equal(t, ActionReceive, doc.Operations["commands"].Action)
+	assert.Equal(t, &Channel{
+		Address:  "commands",
+		Servers:  []Reference{{Ref: "#/servers/amqps"}},
+		Bindings: map[string]interface{}{"amqp": map[string]interface{}{"exchange": map[string]interface{}{"name": "users"}}},
+		HTTP:     &HTTPPaths{Path: "/commands"},
+	}, doc.Channels["commands"])
+
+	assert.Equal(t, ActionSend, doc.Operations["chat"].Action)
+	assert.Equal(t, &Channel{
+		Address: "/chat",
+		Servers: []Reference{{Ref: "#/servers/gateway"}, {Ref: "#/servers/gateway-ws"}},
+		HTTP:    &HTTPPaths{Path: "/chat", StreamPath: "/get/stream", WSPath: "/chat/ws"},
+	}, doc.Channels["chat"])
+
+	t.Run("round trip", func(t *testing.T) {
+		data, err := json.Marshal(doc)
+		require.NoError(t, err)
+
+		imported, err := Parse(data)
+		require.NoError(t, err)
+
+		streams, err := imported.Streams()
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]interface{}{
+			"input": map[string]interface{}{"kafka": map[string]interface{}{
+				"addresses": []interface{}{"broker-1:9092", "broker-2:9092"},
+				"topics":    []interface{}{"user.signedup"},
+			}},
+			"output": map[string]interface{}{"http_server": map[string]interface{}{
+				"stream_path": "/signups/sse",
+				"ws_path":     "/get/ws",
+			}},
+		}, streams["signups"])
+
+		assert.Equal(t, map[string]interface{}{"amqp_0_9": map[string]interface{}{
+			"urls":     []interface{}{"amqps://rabbit.example.com/vhost"},
+			"exchange": "users",
+			"key":      "commands",
+		}}, streams["commands"].(map[string]interface{})["output"])
+	})
+}
+
+func TestExport_CustomDomain(t *testing.T) {
+	api := streamsAPI(t, `{"s": {"input": {"http_server": {}}, "output": {"kafka": {"addresses": ["k:9092"], "topic": "t"}}}}`)
+	api.GetTykExtension().Server.CustomDomain = &oas.Domain{Enabled: true, Name: "events.example.com"}
+
+	doc, err := Export(api, "localhost:8080")
+	require.NoError(t, err)
+
+	assert.Equal(t, "events.example.com", doc.Servers[ServerGateway].Host)
+	assert.NotContains(t, doc.Servers, ServerGatewayWS)
+	assert.Equal(t, &HTTPPaths{Path: "/post"}, doc.Channels["s"].HTTP)
+}
+
+func TestExport_NoStreams(t *testing.T) {
+	_, err := Export(&oas.OAS{}, "localhost:8080")
+	assert.ErrorIs(t, err, ErrNoStreams)
+
+	api := streamsAPI(t, `{"s": {"input": {"kafka": {}}, "output": {"kafka": {}}}}`)
+	_, err = Export(api, "localhost:8080")
+	assert.ErrorIs(t, err, ErrNoStreams)
+}
@@ -0,0 +1,271 @@
+package asyncapi
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// The broker protocols of the AsyncAPI servers, and the Bento components they map to.
+const (
+	componentKafka      = "kafka"
+	componentMQTT       = "mqtt"
+	componentAMQP       = "amqp_0_9"
+	componentHTTPServer = "http_server"
+)
+
+var brokerComponents = map[string]string{
+	"kafka":        componentKafka,
+	"kafka-secure": componentKafka,
+	"mqtt":         componentMQTT,
+	"secure-mqtt":  componentMQTT,
+	"mqtts":        componentMQTT,
+	"amqp":         componentAMQP,
+	"amqps":        componentAMQP,
+}
+
+// brokerURLSchemes are the URL schemes of the broker protocols, for the components that take URLs.
+var brokerURLSchemes = map[string]string{
+	"mqtt":        "tcp",
+	"secure-mqtt": "ssl",
+	"mqtts":       "ssl",
+	"amqp":        "amqp",
+	"amqps":       "amqps",
+}
+
+var nonPathChars = regexp.MustCompile(`[^a-zA-Z0-9._~-]+`)
+
+// Streams builds the Tyk Streams configuration of the operations of the document, keyed by
+// operation ID. The streams are skeletons to complete with the authentication, processing
+// and tuning of the brokers.
+func (d *Document) Streams() (map[string]interface{}, error) {
+	if len(d.Operations) == 0 {
+		return nil, ErrNoOperations
+	}
+
+	streams := make(map[string]interface{}, len(d.Operations))
+	for _, id := range sortedKeys(d.Operations) {
+		operation := d.Operations[id]
+
+		channelName, ok := operation.Channel.name("channels")
+		channel := d.Channels[channelName]
+		if !ok || channel == nil {
+			return nil, fmt.Errorf("operation %q: channel %q not found", id, operation.Channel.Ref)
+		}
+
+		address := channel.Address
+		if address == "" {
+			address = channelName
+		}
+
+		paths := channel.httpPaths(address)
+		broker := d.broker(channel, address)
+
+		var input, output map[string]interface{}
+		switch operation.Action {
+		case ActionSend:
+			input, output = broker.input(), httpOutput(paths)
+			if broker == nil {
+				input = httpInput(paths)
+			}
+		case ActionReceive:
+			input, output = httpInput(paths), broker.output()
+			if broker == nil {
+				output = httpOutput(paths)
+			}
+		default:
+			return nil, fmt.Errorf("operation %q: unsupported action %q", id, operation.Action)
+		}
+
+		streams[id] = map[string]interface{}{
+			"input":  input,
+			"output": output,
+		}
+	}
+
+	return streams, nil
+}
+
+// httpPaths returns the paths of the channel, derived from its address unless set with x-tyk-http.
+func (c *Channel) httpPaths(address string) HTTPPaths {
+	base := "/" + strings.Trim(nonPathChars.ReplaceAllString(address, "-"), "-")
+	paths := HTTPPaths{
+		Path:       base,
+		StreamPath: path.Join(base, "stream"),
+		WSPath:     path.Join(base, "ws"),
+	}
+
+	if c.HTTP != nil {
+		if c.HTTP.Path != "" {
+			paths.Path = c.HTTP.Path
+		}
+		if c.HTTP.StreamPath != "" {
+			paths.StreamPath = c.HTTP.StreamPath
+		}
+		if c.HTTP.WSPath != "" {
+			paths.WSPath = c.HTTP.WSPath
+		}
+	}
+
+	return paths
+}
+
+func httpInput(paths HTTPPaths) map[string]interface{} {
+	return map[string]interface{}{
+		componentHTTPServer: map[string]interface{}{
+			"path":          paths.Path,
+			"allowed_verbs": []interface{}{"POST"},
+		},
+	}
+}
+
+func httpOutput(paths HTTPPaths) map[string]interface{} {
+	return map[string]interface{}{
+		componentHTTPServer: map[string]interface{}{
+			"stream_path": paths.StreamPath,
+			"ws_path":     paths.WSPath,
+		},
+	}
+}
+
+// broker is a topic or queue of the brokers of a protocol.
+type broker struct {
+	component string
+	protocol  string
+	servers   []*Server
+	address   string
+	bindings  map[string]interface{}
+}
+
+// broker returns the broker of the channel, from the first broker protocol of its servers,
+// or nil when the channel has no broker. Channels without servers are available on all servers.
+func (d *Document) broker(channel *Channel, address string) *broker {
+	var names []string
+	for _, server := range channel.Servers {
+		if name, ok := server.name("servers"); ok {
+			names = append(names, name)
+		}
+	}
+	if len(channel.Servers) == 0 {
+		names = sortedKeys(d.Servers)
+	}
+
+	var b *broker
+	for _, name := range names {
+		server := d.Servers[name]
+		if server == nil {
+			continue
+		}
+
+		component, ok := brokerComponents[server.Protocol]
+		if !ok {
+			continue
+		}
+
+		if b == nil {
+			b = &broker{component: component, protocol: server.Protocol, address: address, bindings: channel.Bindings}
+		}
+		if b.component == component {
+			b.servers = append(b.servers, server)
+		}
+	}
+
+	return b
+}
+
+func (b *broker) input() map[string]interface{} {
+	if b == nil {
+		return nil
+	}
+
+	var config map[string]interface{}
+	switch b.component {
+	case componentKafka:
+		config = map[string]interface{}{
+			"addresses": b.hosts(),
+			"topics":    []interface{}{b.address},
+		}
+		b.setKafkaTLS(config)
+	case componentMQTT:
+		config = map[string]interface{}{
+			"urls":   b.urls(),
+			"topics": []interface{}{b.address},
+		}
+	case componentAMQP:
+		queue := b.binding("queue", "name")
+		if queue == "" {
+			queue = b.address
+		}
+		config = map[string]interface{}{
+			"urls":  b.urls(),
+			"queue": queue,
+		}
+	}
+
+	return map[string]interface{}{b.component: config}
+}
+
+func (b *broker) output() map[string]interface{} {
+	if b == nil {
+		return nil
+	}
+
+	var config map[string]interface{}
+	switch b.component {
+	case componentKafka:
+		config = map[string]interface{}{
+			"addresses": b.hosts(),
+			"topic":     b.address,
+		}
+		b.setKafkaTLS(config)
+	case componentMQTT:
+		config = map[string]interface{}{
+			"urls":  b.urls(),
+			"topic": b.address,
+		}
+	case componentAMQP:
+		config = map[string]interface{}{
+			"urls":     b.urls(),
+			"exchange": b.address,
+		}
+		if exchange := b.binding("exchange", "name"); exchange != "" {
+			config["exchange"] = exchange
+			config["key"] = b.address
+		}
+	}
+
+	return map[string]interface{}{b.component: config}
+}
+
+// setKafkaTLS enables TLS for the kafka-secure protocol.
+func (b *broker) setKafkaTLS(config map[string]interface{}) {
+	if b.protocol == "kafka-secure" {
+		config["tls"] = map[string]interface{}{"enabled": true}
+	}
+}
+
+func (b *broker) hosts() []interface{} {
+	hosts := make([]interface{}, 0, len(b.servers))
+	for _, server := range b.servers {
+		hosts = append(hosts, server.Host)
+	}
+	return hosts
+}
+
+func (b *broker) urls() []interface{} {
+	urls := make([]interface{}, 0, len(b.servers))
+	for _, server := range b.servers {
+		scheme := brokerURLSchemes[server.Protocol]
+		urls = append(urls, scheme+"://"+server.Host+server.Pathname)
+	}
+	return urls
+}
+
+// binding returns a field of the AMQP binding of the channel, e.g. the name of its queue.
+func (b *broker) binding(object, field string) string {
+	amqp, _ := b.bindings["amqp"].(map[string]interface{})
+	value, _ := amqp[object].(map[string]interface{})
+	name, _ := value[field].(string)
+	return name
+}
@@ -0,0 +1,77 @@
+package apidef
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/TykTechnologies/tyk/internal/transcoding"
+)
+
+var (
+	// ErrGRPCTranscodingNoDescriptor is returned when gRPC transcoding is enabled without descriptors.
+	ErrGRPCTranscodingNoDescriptor = errors.New("gRPC transcoding requires a descriptor")
+	// ErrGRPCTranscodingInvalidDescriptor is returned when the descriptors of gRPC transcoding can't be loaded.
+	ErrGRPCTranscodingInvalidDescriptor = errors.New("invalid gRPC transcoding descriptor")
+)
+
+// GRPCTranscoding configures the transcoding of HTTP/JSON requests to a gRPC upstream.
+//
+// The request paths, relative to the listen path, are matched against the google.api.http
+// annotations of the service methods, or POST /package.Service/Method for the methods without
+// an annotation, and sent to the upstream as gRPC calls. The path of the upstream URL is not used. The upstream must be reachable over HTTP/2, using the `h2c://`
+// scheme for plaintext or `https://` with HTTP/2 enabled in the gateway.
+//
+// Unary responses are returned as JSON, server streaming responses as newline delimited
+// JSON or server-sent events, and gRPC status codes are mapped to HTTP status codes.
+// Client and bidirectional streaming methods are not transcoded.
+type GRPCTranscoding struct {
+	// Enabled activates gRPC transcoding.
+	Enabled bool `bson:"enabled" json:"enabled"`
+
+	// Descriptor is the base64 encoded, serialised FileDescriptorSet of the gRPC services,
+	// as produced by `protoc --include_imports --descriptor_set_out`.
+	Descriptor string `bson:"descriptor" json:"descriptor"`
+
+	// StreamFormat is the format of server streaming responses, `ndjson` (default) or `sse`.
+	StreamFormat string `bson:"stream_format" json:"stream_format,omitempty"`
+}
+
+// DescriptorSet returns the decoded FileDescriptorSet.
+func (g *GRPCTranscoding) DescriptorSet() ([]byte, error) {
+	if g.Descriptor == "" {
+		return nil, ErrGRPCTranscodingNoDescriptor
+	}
+
+	data, err := base64.StdEncoding.DecodeString(g.Descriptor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGRPCTranscodingInvalidDescriptor, err)
+	}
+
+	return data, nil
+}
+
+// Transcoder builds the transcoder of the configuration.
+func (g *GRPCTranscoding) Transcoder() (*transcoding.Transcoder, error) {
+	data, err := g.DescriptorSet()
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := transcoding.New(data, g.StreamFormat)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGRPCTranscodingInvalidDescriptor, err)
+	}
+
+	return t, nil
+}
+
+// Validate returns an error if transcoding is enabled with descriptors that can't be loaded.
+func (g *GRPCTranscoding) Validate() error {
+	if g == nil || !g.Enabled {
+		return nil
+	}
+
+	_, err := g.Transcoder()
+	return err
+}
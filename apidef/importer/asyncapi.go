@@ -0,0 +1,75 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/apidef/asyncapi"
+	"github.com/TykTechnologies/tyk/apidef/oas"
+)
+
+const AsyncAPISource APIImporterSource = "asyncapi"
+
+var errAsyncAPIClassic = errors.New("AsyncAPI documents can only be imported as Tyk OAS APIs")
+
+// AsyncAPI is an AsyncAPI 2.x or 3.x document, imported as a Tyk Streams API.
+type AsyncAPI struct {
+	doc *asyncapi.Document
+}
+
+func (a *AsyncAPI) LoadFrom(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	a.doc, err = asyncapi.Parse(data)
+	return err
+}
+
+// ToOAS builds an OpenAPI document with the Tyk Streams configuration of the operations of the
+// AsyncAPI document. The HTTP servers of the AsyncAPI document are set as the servers of the
+// OpenAPI document. Brokers are only set in the streams, they can't be the upstream of the API,
+// so documents without HTTP servers are imported with an explicit upstream URL.
+func (a *AsyncAPI) ToOAS() (*openapi3.T, error) {
+	streams, err := a.doc.Streams()
+	if err != nil {
+		return nil, err
+	}
+
+	builder := newOASBuilder(a.doc.Info.Title, a.doc.Info.Description, a.doc.Info.Version)
+
+	names := make([]string, 0, len(a.doc.Servers))
+	for name := range a.doc.Servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if server := a.doc.Servers[name]; server.Protocol == "http" || server.Protocol == "https" {
+			builder.addServer(server.URL())
+		}
+	}
+
+	builder.doc.Extensions = map[string]interface{}{
+		oas.ExtensionTykStreaming: &oas.XTykStreaming{Streams: streams},
+	}
+
+	return builder.doc, nil
+}
+
+func (a *AsyncAPI) ConvertIntoApiVersion(bool) (apidef.VersionInfo, error) {
+	return apidef.VersionInfo{}, errAsyncAPIClassic
+}
+
+func (a *AsyncAPI) InsertIntoAPIDefinitionAsVersion(apidef.VersionInfo, *apidef.APIDefinition, string) error {
+	return errAsyncAPIClassic
+}
+
+func (a *AsyncAPI) ToAPIDefinition(string, string, bool) (*apidef.APIDefinition, error) {
+	return nil, errAsyncAPIClassic
+}
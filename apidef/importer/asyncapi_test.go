@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef/oas"
+)
+
+const userEventsAsyncAPI = `asyncapi: 3.0.0
+info:
+  title: User events
+  version: 1.2.0
+  description: Events of the user service.
+servers:
+  kafka:
+    host: broker:9092
+    protocol: kafka
+  public:
+    host: api.example.com
+    protocol: https
+channels:
+  signups:
+    address: user.signedup
+    servers:
+      - $ref: '#/servers/kafka'
+operations:
+  userSignedUp:
+    action: send
+    channel:
+      $ref: '#/channels/signups'
+`
+
+func TestAsyncAPI_ToOAS(t *testing.T) {
+	imp, err := GetImporterForSource(AsyncAPISource)
+	require.NoError(t, err)
+	require.NoError(t, imp.LoadFrom(bytes.NewBufferString(userEventsAsyncAPI)))
+
+	doc, err := imp.(OASImporter).ToOAS()
+	require.NoError(t, err)
+
+	assert.Equal(t, "User events", doc.Info.Title)
+	assert.Equal(t, "1.2.0", doc.Info.Version)
+	assert.Equal(t, "Events of the user service.", doc.Info.Description)
+	assert.Equal(t, 0, doc.Paths.Len())
+
+	require.Len(t, doc.Servers, 1, "brokers aren't servers")
+	assert.Equal(t, "https://api.example.com", doc.Servers[0].URL)
+
+	xTykStreaming, ok := doc.Extensions[oas.ExtensionTykStreaming].(*oas.XTykStreaming)
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{
+		"input": map[string]interface{}{"kafka": map[string]interface{}{
+			"addresses": []interface{}{"broker:9092"},
+			"topics":    []interface{}{"user.signedup"},
+		}},
+		"output": map[string]interface{}{"http_server": map[string]interface{}{
+			"stream_path": "/user.signedup/stream",
+			"ws_path":     "/user.signedup/ws",
+		}},
+	}, xTykStreaming.Streams["userSignedUp"])
+
+	_, err = imp.ToAPIDefinition("testOrg", "", false)
+	assert.ErrorIs(t, err, errAsyncAPIClassic)
+}
+
+func TestAsyncAPI_Errors(t *testing.T) {
+	imp, err := GetImporterForSource(AsyncAPISource)
+	require.NoError(t, err)
+	assert.Error(t, imp.LoadFrom(bytes.NewBufferString(`{"asyncapi": "1.0.0"}`)))
+
+	require.NoError(t, imp.LoadFrom(bytes.NewBufferString(`{"asyncapi": "2.6.0", "info": {"title": "empty", "version": "1"}}`)))
+	_, err = imp.(OASImporter).ToOAS()
+	assert.Error(t, err)
+}
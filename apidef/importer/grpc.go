@@ -0,0 +1,490 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/getkin/kin-openapi/openapi3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/apidef/oas"
+	"github.com/TykTechnologies/tyk/internal/transcoding"
+)
+
+const GRPCSource APIImporterSource = "grpc"
+
+// grpcDefaultFilename is the name of a .proto file loaded from a reader.
+const grpcDefaultFilename = "service.proto"
+
+// grpcStatusSchema is the component schema of the error responses.
+const grpcStatusSchema = "google.rpc.Status"
+
+// GRPCDescriptor is a gRPC service description, loaded from a .proto file or a
+// serialised FileDescriptorSet.
+type GRPCDescriptor struct {
+	// Filename is the name of the .proto file, used to resolve its imports.
+	Filename string
+	// ImportPaths are the directories the imports of a .proto file are resolved from.
+	ImportPaths []string
+
+	files      *protoregistry.Files
+	descriptor []byte
+	schemas    openapi3.Schemas
+}
+
+// LoadFrom loads a FileDescriptorSet, as produced by `protoc --include_imports --descriptor_set_out`,
+// or compiles a .proto file. The google/api and google/protobuf imports are built in.
+func (g *GRPCDescriptor) LoadFrom(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err == nil && validDescriptorSet(&set) {
+		if g.files, err = transcoding.NewFiles(&set); err != nil {
+			return err
+		}
+	} else if g.files, err = g.compile(data); err != nil {
+		return err
+	}
+
+	var files []protoreflect.FileDescriptor
+	g.files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		files = append(files, fd)
+		return true
+	})
+
+	g.descriptor, err = transcoding.DescriptorSet(files)
+	return err
+}
+
+func validDescriptorSet(set *descriptorpb.FileDescriptorSet) bool {
+	if len(set.File) == 0 {
+		return false
+	}
+
+	for _, fd := range set.File {
+		if fd.GetName() == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compile compiles a .proto file and returns the registry of the file and its imports.
+func (g *GRPCDescriptor) compile(source []byte) (*protoregistry.Files, error) {
+	filename := g.Filename
+	if filename == "" {
+		filename = grpcDefaultFilename
+	}
+	filename = filepath.ToSlash(filename)
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(protocompile.CompositeResolver{
+			protocompile.ResolverFunc(func(path string) (protocompile.SearchResult, error) {
+				if path == filename {
+					return protocompile.SearchResult{Source: bytes.NewReader(source)}, nil
+				}
+				return protocompile.SearchResult{}, protoregistry.NotFound
+			}),
+			&protocompile.SourceResolver{ImportPaths: g.ImportPaths},
+			protocompile.ResolverFunc(func(path string) (protocompile.SearchResult, error) {
+				fd, err := protoregistry.GlobalFiles.FindFileByPath(path)
+				if err != nil {
+					return protocompile.SearchResult{}, err
+				}
+				return protocompile.SearchResult{Desc: fd}, nil
+			}),
+		}),
+		SourceInfoMode: protocompile.SourceInfoStandard,
+	}
+
+	compiled, err := compiler.Compile(context.Background(), filename)
+	if err != nil {
+		return nil, err
+	}
+
+	files := new(protoregistry.Files)
+
+	var register func(fd protoreflect.FileDescriptor) error
+	register = func(fd protoreflect.FileDescriptor) error {
+		if _, err := files.FindFileByPath(fd.Path()); err == nil {
+			return nil
+		}
+
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			if err := register(imports.Get(i).FileDescriptor); err != nil {
+				return err
+			}
+		}
+
+		return files.RegisterFile(fd)
+	}
+
+	for _, fd := range compiled {
+		if err := register(fd); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// ToOAS builds an OpenAPI document from the HTTP bindings of the services. The methods without
+// a google.api.http annotation are documented as POST /package.Service/Method, and the request
+// and response schemas follow the protobuf JSON mapping. The descriptors are kept in the
+// x-tyk-grpc-descriptor extension, to enable gRPC transcoding on import.
+func (g *GRPCDescriptor) ToOAS() (*openapi3.T, error) {
+	if g.files == nil {
+		return nil, errors.New("no descriptors loaded")
+	}
+
+	bindings := transcoding.Bindings(g.files)
+	if len(bindings) == 0 {
+		return nil, transcoding.ErrNoServices
+	}
+
+	services := map[protoreflect.FullName]protoreflect.ServiceDescriptor{}
+	for _, binding := range bindings {
+		service := binding.Method.Parent().(protoreflect.ServiceDescriptor)
+		services[service.FullName()] = service
+	}
+
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	title := strings.Join(names, ", ")
+	if len(names) == 1 {
+		title = string(services[protoreflect.FullName(names[0])].Name())
+	}
+
+	builder := newOASBuilder(title, "Imported from gRPC service descriptors", "")
+	g.schemas = openapi3.Schemas{grpcStatusSchema: grpcStatus()}
+
+	for _, name := range names {
+		service := services[protoreflect.FullName(name)]
+		builder.addTag(string(service.Name()), comments(service))
+	}
+
+	for _, binding := range bindings {
+		g.addOperation(builder, binding)
+	}
+
+	doc, err := builder.build()
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Components = &openapi3.Components{Schemas: g.schemas}
+	doc.Extensions = map[string]interface{}{
+		oas.ExtensionGRPCDescriptor: base64.StdEncoding.EncodeToString(g.descriptor),
+	}
+
+	return doc, nil
+}
+
+func (g *GRPCDescriptor) addOperation(builder *oasBuilder, binding transcoding.Binding) {
+	method := binding.Method
+	input := method.Input()
+
+	op := builder.operation(transcoding.OASPath(binding.Pattern), binding.HTTPMethod, splitWords(string(method.Name())))
+	op.Summary = string(method.Name())
+	op.Description = comments(method)
+	op.Tags = []string{string(method.Parent().Name())}
+
+	bound := map[string]bool{}
+	for _, param := range op.Parameters {
+		bound[param.Value.Name] = true
+		if fd := fieldByPath(input, param.Value.Name); fd != nil {
+			param.Value.Description = comments(fd)
+		}
+	}
+
+	switch binding.Body {
+	case "":
+	case "*":
+		op.RequestBody = requestBody(g.messageSchema(input))
+	default:
+		if fd := input.Fields().ByName(protoreflect.Name(binding.Body)); fd != nil {
+			bound[string(fd.Name())] = true
+			op.RequestBody = requestBody(g.fieldSchema(fd))
+		}
+	}
+
+	if binding.Body != "*" {
+		fields := input.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			if bound[string(fd.Name())] || !queryField(fd) {
+				continue
+			}
+
+			param := openapi3.NewQueryParameter(fd.JSONName()).WithSchema(g.fieldSchema(fd).Value)
+			param.Description = comments(fd)
+			if fd.IsList() {
+				param.Explode = openapi3.BoolPtr(true)
+			}
+			op.AddParameter(param)
+		}
+	}
+
+	output := g.messageSchema(method.Output())
+	if binding.ResponseBody != "" {
+		if fd := method.Output().Fields().ByName(protoreflect.Name(binding.ResponseBody)); fd != nil {
+			output = g.fieldSchema(fd)
+		}
+	}
+
+	content := openapi3.NewContentWithJSONSchemaRef(output)
+	if method.IsStreamingServer() {
+		result := openapi3.NewObjectSchema().WithPropertyRef("result", output).WithPropertyRef("error", statusRef())
+		content = openapi3.Content{
+			"application/x-ndjson": openapi3.NewMediaType().WithSchema(result),
+			"text/event-stream":    openapi3.NewMediaType().WithSchema(openapi3.NewStringSchema()),
+		}
+	}
+
+	op.Responses = openapi3.NewResponses(
+		openapi3.WithStatus(http.StatusOK, &openapi3.ResponseRef{
+			Value: openapi3.NewResponse().WithDescription(http.StatusText(http.StatusOK)).WithContent(content),
+		}),
+		openapi3.WithName("default", openapi3.NewResponse().WithDescription("An error status").WithJSONSchemaRef(statusRef())),
+	)
+}
+
+func requestBody(schema *openapi3.SchemaRef) *openapi3.RequestBodyRef {
+	return &openapi3.RequestBodyRef{
+		Value: openapi3.NewRequestBody().WithRequired(true).WithContent(openapi3.NewContentWithJSONSchemaRef(schema)),
+	}
+}
+
+// queryField returns true for the fields that can be set from query parameters.
+func queryField(fd protoreflect.FieldDescriptor) bool {
+	if fd.IsMap() {
+		return false
+	}
+
+	if fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind {
+		return true
+	}
+
+	_, ok := wellKnownSchemas[fd.Message().FullName()]
+	return ok && !fd.IsList()
+}
+
+func fieldByPath(md protoreflect.MessageDescriptor, path string) protoreflect.FieldDescriptor {
+	var fd protoreflect.FieldDescriptor
+	for _, name := range strings.Split(path, ".") {
+		if md == nil {
+			return nil
+		}
+		if fd = md.Fields().ByName(protoreflect.Name(name)); fd == nil {
+			return nil
+		}
+		md = fd.Message()
+	}
+	return fd
+}
+
+// messageSchema returns a reference to the component schema of a message, adding it if needed.
+func (g *GRPCDescriptor) messageSchema(md protoreflect.MessageDescriptor) *openapi3.SchemaRef {
+	if wellKnown, ok := wellKnownSchemas[md.FullName()]; ok {
+		return wellKnown().NewRef()
+	}
+
+	name := string(md.FullName())
+	ref := openapi3.NewSchemaRef("#/components/schemas/"+name, nil)
+	if _, ok := g.schemas[name]; ok {
+		return ref
+	}
+
+	schema := openapi3.NewObjectSchema()
+	schema.Description = comments(md)
+	// The schema is added before its fields, for recursive messages.
+	g.schemas[name] = schema.NewRef()
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		schema.WithPropertyRef(fd.JSONName(), g.fieldSchema(fd))
+	}
+
+	return ref
+}
+
+// fieldSchema returns the schema of a field, following the protobuf JSON mapping.
+func (g *GRPCDescriptor) fieldSchema(fd protoreflect.FieldDescriptor) *openapi3.SchemaRef {
+	if fd.IsMap() {
+		schema := openapi3.NewObjectSchema()
+		schema.AdditionalProperties = openapi3.AdditionalProperties{Schema: g.singularSchema(fd.MapValue())}
+		return schema.NewRef()
+	}
+
+	schema := g.singularSchema(fd)
+	if fd.IsList() {
+		array := openapi3.NewArraySchema()
+		array.Items = schema
+		schema = array.NewRef()
+	}
+
+	if description := comments(fd); description != "" && schema.Ref == "" {
+		schema.Value.Description = description
+	}
+
+	return schema
+}
+
+func (g *GRPCDescriptor) singularSchema(fd protoreflect.FieldDescriptor) *openapi3.SchemaRef {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return openapi3.NewBoolSchema().NewRef()
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return openapi3.NewInt32Schema().NewRef()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return openapi3.NewInt64Schema().WithMin(0).NewRef()
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// 64 bit integers are strings in the protobuf JSON mapping.
+		return openapi3.NewStringSchema().WithFormat("int64").NewRef()
+	case protoreflect.FloatKind:
+		return openapi3.NewFloat64Schema().WithFormat("float").NewRef()
+	case protoreflect.DoubleKind:
+		return openapi3.NewFloat64Schema().NewRef()
+	case protoreflect.BytesKind:
+		return openapi3.NewBytesSchema().NewRef()
+	case protoreflect.EnumKind:
+		schema := openapi3.NewStringSchema()
+		values := fd.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			schema.Enum = append(schema.Enum, string(values.Get(i).Name()))
+		}
+		return schema.NewRef()
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return g.messageSchema(fd.Message())
+	default:
+		return openapi3.NewStringSchema().NewRef()
+	}
+}
+
+// wellKnownSchemas are the schemas of the well-known types with a special JSON mapping.
+var wellKnownSchemas = map[protoreflect.FullName]func() *openapi3.Schema{
+	"google.protobuf.Timestamp":   openapi3.NewDateTimeSchema,
+	"google.protobuf.Duration":    func() *openapi3.Schema { return openapi3.NewStringSchema().WithPattern(`^-?\d+(\.\d+)?s$`) },
+	"google.protobuf.FieldMask":   openapi3.NewStringSchema,
+	"google.protobuf.Struct":      openapi3.NewObjectSchema,
+	"google.protobuf.Value":       openapi3.NewSchema,
+	"google.protobuf.ListValue":   func() *openapi3.Schema { return openapi3.NewArraySchema().WithItems(openapi3.NewSchema()) },
+	"google.protobuf.Empty":       openapi3.NewObjectSchema,
+	"google.protobuf.Any":         anySchema,
+	"google.protobuf.BoolValue":   openapi3.NewBoolSchema,
+	"google.protobuf.StringValue": openapi3.NewStringSchema,
+	"google.protobuf.BytesValue":  openapi3.NewBytesSchema,
+	"google.protobuf.Int32Value":  openapi3.NewInt32Schema,
+	"google.protobuf.UInt32Value": openapi3.NewInt64Schema,
+	"google.protobuf.Int64Value":  func() *openapi3.Schema { return openapi3.NewStringSchema().WithFormat("int64") },
+	"google.protobuf.UInt64Value": func() *openapi3.Schema { return openapi3.NewStringSchema().WithFormat("int64") },
+	"google.protobuf.FloatValue":  func() *openapi3.Schema { return openapi3.NewFloat64Schema().WithFormat("float") },
+	"google.protobuf.DoubleValue": openapi3.NewFloat64Schema,
+}
+
+// anySchema is the schema of google.protobuf.Any, an object with the type URL of its message.
+func anySchema() *openapi3.Schema {
+	return openapi3.NewObjectSchema().WithProperty("@type", openapi3.NewStringSchema())
+}
+
+// grpcStatus is the schema of the JSON error responses.
+func grpcStatus() *openapi3.SchemaRef {
+	return openapi3.NewObjectSchema().
+		WithProperty("code", openapi3.NewInt32Schema()).
+		WithProperty("message", openapi3.NewStringSchema()).
+		WithProperty("details", openapi3.NewArraySchema().WithItems(anySchema())).
+		NewRef()
+}
+
+func statusRef() *openapi3.SchemaRef {
+	return openapi3.NewSchemaRef("#/components/schemas/"+grpcStatusSchema, nil)
+}
+
+// splitWords splits a PascalCase name in words, e.g. GetBook becomes Get Book.
+func splitWords(name string) string {
+	var sb strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			sb.WriteRune(' ')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// comments returns the leading comments of a descriptor, when the source info is available.
+func comments(d protoreflect.Descriptor) string {
+	return strings.TrimSpace(d.ParentFile().SourceLocations().ByDescriptor(d).LeadingComments)
+}
+
+// ConvertIntoApiVersion converts the methods to a classic API version. The methods are tracked
+// but not allow listed, as the transcoder only routes the methods of the descriptors, including
+// path variables that span several segments.
+func (g *GRPCDescriptor) ConvertIntoApiVersion(bool) (apidef.VersionInfo, error) {
+	doc, err := g.ToOAS()
+	if err != nil {
+		return apidef.VersionInfo{}, err
+	}
+
+	versionInfo, err := oasToVersionInfo(doc, doc.Info.Version, false)
+	versionInfo.ExtendedPaths.WhiteList = nil
+	return versionInfo, err
+}
+
+func (g *GRPCDescriptor) InsertIntoAPIDefinitionAsVersion(version apidef.VersionInfo, def *apidef.APIDefinition, versionName string) error {
+	return insertVersion(version, def, versionName)
+}
+
+// ToAPIDefinition creates a classic API definition transcoding the requests to the gRPC upstream.
+func (g *GRPCDescriptor) ToAPIDefinition(orgID, upstreamURL string, asMock bool) (*apidef.APIDefinition, error) {
+	if asMock {
+		return nil, fmt.Errorf("mock responses are not supported for %s imports", GRPCSource)
+	}
+
+	doc, err := g.ToOAS()
+	if err != nil {
+		return nil, err
+	}
+
+	def, err := oasToAPIDefinition(doc, orgID, upstreamURL, false)
+	if err != nil {
+		return nil, err
+	}
+
+	versionName := doc.Info.Version
+	versionInfo := def.VersionData.Versions[versionName]
+	versionInfo.ExtendedPaths.WhiteList = nil
+	def.VersionData.Versions[versionName] = versionInfo
+
+	def.GRPCTranscoding = &apidef.GRPCTranscoding{
+		Enabled:    true,
+		Descriptor: base64.StdEncoding.EncodeToString(g.descriptor),
+	}
+
+	return def, nil
+}
@@ -0,0 +1,178 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef/oas"
+)
+
+const libraryProto = `syntax = "proto3";
+
+package library.v1;
+
+import "google/api/annotations.proto";
+import "google/protobuf/timestamp.proto";
+
+// Library manages shelves of books.
+service Library {
+  // GetBook returns a book.
+  rpc GetBook(GetBookRequest) returns (Book) {
+    option (google.api.http) = {get: "/v1/{name=shelves/*/books/*}"};
+  }
+
+  rpc CreateBook(CreateBookRequest) returns (Book) {
+    option (google.api.http) = {
+      post: "/v1/{parent=shelves/*}/books"
+      body: "book"
+      additional_bindings {post: "/v1/books" body: "*"}
+    };
+  }
+
+  rpc ListBooks(ListBooksRequest) returns (stream Book) {
+    option (google.api.http) = {get: "/v1/{parent=shelves/*}/books"};
+  }
+
+  rpc Ping(Book) returns (Book);
+
+  rpc Upload(stream Book) returns (Book);
+}
+
+enum Genre {
+  GENRE_UNSPECIFIED = 0;
+  FICTION = 1;
+}
+
+message Book {
+  string name = 1;
+  string title = 2;
+  int64 page_count = 3;
+  Genre genre = 4;
+  repeated string tags = 5;
+  google.protobuf.Timestamp published = 6;
+  map<string, string> labels = 7;
+  Book sequel = 8;
+}
+
+message GetBookRequest {
+  // The name of the book, e.g. shelves/1/books/2.
+  string name = 1;
+}
+
+message CreateBookRequest {
+  string parent = 1;
+  Book book = 2;
+}
+
+message ListBooksRequest {
+  string parent = 1;
+  int32 page_size = 2;
+  repeated Genre genres = 3;
+}
+`
+
+func loadGRPC(t *testing.T, data []byte) *GRPCDescriptor {
+	t.Helper()
+
+	imp, err := GetImporterForSource(GRPCSource)
+	require.NoError(t, err)
+	require.NoError(t, imp.LoadFrom(bytes.NewReader(data)))
+
+	return imp.(*GRPCDescriptor)
+}
+
+func TestGRPCDescriptor_ToOAS(t *testing.T) {
+	doc, err := loadGRPC(t, []byte(libraryProto)).ToOAS()
+	require.NoError(t, err)
+
+	assert.Equal(t, "Library", doc.Info.Title)
+	require.Len(t, doc.Tags, 1)
+	assert.Equal(t, "Library manages shelves of books.", doc.Tags[0].Description)
+	assert.Equal(t, 4, doc.Paths.Len(), "client streaming methods are not imported")
+	assert.NotEmpty(t, doc.Extensions[oas.ExtensionGRPCDescriptor])
+
+	get := doc.Paths.Find("/v1/{name}").Get
+	require.NotNil(t, get)
+	assert.Equal(t, "getBook", get.OperationID)
+	assert.Equal(t, "GetBook returns a book.", get.Description)
+	assert.Equal(t, "The name of the book, e.g. shelves/1/books/2.", get.Parameters.GetByInAndName("path", "name").Description)
+	assert.Equal(t, "#/components/schemas/library.v1.Book", get.Responses.Status(200).Value.Content.Get("application/json").Schema.Ref)
+	assert.Equal(t, "#/components/schemas/google.rpc.Status", get.Responses.Default().Value.Content.Get("application/json").Schema.Ref)
+
+	create := doc.Paths.Find("/v1/{parent}/books").Post
+	require.NotNil(t, create)
+	assert.Equal(t, "#/components/schemas/library.v1.Book", create.RequestBody.Value.Content.Get("application/json").Schema.Ref)
+	assert.NotNil(t, doc.Paths.Find("/v1/books").Post, "additional bindings are imported")
+	assert.Equal(t, "createBook2", doc.Paths.Find("/v1/books").Post.OperationID)
+
+	list := doc.Paths.Find("/v1/{parent}/books").Get
+	require.NotNil(t, list)
+	assert.NotNil(t, list.Parameters.GetByInAndName("query", "pageSize"))
+	genres := list.Parameters.GetByInAndName("query", "genres")
+	require.NotNil(t, genres)
+	assert.Equal(t, []interface{}{"GENRE_UNSPECIFIED", "FICTION"}, genres.Schema.Value.Items.Value.Enum)
+	assert.NotNil(t, list.Responses.Status(200).Value.Content.Get("application/x-ndjson"))
+	assert.NotNil(t, list.Responses.Status(200).Value.Content.Get("text/event-stream"))
+
+	ping := doc.Paths.Find("/library.v1.Library/Ping").Post
+	require.NotNil(t, ping, "methods without annotation are bound to their gRPC path")
+	assert.NotNil(t, ping.RequestBody)
+
+	book := doc.Components.Schemas["library.v1.Book"].Value
+	require.NotNil(t, book)
+	assert.True(t, book.Properties["pageCount"].Value.Type.Is("string"), "64 bit integers are strings in JSON")
+	assert.Equal(t, "date-time", book.Properties["published"].Value.Format)
+	assert.True(t, book.Properties["tags"].Value.Type.Is("array"))
+	assert.True(t, book.Properties["labels"].Value.Type.Is("object"))
+	assert.Equal(t, "#/components/schemas/library.v1.Book", book.Properties["sequel"].Ref)
+
+	data, err := doc.MarshalJSON()
+	require.NoError(t, err)
+	loaded, err := openapi3.NewLoader().LoadFromData(data)
+	require.NoError(t, err)
+	assert.NoError(t, loaded.Validate(context.Background()))
+}
+
+func TestGRPCDescriptor_LoadDescriptorSet(t *testing.T) {
+	descriptor := loadGRPC(t, []byte(libraryProto)).descriptor
+
+	doc, err := loadGRPC(t, descriptor).ToOAS()
+	require.NoError(t, err)
+	assert.Equal(t, 4, doc.Paths.Len())
+	assert.Equal(t, "", doc.Paths.Find("/v1/{name}").Get.Description, "the descriptor set has no source info")
+}
+
+func TestGRPCDescriptor_Errors(t *testing.T) {
+	imp, err := GetImporterForSource(GRPCSource)
+	require.NoError(t, err)
+	assert.Error(t, imp.LoadFrom(bytes.NewBufferString(`syntax = "proto3"; message {`)))
+
+	noService := loadGRPC(t, []byte(`syntax = "proto3"; message Empty {}`))
+	_, err = noService.ToOAS()
+	assert.Error(t, err)
+}
+
+func TestGRPCDescriptor_ToAPIDefinition(t *testing.T) {
+	imp := loadGRPC(t, []byte(libraryProto))
+
+	def, err := imp.ToAPIDefinition("testOrg", "h2c://localhost:50051", false)
+	require.NoError(t, err)
+
+	require.NotNil(t, def.GRPCTranscoding)
+	assert.True(t, def.GRPCTranscoding.Enabled)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(imp.descriptor), def.GRPCTranscoding.Descriptor)
+	assert.NoError(t, def.GRPCTranscoding.Validate())
+
+	v := def.VersionData.Versions["1.0.0"]
+	assert.Empty(t, v.ExtendedPaths.WhiteList)
+	assert.Len(t, v.ExtendedPaths.TrackEndpoints, 5)
+
+	_, err = imp.ToAPIDefinition("testOrg", "h2c://localhost:50051", true)
+	assert.Error(t, err)
+}
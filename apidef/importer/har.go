@@ -0,0 +1,214 @@
+package importer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/TykTechnologies/tyk/apidef"
+)
+
+const HARSource APIImporterSource = "har"
+
+var (
+	harUUID  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	harHexID = regexp.MustCompile(`^[0-9a-fA-F]{12,}$`)
+	harDigit = regexp.MustCompile(`[0-9]`)
+)
+
+// harStaticExtensions are the extensions of the static files that are not imported.
+var harStaticExtensions = map[string]bool{
+	".css": true, ".js": true, ".map": true, ".png": true, ".jpg": true, ".jpeg": true, ".gif": true,
+	".svg": true, ".ico": true, ".webp": true, ".woff": true, ".woff2": true, ".ttf": true, ".eot": true,
+}
+
+// HAR is an HTTP Archive of captured traffic.
+type HAR struct {
+	Log struct {
+		Entries []HAREntry `json:"entries"`
+	} `json:"log"`
+}
+
+// HAREntry is a request captured with its response.
+type HAREntry struct {
+	Request struct {
+		Method      string         `json:"method"`
+		URL         string         `json:"url"`
+		Headers     []HARNameValue `json:"headers"`
+		QueryString []HARNameValue `json:"queryString"`
+		PostData    *struct {
+			MimeType string         `json:"mimeType"`
+			Text     string         `json:"text"`
+			Params   []HARNameValue `json:"params"`
+		} `json:"postData"`
+	} `json:"request"`
+	Response struct {
+		Status     int            `json:"status"`
+		StatusText string         `json:"statusText"`
+		Headers    []HARNameValue `json:"headers"`
+		Content    struct {
+			MimeType string `json:"mimeType"`
+			Text     string `json:"text"`
+			Encoding string `json:"encoding"`
+		} `json:"content"`
+	} `json:"response"`
+}
+
+// HARNameValue is a header, query parameter or form field.
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (h *HAR) LoadFrom(r io.Reader) error {
+	return json.NewDecoder(r).Decode(&h)
+}
+
+// ToOAS builds an OpenAPI document from the captured traffic. The identifiers in the request
+// paths are replaced with path parameters, and the requests to the same operation are merged,
+// keeping the first response of each status code as its example.
+func (h *HAR) ToOAS() (*openapi3.T, error) {
+	var builder *oasBuilder
+
+	for _, entry := range h.Log.Entries {
+		u, err := url.Parse(entry.Request.URL)
+		if err != nil {
+			log.WithError(err).Warningf("Skipping HAR entry with an invalid URL: %s", entry.Request.URL)
+			continue
+		}
+
+		if harStatic(u.Path, entry.Response.Content.MimeType) {
+			continue
+		}
+
+		if builder == nil {
+			builder = newOASBuilder(u.Host, "Imported from captured traffic", "")
+		}
+
+		builder.addServer(u.Scheme + "://" + u.Host)
+
+		method := strings.ToUpper(entry.Request.Method)
+		if method == "" {
+			method = http.MethodGet
+		}
+		op := builder.operation(harTemplatePath(u.Path), method, "")
+
+		for _, q := range entry.Request.QueryString {
+			addQueryParam(op, q.Name, q.Value)
+		}
+
+		if postData := entry.Request.PostData; postData != nil {
+			if len(postData.Params) > 0 {
+				fields := map[string]string{}
+				for _, p := range postData.Params {
+					fields[p.Name] = p.Value
+				}
+				setFormRequestBody(op, postData.MimeType, fields)
+			} else {
+				setRequestBody(op, postData.MimeType, postData.Text)
+			}
+		}
+
+		content := entry.Response.Content
+		body := content.Text
+		if content.Encoding == "base64" {
+			decoded, err := base64.StdEncoding.DecodeString(body)
+			if err != nil {
+				body = ""
+			} else {
+				body = string(decoded)
+			}
+		}
+
+		addResponseExample(op, entry.Response.Status, entry.Response.StatusText, content.MimeType, body, nil, "")
+	}
+
+	if builder == nil {
+		return nil, errNoOperations
+	}
+
+	return builder.build()
+}
+
+func (h *HAR) ConvertIntoApiVersion(asMock bool) (apidef.VersionInfo, error) {
+	doc, err := h.ToOAS()
+	if err != nil {
+		return apidef.VersionInfo{}, err
+	}
+
+	return oasToVersionInfo(doc, doc.Info.Version, asMock)
+}
+
+func (h *HAR) InsertIntoAPIDefinitionAsVersion(version apidef.VersionInfo, def *apidef.APIDefinition, versionName string) error {
+	return insertVersion(version, def, versionName)
+}
+
+func (h *HAR) ToAPIDefinition(orgID, upstreamURL string, asMock bool) (*apidef.APIDefinition, error) {
+	doc, err := h.ToOAS()
+	if err != nil {
+		return nil, err
+	}
+
+	return oasToAPIDefinition(doc, orgID, upstreamURL, asMock)
+}
+
+// harStatic returns true for the requests of static files.
+func harStatic(urlPath, mimeType string) bool {
+	if harStaticExtensions[strings.ToLower(path.Ext(urlPath))] {
+		return true
+	}
+
+	mimeType = strings.ToLower(mimeType)
+	return strings.HasPrefix(mimeType, "image/") || strings.HasPrefix(mimeType, "font/") ||
+		strings.HasPrefix(mimeType, "text/css") || strings.Contains(mimeType, "javascript")
+}
+
+// harTemplatePath replaces the path segments that look like identifiers with path parameters,
+// named after the previous segment, e.g. /users/42 becomes /users/{userId}.
+func harTemplatePath(urlPath string) string {
+	var (
+		segments []string
+		names    = map[string]int{}
+	)
+
+	for _, segment := range strings.Split(urlPath, "/") {
+		if segment == "" {
+			continue
+		}
+
+		if harIdentifier(segment) {
+			name := "id"
+			if n := len(segments); n > 0 && !strings.HasPrefix(segments[n-1], "{") {
+				name = camelCase(strings.TrimSuffix(segments[n-1], "s") + " id")
+			}
+
+			names[name]++
+			if count := names[name]; count > 1 {
+				name = fmt.Sprintf("%s%d", name, count)
+			}
+
+			segment = "{" + name + "}"
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
+
+// harIdentifier returns true for numbers, UUIDs and long hexadecimal strings with digits.
+func harIdentifier(segment string) bool {
+	if strings.Trim(segment, "0123456789") == "" {
+		return true
+	}
+
+	return harUUID.MatchString(segment) || (harHexID.MatchString(segment) && harDigit.MatchString(segment))
+}
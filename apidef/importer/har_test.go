@@ -0,0 +1,81 @@
+package importer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const harLog = `{
+  "log": {
+    "entries": [
+      {
+        "request": {"method": "GET", "url": "https://api.example.com/users/42?expand=posts", "queryString": [{"name": "expand", "value": "posts"}]},
+        "response": {"status": 200, "statusText": "OK", "content": {"mimeType": "application/json; charset=utf-8", "text": "{\"id\": 42}"}}
+      },
+      {
+        "request": {"method": "GET", "url": "https://api.example.com/users/7"},
+        "response": {"status": 200, "content": {"mimeType": "application/json", "text": "{\"id\": 7}"}}
+      },
+      {
+        "request": {"method": "GET", "url": "https://api.example.com/users/3f2504e0-4f89-11d3-9a0c-0305e82c3301"},
+        "response": {"status": 404, "content": {"mimeType": "application/json", "text": "eyJlcnJvciI6Im5vdCBmb3VuZCJ9", "encoding": "base64"}}
+      },
+      {
+        "request": {
+          "method": "POST",
+          "url": "https://api.example.com/users",
+          "postData": {"mimeType": "application/json", "text": "{\"name\": \"Ann\"}"}
+        },
+        "response": {"status": 201, "content": {"mimeType": "application/json", "text": "{\"id\": 43}"}}
+      },
+      {
+        "request": {"method": "GET", "url": "https://api.example.com/static/app.js"},
+        "response": {"status": 200, "content": {"mimeType": "application/javascript", "text": "alert(1)"}}
+      }
+    ]
+  }
+}`
+
+func TestHAR_ToOAS(t *testing.T) {
+	imp, err := GetImporterForSource(HARSource)
+	require.NoError(t, err)
+	require.NoError(t, imp.LoadFrom(bytes.NewBufferString(harLog)))
+
+	doc, err := imp.(*HAR).ToOAS()
+	require.NoError(t, err)
+
+	assert.Equal(t, "api.example.com", doc.Info.Title)
+	require.Len(t, doc.Servers, 1)
+	assert.Equal(t, "https://api.example.com", doc.Servers[0].URL)
+	assert.Equal(t, 2, doc.Paths.Len(), "the requests are deduplicated and static files skipped")
+
+	get := doc.Paths.Find("/users/{userId}").Get
+	require.NotNil(t, get)
+	assert.NotNil(t, get.Parameters.GetByInAndName("path", "userId"))
+	assert.NotNil(t, get.Parameters.GetByInAndName("query", "expand"))
+	assert.Equal(t, map[string]interface{}{"id": float64(42)}, get.Responses.Status(200).Value.Content.Get("application/json").Example)
+	assert.Equal(t, map[string]interface{}{"error": "not found"}, get.Responses.Status(404).Value.Content.Get("application/json").Example)
+
+	post := doc.Paths.Find("/users").Post
+	require.NotNil(t, post)
+	assert.NotNil(t, post.RequestBody.Value.Content.Get("application/json"))
+	assert.NotNil(t, post.Responses.Status(201))
+}
+
+func TestHARTemplatePath(t *testing.T) {
+	tcs := map[string]string{
+		"/users/42/posts/7":                "/users/{userId}/posts/{postId}",
+		"/orders/5f1d7a3b9c2e4a0012ab34cd": "/orders/{orderId}",
+		"/42/43":                           "/{id}/{id2}",
+		"/v1/users":                        "/v1/users",
+		"/files/report":                    "/files/report",
+		"/items/3f2504e0-4f89-11d3-9a0c-0305e82c3301": "/items/{itemId}",
+	}
+
+	for path, expected := range tcs {
+		assert.Equal(t, expected, harTemplatePath(path), path)
+	}
+}
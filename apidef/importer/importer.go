@@ -28,6 +28,14 @@ func GetImporterForSource(source APIImporterSource) (APIImporter, error) {
 		return &SwaggerAST{}, nil
 	case WSDLSource:
 		return &WSDLDef{}, nil
+	case PostmanSource:
+		return &PostmanCollection{}, nil
+	case HARSource:
+		return &HAR{}, nil
+	case GRPCSource:
+		return &GRPCDescriptor{}, nil
+	case AsyncAPISource:
+		return &AsyncAPI{}, nil
 	default:
 		return nil, errors.New("source not matched, failing")
 	}
@@ -0,0 +1,491 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/internal/uuid"
+)
+
+// oasVersion is the OpenAPI version of the documents built by the importers.
+const oasVersion = "3.0.3"
+
+var errNoOperations = errors.New("no requests found to import")
+
+// OASImporter is implemented by the importers that build an OpenAPI 3 document,
+// the document is imported as a Tyk OAS API.
+type OASImporter interface {
+	ToOAS() (*openapi3.T, error)
+}
+
+// oasBuilder builds an OpenAPI document from the requests and responses of an API description.
+type oasBuilder struct {
+	doc          *openapi3.T
+	operationIDs map[string]bool
+}
+
+func newOASBuilder(title, description, version string) *oasBuilder {
+	if version == "" {
+		version = "1.0.0"
+	}
+
+	return &oasBuilder{
+		doc: &openapi3.T{
+			OpenAPI: oasVersion,
+			Info: &openapi3.Info{
+				Title:       title,
+				Description: description,
+				Version:     version,
+			},
+			Paths: openapi3.NewPaths(),
+		},
+		operationIDs: map[string]bool{},
+	}
+}
+
+// addServer adds a server URL, duplicates are ignored.
+func (b *oasBuilder) addServer(url string) {
+	url = strings.TrimSuffix(url, "/")
+	if url == "" {
+		return
+	}
+
+	for _, server := range b.doc.Servers {
+		if server.URL == url {
+			return
+		}
+	}
+
+	b.doc.Servers = append(b.doc.Servers, &openapi3.Server{URL: url})
+}
+
+// addTag adds a tag, duplicates are ignored.
+func (b *oasBuilder) addTag(name, description string) {
+	if b.doc.Tags.Get(name) != nil {
+		return
+	}
+
+	b.doc.Tags = append(b.doc.Tags, &openapi3.Tag{Name: name, Description: description})
+}
+
+// addSecurityScheme adds a security scheme to the components of the document.
+func (b *oasBuilder) addSecurityScheme(name string, scheme *openapi3.SecurityScheme) {
+	if b.doc.Components == nil {
+		b.doc.Components = &openapi3.Components{}
+	}
+
+	if b.doc.Components.SecuritySchemes == nil {
+		b.doc.Components.SecuritySchemes = openapi3.SecuritySchemes{}
+	}
+
+	b.doc.Components.SecuritySchemes[name] = &openapi3.SecuritySchemeRef{Value: scheme}
+}
+
+// operation returns the operation of the method on the path, creating it if needed.
+// The name is used to generate a unique operation ID.
+func (b *oasBuilder) operation(path, method, name string) *openapi3.Operation {
+	pathItem := b.doc.Paths.Value(path)
+	if pathItem == nil {
+		pathItem = &openapi3.PathItem{}
+		b.doc.Paths.Set(path, pathItem)
+	}
+
+	if op := pathItem.GetOperation(method); op != nil {
+		return op
+	}
+
+	op := openapi3.NewOperation()
+	op.OperationID = b.operationID(name, path, method)
+	op.Responses = openapi3.NewResponses()
+	for _, param := range pathParams(path) {
+		op.AddParameter(openapi3.NewPathParameter(param).WithSchema(openapi3.NewStringSchema()))
+	}
+
+	pathItem.SetOperation(method, op)
+	return op
+}
+
+// operationID returns a unique operation ID, in camel case, from the name of the operation
+// or its method and path.
+func (b *oasBuilder) operationID(name, path, method string) string {
+	id := camelCase(name)
+	if id == "" {
+		id = camelCase(strings.ToLower(method) + " " + path)
+	}
+
+	unique := id
+	for i := 2; b.operationIDs[unique]; i++ {
+		unique = id + strconv.Itoa(i)
+	}
+
+	b.operationIDs[unique] = true
+	return unique
+}
+
+// build returns the document, it fails if no operation was added.
+func (b *oasBuilder) build() (*openapi3.T, error) {
+	if b.doc.Paths.Len() == 0 {
+		return nil, errNoOperations
+	}
+
+	return b.doc, nil
+}
+
+// addQueryParam documents a query parameter of the operation, with an example value.
+func addQueryParam(op *openapi3.Operation, name, example string) {
+	if name == "" || op.Parameters.GetByInAndName(openapi3.ParameterInQuery, name) != nil {
+		return
+	}
+
+	param := openapi3.NewQueryParameter(name).WithSchema(openapi3.NewStringSchema())
+	if example != "" {
+		param.Example = example
+	}
+
+	op.AddParameter(param)
+}
+
+// setRequestBody documents the request body of the operation from an example body.
+func setRequestBody(op *openapi3.Operation, contentType, body string) {
+	if op.RequestBody != nil || body == "" {
+		return
+	}
+
+	op.RequestBody = &openapi3.RequestBodyRef{
+		Value: openapi3.NewRequestBody().WithContent(openapi3.Content{
+			mediaTypeOf(contentType): mediaTypeFromExample(contentType, body, ""),
+		}),
+	}
+}
+
+// setFormRequestBody documents a form request body of the operation from its fields.
+func setFormRequestBody(op *openapi3.Operation, contentType string, fields map[string]string) {
+	if op.RequestBody != nil || len(fields) == 0 {
+		return
+	}
+
+	schema := openapi3.NewObjectSchema()
+	example := map[string]interface{}{}
+	for name, value := range fields {
+		schema.WithProperty(name, openapi3.NewStringSchema())
+		example[name] = value
+	}
+
+	mediaType := openapi3.NewMediaType().WithSchema(schema)
+	mediaType.Example = example
+
+	op.RequestBody = &openapi3.RequestBodyRef{
+		Value: openapi3.NewRequestBody().WithContent(openapi3.Content{mediaTypeOf(contentType): mediaType}),
+	}
+}
+
+// addResponseExample documents an example response of the operation. The examples of the same
+// status code and content type are kept by name, so they can be selected for mock responses.
+func addResponseExample(op *openapi3.Operation, code int, description, contentType, body string, headers map[string]string, exampleName string) {
+	if code == 0 {
+		code = http.StatusOK
+	}
+
+	response := op.Responses.Status(code)
+	if response == nil {
+		if description == "" {
+			description = http.StatusText(code)
+		}
+
+		// The placeholder default response is replaced with the documented responses.
+		if placeholder := op.Responses.Default(); placeholder != nil && placeholder.Value.Description != nil && *placeholder.Value.Description == "" {
+			op.Responses.Delete("default")
+		}
+
+		response = &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription(description)}
+		op.Responses.Set(strconv.Itoa(code), response)
+	}
+
+	for name, value := range headers {
+		if response.Value.Headers == nil {
+			response.Value.Headers = openapi3.Headers{}
+		}
+		if _, ok := response.Value.Headers[name]; ok || ignoredHeader(name) {
+			continue
+		}
+
+		header := &openapi3.Header{Parameter: openapi3.Parameter{Schema: openapi3.NewStringSchema().NewRef(), Example: value}}
+		response.Value.Headers[name] = &openapi3.HeaderRef{Value: header}
+	}
+
+	if body == "" {
+		return
+	}
+
+	if response.Value.Content == nil {
+		response.Value.Content = openapi3.Content{}
+	}
+
+	contentType = mediaTypeOf(contentType)
+
+	mediaType := response.Value.Content.Get(contentType)
+	if mediaType == nil {
+		response.Value.Content[contentType] = mediaTypeFromExample(contentType, body, exampleName)
+		return
+	}
+
+	if _, ok := mediaType.Examples[exampleName]; ok || mediaType.Example != nil {
+		return
+	}
+
+	if mediaType.Examples == nil {
+		mediaType.Examples = openapi3.Examples{}
+	}
+
+	mediaType.Examples[exampleName] = &openapi3.ExampleRef{Value: openapi3.NewExample(exampleValue(contentType, body))}
+}
+
+// mediaTypeFromExample documents a media type from an example body, the schema is inferred from JSON bodies.
+func mediaTypeFromExample(contentType, body, exampleName string) *openapi3.MediaType {
+	value := exampleValue(contentType, body)
+
+	mediaType := openapi3.NewMediaType().WithSchemaRef(schemaFromExample(value))
+	if exampleName == "" {
+		mediaType.Example = value
+	} else {
+		mediaType.Examples = openapi3.Examples{
+			exampleName: &openapi3.ExampleRef{Value: openapi3.NewExample(value)},
+		}
+	}
+
+	return mediaType
+}
+
+// exampleValue returns the decoded JSON body, or the body as is for other content types.
+func exampleValue(contentType, body string) interface{} {
+	if !isJSON(contentType) {
+		return body
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return body
+	}
+
+	return value
+}
+
+// schemaFromExample infers the schema of a decoded JSON value.
+func schemaFromExample(value interface{}) *openapi3.SchemaRef {
+	switch v := value.(type) {
+	case bool:
+		return openapi3.NewBoolSchema().NewRef()
+	case float64:
+		if v == float64(int64(v)) {
+			return openapi3.NewIntegerSchema().NewRef()
+		}
+		return openapi3.NewFloat64Schema().NewRef()
+	case string:
+		return openapi3.NewStringSchema().NewRef()
+	case []interface{}:
+		items := openapi3.NewSchema()
+		if len(v) > 0 {
+			items = schemaFromExample(v[0]).Value
+		}
+		return openapi3.NewArraySchema().WithItems(items).NewRef()
+	case map[string]interface{}:
+		schema := openapi3.NewObjectSchema()
+		for name, prop := range v {
+			schema.WithPropertyRef(name, schemaFromExample(prop))
+		}
+		return schema.NewRef()
+	}
+
+	return openapi3.NewSchema().NewRef()
+}
+
+// mediaTypeOf returns the media type of a content type, without its parameters.
+func mediaTypeOf(contentType string) string {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+	if mediaType == "" {
+		return "text/plain"
+	}
+	return mediaType
+}
+
+// isJSON returns true for JSON content types.
+func isJSON(contentType string) bool {
+	mediaType := mediaTypeOf(contentType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// ignoredHeader returns true for the headers that are not documented on operations.
+func ignoredHeader(name string) bool {
+	switch http.CanonicalHeaderKey(name) {
+	case "Content-Type", "Content-Length", "Date", "Connection", "Transfer-Encoding", "Server":
+		return true
+	}
+	return false
+}
+
+// pathParams returns the names of the templated segments of the path.
+func pathParams(path string) []string {
+	var params []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params = append(params, strings.Trim(segment, "{}"))
+		}
+	}
+	return params
+}
+
+// camelCase converts a free text name to camel case, keeping letters and digits only.
+func camelCase(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var sb strings.Builder
+	for i, word := range words {
+		runes := []rune(strings.ToLower(word))
+		if i > 0 {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+		sb.WriteString(string(runes))
+	}
+
+	return sb.String()
+}
+
+// oasToVersionInfo converts the operations of an OpenAPI document to the allow list of a classic API version.
+// With asMock, the operations documenting an example response reply with the first one.
+func oasToVersionInfo(doc *openapi3.T, name string, asMock bool) (apidef.VersionInfo, error) {
+	versionInfo := apidef.VersionInfo{
+		Name:             name,
+		UseExtendedPaths: true,
+	}
+
+	if doc.Paths.Len() == 0 {
+		return versionInfo, errNoOperations
+	}
+
+	for _, path := range doc.Paths.InMatchingOrder() {
+		whitelistMeta := apidef.EndPointMeta{
+			Path:          path,
+			MethodActions: map[string]apidef.EndpointMethodMeta{},
+		}
+
+		for method, op := range doc.Paths.Value(path).Operations() {
+			methodMeta := apidef.EndpointMethodMeta{
+				Action: apidef.NoAction,
+				Code:   http.StatusOK,
+			}
+
+			if asMock {
+				if code, data, headers, ok := firstExample(op); ok {
+					methodMeta = apidef.EndpointMethodMeta{Action: apidef.Reply, Code: code, Data: data, Headers: headers}
+				}
+			}
+
+			whitelistMeta.MethodActions[method] = methodMeta
+			versionInfo.ExtendedPaths.TrackEndpoints = append(versionInfo.ExtendedPaths.TrackEndpoints, apidef.TrackEndpointMeta{
+				Path:   path,
+				Method: method,
+			})
+		}
+
+		versionInfo.ExtendedPaths.WhiteList = append(versionInfo.ExtendedPaths.WhiteList, whitelistMeta)
+	}
+
+	return versionInfo, nil
+}
+
+// firstExample returns the example response of the lowest status code documented by the operation.
+func firstExample(op *openapi3.Operation) (int, string, map[string]string, bool) {
+	codes := make([]int, 0, op.Responses.Len())
+	for status := range op.Responses.Map() {
+		if code, err := strconv.Atoi(status); err == nil {
+			codes = append(codes, code)
+		}
+	}
+	sort.Ints(codes)
+
+	for _, code := range codes {
+		response := op.Responses.Status(code).Value
+		for contentType, mediaType := range response.Content {
+			value := mediaType.Example
+			if value == nil {
+				for _, name := range sortedKeys(mediaType.Examples) {
+					value = mediaType.Examples[name].Value.Value
+					break
+				}
+			}
+
+			if value == nil {
+				continue
+			}
+
+			data, ok := value.(string)
+			if !ok {
+				raw, err := json.Marshal(value)
+				if err != nil {
+					continue
+				}
+				data = string(raw)
+			}
+
+			return code, data, map[string]string{"Content-Type": contentType}, true
+		}
+	}
+
+	return 0, "", nil, false
+}
+
+func sortedKeys(examples openapi3.Examples) []string {
+	keys := make([]string, 0, len(examples))
+	for key := range examples {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// oasToAPIDefinition creates a classic API definition allowing the operations of an OpenAPI document.
+func oasToAPIDefinition(doc *openapi3.T, orgID, upstreamURL string, asMock bool) (*apidef.APIDefinition, error) {
+	ad := apidef.APIDefinition{
+		Name:             doc.Info.Title,
+		Active:           true,
+		UseKeylessAccess: true,
+		APIID:            uuid.NewHex(),
+		OrgID:            orgID,
+	}
+	ad.VersionDefinition.Key = "version"
+	ad.VersionDefinition.Location = "header"
+	ad.VersionData.Versions = make(map[string]apidef.VersionInfo)
+	ad.Proxy.ListenPath = "/" + ad.APIID + "/"
+	ad.Proxy.StripListenPath = true
+	ad.Proxy.TargetURL = upstreamURL
+
+	versionName := strings.TrimSpace(doc.Info.Version)
+	versionData, err := oasToVersionInfo(doc, versionName, asMock)
+	if err != nil {
+		return nil, err
+	}
+
+	ad.VersionData.Versions[versionName] = versionData
+	return &ad, nil
+}
+
+// insertVersion adds a version to a classic API definition.
+func insertVersion(version apidef.VersionInfo, def *apidef.APIDefinition, versionName string) error {
+	if def.VersionData.Versions == nil {
+		def.VersionData.Versions = make(map[string]apidef.VersionInfo)
+	}
+
+	def.VersionData.NotVersioned = false
+	def.VersionData.Versions[versionName] = version
+	return nil
+}
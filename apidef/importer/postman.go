@@ -0,0 +1,513 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/TykTechnologies/tyk/apidef"
+)
+
+const PostmanSource APIImporterSource = "postman"
+
+const (
+	postmanAuthInherit = "inherit"
+	postmanAuthAPIKey  = "apikey"
+	postmanAuthBearer  = "bearer"
+	postmanAuthBasic   = "basic"
+	postmanAuthOAuth2  = "oauth2"
+)
+
+var postmanVariable = regexp.MustCompile(`{{\s*([^{}\s]+)\s*}}`)
+
+// PostmanCollection is a Postman v2.1 collection.
+type PostmanCollection struct {
+	Info struct {
+		Name        string             `json:"name"`
+		Description PostmanDescription `json:"description"`
+		Schema      string             `json:"schema"`
+	} `json:"info"`
+	Item     []PostmanItem  `json:"item"`
+	Auth     *PostmanAuth   `json:"auth"`
+	Variable []PostmanParam `json:"variable"`
+}
+
+// PostmanItem is a folder, when it has items, or a request of a collection.
+type PostmanItem struct {
+	Name        string             `json:"name"`
+	Description PostmanDescription `json:"description"`
+	Item        []PostmanItem      `json:"item"`
+	Auth        *PostmanAuth       `json:"auth"`
+	Request     *PostmanRequest    `json:"request"`
+	Response    []PostmanResponse  `json:"response"`
+}
+
+// PostmanRequest is a request of a collection.
+type PostmanRequest struct {
+	Method      string             `json:"method"`
+	URL         PostmanURL         `json:"url"`
+	Header      []PostmanKeyValue  `json:"header"`
+	Body        *PostmanBody       `json:"body"`
+	Auth        *PostmanAuth       `json:"auth"`
+	Description PostmanDescription `json:"description"`
+}
+
+// UnmarshalJSON decodes a request, which can be given as its URL only.
+func (r *PostmanRequest) UnmarshalJSON(data []byte) error {
+	var url string
+	if err := json.Unmarshal(data, &url); err == nil {
+		*r = PostmanRequest{Method: http.MethodGet, URL: PostmanURL{Raw: url}}
+		return nil
+	}
+
+	type request PostmanRequest
+	return json.Unmarshal(data, (*request)(r))
+}
+
+// PostmanURL is the URL of a request.
+type PostmanURL struct {
+	Raw      string            `json:"raw"`
+	Protocol string            `json:"protocol"`
+	Host     []string          `json:"host"`
+	Port     string            `json:"port"`
+	Path     []string          `json:"path"`
+	Query    []PostmanKeyValue `json:"query"`
+}
+
+// UnmarshalJSON decodes a URL, which can be given as a string.
+func (u *PostmanURL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		*u = PostmanURL{Raw: raw}
+		return nil
+	}
+
+	type url PostmanURL
+	return json.Unmarshal(data, (*url)(u))
+}
+
+// PostmanBody is the body of a request.
+type PostmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw"`
+	URLEncoded []PostmanKeyValue `json:"urlencoded"`
+	FormData   []PostmanKeyValue `json:"formdata"`
+	Options    struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+}
+
+// PostmanResponse is an example response saved with a request.
+type PostmanResponse struct {
+	Name     string            `json:"name"`
+	Code     int               `json:"code"`
+	Status   string            `json:"status"`
+	Header   []PostmanKeyValue `json:"header"`
+	Body     string            `json:"body"`
+	Language string            `json:"_postman_previewlanguage"`
+}
+
+// PostmanAuth is the authentication of a collection, folder or request. The parameters
+// of the authentication are given under the name of its type.
+type PostmanAuth struct {
+	Type   string         `json:"type"`
+	APIKey []PostmanParam `json:"apikey"`
+	Bearer []PostmanParam `json:"bearer"`
+	OAuth2 []PostmanParam `json:"oauth2"`
+}
+
+func (a *PostmanAuth) param(params []PostmanParam, key string) string {
+	for _, p := range params {
+		if p.Key == key {
+			if s, ok := p.Value.(string); ok {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// PostmanKeyValue is a header, query parameter or form field.
+type PostmanKeyValue struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+}
+
+// PostmanParam is a variable or authentication parameter, its value can be of any type.
+type PostmanParam struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// PostmanDescription is a description, which can be given as a string or an object.
+type PostmanDescription string
+
+// UnmarshalJSON decodes a description given as a string or an object with content.
+func (d *PostmanDescription) UnmarshalJSON(data []byte) error {
+	var content string
+	if err := json.Unmarshal(data, &content); err == nil {
+		*d = PostmanDescription(content)
+		return nil
+	}
+
+	var description struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(data, &description); err != nil {
+		return err
+	}
+
+	*d = PostmanDescription(description.Content)
+	return nil
+}
+
+func (p *PostmanCollection) LoadFrom(r io.Reader) error {
+	return json.NewDecoder(r).Decode(&p)
+}
+
+// ToOAS builds an OpenAPI document from the collection. Folders are documented as tags,
+// requests as operations, saved examples as example responses and authentications as
+// security schemes.
+func (p *PostmanCollection) ToOAS() (*openapi3.T, error) {
+	if len(p.Item) == 0 {
+		return nil, errNoOperations
+	}
+
+	c := &postmanConverter{
+		collection: p,
+		builder:    newOASBuilder(p.Info.Name, string(p.Info.Description), ""),
+		variables:  map[string]string{},
+		schemes:    map[string]*openapi3.SecurityScheme{},
+	}
+
+	for _, v := range p.Variable {
+		if s, ok := v.Value.(string); ok {
+			c.variables[v.Key] = s
+		}
+	}
+
+	if name := c.securityScheme(p.Auth); name != "" {
+		c.builder.doc.Security = openapi3.SecurityRequirements{openapi3.NewSecurityRequirement().Authenticate(name)}
+	}
+
+	c.addItems(p.Item, "", p.Auth)
+
+	return c.builder.build()
+}
+
+func (p *PostmanCollection) ConvertIntoApiVersion(asMock bool) (apidef.VersionInfo, error) {
+	doc, err := p.ToOAS()
+	if err != nil {
+		return apidef.VersionInfo{}, err
+	}
+
+	return oasToVersionInfo(doc, doc.Info.Version, asMock)
+}
+
+func (p *PostmanCollection) InsertIntoAPIDefinitionAsVersion(version apidef.VersionInfo, def *apidef.APIDefinition, versionName string) error {
+	return insertVersion(version, def, versionName)
+}
+
+func (p *PostmanCollection) ToAPIDefinition(orgID, upstreamURL string, asMock bool) (*apidef.APIDefinition, error) {
+	doc, err := p.ToOAS()
+	if err != nil {
+		return nil, err
+	}
+
+	return oasToAPIDefinition(doc, orgID, upstreamURL, asMock)
+}
+
+// postmanConverter holds the state of the conversion of a collection.
+type postmanConverter struct {
+	collection *PostmanCollection
+	builder    *oasBuilder
+	variables  map[string]string
+	// schemes holds the security schemes by name, to reuse the names of identical schemes.
+	schemes map[string]*openapi3.SecurityScheme
+}
+
+// addItems documents the requests of the items, auth is the authentication inherited from the parent.
+func (c *postmanConverter) addItems(items []PostmanItem, tag string, auth *PostmanAuth) {
+	for _, item := range items {
+		itemAuth := auth
+		if item.Auth != nil && item.Auth.Type != postmanAuthInherit {
+			itemAuth = item.Auth
+		}
+
+		if item.Request == nil {
+			c.builder.addTag(item.Name, string(item.Description))
+			c.addItems(item.Item, item.Name, itemAuth)
+			continue
+		}
+
+		c.addRequest(item, tag, itemAuth)
+	}
+}
+
+func (c *postmanConverter) addRequest(item PostmanItem, tag string, auth *PostmanAuth) {
+	req := item.Request
+	if req.Auth != nil && req.Auth.Type != postmanAuthInherit {
+		auth = req.Auth
+	}
+
+	method := strings.ToUpper(req.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	server, path := c.splitURL(req.URL)
+	c.builder.addServer(server)
+
+	op := c.builder.operation(path, method, item.Name)
+	op.Summary = item.Name
+	op.Description = string(req.Description)
+	if op.Description == "" {
+		op.Description = string(item.Description)
+	}
+	if tag != "" {
+		op.Tags = []string{tag}
+	}
+
+	if auth != c.collection.Auth {
+		security := openapi3.SecurityRequirements{}
+		if name := c.securityScheme(auth); name != "" {
+			security = append(security, openapi3.NewSecurityRequirement().Authenticate(name))
+		}
+		op.Security = &security
+	}
+
+	for _, q := range req.URL.Query {
+		if !q.Disabled {
+			addQueryParam(op, q.Key, q.Value)
+		}
+	}
+
+	if req.Body != nil {
+		c.addBody(op, req)
+	}
+
+	for _, resp := range item.Response {
+		headers := map[string]string{}
+		for _, h := range resp.Header {
+			headers[h.Key] = h.Value
+		}
+
+		contentType := contentTypeOf(resp.Header, resp.Language)
+		name := resp.Name
+		if name == "" {
+			name = fmt.Sprintf("example%d", resp.Code)
+		}
+
+		addResponseExample(op, resp.Code, resp.Status, contentType, resp.Body, headers, name)
+	}
+}
+
+func (c *postmanConverter) addBody(op *openapi3.Operation, req *PostmanRequest) {
+	switch req.Body.Mode {
+	case "raw":
+		setRequestBody(op, contentTypeOf(req.Header, req.Body.Options.Raw.Language), req.Body.Raw)
+	case "urlencoded":
+		setFormRequestBody(op, "application/x-www-form-urlencoded", formFields(req.Body.URLEncoded))
+	case "formdata":
+		setFormRequestBody(op, "multipart/form-data", formFields(req.Body.FormData))
+	}
+}
+
+// splitURL returns the server and the templated path of a request URL. The server is
+// empty when it can't be resolved from the collection variables.
+func (c *postmanConverter) splitURL(u PostmanURL) (string, string) {
+	var server, path string
+
+	if len(u.Host) > 0 || len(u.Path) > 0 {
+		server = c.resolve(strings.Join(u.Host, "."))
+		if server != "" && !strings.Contains(server, "://") {
+			protocol := u.Protocol
+			if protocol == "" {
+				protocol = "https"
+			}
+			server = protocol + "://" + server
+		}
+		if server != "" && u.Port != "" {
+			server += ":" + u.Port
+		}
+		path = strings.Join(u.Path, "/")
+	} else {
+		raw := strings.SplitN(u.Raw, "?", 2)[0]
+		rest, scheme := raw, ""
+		if i := strings.Index(raw, "://"); i >= 0 {
+			scheme, rest = raw[:i+3], raw[i+3:]
+		}
+
+		host := rest
+		if i := strings.Index(rest, "/"); i >= 0 {
+			host, path = rest[:i], rest[i+1:]
+		}
+		server = c.resolve(scheme + host)
+	}
+
+	if strings.Contains(server, "{{") {
+		server = ""
+	}
+
+	return server, templatePath(path)
+}
+
+// resolve replaces the collection variables in s.
+func (c *postmanConverter) resolve(s string) string {
+	return postmanVariable.ReplaceAllStringFunc(s, func(match string) string {
+		name := postmanVariable.FindStringSubmatch(match)[1]
+		if value, ok := c.variables[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// securityScheme documents the security scheme of the authentication and returns its name,
+// or an empty name when the authentication isn't supported.
+func (c *postmanConverter) securityScheme(auth *PostmanAuth) string {
+	scheme := postmanSecurityScheme(auth)
+	if scheme == nil {
+		return ""
+	}
+
+	name := auth.Type
+	for i := 2; ; i++ {
+		existing, ok := c.schemes[name]
+		if !ok {
+			break
+		}
+		if reflect.DeepEqual(existing, scheme) {
+			return name
+		}
+		name = fmt.Sprintf("%s%d", auth.Type, i)
+	}
+
+	c.schemes[name] = scheme
+	c.builder.addSecurityScheme(name, scheme)
+	return name
+}
+
+// postmanSecurityScheme converts a Postman authentication to a security scheme.
+func postmanSecurityScheme(auth *PostmanAuth) *openapi3.SecurityScheme {
+	if auth == nil {
+		return nil
+	}
+
+	switch auth.Type {
+	case postmanAuthAPIKey:
+		in := auth.param(auth.APIKey, "in")
+		if in == "" {
+			in = openapi3.ParameterInHeader
+		}
+		name := auth.param(auth.APIKey, "key")
+		if name == "" {
+			name = "X-API-Key"
+		}
+		return &openapi3.SecurityScheme{Type: "apiKey", In: in, Name: name}
+	case postmanAuthBearer:
+		scheme := openapi3.NewJWTSecurityScheme()
+		if token := auth.param(auth.Bearer, "token"); strings.Count(token, ".") != 2 {
+			scheme.BearerFormat = ""
+		}
+		return scheme
+	case postmanAuthBasic:
+		return openapi3.NewSecurityScheme().WithType("http").WithScheme("basic")
+	case postmanAuthOAuth2:
+		return postmanOAuth2(auth)
+	}
+
+	return nil
+}
+
+func postmanOAuth2(auth *PostmanAuth) *openapi3.SecurityScheme {
+	scopes := map[string]string{}
+	for _, scope := range strings.Fields(auth.param(auth.OAuth2, "scope")) {
+		scopes[scope] = ""
+	}
+
+	flow := &openapi3.OAuthFlow{
+		AuthorizationURL: auth.param(auth.OAuth2, "authUrl"),
+		TokenURL:         auth.param(auth.OAuth2, "accessTokenUrl"),
+		Scopes:           scopes,
+	}
+
+	flows := &openapi3.OAuthFlows{}
+	switch auth.param(auth.OAuth2, "grant_type") {
+	case "client_credentials":
+		flows.ClientCredentials = flow
+	case "password_credentials":
+		flows.Password = flow
+	case "implicit":
+		flows.Implicit = flow
+	default:
+		flows.AuthorizationCode = flow
+	}
+
+	if err := flows.Validate(context.Background()); err != nil {
+		log.WithError(err).Warning("Ignoring OAuth 2.0 authentication without URLs")
+		return nil
+	}
+
+	return &openapi3.SecurityScheme{Type: "oauth2", Flows: flows}
+}
+
+// templatePath converts the path variables of a request path, :name and {{name}}, to path templates.
+func templatePath(path string) string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		switch {
+		case segment == "":
+			continue
+		case strings.HasPrefix(segment, ":") && len(segment) > 1:
+			segment = "{" + segment[1:] + "}"
+		case postmanVariable.MatchString(segment):
+			segment = postmanVariable.ReplaceAllString(segment, "{$1}")
+		}
+		segments = append(segments, segment)
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
+
+// contentTypeOf returns the content type from the headers, or from the language of a raw body.
+func contentTypeOf(headers []PostmanKeyValue, language string) string {
+	for _, h := range headers {
+		if !h.Disabled && http.CanonicalHeaderKey(h.Key) == "Content-Type" && h.Value != "" {
+			return h.Value
+		}
+	}
+
+	switch language {
+	case "json":
+		return "application/json"
+	case "xml":
+		return "application/xml"
+	case "html":
+		return "text/html"
+	case "javascript":
+		return "application/javascript"
+	}
+
+	return "text/plain"
+}
+
+func formFields(fields []PostmanKeyValue) map[string]string {
+	values := map[string]string{}
+	for _, f := range fields {
+		if !f.Disabled {
+			values[f.Key] = f.Value
+		}
+	}
+	return values
+}
@@ -0,0 +1,162 @@
+package importer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef"
+)
+
+const postmanCollection = `{
+  "info": {
+    "name": "Pets",
+    "description": {"content": "The pet store"},
+    "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+  },
+  "auth": {
+    "type": "apikey",
+    "apikey": [{"key": "key", "value": "X-Pet-Key"}, {"key": "in", "value": "header"}]
+  },
+  "variable": [{"key": "baseUrl", "value": "https://pets.example.com/v1"}],
+  "item": [
+    {
+      "name": "pets",
+      "description": "Manage pets",
+      "item": [
+        {
+          "name": "List pets",
+          "request": {
+            "method": "GET",
+            "url": {
+              "raw": "{{baseUrl}}/pets?limit=10",
+              "host": ["{{baseUrl}}"],
+              "path": ["pets"],
+              "query": [{"key": "limit", "value": "10"}]
+            }
+          },
+          "response": [
+            {
+              "name": "all pets",
+              "code": 200,
+              "status": "OK",
+              "header": [{"key": "Content-Type", "value": "application/json"}],
+              "body": "[{\"id\": 1, \"name\": \"Rex\"}]"
+            }
+          ]
+        },
+        {
+          "name": "Get pet",
+          "request": {
+            "method": "GET",
+            "url": "{{baseUrl}}/pets/:petId"
+          },
+          "response": [
+            {"name": "found", "code": 200, "_postman_previewlanguage": "json", "body": "{\"id\": 1}"},
+            {"name": "not found", "code": 404, "_postman_previewlanguage": "json", "body": "{\"error\": \"not found\"}"}
+          ]
+        },
+        {
+          "name": "Create pet",
+          "request": {
+            "method": "POST",
+            "auth": {"type": "basic"},
+            "url": {"raw": "{{baseUrl}}/pets", "host": ["{{baseUrl}}"], "path": ["pets"]},
+            "body": {"mode": "raw", "raw": "{\"name\": \"Rex\", \"age\": 3}", "options": {"raw": {"language": "json"}}}
+          }
+        }
+      ]
+    },
+    {
+      "name": "Health",
+      "request": {
+        "method": "GET",
+        "auth": {"type": "noauth"},
+        "url": {"raw": "{{baseUrl}}/health", "host": ["{{baseUrl}}"], "path": ["health"]}
+      }
+    }
+  ]
+}`
+
+func loadPostman(t *testing.T) *PostmanCollection {
+	t.Helper()
+
+	imp, err := GetImporterForSource(PostmanSource)
+	require.NoError(t, err)
+	require.NoError(t, imp.LoadFrom(bytes.NewBufferString(postmanCollection)))
+
+	return imp.(*PostmanCollection)
+}
+
+func TestPostmanCollection_ToOAS(t *testing.T) {
+	doc, err := loadPostman(t).ToOAS()
+	require.NoError(t, err)
+
+	assert.Equal(t, "Pets", doc.Info.Title)
+	assert.Equal(t, "The pet store", doc.Info.Description)
+	require.Len(t, doc.Servers, 1)
+	assert.Equal(t, "https://pets.example.com/v1", doc.Servers[0].URL)
+
+	require.Len(t, doc.Tags, 1)
+	assert.Equal(t, "pets", doc.Tags[0].Name)
+	assert.Equal(t, "Manage pets", doc.Tags[0].Description)
+
+	list := doc.Paths.Find("/pets").Get
+	require.NotNil(t, list)
+	assert.Equal(t, "listPets", list.OperationID)
+	assert.Equal(t, []string{"pets"}, list.Tags)
+	assert.NotNil(t, list.Parameters.GetByInAndName("query", "limit"))
+	assert.Nil(t, list.Security, "the collection authentication is inherited")
+	assert.NotNil(t, list.Responses.Status(200).Value.Content.Get("application/json").Examples["all pets"])
+
+	get := doc.Paths.Find("/pets/{petId}").Get
+	require.NotNil(t, get)
+	assert.NotNil(t, get.Parameters.GetByInAndName("path", "petId"))
+	assert.NotNil(t, get.Responses.Status(404))
+
+	create := doc.Paths.Find("/pets").Post
+	require.NotNil(t, create)
+	require.NotNil(t, create.Security)
+	assert.Contains(t, (*create.Security)[0], "basic")
+	schema := create.RequestBody.Value.Content.Get("application/json").Schema.Value
+	assert.Contains(t, schema.Properties, "name")
+	assert.True(t, schema.Properties["age"].Value.Type.Is("integer"))
+
+	health := doc.Paths.Find("/health").Get
+	require.NotNil(t, health.Security)
+	assert.Empty(t, *health.Security)
+
+	apiKey := doc.Components.SecuritySchemes["apikey"].Value
+	assert.Equal(t, "apiKey", apiKey.Type)
+	assert.Equal(t, "X-Pet-Key", apiKey.Name)
+	assert.Equal(t, "header", apiKey.In)
+	assert.Contains(t, doc.Security[0], "apikey")
+}
+
+func TestPostmanCollection_ToAPIDefinition(t *testing.T) {
+	def, err := loadPostman(t).ToAPIDefinition("testOrg", "http://test.com", true)
+	require.NoError(t, err)
+
+	v, ok := def.VersionData.Versions["1.0.0"]
+	require.True(t, ok)
+	assert.Len(t, v.ExtendedPaths.TrackEndpoints, 4)
+
+	for _, meta := range v.ExtendedPaths.WhiteList {
+		if meta.Path != "/pets/{petId}" {
+			continue
+		}
+		assert.Equal(t, apidef.EndpointMethodMeta{
+			Action:  apidef.Reply,
+			Code:    200,
+			Data:    `{"id":1}`,
+			Headers: map[string]string{"Content-Type": "application/json"},
+		}, meta.MethodActions["GET"])
+	}
+}
+
+func TestTemplatePath(t *testing.T) {
+	assert.Equal(t, "/users/{id}/posts/{postId}", templatePath("users/:id/posts/{{postId}}/"))
+	assert.Equal(t, "/", templatePath(""))
+}
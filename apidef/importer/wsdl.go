@@ -10,6 +10,7 @@ import (
 	"github.com/TykTechnologies/tyk/apidef"
 
 	"github.com/TykTechnologies/tyk/internal/uuid"
+	"github.com/TykTechnologies/tyk/internal/xsd"
 )
 
 const WSDLSource APIImporterSource = "wsdl"
@@ -39,11 +40,45 @@ const (
 
 type WSDLDef struct {
 	Definition WSDL `xml:"http://schemas.xmlsoap.org/wsdl/ definitions"`
+
+	// namespaces are the namespace declarations of the definitions, the schemas of the types refer to them.
+	namespaces []xml.Attr
 }
 
 type WSDL struct {
-	Services []*WSDLService `xml:"http://schemas.xmlsoap.org/wsdl/ service"`
-	Bindings []*WSDLBinding `xml:"http://schemas.xmlsoap.org/wsdl/ binding"`
+	Types     WSDLTypes       `xml:"http://schemas.xmlsoap.org/wsdl/ types"`
+	Messages  []*WSDLMessage  `xml:"http://schemas.xmlsoap.org/wsdl/ message"`
+	PortTypes []*WSDLPortType `xml:"http://schemas.xmlsoap.org/wsdl/ portType"`
+	Services  []*WSDLService  `xml:"http://schemas.xmlsoap.org/wsdl/ service"`
+	Bindings  []*WSDLBinding  `xml:"http://schemas.xmlsoap.org/wsdl/ binding"`
+}
+
+// WSDLTypes holds the XML schemas of the messages.
+type WSDLTypes struct {
+	Schemas string `xml:",innerxml"`
+}
+
+type WSDLMessage struct {
+	Name  string      `xml:"name,attr"`
+	Parts []*WSDLPart `xml:"http://schemas.xmlsoap.org/wsdl/ part"`
+}
+
+type WSDLPart struct {
+	Name    string `xml:"name,attr"`
+	Element string `xml:"element,attr"`
+	Type    string `xml:"type,attr"`
+}
+
+type WSDLPortType struct {
+	Name       string                   `xml:"name,attr"`
+	Operations []*WSDLPortTypeOperation `xml:"http://schemas.xmlsoap.org/wsdl/ operation"`
+}
+
+type WSDLPortTypeOperation struct {
+	Name  string `xml:"name,attr"`
+	Input struct {
+		Message string `xml:"message,attr"`
+	} `xml:"http://schemas.xmlsoap.org/wsdl/ input"`
 }
 
 type WSDLService struct {
@@ -63,6 +98,7 @@ type WSDLAddress struct {
 
 type WSDLBinding struct {
 	Name                string           `xml:"name,attr"`
+	Type                string           `xml:"type,attr"`
 	Operations          []*WSDLOperation `xml:"http://schemas.xmlsoap.org/wsdl/ operation"`
 	Protocol            string
 	Method              string
@@ -79,6 +115,11 @@ func (def *WSDLDef) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	if start.Name.Space == NS_WSDL20 {
 		return errors.New("WSDL 2.0 is not supported")
 	} else if start.Name.Space == NS_WSDL && start.Name.Local == "definitions" {
+		for _, attr := range start.Attr {
+			if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+				def.namespaces = append(def.namespaces, attr)
+			}
+		}
 		return d.DecodeElement(&def.Definition, &start)
 	} else {
 		return errors.New("Invalid WSDL file. WSDL definition must start contain <definitions> element")
@@ -86,11 +127,13 @@ func (def *WSDLDef) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 }
 
 func (b *WSDLBinding) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	//Get value of name attribute
+	//Get value of name and type attributes
 	for _, attr := range start.Attr {
-		if attr.Name.Local == "name" {
+		switch attr.Name.Local {
+		case "name":
 			b.Name = attr.Value
-			break
+		case "type":
+			b.Type = attr.Value
 		}
 	}
 
@@ -323,6 +366,8 @@ func (def *WSDLDef) ConvertIntoApiVersion(bool) (apidef.VersionInfo, error) {
 	var foundPort bool
 	var serviceCount int
 
+	schema := def.schema()
+
 	for _, service := range def.Definition.Services {
 		foundPort = false
 		if service.Name == "" {
@@ -400,6 +445,16 @@ func (def *WSDLDef) ConvertIntoApiVersion(bool) (apidef.VersionInfo, error) {
 					}
 
 					versionInfo.ExtendedPaths.URLRewrite = append(versionInfo.ExtendedPaths.URLRewrite, operationUrlRewrite)
+
+					//Validate the payload of SOAP operations against the element of their input message
+					if element := def.inputElement(binding, op); schema != "" && binding.Protocol != PROT_HTTP && element != "" {
+						versionInfo.ExtendedPaths.ValidateXML = append(versionInfo.ExtendedPaths.ValidateXML, apidef.ValidateXMLMeta{
+							Path:    path,
+							Method:  method,
+							Schema:  schema,
+							Element: element,
+						})
+					}
 				}
 
 				break
@@ -418,6 +473,60 @@ func (def *WSDLDef) ConvertIntoApiVersion(bool) (apidef.VersionInfo, error) {
 	return versionInfo, nil
 }
 
+// schema returns the types of the definitions as an XML document declaring the namespaces of the
+// definitions, or an empty string when the types have no valid XML schema.
+func (def *WSDLDef) schema() string {
+	if strings.TrimSpace(def.Definition.Types.Schemas) == "" {
+		return ""
+	}
+
+	var buf strings.Builder
+	buf.WriteString("<types")
+	for _, attr := range def.namespaces {
+		name := attr.Name.Local
+		if attr.Name.Space != "" {
+			name = attr.Name.Space + ":" + name
+		}
+
+		buf.WriteString(" " + name + `="`)
+		xml.EscapeText(&buf, []byte(attr.Value))
+		buf.WriteString(`"`)
+	}
+	buf.WriteString(">" + def.Definition.Types.Schemas + "</types>")
+
+	if _, err := xsd.Parse([]byte(buf.String())); err != nil {
+		log.WithError(err).Warning("Couldn't parse the WSDL types, SOAP requests won't be validated")
+		return ""
+	}
+
+	return buf.String()
+}
+
+// inputElement returns the name of the element of the input message of an operation, or an empty
+// string when the message isn't a single element.
+func (def *WSDLDef) inputElement(binding *WSDLBinding, op *WSDLOperation) string {
+	var message string
+	for _, portType := range def.Definition.PortTypes {
+		if portType.Name != trimNamespace(binding.Type) {
+			continue
+		}
+
+		for _, portTypeOp := range portType.Operations {
+			if portTypeOp.Name == op.Name {
+				message = trimNamespace(portTypeOp.Input.Message)
+			}
+		}
+	}
+
+	for _, msg := range def.Definition.Messages {
+		if msg.Name == message && len(msg.Parts) == 1 && msg.Parts[0].Element != "" {
+			return trimNamespace(msg.Parts[0].Element)
+		}
+	}
+
+	return ""
+}
+
 func (def *WSDLDef) InsertIntoAPIDefinitionAsVersion(version apidef.VersionInfo, apidef *apidef.APIDefinition, versionName string) error {
 	apidef.VersionData.NotVersioned = false
 	apidef.VersionData.Versions[versionName] = version
@@ -3,6 +3,12 @@ package importer
 import (
 	"bytes"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/internal/xsd"
 )
 
 type testWSDLInput struct {
@@ -294,6 +300,43 @@ func TestToAPIDefinition_WSDL(t *testing.T) {
 	}
 }
 
+func TestToAPIDefinition_WSDL_ValidateXML(t *testing.T) {
+	wsdlImp := &WSDLDef{}
+	require.NoError(t, wsdlImp.LoadFrom(bytes.NewBufferString(holidayService)))
+	wsdlImp.SetServicePortMapping(map[string]string{"HolidayService2": "HolidayService2Soap"})
+
+	def, err := wsdlImp.ToAPIDefinition("testOrg", "http://test.com", false)
+	require.NoError(t, err)
+
+	validateXML := def.VersionData.Versions["1.0.0"].ExtendedPaths.ValidateXML
+	require.Len(t, validateXML, 6)
+
+	var meta *apidef.ValidateXMLMeta
+	for i := range validateXML {
+		if validateXML[i].Path == "HolidayService2/GetHolidaysForMonth" {
+			meta = &validateXML[i]
+		}
+	}
+	require.NotNil(t, meta)
+	assert.Equal(t, "POST", meta.Method)
+	assert.Equal(t, "GetHolidaysForMonth", meta.Element)
+
+	schema, err := xsd.Parse([]byte(meta.Schema))
+	require.NoError(t, err)
+
+	envelope := func(body string) []byte {
+		return []byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>` + body + `</soap:Body></soap:Envelope>`)
+	}
+
+	valid := `<GetHolidaysForMonth xmlns="http://www.holidaywebservice.com/HolidayService_v2/"><countryCode>Canada</countryCode><year>2024</year><month>7</month></GetHolidaysForMonth>`
+	assert.NoError(t, schema.Validate(envelope(valid), meta.Element))
+
+	invalid := `<GetHolidaysForMonth xmlns="http://www.holidaywebservice.com/HolidayService_v2/"><countryCode>France</countryCode><year>2024</year></GetHolidaysForMonth>`
+	assert.EqualError(t, schema.Validate(envelope(invalid), meta.Element),
+		`/GetHolidaysForMonth/countryCode: value "France" is not one of Canada, GreatBritain, IrelandNorthern, IrelandRepublicOf, Scotland, UnitedStates; `+
+			`/GetHolidaysForMonth: missing element month`)
+}
+
 var holidayService string = `
 <?xml version="1.0" encoding="UTF-8"?>
 <wsdl:definitions xmlns:tm="http://microsoft.com/wsdl/mime/textMatching/" xmlns:soapenc="http://schemas.xmlsoap.org/soap/encoding/" xmlns:mime="http://schemas.xmlsoap.org/wsdl/mime/" xmlns:tns="http://www.holidaywebservice.com/HolidayService_v2/" xmlns:soap="http://schemas.xmlsoap.org/wsdl/soap/" xmlns:s="http://www.w3.org/2001/XMLSchema" xmlns:soap12="http://schemas.xmlsoap.org/wsdl/soap12/" xmlns:http="http://schemas.xmlsoap.org/wsdl/http/" targetNamespace="http://www.holidaywebservice.com/HolidayService_v2/" xmlns:wsdl="http://schemas.xmlsoap.org/wsdl/">
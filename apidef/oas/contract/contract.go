@@ -0,0 +1,283 @@
+// Package contract generates contract tests from the operations of an OAS API definition and
+// validates the responses of the API against its documented responses.
+//
+// Each operation gets a request built from its examples, one per named request body example,
+// and optionally requests with values generated from the parameter and request body schemas.
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+
+	"github.com/TykTechnologies/tyk/apidef/oas"
+)
+
+// The kinds of cases.
+const (
+	KindExample = "example"
+	KindFuzz    = "fuzz"
+)
+
+// Options configures the generation of the cases.
+type Options struct {
+	// Fuzz is the number of requests generated from the schemas for each operation.
+	Fuzz int
+	// Seed seeds the generated values, the same seed generates the same requests.
+	Seed int64
+}
+
+// Case is a request to an operation of the API.
+type Case struct {
+	Name        string
+	Kind        string
+	OperationID string
+	Method      string
+	// Path is the path of the operation with its path parameters, relative to the listen path.
+	Path        string
+	Query       url.Values
+	Header      http.Header
+	ContentType string
+	Body        []byte
+
+	route      *routers.Route
+	pathParams map[string]string
+}
+
+// Generate returns the cases of the operations of the API, sorted by path and method.
+func Generate(api *oas.OAS, opts Options) []*Case {
+	if api.Paths == nil {
+		return nil
+	}
+
+	generator := oas.NewRequestExampleGenerator(opts.Seed)
+
+	paths := make([]string, 0, api.Paths.Len())
+	for path := range api.Paths.Map() {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var cases []*Case
+	for _, path := range paths {
+		item := api.Paths.Value(path)
+
+		methods := make([]string, 0)
+		for method := range item.Operations() {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			op := item.GetOperation(method)
+			route := &routers.Route{Spec: &api.T, Path: path, PathItem: item, Method: method, Operation: op}
+
+			operationID := op.OperationID
+			if operationID == "" {
+				operationID = method + " " + path
+			}
+
+			params := parameters(item, op)
+
+			for _, body := range exampleBodies(op) {
+				c := newCase(route, operationID, KindExample)
+				c.Name = operationID + "/" + body.name
+				for _, param := range params {
+					if value, ok := exampleValue(param); ok {
+						c.setParameter(param, value)
+					}
+				}
+				c.setBody(body.contentType, body.value)
+				cases = append(cases, c)
+			}
+
+			for i := 1; i <= opts.Fuzz; i++ {
+				c := newCase(route, operationID, KindFuzz)
+				c.Name = fmt.Sprintf("%s/fuzz-%d", operationID, i)
+				for _, param := range params {
+					if param.Required || generator.Intn(2) == 0 {
+						c.setParameter(param, generator.Generate(param.Schema))
+					}
+				}
+				if contentType, media := jsonMedia(op); media != nil {
+					c.setBody(contentType, generator.Generate(media.Schema))
+				}
+				cases = append(cases, c)
+			}
+		}
+	}
+
+	return cases
+}
+
+func newCase(route *routers.Route, operationID, kind string) *Case {
+	return &Case{
+		Kind:        kind,
+		OperationID: operationID,
+		Method:      route.Method,
+		Path:        route.Path,
+		Query:       url.Values{},
+		Header:      http.Header{},
+		route:       route,
+		pathParams:  map[string]string{},
+	}
+}
+
+// setParameter sets a parameter in the path, query or headers of the request.
+func (c *Case) setParameter(param *openapi3.Parameter, value interface{}) {
+	values := parameterValues(value)
+
+	switch param.In {
+	case openapi3.ParameterInPath:
+		joined := strings.Join(values, ",")
+		c.pathParams[param.Name] = joined
+		c.Path = strings.ReplaceAll(c.Path, "{"+param.Name+"}", url.PathEscape(joined))
+	case openapi3.ParameterInQuery:
+		if param.Explode != nil && !*param.Explode {
+			c.Query.Set(param.Name, strings.Join(values, ","))
+			return
+		}
+		c.Query[param.Name] = values
+	case openapi3.ParameterInHeader:
+		c.Header.Set(param.Name, strings.Join(values, ","))
+	case openapi3.ParameterInCookie:
+		c.Header.Add("Cookie", (&http.Cookie{Name: param.Name, Value: strings.Join(values, ",")}).String())
+	}
+}
+
+func (c *Case) setBody(contentType string, value interface{}) {
+	if contentType == "" {
+		return
+	}
+
+	c.ContentType = contentType
+	c.Body, _ = json.Marshal(value)
+}
+
+// parameterValues formats a parameter value, arrays have a value per item.
+func parameterValues(value interface{}) []string {
+	if items, ok := value.([]interface{}); ok {
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			values = append(values, fmt.Sprint(item))
+		}
+		return values
+	}
+
+	if value == nil {
+		return []string{""}
+	}
+
+	return []string{fmt.Sprint(value)}
+}
+
+// parameters returns the parameters of the operation and its path, the operation overrides the path.
+func parameters(item *openapi3.PathItem, op *openapi3.Operation) []*openapi3.Parameter {
+	var params []*openapi3.Parameter
+	seen := map[string]bool{}
+	for _, list := range []openapi3.Parameters{op.Parameters, item.Parameters} {
+		for _, ref := range list {
+			if ref == nil || ref.Value == nil || ref.Value.Schema == nil {
+				continue
+			}
+
+			key := ref.Value.In + ":" + ref.Value.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			params = append(params, ref.Value)
+		}
+	}
+
+	return params
+}
+
+// exampleValue returns the example of a parameter, or a value built from its schema.
+func exampleValue(param *openapi3.Parameter) (interface{}, bool) {
+	if param.Example != nil {
+		return param.Example, true
+	}
+
+	for _, name := range sortedKeys(param.Examples) {
+		if example := param.Examples[name]; example != nil && example.Value != nil {
+			return example.Value.Value, true
+		}
+	}
+
+	if !param.Required {
+		return nil, false
+	}
+
+	return oas.ExampleExtractor(param.Schema), true
+}
+
+type body struct {
+	name        string
+	contentType string
+	value       interface{}
+}
+
+// exampleBodies returns a body per named example of the JSON request body, the example of the
+// media type or a body built from its schema otherwise. Operations without a JSON request body
+// get a single case without body.
+func exampleBodies(op *openapi3.Operation) []body {
+	contentType, media := jsonMedia(op)
+	if media == nil {
+		return []body{{name: KindExample}}
+	}
+
+	if len(media.Examples) > 0 {
+		var bodies []body
+		for _, name := range sortedKeys(media.Examples) {
+			if example := media.Examples[name]; example != nil && example.Value != nil {
+				bodies = append(bodies, body{name: KindExample + "-" + name, contentType: contentType, value: example.Value.Value})
+			}
+		}
+		if len(bodies) > 0 {
+			return bodies
+		}
+	}
+
+	value := media.Example
+	if value == nil {
+		value = oas.ExampleExtractor(media.Schema)
+	}
+
+	return []body{{name: KindExample, contentType: contentType, value: value}}
+}
+
+// jsonMedia returns the JSON media type of the request body of the operation.
+func jsonMedia(op *openapi3.Operation) (string, *openapi3.MediaType) {
+	if op.RequestBody == nil || op.RequestBody.Value == nil {
+		return "", nil
+	}
+
+	content := op.RequestBody.Value.Content
+	if media := content.Get("application/json"); media != nil {
+		return "application/json", media
+	}
+
+	for _, contentType := range sortedKeys(content) {
+		if strings.Contains(contentType, "json") && content[contentType] != nil {
+			return contentType, content[contentType]
+		}
+	}
+
+	return "", nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,196 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef/oas"
+)
+
+const petsOAS = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Pets", "version": "1.0.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "listPets",
+        "parameters": [
+          {"name": "limit", "in": "query", "required": true, "schema": {"type": "integer", "minimum": 1, "maximum": 10}, "example": 5},
+          {"name": "tag", "in": "query", "schema": {"type": "string", "enum": ["cat", "dog"]}}
+        ],
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}}}
+        }
+      },
+      "post": {
+        "operationId": "createPet",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {
+            "schema": {"$ref": "#/components/schemas/Pet"},
+            "examples": {
+              "cat": {"value": {"name": "Tom", "tag": "cat"}},
+              "dog": {"value": {"name": "Rex", "tag": "dog"}}
+            }
+          }}
+        },
+        "responses": {
+          "201": {"description": "Created", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}
+        }
+      }
+    },
+    "/pets/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string", "format": "uuid"}}],
+      "get": {
+        "operationId": "getPet",
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "required": ["name"],
+        "properties": {
+          "id": {"type": "integer", "readOnly": true},
+          "name": {"type": "string", "minLength": 1, "maxLength": 20},
+          "tag": {"type": "string", "enum": ["cat", "dog"]},
+          "born": {"type": "string", "format": "date"},
+          "weight": {"type": "number", "minimum": 0, "exclusiveMinimum": true, "maximum": 50}
+        }
+      }
+    }
+  }
+}`
+
+func petsAPI(t *testing.T) *oas.OAS {
+	t.Helper()
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(petsOAS))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+
+	return &oas.OAS{T: *doc}
+}
+
+func TestGenerate(t *testing.T) {
+	api := petsAPI(t)
+	cases := Generate(api, Options{})
+
+	names := make([]string, 0, len(cases))
+	for _, c := range cases {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"listPets/example", "createPet/example-cat", "createPet/example-dog", "getPet/example"}, names)
+
+	assert.Equal(t, http.MethodGet, cases[0].Method)
+	assert.Equal(t, "/pets", cases[0].Path)
+	assert.Equal(t, "5", cases[0].Query.Get("limit"))
+	assert.False(t, cases[0].Query.Has("tag"), "optional parameters without examples aren't set")
+	assert.Nil(t, cases[0].Body)
+
+	assert.Equal(t, "application/json", cases[1].ContentType)
+	assert.JSONEq(t, `{"name": "Tom", "tag": "cat"}`, string(cases[1].Body))
+
+	assert.Equal(t, "/pets/string", cases[3].Path, "required parameters without examples are built from their schema")
+}
+
+func TestGenerate_Fuzz(t *testing.T) {
+	api := petsAPI(t)
+
+	cases := Generate(api, Options{Fuzz: 20, Seed: 1})
+	require.Len(t, cases, 4+3*20)
+	assert.Equal(t, cases, Generate(api, Options{Fuzz: 20, Seed: 1}), "the same seed generates the same cases")
+
+	for _, c := range cases {
+		if c.Kind != KindFuzz {
+			continue
+		}
+
+		req := httptest.NewRequest(c.Method, c.Path+"?"+c.Query.Encode(), bytes.NewReader(c.Body))
+		req.Header = c.Header.Clone()
+		if c.ContentType != "" {
+			req.Header.Set("Content-Type", c.ContentType)
+		}
+
+		err := openapi3filter.ValidateRequest(context.Background(), &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: c.pathParams,
+			Route:      c.route,
+		})
+		assert.NoError(t, err, "%s: %s", c.Name, c.Body)
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	api := petsAPI(t)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			var pet map[string]interface{}
+			_ = json.Unmarshal(body, &pet)
+			if pet["tag"] == "dog" {
+				// wrong status
+				w.WriteHeader(http.StatusOK)
+			} else {
+				w.WriteHeader(http.StatusCreated)
+			}
+			_, _ = w.Write(body)
+		case strings.HasPrefix(r.URL.Path, "/api/pets/"):
+			// name is required
+			_, _ = w.Write([]byte(`{"tag": "cat"}`))
+		default:
+			_, _ = w.Write([]byte(`[{"id": 1, "name": "Tom"}]`))
+		}
+	}))
+	defer upstream.Close()
+
+	runner := &Runner{BaseURL: upstream.URL + "/api/", Header: http.Header{"Authorization": {"secret"}}}
+	report := runner.Run(context.Background(), "Pets", Generate(api, Options{}))
+
+	require.Len(t, report.Results, 4)
+	assert.Equal(t, 2, report.Failures())
+
+	assert.NoError(t, report.Results[0].Err)
+	assert.Equal(t, http.StatusOK, report.Results[0].Status)
+	assert.NoError(t, report.Results[1].Err)
+	assert.Error(t, report.Results[2].Err, "undocumented status")
+	assert.Error(t, report.Results[3].Err, "invalid body")
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJUnit(&buf))
+	assert.True(t, strings.HasPrefix(buf.String(), xml.Header))
+
+	var junit JUnitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &junit))
+	assert.Equal(t, 4, junit.Tests)
+	assert.Equal(t, 2, junit.Failures)
+	require.Len(t, junit.Suites, 1)
+	assert.Equal(t, "Pets", junit.Suites[0].Name)
+	assert.Equal(t, "Pets.createPet", junit.Suites[0].Cases[2].Classname)
+	assert.Equal(t, "createPet/example-dog", junit.Suites[0].Cases[2].Name)
+	require.NotNil(t, junit.Suites[0].Cases[2].Failure)
+	assert.Equal(t, KindExample, junit.Suites[0].Cases[2].Failure.Type)
+	assert.Nil(t, junit.Suites[0].Cases[0].Failure)
+}
@@ -0,0 +1,91 @@
+package contract
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// JUnitTestSuites is the root of a JUnit XML report.
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite is the test suite of an API.
+type JUnitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is a case, classified by operation.
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+}
+
+// JUnitFailure is the failure of a case.
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnit returns the report in the JUnit format.
+func (r *Report) JUnit() *JUnitTestSuites {
+	suite := JUnitTestSuite{
+		Name:     r.Name,
+		Tests:    len(r.Results),
+		Failures: r.Failures(),
+		Time:     seconds(r.Duration),
+	}
+
+	for _, result := range r.Results {
+		tc := JUnitTestCase{
+			Name:      result.Case.Name,
+			Classname: r.Name + "." + result.Case.OperationID,
+			Time:      seconds(result.Duration),
+		}
+
+		if result.Err != nil {
+			tc.Failure = &JUnitFailure{
+				Message: result.Err.Error(),
+				Type:    result.Case.Kind,
+				Text:    fmt.Sprintf("%s %s returned %d: %v", result.Case.Method, result.Case.Path, result.Status, result.Err),
+			}
+		}
+
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	return &JUnitTestSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Time:     suite.Time,
+		Suites:   []JUnitTestSuite{suite},
+	}
+}
+
+// WriteJUnit writes the report in the JUnit XML format.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	data, err := xml.MarshalIndent(r.JUnit(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, data)
+	return err
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,146 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// Result is the result of a case.
+type Result struct {
+	Case     *Case
+	Status   int
+	Duration time.Duration
+	// Err is the error sending the request, or the violation of the documented responses.
+	Err error
+}
+
+// Report is the result of a run.
+type Report struct {
+	// Name is the name of the API.
+	Name     string
+	Results  []Result
+	Duration time.Duration
+}
+
+// Failures returns the number of failed cases.
+func (r *Report) Failures() int {
+	failures := 0
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failures++
+		}
+	}
+	return failures
+}
+
+// Runner sends the cases to the API and validates the responses against its OAS.
+type Runner struct {
+	// BaseURL is the URL of the API, with its listen path.
+	BaseURL string
+	// Header is added to every request, e.g. the credentials of the API.
+	Header http.Header
+	Client *http.Client
+}
+
+// Run runs the cases in order.
+func (r *Runner) Run(ctx context.Context, name string, cases []*Case) *Report {
+	report := &Report{Name: name}
+	start := time.Now()
+
+	for _, c := range cases {
+		report.Results = append(report.Results, r.run(ctx, c))
+	}
+
+	report.Duration = time.Since(start)
+	return report
+}
+
+func (r *Runner) run(ctx context.Context, c *Case) Result {
+	start := time.Now()
+	status, err := r.do(ctx, c)
+	return Result{Case: c, Status: status, Duration: time.Since(start), Err: err}
+}
+
+func (r *Runner) do(ctx context.Context, c *Case) (int, error) {
+	req, err := r.request(ctx, c)
+	if err != nil {
+		return 0, err
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+
+	return resp.StatusCode, Validate(ctx, c, req, resp, body)
+}
+
+func (r *Runner) request(ctx context.Context, c *Case) (*http.Request, error) {
+	target := strings.TrimSuffix(r.BaseURL, "/") + c.Path
+	if len(c.Query) > 0 {
+		target += "?" + c.Query.Encode()
+	}
+
+	var body io.Reader
+	if c.Body != nil {
+		body = bytes.NewReader(c.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, c.Method, target, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range r.Header {
+		req.Header[name] = values
+	}
+	for name, values := range c.Header {
+		req.Header[name] = values
+	}
+	if c.ContentType != "" {
+		req.Header.Set("Content-Type", c.ContentType)
+	}
+
+	return req, nil
+}
+
+// Validate validates a response of a case against the documented responses of its operation.
+func Validate(ctx context.Context, c *Case, req *http.Request, resp *http.Response, body []byte) error {
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: c.pathParams,
+			Route:      c.route,
+		},
+		Status: resp.StatusCode,
+		Header: resp.Header,
+		Body:   io.NopCloser(bytes.NewReader(body)),
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+		},
+	}
+
+	if err := openapi3filter.ValidateResponse(ctx, input); err != nil {
+		return fmt.Errorf("response %d doesn't match the contract: %w", resp.StatusCode, err)
+	}
+
+	return nil
+}
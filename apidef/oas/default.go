@@ -81,6 +81,7 @@ func (s *OAS) BuildDefaultTykExtension(overRideValues TykExtensionConfigParams,
 		xTykAPIGateway.Server.ListenPath.Strip = true
 		xTykAPIGateway.enableContextVariablesIfEmpty()
 		xTykAPIGateway.enableTrafficLogsIfEmpty()
+		s.importGRPCDescriptor(xTykAPIGateway)
 	}
 
 	if xTykAPIGateway.Info.Name == "" {
@@ -15,9 +15,10 @@ type Kind = event.Kind
 
 // WebhookKind is an alias maintained to be used in imports.
 const (
-	WebhookKind = event.WebhookKind
-	JSVMKind    = event.JSVMKind
-	LogKind     = event.LogKind
+	WebhookKind     = event.WebhookKind
+	JSVMKind        = event.JSVMKind
+	LogKind         = event.LogKind
+	CloudEventsKind = event.CloudEventsKind
 )
 
 // EventHandler holds information about individual event to be configured on the API.
@@ -51,6 +52,9 @@ type EventHandler struct {
 
 	// LogEvent represents the configuration for logging events tied to an event handler.
 	LogEvent LogEvent `bson:"-" json:"-"`
+
+	// CloudEvents holds the configuration for delivering events as CloudEvents to a message broker.
+	CloudEvents CloudEventsEvent `bson:"-" json:"-"`
 }
 
 // MarshalJSON marshals EventHandler as per Tyk OAS API definition contract.
@@ -84,6 +88,12 @@ func (e EventHandler) MarshalJSON() ([]byte, error) {
 			return nil, err
 		}
 		maps.Insert(outMapVal, maps.All(*logMap))
+	case CloudEventsKind:
+		ceMap, err := reflect.Cast[map[string]any](helper.CloudEvents)
+		if err != nil {
+			return nil, err
+		}
+		maps.Insert(outMapVal, maps.All(*ceMap))
 	}
 
 	return json.Marshal(outMapVal)
@@ -110,6 +120,10 @@ func (e *EventHandler) UnmarshalJSON(in []byte) error {
 		if err := json.Unmarshal(in, &helper.LogEvent); err != nil {
 			return err
 		}
+	case CloudEventsKind:
+		if err := json.Unmarshal(in, &helper.CloudEvents); err != nil {
+			return err
+		}
 	}
 
 	*e = EventHandler(helper)
@@ -153,6 +167,20 @@ type WebhookEvent struct {
 	//
 	// Tyk classic API definition: `event_handlers.events[].handler_meta.header_map`.
 	Headers Headers `json:"headers,omitempty" bson:"headers,omitempty"`
+	// SigningSecret is the secret used to sign deliveries per the Standard Webhooks specification.
+	// Secrets prefixed with `whsec_` are base64 decoded.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.signing_secret`.
+	SigningSecret string `json:"signingSecret,omitempty" bson:"signingSecret,omitempty"`
+	// MaxRetries is the number of retries for a failed delivery. When set, deliveries are
+	// persisted to the outbox and retried with exponential backoff.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.max_retries`.
+	MaxRetries int `json:"maxRetries,omitempty" bson:"maxRetries,omitempty"`
+	// RetryInterval is the delay before the first retry, doubled on each retry. It uses shorthand notation.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.retry_interval`.
+	RetryInterval ReadableDuration `json:"retryInterval,omitempty" bson:"retryInterval,omitempty"`
 }
 
 // GetWebhookConf converts EventHandler.WebhookEvent apidef.WebHookHandlerConf.
@@ -166,6 +194,10 @@ func (e *EventHandler) GetWebhookConf() apidef.WebHookHandlerConf {
 		HeaderList:   e.Webhook.Headers.Map(),
 		EventTimeout: int64(e.Webhook.CoolDownPeriod.Seconds()),
 		TemplatePath: e.Webhook.BodyTemplate,
+
+		SigningSecret: e.Webhook.SigningSecret,
+		MaxRetries:    e.Webhook.MaxRetries,
+		RetryInterval: int64(e.Webhook.RetryInterval.Seconds()),
 	}
 }
 
@@ -207,10 +239,173 @@ func (e *EventHandler) GetLogEventHandlerConf() apidef.LogEventHandlerConf {
 	}
 }
 
+// CloudEventsEvent represents the configuration for delivering events as CloudEvents 1.0
+// structured JSON envelopes to a message broker.
+type CloudEventsEvent struct {
+	// Source is the CloudEvents `source` attribute, defaults to `/tyk/gateway`.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.source`.
+	Source string `json:"source,omitempty" bson:"source,omitempty"`
+	// Transport is the protocol binding used for delivery, one of `kafka`, `nats`, `amqp` or `http`.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.transport`.
+	Transport string `json:"transport" bson:"transport"`
+	// Kafka holds the Kafka transport configuration.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.kafka`.
+	Kafka *CloudEventsKafka `json:"kafka,omitempty" bson:"kafka,omitempty"`
+	// NATS holds the NATS transport configuration.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.nats`.
+	NATS *CloudEventsNATS `json:"nats,omitempty" bson:"nats,omitempty"`
+	// AMQP holds the AMQP 0.9.1 transport configuration.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.amqp`.
+	AMQP *CloudEventsAMQP `json:"amqp,omitempty" bson:"amqp,omitempty"`
+	// HTTP holds the HTTP transport configuration.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.http`.
+	HTTP *CloudEventsHTTP `json:"http,omitempty" bson:"http,omitempty"`
+	// QueueSize is the maximum number of events waiting for delivery, defaults to 1000.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.queue_size`.
+	QueueSize int `json:"queueSize,omitempty" bson:"queueSize,omitempty"`
+	// MaxRetries is the number of delivery retries before an event is dead-lettered, defaults to 3.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.max_retries`.
+	MaxRetries int `json:"maxRetries,omitempty" bson:"maxRetries,omitempty"`
+	// RetryInterval is the delay before the first retry, doubled on each retry. It uses shorthand notation.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.retry_interval`.
+	RetryInterval ReadableDuration `json:"retryInterval,omitempty" bson:"retryInterval,omitempty"`
+	// DeadLetterPath is the file undeliverable events are appended to, as newline-delimited JSON.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.dead_letter_path`.
+	DeadLetterPath string `json:"deadLetterPath,omitempty" bson:"deadLetterPath,omitempty"`
+}
+
+// CloudEventsKafka configures the Kafka binding of a CloudEvents event handler.
+type CloudEventsKafka struct {
+	// Brokers is the list of Kafka broker addresses.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.kafka.brokers`.
+	Brokers []string `json:"brokers" bson:"brokers"`
+	// Topic is the topic events are produced to.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.kafka.topic`.
+	Topic string `json:"topic" bson:"topic"`
+}
+
+// CloudEventsNATS configures the NATS binding of a CloudEvents event handler.
+type CloudEventsNATS struct {
+	// URL is the NATS server URL.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.nats.url`.
+	URL string `json:"url" bson:"url"`
+	// Subject is the subject events are published to.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.nats.subject`.
+	Subject string `json:"subject" bson:"subject"`
+}
+
+// CloudEventsAMQP configures the AMQP 0.9.1 binding of a CloudEvents event handler.
+type CloudEventsAMQP struct {
+	// URL is the AMQP broker URL.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.amqp.url`.
+	URL string `json:"url" bson:"url"`
+	// Exchange is the exchange events are published to.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.amqp.exchange`.
+	Exchange string `json:"exchange,omitempty" bson:"exchange,omitempty"`
+	// RoutingKey is the routing key events are published with.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.amqp.routing_key`.
+	RoutingKey string `json:"routingKey,omitempty" bson:"routingKey,omitempty"`
+}
+
+// CloudEventsHTTP configures the HTTP binding of a CloudEvents event handler.
+type CloudEventsHTTP struct {
+	// URL is the endpoint events are POSTed to.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.http.url`.
+	URL string `json:"url" bson:"url"`
+	// Headers are additional headers to set on the request.
+	//
+	// Tyk classic API definition: `event_handlers.events[].handler_meta.http.headers`.
+	Headers Headers `json:"headers,omitempty" bson:"headers,omitempty"`
+}
+
+// GetCloudEventsHandlerConf creates and returns a CloudEventsHandlerConf based on the current EventHandler configuration.
+func (e *EventHandler) GetCloudEventsHandlerConf() apidef.CloudEventsHandlerConf {
+	conf := apidef.CloudEventsHandlerConf{
+		Disabled:       !e.Enabled,
+		ID:             e.ID,
+		Name:           e.Name,
+		Source:         e.CloudEvents.Source,
+		Transport:      e.CloudEvents.Transport,
+		QueueSize:      e.CloudEvents.QueueSize,
+		MaxRetries:     e.CloudEvents.MaxRetries,
+		RetryInterval:  int64(e.CloudEvents.RetryInterval.Seconds()),
+		DeadLetterPath: e.CloudEvents.DeadLetterPath,
+	}
+
+	if kafka := e.CloudEvents.Kafka; kafka != nil {
+		conf.Kafka = apidef.CloudEventsKafkaConf{Brokers: kafka.Brokers, Topic: kafka.Topic}
+	}
+
+	if nats := e.CloudEvents.NATS; nats != nil {
+		conf.NATS = apidef.CloudEventsNATSConf{URL: nats.URL, Subject: nats.Subject}
+	}
+
+	if amqp := e.CloudEvents.AMQP; amqp != nil {
+		conf.AMQP = apidef.CloudEventsAMQPConf{URL: amqp.URL, Exchange: amqp.Exchange, RoutingKey: amqp.RoutingKey}
+	}
+
+	if httpConf := e.CloudEvents.HTTP; httpConf != nil {
+		conf.HTTP = apidef.CloudEventsHTTPConf{URL: httpConf.URL, Headers: httpConf.Headers.Map()}
+	}
+
+	return conf
+}
+
+// newCloudEventsEvent converts a classic CloudEventsHandlerConf to CloudEventsEvent.
+func newCloudEventsEvent(conf apidef.CloudEventsHandlerConf) CloudEventsEvent {
+	ce := CloudEventsEvent{
+		Source:         conf.Source,
+		Transport:      conf.Transport,
+		QueueSize:      conf.QueueSize,
+		MaxRetries:     conf.MaxRetries,
+		RetryInterval:  ReadableDuration(time.Duration(conf.RetryInterval) * time.Second),
+		DeadLetterPath: conf.DeadLetterPath,
+	}
+
+	if len(conf.Kafka.Brokers) > 0 || conf.Kafka.Topic != "" {
+		ce.Kafka = &CloudEventsKafka{Brokers: conf.Kafka.Brokers, Topic: conf.Kafka.Topic}
+	}
+
+	if conf.NATS.URL != "" || conf.NATS.Subject != "" {
+		ce.NATS = &CloudEventsNATS{URL: conf.NATS.URL, Subject: conf.NATS.Subject}
+	}
+
+	if conf.AMQP.URL != "" || conf.AMQP.Exchange != "" || conf.AMQP.RoutingKey != "" {
+		ce.AMQP = &CloudEventsAMQP{URL: conf.AMQP.URL, Exchange: conf.AMQP.Exchange, RoutingKey: conf.AMQP.RoutingKey}
+	}
+
+	if conf.HTTP.URL != "" || len(conf.HTTP.Headers) > 0 {
+		ce.HTTP = &CloudEventsHTTP{URL: conf.HTTP.URL}
+		if len(conf.HTTP.Headers) > 0 {
+			ce.HTTP.Headers = NewHeaders(conf.HTTP.Headers)
+		}
+	}
+
+	return ce
+}
+
 // EventHandlers holds the list of events to be processed for the API.
 type EventHandlers []EventHandler
 
-// Fill fills EventHandlers from classic API definition. Currently only webhook and jsvm events are supported.
+// Fill fills EventHandlers from classic API definition. Currently webhook, jsvm, log and cloudevents events are supported.
 func (e *EventHandlers) Fill(api apidef.APIDefinition) {
 	events := EventHandlers{}
 	if len(api.EventHandlers.Events) == 0 {
@@ -240,6 +435,9 @@ func (e *EventHandlers) Fill(api apidef.APIDefinition) {
 						Headers:        NewHeaders(whConf.HeaderList),
 						BodyTemplate:   whConf.TemplatePath,
 						CoolDownPeriod: ReadableDuration(time.Duration(whConf.EventTimeout) * time.Second),
+						SigningSecret:  whConf.SigningSecret,
+						MaxRetries:     whConf.MaxRetries,
+						RetryInterval:  ReadableDuration(time.Duration(whConf.RetryInterval) * time.Second),
 					},
 				}
 
@@ -281,6 +479,23 @@ func (e *EventHandlers) Fill(api apidef.APIDefinition) {
 					},
 				}
 
+				events = append(events, ev)
+			case event.CloudEventsHandler:
+				ceConf := apidef.CloudEventsHandlerConf{}
+				err := ceConf.Scan(eh.HandlerMeta)
+				if err != nil {
+					continue
+				}
+
+				ev := EventHandler{
+					Enabled:     !ceConf.Disabled,
+					Trigger:     gwEvent,
+					Kind:        CloudEventsKind,
+					ID:          ceConf.ID,
+					Name:        ceConf.Name,
+					CloudEvents: newCloudEventsEvent(ceConf),
+				}
+
 				events = append(events, ev)
 			default:
 				continue
@@ -327,6 +542,10 @@ func (e *EventHandlers) ExtractTo(api *apidef.APIDefinition) {
 			handler = event.LogHandler
 			logConf := ev.GetLogEventHandlerConf()
 			handlerMeta, err = reflect.Cast[map[string]any](logConf)
+		case CloudEventsKind:
+			handler = event.CloudEventsHandler
+			ceConf := ev.GetCloudEventsHandlerConf()
+			handlerMeta, err = reflect.Cast[map[string]any](ceConf)
 		default:
 			continue
 		}
@@ -358,7 +577,7 @@ func resetOASSupportedEventHandlers(api *apidef.APIDefinition) {
 		triggersExcludingWebhooks := make([]apidef.EventHandlerTriggerConfig, 0)
 		for _, eventTrigger := range eventTriggers {
 			switch eventTrigger.Handler {
-			case event.WebHookHandler, event.JSVMHandler, event.LogHandler:
+			case event.WebHookHandler, event.JSVMHandler, event.LogHandler, event.CloudEventsHandler:
 				continue
 			}
 
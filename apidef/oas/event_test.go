@@ -88,6 +88,48 @@ func TestEventHandlers(t *testing.T) {
 					},
 				},
 			},
+			{
+				title: "webhook with retries and signing",
+				input: EventHandlers{
+					{
+						Enabled: true,
+						Trigger: event.QuotaExceeded,
+						Kind:    event.WebhookKind,
+						ID:      "random-id",
+						Name:    "test-webhook",
+						Webhook: WebhookEvent{
+							URL:            "https://webhook.site/uuid",
+							CoolDownPeriod: ReadableDuration(time.Second * 20),
+							Method:         http.MethodPost,
+							SigningSecret:  "whsec_c2VjcmV0",
+							MaxRetries:     5,
+							RetryInterval:  ReadableDuration(time.Second * 30),
+						},
+					},
+				},
+				expected: apidef.EventHandlerMetaConfig{
+					Events: map[event.Event][]apidef.EventHandlerTriggerConfig{
+						event.QuotaExceeded: {
+							{
+								Handler: event.WebHookHandler,
+								HandlerMeta: map[string]interface{}{
+									"disabled":       false,
+									"method":         "POST",
+									"template_path":  "",
+									"header_map":     map[string]interface{}{},
+									"target_path":    "https://webhook.site/uuid",
+									"event_timeout":  float64(20),
+									"id":             "random-id",
+									"name":           "test-webhook",
+									"signing_secret": "whsec_c2VjcmV0",
+									"max_retries":    float64(5),
+									"retry_interval": float64(30),
+								},
+							},
+						},
+					},
+				},
+			},
 			{
 				title: "jsvm events",
 				input: EventHandlers{
@@ -623,6 +665,39 @@ func TestEventHandler_UnmarshalJSON(t *testing.T) {
 				},
 			},
 		},
+		{
+			title: "should unmarshal cloudevents event handler",
+			input: map[string]any{
+				"id":        "random-id",
+				"enabled":   true,
+				"trigger":   "HostDown",
+				"type":      "cloudevents",
+				"name":      "incidents",
+				"transport": "kafka",
+				"kafka": map[string]any{
+					"brokers": []string{"localhost:9092"},
+					"topic":   "tyk-events",
+				},
+				"maxRetries":    5,
+				"retryInterval": "2s",
+			},
+			expected: EventHandler{
+				Enabled: true,
+				Trigger: event.HostDown,
+				Kind:    event.CloudEventsKind,
+				ID:      "random-id",
+				Name:    "incidents",
+				CloudEvents: CloudEventsEvent{
+					Transport: "kafka",
+					Kafka: &CloudEventsKafka{
+						Brokers: []string{"localhost:9092"},
+						Topic:   "tyk-events",
+					},
+					MaxRetries:    5,
+					RetryInterval: ReadableDuration(2 * time.Second),
+				},
+			},
+		},
 		{
 			title: "should unmarshal log event handler",
 			input: map[string]any{
@@ -660,3 +735,80 @@ func TestEventHandler_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestCloudEventsEventHandler(t *testing.T) {
+	t.Parallel()
+
+	handlers := EventHandlers{
+		{
+			Enabled: true,
+			Trigger: event.HostDown,
+			Kind:    event.CloudEventsKind,
+			ID:      "kafka-id",
+			Name:    "incidents",
+			CloudEvents: CloudEventsEvent{
+				Source:    "/tyk/prod",
+				Transport: "kafka",
+				Kafka: &CloudEventsKafka{
+					Brokers: []string{"broker-1:9092", "broker-2:9092"},
+					Topic:   "tyk-events",
+				},
+				QueueSize:      100,
+				MaxRetries:     5,
+				RetryInterval:  ReadableDuration(2 * time.Second),
+				DeadLetterPath: "/var/log/tyk/dead-letter.jsonl",
+			},
+		},
+		{
+			Enabled: false,
+			Trigger: event.HostUp,
+			Kind:    event.CloudEventsKind,
+			Name:    "http-sink",
+			CloudEvents: CloudEventsEvent{
+				Transport: "http",
+				HTTP: &CloudEventsHTTP{
+					URL:     "https://events.example.com/ingest",
+					Headers: Headers{{Name: "Authorization", Value: "Bearer token"}},
+				},
+			},
+		},
+	}
+
+	server := new(Server)
+	server.EventHandlers = handlers
+
+	var apiDef apidef.APIDefinition
+	server.ExtractTo(&apiDef)
+
+	assert.Len(t, apiDef.EventHandlers.Events[event.HostDown], 1)
+	assert.Equal(t, event.CloudEventsHandler, apiDef.EventHandlers.Events[event.HostDown][0].Handler)
+
+	var conf apidef.CloudEventsHandlerConf
+	assert.NoError(t, conf.Scan(apiDef.EventHandlers.Events[event.HostDown][0].HandlerMeta))
+	assert.Equal(t, apidef.CloudEventsHandlerConf{
+		ID:             "kafka-id",
+		Name:           "incidents",
+		Source:         "/tyk/prod",
+		Transport:      "kafka",
+		Kafka:          apidef.CloudEventsKafkaConf{Brokers: []string{"broker-1:9092", "broker-2:9092"}, Topic: "tyk-events"},
+		QueueSize:      100,
+		MaxRetries:     5,
+		RetryInterval:  2,
+		DeadLetterPath: "/var/log/tyk/dead-letter.jsonl",
+	}, conf)
+
+	var filled Server
+	filled.Fill(apiDef)
+	assert.ElementsMatch(t, handlers, filled.EventHandlers)
+
+	t.Run("marshal", func(t *testing.T) {
+		data, err := json.Marshal(handlers[0])
+		assert.NoError(t, err)
+
+		var out map[string]any
+		assert.NoError(t, json.Unmarshal(data, &out))
+		assert.Equal(t, "cloudevents", out["type"])
+		assert.Equal(t, "kafka", out["transport"])
+		assert.Equal(t, "2s", out["retryInterval"])
+	})
+}
@@ -0,0 +1,222 @@
+package oas
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// exampleMaxDepth is the depth of the nested objects and arrays after which only required properties
+// and the minimum number of items are generated.
+const exampleMaxDepth = 4
+
+const exampleLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// ExampleGenerator generates random values valid against a schema, honouring its formats, enums,
+// bounds and array sizes. Unlike ExampleExtractor, the examples of the schemas aren't used.
+type ExampleGenerator struct {
+	rand *rand.Rand
+	// request skips the read-only properties instead of the write-only properties.
+	request bool
+}
+
+// NewExampleGenerator returns a generator of response bodies, the same seed generates the same values.
+func NewExampleGenerator(seed int64) *ExampleGenerator {
+	return &ExampleGenerator{rand: rand.New(rand.NewSource(seed))}
+}
+
+// NewRequestExampleGenerator returns a generator of request bodies and parameters.
+func NewRequestExampleGenerator(seed int64) *ExampleGenerator {
+	return &ExampleGenerator{rand: rand.New(rand.NewSource(seed)), request: true}
+}
+
+// Generate returns a random value valid against the schema.
+func (g *ExampleGenerator) Generate(schema *openapi3.SchemaRef) interface{} {
+	return g.value(schema, 0)
+}
+
+// Intn returns a random number in [0,n).
+func (g *ExampleGenerator) Intn(n int) int {
+	return g.rand.Intn(n)
+}
+
+func (g *ExampleGenerator) value(ref *openapi3.SchemaRef, depth int) interface{} {
+	if ref == nil || ref.Value == nil {
+		return nil
+	}
+
+	schema := ref.Value
+
+	if len(schema.Enum) > 0 {
+		return schema.Enum[g.rand.Intn(len(schema.Enum))]
+	}
+
+	switch {
+	case len(schema.AllOf) > 0:
+		obj := map[string]interface{}{}
+		for _, sub := range schema.AllOf {
+			if m, ok := g.value(sub, depth).(map[string]interface{}); ok {
+				for k, v := range m {
+					obj[k] = v
+				}
+			}
+		}
+		return obj
+	case len(schema.OneOf) > 0:
+		return g.value(schema.OneOf[g.rand.Intn(len(schema.OneOf))], depth)
+	case len(schema.AnyOf) > 0:
+		return g.value(schema.AnyOf[g.rand.Intn(len(schema.AnyOf))], depth)
+	}
+
+	switch {
+	case schema.Type.Is(openapi3.TypeObject) || (schema.Type == nil && len(schema.Properties) > 0):
+		return g.object(schema, depth)
+	case schema.Type.Is(openapi3.TypeArray):
+		return g.array(schema, depth)
+	case schema.Type.Is(openapi3.TypeString):
+		return g.string(schema)
+	case schema.Type.Is(openapi3.TypeInteger):
+		return int64(math.Round(g.number(schema, 1)))
+	case schema.Type.Is(openapi3.TypeNumber):
+		return g.number(schema, 0)
+	case schema.Type.Is(openapi3.TypeBoolean):
+		return g.rand.Intn(2) == 0
+	default:
+		return nil
+	}
+}
+
+func (g *ExampleGenerator) object(schema *openapi3.Schema, depth int) map[string]interface{} {
+	required := map[string]bool{}
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	obj := map[string]interface{}{}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop := schema.Properties[name]
+		if prop == nil || prop.Value == nil || (g.request && prop.Value.ReadOnly) || (!g.request && prop.Value.WriteOnly) {
+			continue
+		}
+
+		if required[name] || (depth < exampleMaxDepth && g.rand.Intn(2) == 0) {
+			obj[name] = g.value(prop, depth+1)
+		}
+	}
+
+	return obj
+}
+
+func (g *ExampleGenerator) array(schema *openapi3.Schema, depth int) []interface{} {
+	n := int(schema.MinItems)
+	if depth < exampleMaxDepth {
+		max := n + 3
+		if schema.MaxItems != nil && int(*schema.MaxItems) < max {
+			max = int(*schema.MaxItems)
+		}
+		// a maxItems below minItems can't be satisfied, the minimum is generated
+		if max > n {
+			n += g.rand.Intn(max - n + 1)
+		}
+	}
+
+	items := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		items = append(items, g.value(schema.Items, depth+1))
+	}
+
+	return items
+}
+
+// number returns a number in the bounds of the schema, step is 1 for integers.
+func (g *ExampleGenerator) number(schema *openapi3.Schema, step float64) float64 {
+	min, max := 0.0, 1000.0
+	if schema.Min != nil {
+		min = *schema.Min
+		if schema.ExclusiveMin {
+			min += math.Max(step, 0.001)
+		}
+		if schema.Max == nil {
+			max = min + 1000
+		}
+	}
+	if schema.Max != nil {
+		max = *schema.Max
+		if schema.ExclusiveMax {
+			max -= math.Max(step, 0.001)
+		}
+		if schema.Min == nil {
+			min = math.Min(0, max-1000)
+		}
+	}
+
+	if step > 0 {
+		min, max = math.Ceil(min), math.Floor(max)
+	}
+
+	if max <= min {
+		return min
+	}
+
+	return min + g.rand.Float64()*(max-min)
+}
+
+func (g *ExampleGenerator) string(schema *openapi3.Schema) string {
+	switch schema.Format {
+	case "date-time":
+		return g.time().Format(time.RFC3339)
+	case "date":
+		return g.time().Format(time.DateOnly)
+	case "uuid":
+		b := make([]byte, 16)
+		g.rand.Read(b)
+		b[6], b[8] = b[6]&0x0f|0x40, b[8]&0x3f|0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	case "email":
+		return g.letters(8) + "@example.com"
+	case "uri", "url":
+		return "https://example.com/" + g.letters(8)
+	case "ipv4":
+		return fmt.Sprintf("10.%d.%d.%d", g.rand.Intn(256), g.rand.Intn(256), 1+g.rand.Intn(254))
+	}
+
+	// Random strings can't be generated from a pattern, the example is valid against it.
+	if schema.Pattern != "" {
+		if example, ok := schema.Example.(string); ok {
+			return example
+		}
+	}
+
+	n := int(schema.MinLength)
+	max := n + 16
+	if schema.MaxLength != nil && int(*schema.MaxLength) < max {
+		max = int(*schema.MaxLength)
+	}
+	if max > n {
+		n += g.rand.Intn(max-n) + 1
+	}
+
+	return g.letters(n)
+}
+
+func (g *ExampleGenerator) letters(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = exampleLetters[g.rand.Intn(len(exampleLetters))]
+	}
+	return string(b)
+}
+
+func (g *ExampleGenerator) time() time.Time {
+	return time.Date(2000+g.rand.Intn(30), time.Month(1+g.rand.Intn(12)), 1+g.rand.Intn(28), g.rand.Intn(24), g.rand.Intn(60), g.rand.Intn(60), 0, time.UTC)
+}
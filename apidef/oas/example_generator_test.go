@@ -0,0 +1,57 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExampleGenerator_Generate(t *testing.T) {
+	schema := &openapi3.SchemaRef{}
+	require.NoError(t, schema.UnmarshalJSON([]byte(`{
+		"type": "object",
+		"required": ["id", "email", "tags", "createdAt", "uuid", "ratio", "status"],
+		"properties": {
+			"id": {"type": "integer", "minimum": 1, "maximum": 5},
+			"email": {"type": "string", "format": "email"},
+			"tags": {"type": "array", "minItems": 1, "maxItems": 2, "items": {"type": "string", "minLength": 3, "maxLength": 4}},
+			"createdAt": {"type": "string", "format": "date-time"},
+			"uuid": {"type": "string", "format": "uuid"},
+			"ratio": {"type": "number", "exclusiveMinimum": true, "minimum": 0, "maximum": 1},
+			"status": {"type": "string", "enum": ["available", "sold"]},
+			"password": {"type": "string", "writeOnly": true},
+			"owner": {"type": "string", "readOnly": true}
+		}
+	}`)))
+
+	t.Run("valid values", func(t *testing.T) {
+		for seed := int64(0); seed < 100; seed++ {
+			value := NewExampleGenerator(seed).Generate(schema)
+			assert.NoError(t, schema.Value.VisitJSON(value, openapi3.EnableFormatValidation()))
+			assert.NotContains(t, value, "password")
+
+			value = NewRequestExampleGenerator(seed).Generate(schema)
+			assert.NoError(t, schema.Value.VisitJSON(value, openapi3.EnableFormatValidation()))
+			assert.NotContains(t, value, "owner")
+		}
+	})
+
+	t.Run("seeded", func(t *testing.T) {
+		assert.Equal(t, NewExampleGenerator(42).Generate(schema), NewExampleGenerator(42).Generate(schema))
+	})
+
+	t.Run("maxItems below minItems", func(t *testing.T) {
+		items := &openapi3.SchemaRef{}
+		require.NoError(t, items.UnmarshalJSON([]byte(`{"type": "array", "minItems": 3, "maxItems": 1, "items": {"type": "integer"}}`)))
+
+		for seed := int64(0); seed < 10; seed++ {
+			assert.Len(t, NewExampleGenerator(seed).Generate(items), 3)
+		}
+	})
+
+	t.Run("nil schema", func(t *testing.T) {
+		assert.Nil(t, NewExampleGenerator(1).Generate(nil))
+	})
+}
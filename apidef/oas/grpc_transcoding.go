@@ -0,0 +1,76 @@
+package oas
+
+import (
+	"github.com/TykTechnologies/tyk/apidef"
+)
+
+// ExtensionGRPCDescriptor is the OAS schema key of the base64 encoded FileDescriptorSet of an API
+// imported from gRPC service descriptors. On import, it enables gRPC transcoding in the upstream.
+const ExtensionGRPCDescriptor = "x-tyk-grpc-descriptor"
+
+// GRPCTranscoding configures the transcoding of HTTP/JSON requests to a gRPC upstream.
+//
+// The request paths, relative to the listen path, are matched against the `google.api.http`
+// annotations of the service methods and sent to the upstream as gRPC calls. The upstream URL must use the `h2c://` scheme for
+// plaintext, or `https://` with HTTP/2 enabled in the gateway.
+//
+// Tyk classic API definition: `grpc_transcoding`.
+type GRPCTranscoding struct {
+	// Enabled activates gRPC transcoding.
+	//
+	// Tyk classic API definition: `grpc_transcoding.enabled`.
+	Enabled bool `bson:"enabled" json:"enabled"` // required
+
+	// Descriptor is the base64 encoded FileDescriptorSet of the gRPC services and their imports.
+	//
+	// Tyk classic API definition: `grpc_transcoding.descriptor`.
+	Descriptor string `bson:"descriptor" json:"descriptor"` // required
+
+	// StreamFormat is the format of server streaming responses, `ndjson` for newline delimited JSON (default)
+	// or `sse` for server-sent events.
+	//
+	// Tyk classic API definition: `grpc_transcoding.stream_format`.
+	StreamFormat string `bson:"streamFormat,omitempty" json:"streamFormat,omitempty"`
+}
+
+// Fill fills *GRPCTranscoding from apidef.APIDefinition.
+func (g *GRPCTranscoding) Fill(api apidef.APIDefinition) {
+	*g = GRPCTranscoding{}
+	if api.GRPCTranscoding == nil {
+		return
+	}
+
+	g.Enabled = api.GRPCTranscoding.Enabled
+	g.Descriptor = api.GRPCTranscoding.Descriptor
+	g.StreamFormat = api.GRPCTranscoding.StreamFormat
+}
+
+// ExtractTo extracts *GRPCTranscoding into *apidef.APIDefinition.
+func (g *GRPCTranscoding) ExtractTo(api *apidef.APIDefinition) {
+	if ShouldOmit(g) {
+		api.GRPCTranscoding = nil
+		return
+	}
+
+	api.GRPCTranscoding = &apidef.GRPCTranscoding{
+		Enabled:      g.Enabled,
+		Descriptor:   g.Descriptor,
+		StreamFormat: g.StreamFormat,
+	}
+}
+
+// importGRPCDescriptor enables gRPC transcoding with the descriptors of an API imported from
+// gRPC service descriptors, and removes them from the OAS document.
+func (s *OAS) importGRPCDescriptor(xTykAPIGateway *XTykAPIGateway) {
+	descriptor, ok := s.Extensions[ExtensionGRPCDescriptor].(string)
+	if !ok {
+		return
+	}
+
+	delete(s.Extensions, ExtensionGRPCDescriptor)
+
+	xTykAPIGateway.Upstream.GRPCTranscoding = &GRPCTranscoding{
+		Enabled:    true,
+		Descriptor: descriptor,
+	}
+}
@@ -0,0 +1,70 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef"
+)
+
+func TestGRPCTranscoding(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		var emptyGRPCTranscoding GRPCTranscoding
+
+		var convertedAPI apidef.APIDefinition
+		emptyGRPCTranscoding.ExtractTo(&convertedAPI)
+		assert.Nil(t, convertedAPI.GRPCTranscoding)
+
+		var resultGRPCTranscoding GRPCTranscoding
+		resultGRPCTranscoding.Fill(convertedAPI)
+
+		assert.Equal(t, emptyGRPCTranscoding, resultGRPCTranscoding)
+	})
+
+	t.Run("filled", func(t *testing.T) {
+		grpcTranscoding := GRPCTranscoding{
+			Enabled:      true,
+			Descriptor:   "ZGVzY3JpcHRvcg==",
+			StreamFormat: "sse",
+		}
+
+		var convertedAPI apidef.APIDefinition
+		grpcTranscoding.ExtractTo(&convertedAPI)
+
+		var resultGRPCTranscoding GRPCTranscoding
+		resultGRPCTranscoding.Fill(convertedAPI)
+
+		assert.Equal(t, grpcTranscoding, resultGRPCTranscoding)
+	})
+}
+
+func TestOAS_BuildDefaultTykExtension_GRPCDescriptor(t *testing.T) {
+	newOAS := func() OAS {
+		return OAS{
+			T: openapi3.T{
+				Info:       &openapi3.Info{Title: "Library"},
+				Servers:    openapi3.Servers{{URL: "h2c://localhost:50051"}},
+				Extensions: map[string]interface{}{ExtensionGRPCDescriptor: "ZGVzY3JpcHRvcg=="},
+			},
+		}
+	}
+
+	t.Run("import", func(t *testing.T) {
+		oasDef := newOAS()
+		require.NoError(t, oasDef.BuildDefaultTykExtension(TykExtensionConfigParams{}, true))
+
+		assert.NotContains(t, oasDef.Extensions, ExtensionGRPCDescriptor)
+		assert.Equal(t, &GRPCTranscoding{Enabled: true, Descriptor: "ZGVzY3JpcHRvcg=="}, oasDef.GetTykExtension().Upstream.GRPCTranscoding)
+	})
+
+	t.Run("create", func(t *testing.T) {
+		oasDef := newOAS()
+		require.NoError(t, oasDef.BuildDefaultTykExtension(TykExtensionConfigParams{}, false))
+
+		assert.Contains(t, oasDef.Extensions, ExtensionGRPCDescriptor)
+		assert.Nil(t, oasDef.GetTykExtension().Upstream.GRPCTranscoding)
+	})
+}
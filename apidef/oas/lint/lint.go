@@ -0,0 +1,190 @@
+// Package lint checks Tyk OAS API definitions against rules of good practice, beyond the
+// structural validation of the JSON schemas.
+//
+// Each rule has a default severity that can be changed or turned off with a Config. Findings
+// are suppressed inline with the x-tyk-lint extension, at the root of the OpenAPI document
+// for the whole API or on an OpenAPI operation for that operation:
+//
+//	x-tyk-lint:
+//	  disable: [auth-disabled, missing-rate-limit]
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oasdiff/yaml"
+
+	"github.com/TykTechnologies/tyk/apidef/oas"
+)
+
+// ExtensionTykLint is the OAS schema key of the inline lint suppressions.
+const ExtensionTykLint = "x-tyk-lint"
+
+// Severity is the severity of a finding, the values are the SARIF result levels.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityNote    Severity = "note"
+	// SeverityOff turns a rule off.
+	SeverityOff Severity = "off"
+)
+
+var severityRanks = map[Severity]int{
+	SeverityNote:    1,
+	SeverityWarning: 2,
+	SeverityError:   3,
+}
+
+// AtLeast returns true if the severity is at least as severe as other.
+func (s Severity) AtLeast(other Severity) bool {
+	return severityRanks[s] >= severityRanks[other] && severityRanks[s] > 0
+}
+
+// Definition is a Tyk OAS API definition and the file it was loaded from.
+type Definition struct {
+	File string
+	API  *oas.OAS
+}
+
+// name returns the name of the API, or its ID.
+func (d *Definition) name() string {
+	if x := d.API.GetTykExtension(); x != nil && x.Info.Name != "" {
+		return x.Info.Name
+	}
+	if d.API.Info != nil {
+		return d.API.Info.Title
+	}
+	return d.File
+}
+
+// Finding is a rule violation.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	File     string   `json:"file,omitempty"`
+	API      string   `json:"api"`
+	// Pointer is the JSON pointer of the violation in the API definition.
+	Pointer string `json:"pointer"`
+
+	operation *openapi3.Operation
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s [%s] %s: %s (%s)", f.File, f.Severity, f.Rule, f.API, f.Message, f.Pointer)
+}
+
+// Config changes the severity of the rules, keyed by rule ID.
+type Config struct {
+	Rules map[string]Severity `json:"rules"`
+}
+
+// LoadConfig loads a configuration in JSON or YAML.
+func LoadConfig(data []byte) (Config, error) {
+	var conf Config
+
+	data, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return conf, err
+	}
+
+	err = json.Unmarshal(data, &conf)
+	return conf, err
+}
+
+// Linter lints API definitions with the rules.
+type Linter struct {
+	severities map[string]Severity
+}
+
+// New returns a linter with the configured severities of the rules.
+func New(conf Config) (*Linter, error) {
+	l := &Linter{severities: map[string]Severity{}}
+	for _, rule := range Rules() {
+		l.severities[rule.ID] = rule.Severity
+	}
+
+	for id, severity := range conf.Rules {
+		if _, ok := l.severities[id]; !ok {
+			return nil, fmt.Errorf("unknown lint rule %q", id)
+		}
+		if _, ok := severityRanks[severity]; !ok && severity != SeverityOff {
+			return nil, fmt.Errorf("invalid severity %q of lint rule %q", severity, id)
+		}
+		l.severities[id] = severity
+	}
+
+	return l, nil
+}
+
+// Severity returns the configured severity of a rule.
+func (l *Linter) Severity(rule string) Severity {
+	return l.severities[rule]
+}
+
+// Lint lints the API definitions, the findings are sorted by file.
+func (l *Linter) Lint(defs []*Definition) []Finding {
+	var findings []Finding
+	for _, rule := range Rules() {
+		severity := l.severities[rule.ID]
+		if severity == SeverityOff {
+			continue
+		}
+
+		rule.check(defs, func(def *Definition, f Finding) {
+			if suppressed(def.API.Extensions, rule.ID) || (f.operation != nil && suppressed(f.operation.Extensions, rule.ID)) {
+				return
+			}
+
+			f.Rule, f.Severity, f.File, f.API = rule.ID, severity, def.File, def.name()
+			findings = append(findings, f)
+		})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].File < findings[j].File
+	})
+
+	return findings
+}
+
+// suppressed returns true if the rule is disabled by the x-tyk-lint extension.
+func suppressed(extensions map[string]interface{}, rule string) bool {
+	ext, ok := extensions[ExtensionTykLint]
+	if !ok {
+		return false
+	}
+
+	var lint struct {
+		Disable []string `json:"disable"`
+	}
+
+	data, err := json.Marshal(ext)
+	if err != nil || json.Unmarshal(data, &lint) != nil {
+		return false
+	}
+
+	for _, id := range lint.Disable {
+		if id == rule || id == "all" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pointer returns the JSON pointer of the tokens.
+func pointer(tokens ...string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteString("/")
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
@@ -0,0 +1,266 @@
+package lint
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oasdiff/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef/oas"
+)
+
+const petsAPI = `
+openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.0.0
+components:
+  securitySchemes:
+    key:
+      type: apiKey
+      in: header
+      name: X-Api-Key
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: OK
+    post:
+      operationId: createPet
+      x-tyk-lint:
+        disable: [mock-response-enabled]
+      responses:
+        "200":
+          description: OK
+  /pets/{id}:
+    get:
+      operationId: getPet
+      responses:
+        "200":
+          description: OK
+x-tyk-api-gateway:
+  info:
+    name: Pets
+    state:
+      active: true
+  upstream:
+    url: https://pets.example.com
+    tlsTransport:
+      insecureSkipVerify: true
+      minVersion: "1.1"
+  server:
+    listenPath:
+      value: /pets/
+    authentication:
+      enabled: true
+  middleware:
+    global:
+      cors:
+        enabled: true
+        allowCredentials: true
+        allowedOrigins: ["*"]
+      cache:
+        enabled: true
+        cacheAllSafeRequests: true
+    operations:
+      listPets:
+        allow:
+          enabled: true
+        mockResponse:
+          enabled: true
+      createPet:
+        allow:
+          enabled: true
+        mockResponse:
+          enabled: true
+        ignoreAuthentication:
+          enabled: true
+      deletePet:
+        block:
+          enabled: true
+`
+
+const keylessAPI = `
+openapi: 3.0.3
+info:
+  title: Keyless
+  version: 1.0.0
+paths: {}
+x-tyk-api-gateway:
+  info:
+    name: Keyless
+    state:
+      active: true
+  upstream:
+    url: https://keyless.example.com
+  server:
+    listenPath:
+      value: /pets
+`
+
+func definition(t *testing.T, file, doc string) *Definition {
+	t.Helper()
+
+	data, err := yaml.YAMLToJSON([]byte(doc))
+	require.NoError(t, err)
+
+	loader := openapi3.NewLoader()
+	loaded, err := loader.LoadFromData(data)
+	require.NoError(t, err)
+
+	return &Definition{File: file, API: &oas.OAS{T: *loaded}}
+}
+
+func rulesOf(findings []Finding) map[string][]string {
+	rules := map[string][]string{}
+	for _, f := range findings {
+		rules[f.Rule] = append(rules[f.Rule], f.Pointer)
+	}
+	return rules
+}
+
+func TestLinter_Lint(t *testing.T) {
+	l, err := New(Config{})
+	require.NoError(t, err)
+
+	findings := l.Lint([]*Definition{definition(t, "pets.yml", petsAPI), definition(t, "keyless.yml", keylessAPI)})
+
+	assert.Equal(t, map[string][]string{
+		"auth-disabled": {
+			"/x-tyk-api-gateway/server/authentication",
+			"/x-tyk-api-gateway/middleware/operations/createPet/ignoreAuthentication",
+		},
+		"listen-path-collision":     {"/x-tyk-api-gateway/server/listenPath/value"},
+		"missing-rate-limit":        {"/x-tyk-api-gateway/upstream/rateLimit"},
+		"cors-wildcard-credentials": {"/x-tyk-api-gateway/middleware/global/cors"},
+		"cache-without-key-header":  {"/x-tyk-api-gateway/middleware/global/cache"},
+		"mock-response-enabled":     {"/x-tyk-api-gateway/middleware/operations/listPets/mockResponse"},
+		"insecure-upstream-tls": {
+			"/x-tyk-api-gateway/upstream/tlsTransport/insecureSkipVerify",
+			"/x-tyk-api-gateway/upstream/tlsTransport/minVersion",
+		},
+		"unreachable-operation": {"/paths/~1pets~1{id}/get"},
+		"unknown-operation":     {"/x-tyk-api-gateway/middleware/operations/deletePet"},
+	}, rulesOf(findings))
+
+	assert.Equal(t, "keyless.yml", findings[0].File, "findings are sorted by file")
+
+	for _, f := range findings {
+		if f.Rule == "listen-path-collision" {
+			assert.Equal(t, "keyless.yml", f.File)
+			assert.Equal(t, `listen path "/pets" collides with API "Pets" in pets.yml`, f.Message)
+		}
+		if f.Rule == "cache-without-key-header" {
+			assert.Equal(t, SeverityWarning, f.Severity)
+			assert.Equal(t, "responses are cached without X-Api-Key in cacheByHeaders", f.Message)
+		}
+	}
+}
+
+func TestLinter_Config(t *testing.T) {
+	conf, err := LoadConfig([]byte(`
+rules:
+  insecure-upstream-tls: warning
+  missing-rate-limit: "off"
+`))
+	require.NoError(t, err)
+
+	l, err := New(conf)
+	require.NoError(t, err)
+	assert.Equal(t, SeverityWarning, l.Severity("insecure-upstream-tls"))
+	assert.Equal(t, SeverityError, l.Severity("cors-wildcard-credentials"))
+
+	findings := l.Lint([]*Definition{definition(t, "pets.yml", petsAPI), definition(t, "keyless.yml", keylessAPI)})
+	for _, f := range findings {
+		assert.NotEqual(t, "missing-rate-limit", f.Rule)
+		if f.Rule == "insecure-upstream-tls" {
+			assert.Equal(t, SeverityWarning, f.Severity)
+		}
+	}
+
+	_, err = New(Config{Rules: map[string]Severity{"unknown": SeverityError}})
+	assert.Error(t, err)
+
+	_, err = New(Config{Rules: map[string]Severity{"auth-disabled": "fatal"}})
+	assert.Error(t, err)
+}
+
+func TestLinter_Suppressions(t *testing.T) {
+	def := definition(t, "keyless.yml", keylessAPI)
+	def.API.Extensions[ExtensionTykLint] = map[string]interface{}{"disable": []interface{}{"auth-disabled"}}
+
+	l, err := New(Config{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"missing-rate-limit": {"/x-tyk-api-gateway/upstream/rateLimit"},
+	}, rulesOf(l.Lint([]*Definition{def})))
+
+	def.API.Extensions[ExtensionTykLint] = map[string]interface{}{"disable": []interface{}{"all"}}
+	assert.Empty(t, l.Lint([]*Definition{def}))
+}
+
+func TestLinter_SARIF(t *testing.T) {
+	l, err := New(Config{Rules: map[string]Severity{"unknown-operation": SeverityOff}})
+	require.NoError(t, err)
+
+	log := l.SARIF(l.Lint([]*Definition{definition(t, "keyless.yml", keylessAPI)}))
+
+	data, err := json.Marshal(log)
+	require.NoError(t, err)
+
+	var sarif struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID                   string `json:"id"`
+						DefaultConfiguration struct {
+							Enabled bool   `json:"enabled"`
+							Level   string `json:"level"`
+						} `json:"defaultConfiguration"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+					} `json:"physicalLocation"`
+					LogicalLocations []struct {
+						FullyQualifiedName string `json:"fullyQualifiedName"`
+					} `json:"logicalLocations"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(data, &sarif))
+
+	assert.Equal(t, "2.1.0", sarif.Version)
+	require.Len(t, sarif.Runs, 1)
+
+	run := sarif.Runs[0]
+	assert.Equal(t, "tyk-lint", run.Tool.Driver.Name)
+	assert.Len(t, run.Tool.Driver.Rules, len(Rules()))
+	for _, rule := range run.Tool.Driver.Rules {
+		if rule.ID == "unknown-operation" {
+			assert.False(t, rule.DefaultConfiguration.Enabled)
+		}
+	}
+
+	require.Len(t, run.Results, 2)
+	assert.Equal(t, "auth-disabled", run.Results[0].RuleID)
+	assert.Equal(t, "warning", run.Results[0].Level)
+	assert.Equal(t, "keyless.yml", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "/x-tyk-api-gateway/server/authentication", run.Results[0].Locations[0].LogicalLocations[0].FullyQualifiedName)
+}
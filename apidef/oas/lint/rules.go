@@ -0,0 +1,414 @@
+package lint
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/TykTechnologies/tyk/apidef/oas"
+)
+
+// Rule is a lint rule.
+type Rule struct {
+	ID          string
+	Description string
+	Severity    Severity
+
+	check func(defs []*Definition, report reportFunc)
+}
+
+type reportFunc func(def *Definition, f Finding)
+
+var rules = []Rule{
+	{
+		ID:          "auth-disabled",
+		Description: "Authentication is disabled or ignored on an API that isn't internal.",
+		Severity:    SeverityWarning,
+		check:       eachAPI(checkAuthDisabled),
+	},
+	{
+		ID:          "listen-path-collision",
+		Description: "The listen path of the API is used by another API on the same domain.",
+		Severity:    SeverityError,
+		check:       checkListenPathCollision,
+	},
+	{
+		ID:          "cors-wildcard-credentials",
+		Description: "CORS allows credentials from any origin.",
+		Severity:    SeverityError,
+		check:       eachAPI(checkCORSWildcardCredentials),
+	},
+	{
+		ID:          "cache-without-key-header",
+		Description: "Responses of an authenticated API are cached without the authentication header in the cache key, and may be served to other users.",
+		Severity:    SeverityWarning,
+		check:       eachAPI(checkCacheWithoutKeyHeader),
+	},
+	{
+		ID:          "missing-rate-limit",
+		Description: "A keyless API has no API or endpoint level rate limit.",
+		Severity:    SeverityWarning,
+		check:       eachAPI(checkMissingRateLimit),
+	},
+	{
+		ID:          "mock-response-enabled",
+		Description: "An operation returns a mock response instead of proxying to the upstream.",
+		Severity:    SeverityWarning,
+		check:       eachAPI(checkMockResponseEnabled),
+	},
+	{
+		ID:          "insecure-upstream-tls",
+		Description: "The upstream certificate isn't verified or a deprecated TLS version is allowed.",
+		Severity:    SeverityError,
+		check:       eachAPI(checkInsecureUpstreamTLS),
+	},
+	{
+		ID:          "unreachable-operation",
+		Description: "An operation is blocked by the allow list of the API.",
+		Severity:    SeverityWarning,
+		check:       eachAPI(checkUnreachableOperation),
+	},
+	{
+		ID:          "unknown-operation",
+		Description: "The Tyk extension configures an operation that isn't in the OpenAPI paths.",
+		Severity:    SeverityWarning,
+		check:       eachAPI(checkUnknownOperation),
+	},
+}
+
+// Rules returns the lint rules with their default severity.
+func Rules() []Rule {
+	return append([]Rule(nil), rules...)
+}
+
+// eachAPI runs a check on each API definition with a Tyk extension.
+func eachAPI(check func(def *Definition, x *oas.XTykAPIGateway, report func(Finding))) func([]*Definition, reportFunc) {
+	return func(defs []*Definition, report reportFunc) {
+		for _, def := range defs {
+			x := def.API.GetTykExtension()
+			if x == nil {
+				continue
+			}
+
+			check(def, x, func(f Finding) { report(def, f) })
+		}
+	}
+}
+
+func checkAuthDisabled(def *Definition, x *oas.XTykAPIGateway, report func(Finding)) {
+	if x.Info.State.Internal {
+		return
+	}
+
+	if auth := x.Server.Authentication; auth == nil || !auth.Enabled {
+		report(Finding{
+			Message: "authentication is disabled",
+			Pointer: pointer(oas.ExtensionTykAPIGateway, "server", "authentication"),
+		})
+		return
+	}
+
+	for _, op := range operations(def.API) {
+		if tykOp := tykOperation(x, op.OperationID); tykOp != nil && tykOp.IgnoreAuthentication != nil && tykOp.IgnoreAuthentication.Enabled {
+			report(Finding{
+				Message:   fmt.Sprintf("authentication is ignored on %s", op),
+				Pointer:   pointer(oas.ExtensionTykAPIGateway, "middleware", "operations", op.OperationID, "ignoreAuthentication"),
+				operation: op.Operation,
+			})
+		}
+	}
+}
+
+func checkListenPathCollision(defs []*Definition, report reportFunc) {
+	seen := map[string]*Definition{}
+	for _, def := range defs {
+		x := def.API.GetTykExtension()
+		if x == nil || x.Info.State.Internal {
+			continue
+		}
+
+		domain := ""
+		if d := x.Server.CustomDomain; d != nil && d.Enabled {
+			domain = d.Name
+		}
+
+		listenPath := strings.TrimSuffix(x.Server.ListenPath.Value, "/")
+		if listenPath == "" {
+			listenPath = "/"
+		}
+
+		key := domain + listenPath
+		if other, ok := seen[key]; ok {
+			report(def, Finding{
+				Message: fmt.Sprintf("listen path %q collides with API %q in %s", x.Server.ListenPath.Value, other.name(), other.File),
+				Pointer: pointer(oas.ExtensionTykAPIGateway, "server", "listenPath", "value"),
+			})
+			continue
+		}
+
+		seen[key] = def
+	}
+}
+
+func checkCORSWildcardCredentials(_ *Definition, x *oas.XTykAPIGateway, report func(Finding)) {
+	global := globalMiddleware(x)
+	if global == nil || global.CORS == nil || !global.CORS.Enabled || !global.CORS.AllowCredentials {
+		return
+	}
+
+	wildcard := len(global.CORS.AllowedOrigins) == 0
+	for _, origin := range global.CORS.AllowedOrigins {
+		if origin == "*" {
+			wildcard = true
+		}
+	}
+
+	if wildcard {
+		report(Finding{
+			Message: "CORS allows credentials from any origin",
+			Pointer: pointer(oas.ExtensionTykAPIGateway, "middleware", "global", "cors"),
+		})
+	}
+}
+
+func checkCacheWithoutKeyHeader(def *Definition, x *oas.XTykAPIGateway, report func(Finding)) {
+	if auth := x.Server.Authentication; auth == nil || !auth.Enabled {
+		return
+	}
+
+	global := globalMiddleware(x)
+	if global == nil || global.Cache == nil || !global.Cache.Enabled {
+		return
+	}
+
+	cached := global.Cache.CacheAllSafeRequests
+	for _, tykOp := range x.Middleware.Operations {
+		if tykOp != nil && tykOp.Cache != nil && tykOp.Cache.Enabled {
+			cached = true
+		}
+	}
+	if !cached {
+		return
+	}
+
+	headers := authHeaders(def.API)
+	for _, header := range global.Cache.CacheByHeaders {
+		if headers[http.CanonicalHeaderKey(header)] {
+			return
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	report(Finding{
+		Message: fmt.Sprintf("responses are cached without %s in cacheByHeaders", strings.Join(names, " or ")),
+		Pointer: pointer(oas.ExtensionTykAPIGateway, "middleware", "global", "cache"),
+	})
+}
+
+// authHeaders returns the headers of the security schemes, Authorization by default.
+func authHeaders(api *oas.OAS) map[string]bool {
+	headers := map[string]bool{}
+	if api.Components != nil {
+		for _, scheme := range api.Components.SecuritySchemes {
+			if scheme == nil || scheme.Value == nil {
+				continue
+			}
+
+			switch scheme.Value.Type {
+			case "apiKey":
+				if scheme.Value.In == "header" {
+					headers[http.CanonicalHeaderKey(scheme.Value.Name)] = true
+				}
+			case "http", "oauth2", "openIdConnect":
+				headers["Authorization"] = true
+			}
+		}
+	}
+
+	if len(headers) == 0 {
+		headers["Authorization"] = true
+	}
+
+	return headers
+}
+
+func checkMissingRateLimit(_ *Definition, x *oas.XTykAPIGateway, report func(Finding)) {
+	if x.Info.State.Internal {
+		return
+	}
+	if auth := x.Server.Authentication; auth != nil && auth.Enabled {
+		return
+	}
+	if x.Upstream.RateLimit != nil && x.Upstream.RateLimit.Enabled {
+		return
+	}
+
+	if x.Middleware != nil {
+		for _, tykOp := range x.Middleware.Operations {
+			if tykOp != nil && tykOp.RateLimit != nil && tykOp.RateLimit.Enabled {
+				return
+			}
+		}
+	}
+
+	report(Finding{
+		Message: "the keyless API has no rate limit",
+		Pointer: pointer(oas.ExtensionTykAPIGateway, "upstream", "rateLimit"),
+	})
+}
+
+func checkMockResponseEnabled(def *Definition, x *oas.XTykAPIGateway, report func(Finding)) {
+	for _, op := range operations(def.API) {
+		if tykOp := tykOperation(x, op.OperationID); tykOp != nil && tykOp.MockResponse != nil && tykOp.MockResponse.Enabled {
+			report(Finding{
+				Message:   fmt.Sprintf("%s returns a mock response", op),
+				Pointer:   pointer(oas.ExtensionTykAPIGateway, "middleware", "operations", op.OperationID, "mockResponse"),
+				operation: op.Operation,
+			})
+		}
+	}
+}
+
+func checkInsecureUpstreamTLS(_ *Definition, x *oas.XTykAPIGateway, report func(Finding)) {
+	tls := x.Upstream.TLSTransport
+	if tls == nil {
+		return
+	}
+
+	if tls.InsecureSkipVerify {
+		report(Finding{
+			Message: "the upstream certificate isn't verified",
+			Pointer: pointer(oas.ExtensionTykAPIGateway, "upstream", "tlsTransport", "insecureSkipVerify"),
+		})
+	}
+
+	if tls.MinVersion == "1.0" || tls.MinVersion == "1.1" {
+		report(Finding{
+			Message: fmt.Sprintf("the deprecated TLS %s is allowed to the upstream", tls.MinVersion),
+			Pointer: pointer(oas.ExtensionTykAPIGateway, "upstream", "tlsTransport", "minVersion"),
+		})
+	}
+}
+
+func checkUnreachableOperation(def *Definition, x *oas.XTykAPIGateway, report func(Finding)) {
+	if x.Middleware == nil {
+		return
+	}
+
+	allowList := false
+	for _, tykOp := range x.Middleware.Operations {
+		if tykOp != nil && tykOp.Allow != nil && tykOp.Allow.Enabled {
+			allowList = true
+		}
+	}
+	if !allowList {
+		return
+	}
+
+	for _, op := range operations(def.API) {
+		if tykOp := tykOperation(x, op.OperationID); tykOp != nil && tykOp.Allow != nil && tykOp.Allow.Enabled {
+			continue
+		}
+
+		report(Finding{
+			Message:   fmt.Sprintf("%s isn't in the allow list of the API", op),
+			Pointer:   pointer("paths", op.path, strings.ToLower(op.method)),
+			operation: op.Operation,
+		})
+	}
+}
+
+func checkUnknownOperation(def *Definition, x *oas.XTykAPIGateway, report func(Finding)) {
+	if x.Middleware == nil {
+		return
+	}
+
+	known := map[string]bool{}
+	for _, op := range operations(def.API) {
+		known[op.OperationID] = true
+	}
+
+	ids := make([]string, 0, len(x.Middleware.Operations))
+	for id := range x.Middleware.Operations {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if !known[id] {
+			report(Finding{
+				Message: fmt.Sprintf("operation %q isn't in the OpenAPI paths", id),
+				Pointer: pointer(oas.ExtensionTykAPIGateway, "middleware", "operations", id),
+			})
+		}
+	}
+}
+
+func globalMiddleware(x *oas.XTykAPIGateway) *oas.Global {
+	if x.Middleware == nil {
+		return nil
+	}
+	return x.Middleware.Global
+}
+
+func tykOperation(x *oas.XTykAPIGateway, operationID string) *oas.Operation {
+	if x.Middleware == nil || operationID == "" {
+		return nil
+	}
+	return x.Middleware.Operations[operationID]
+}
+
+// operation is an OpenAPI operation with its path and method.
+type operation struct {
+	*openapi3.Operation
+	path   string
+	method string
+}
+
+func (o operation) String() string {
+	if o.OperationID != "" {
+		return fmt.Sprintf("operation %q", o.OperationID)
+	}
+	return fmt.Sprintf("operation %s %s", o.method, o.path)
+}
+
+// operations returns the operations of the API, sorted by path and method.
+func operations(api *oas.OAS) []operation {
+	if api.Paths == nil {
+		return nil
+	}
+
+	paths := make([]string, 0, api.Paths.Len())
+	for path := range api.Paths.Map() {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var result []operation
+	for _, path := range paths {
+		item := api.Paths.Value(path)
+		if item == nil {
+			continue
+		}
+
+		methods := make([]string, 0)
+		for method := range item.Operations() {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			result = append(result, operation{Operation: item.GetOperation(method), path: path, method: method})
+		}
+	}
+
+	return result
+}
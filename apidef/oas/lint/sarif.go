@@ -0,0 +1,117 @@
+package lint
+
+// SARIFVersion is the version of the SARIF logs.
+const SARIFVersion = "2.1.0"
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// SARIF is a SARIF log, as read by code scanning tools.
+type SARIF struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is a run of the linter.
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the linter and its rules.
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the linter.
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule is a lint rule.
+type SARIFRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     SARIFMessage       `json:"shortDescription"`
+	DefaultConfiguration SARIFConfiguration `json:"defaultConfiguration"`
+}
+
+// SARIFConfiguration is the configured severity of a rule.
+type SARIFConfiguration struct {
+	Enabled bool     `json:"enabled"`
+	Level   Severity `json:"level,omitempty"`
+}
+
+// SARIFMessage is a text message.
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult is a finding.
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     Severity        `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+}
+
+// SARIFLocation locates a finding with its file and the JSON pointer in the file.
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+// SARIFPhysicalLocation is the file of a finding.
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+}
+
+// SARIFArtifactLocation is the URI of a file.
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFLogicalLocation is the JSON pointer of a finding.
+type SARIFLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// SARIF returns the findings as a SARIF log, with the rules and their configured severity.
+func (l *Linter) SARIF(findings []Finding) *SARIF {
+	run := SARIFRun{
+		Tool: SARIFTool{Driver: SARIFDriver{
+			Name:           "tyk-lint",
+			InformationURI: "https://tyk.io/docs",
+		}},
+		Results: make([]SARIFResult, 0, len(findings)),
+	}
+
+	for _, rule := range Rules() {
+		conf := SARIFConfiguration{Enabled: l.severities[rule.ID] != SeverityOff}
+		if conf.Enabled {
+			conf.Level = l.severities[rule.ID]
+		}
+
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, SARIFRule{
+			ID:                   rule.ID,
+			ShortDescription:     SARIFMessage{Text: rule.Description},
+			DefaultConfiguration: conf,
+		})
+	}
+
+	for _, f := range findings {
+		run.Results = append(run.Results, SARIFResult{
+			RuleID:  f.Rule,
+			Level:   f.Severity,
+			Message: SARIFMessage{Text: f.API + ": " + f.Message},
+			Locations: []SARIFLocation{{
+				PhysicalLocation: SARIFPhysicalLocation{ArtifactLocation: SARIFArtifactLocation{URI: f.File}},
+				LogicalLocations: []SARIFLogicalLocation{{FullyQualifiedName: f.Pointer, Kind: "member"}},
+			}},
+		})
+	}
+
+	return &SARIF{Schema: sarifSchema, Version: SARIFVersion, Runs: []SARIFRun{run}}
+}
@@ -62,11 +62,16 @@ func (s *OAS) MarshalJSON() ([]byte, error) {
 	type Alias OAS
 
 	// to prevent infinite recursion
-	return json.Marshal(&struct {
+	data, err := json.Marshal(&struct {
 		*Alias
 	}{
 		Alias: (*Alias)(s),
 	})
+	if err != nil || !s.IsOpenAPI31() {
+		return data, err
+	}
+
+	return restoreDocument(data)
 }
 
 // Fill fills *OAS definition from apidef.APIDefinition.
@@ -538,8 +543,18 @@ func (s *OAS) ReplaceServers(apiURLs, oldAPIURLs []string) {
 
 // Validate validates OAS document by calling openapi3.T.Validate() function. In addition, it validates Security
 // Requirement section and it's requirements by calling OAS.validateSecurity() function.
+// OpenAPI 3.1 documents are validated once downgraded to OpenAPI 3.0.
 func (s *OAS) Validate(ctx context.Context, opts ...openapi3.ValidationOption) error {
-	validationErr := s.T.Validate(ctx, opts...)
+	doc := &s.T
+	if s.IsOpenAPI31() {
+		downgraded, err := s.Downgrade()
+		if err != nil {
+			return err
+		}
+		doc = &downgraded.T
+	}
+
+	validationErr := doc.Validate(ctx, opts...)
 	securityErr := s.validateSecurity()
 	compliantModeErr := s.validateCompliantModeAuthentication()
 
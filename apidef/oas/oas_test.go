@@ -233,6 +233,12 @@ func TestOAS_ExtractTo_ResetAPIDefinition(t *testing.T) {
 		"APIDefinition.VersionData.Versions[0].ExtendedPaths.TransformJQResponse[0].Filter",
 		"APIDefinition.VersionData.Versions[0].ExtendedPaths.TransformJQResponse[0].Path",
 		"APIDefinition.VersionData.Versions[0].ExtendedPaths.TransformJQResponse[0].Method",
+		"APIDefinition.VersionData.Versions[0].ExtendedPaths.ValidateXML[0].Disabled",
+		"APIDefinition.VersionData.Versions[0].ExtendedPaths.ValidateXML[0].Path",
+		"APIDefinition.VersionData.Versions[0].ExtendedPaths.ValidateXML[0].Method",
+		"APIDefinition.VersionData.Versions[0].ExtendedPaths.ValidateXML[0].Schema",
+		"APIDefinition.VersionData.Versions[0].ExtendedPaths.ValidateXML[0].Element",
+		"APIDefinition.VersionData.Versions[0].ExtendedPaths.ValidateXML[0].ErrorResponseCode",
 		"APIDefinition.VersionData.Versions[0].ExtendedPaths.PersistGraphQL[0].Path",
 		"APIDefinition.VersionData.Versions[0].ExtendedPaths.PersistGraphQL[0].Method",
 		"APIDefinition.VersionData.Versions[0].ExtendedPaths.PersistGraphQL[0].Operation",
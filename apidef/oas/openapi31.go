@@ -0,0 +1,593 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oasdiff/yaml"
+)
+
+// OpenAPI 3.1 documents describe their schemas with JSON Schema 2020-12, while openapi3 models the
+// schemas of OpenAPI 3.0. The 2020-12 keywords unknown to openapi3 are kept as schema extensions, and
+// the keywords whose type changed are rewritten when a 3.1 document is loaded and restored when it's
+// marshalled. Downgrade converts a 3.1 document to the equivalent 3.0 document used to validate the
+// document, route the requests and mock the responses.
+
+const (
+	// OpenAPI31 is the minor version of the OpenAPI 3.1 documents.
+	OpenAPI31 = "3.1"
+
+	// keyWebhooks is the OpenAPI 3.1 webhooks, they're kept as an extension in 3.0 documents.
+	keyWebhooks = "webhooks"
+)
+
+// schemaKeywords are the keywords of a schema holding a subschema.
+var schemaKeywords = []string{
+	"items", "additionalProperties", "not", "contains", "if", "then", "else", "propertyNames",
+	"unevaluatedItems", "unevaluatedProperties", "contentSchema", "additionalItems",
+}
+
+// schemaListKeywords are the keywords of a schema holding a list of subschemas.
+var schemaListKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+
+// schemaMapKeywords are the keywords of a schema holding subschemas by name.
+var schemaMapKeywords = []string{"properties", "patternProperties", "$defs", "definitions", "dependentSchemas"}
+
+// openapi3Keywords are the keywords holding subschemas modelled by openapi3, boolean schemas
+// aren't supported there.
+var openapi3Keywords = map[string]bool{
+	"schema": true, "schemas": true, "items": true, "not": true,
+	"allOf": true, "anyOf": true, "oneOf": true, "properties": true,
+}
+
+// unsupportedKeywords are the JSON Schema 2020-12 keywords without an OpenAPI 3.0 equivalent,
+// they're removed by the downgrade and only checked by the 2020-12 validation of the request bodies.
+var unsupportedKeywords = []string{
+	"$schema", "$id", "$anchor", "$dynamicAnchor", "$dynamicRef", "$comment", "$vocabulary",
+	"prefixItems", "contains", "minContains", "maxContains", "if", "then", "else",
+	"dependentRequired", "dependentSchemas", "unevaluatedItems", "unevaluatedProperties",
+	"patternProperties", "propertyNames", "contentEncoding", "contentMediaType", "contentSchema",
+}
+
+// IsOpenAPI31 returns true if the version is an OpenAPI 3.1 version.
+func IsOpenAPI31(version string) bool {
+	minor, err := getMinorVersion(version)
+	return err == nil && minor == OpenAPI31
+}
+
+// IsOpenAPI31 returns true if the OAS document is an OpenAPI 3.1 document.
+func (s *OAS) IsOpenAPI31() bool {
+	return IsOpenAPI31(s.OpenAPI)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, OpenAPI 3.1 documents are normalized for openapi3.
+func (s *OAS) UnmarshalJSON(data []byte) error {
+	if isOpenAPI31Document(data) {
+		var err error
+		if data, err = normalizeDocument(data); err != nil {
+			return err
+		}
+	}
+
+	return s.T.UnmarshalJSON(data)
+}
+
+// LoadFromData loads an OAS document in JSON or YAML with the loader, OpenAPI 3.1 documents
+// are normalized for openapi3.
+func LoadFromData(loader *openapi3.Loader, data []byte) (*openapi3.T, error) {
+	data, err := normalizeData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return loader.LoadFromData(data)
+}
+
+// LoadFromFile loads an OAS document from a file with the loader, the references are resolved
+// relative to the file. OpenAPI 3.1 documents are normalized for openapi3.
+func LoadFromFile(loader *openapi3.Loader, path string) (*openapi3.T, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized, err := normalizeData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.Equal(normalized, data) {
+		return loader.LoadFromFile(path)
+	}
+
+	return loader.LoadFromDataWithPath(normalized, &url.URL{Path: filepath.ToSlash(path)})
+}
+
+// Downgrade returns the OpenAPI 3.0 equivalent of an OpenAPI 3.1 document, a copy is returned for
+// other versions. The JSON Schema 2020-12 keywords without a 3.0 equivalent are removed.
+func (s *OAS) Downgrade() (*OAS, error) {
+	if !s.IsOpenAPI31() {
+		return s.Clone()
+	}
+
+	data, err := s.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = DowngradeOpenAPI31(data)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load the downgraded document: %w", err)
+	}
+
+	return &OAS{T: *t}, nil
+}
+
+// DowngradeOpenAPI31 converts an OpenAPI 3.1 document in JSON to an OpenAPI 3.0 document.
+func DowngradeOpenAPI31(data []byte) ([]byte, error) {
+	var doc map[string]interface{}
+	if err := decodeJSON(data, &doc); err != nil {
+		return nil, err
+	}
+
+	doc["openapi"] = DefaultOpenAPI
+	delete(doc, "jsonSchemaDialect")
+
+	if webhooks, ok := doc[keyWebhooks]; ok {
+		doc["x-"+keyWebhooks] = webhooks
+		delete(doc, keyWebhooks)
+	}
+
+	if info, ok := doc["info"].(map[string]interface{}); ok {
+		delete(info, "summary")
+		if license, ok := info["license"].(map[string]interface{}); ok {
+			delete(license, "identifier")
+		}
+	}
+
+	components, _ := doc["components"].(map[string]interface{})
+	if pathItems, ok := components["pathItems"].(map[string]interface{}); ok {
+		inlinePathItems(doc, pathItems)
+		delete(components, "pathItems")
+	}
+
+	if schemas, ok := components["schemas"].(map[string]interface{}); ok {
+		refs := map[string]string{}
+		for _, name := range sortedKeys(schemas) {
+			hoistDefs(schemas, name, "#/components/schemas/"+escapePointer(name), schemas[name], refs)
+		}
+		rewriteRefs(doc, refs)
+	}
+
+	if _, ok := doc["paths"]; !ok {
+		doc["paths"] = map[string]interface{}{}
+	}
+
+	if paths, ok := doc["paths"].(map[string]interface{}); ok {
+		for _, pathItem := range paths {
+			addDefaultResponses(pathItem)
+		}
+	}
+
+	visitDocumentSchemas(doc, downgradeSchema)
+
+	return json.Marshal(doc)
+}
+
+// normalizeData normalizes OpenAPI 3.1 documents in JSON or YAML, other documents are returned unchanged.
+func normalizeData(data []byte) ([]byte, error) {
+	jsonData := data
+	if !json.Valid(data) {
+		var err error
+		if jsonData, err = yaml.YAMLToJSON(data); err != nil {
+			// the loader reports the invalid documents.
+			return data, nil
+		}
+	}
+
+	if !isOpenAPI31Document(jsonData) {
+		return data, nil
+	}
+
+	return normalizeDocument(jsonData)
+}
+
+// isOpenAPI31Document returns true if the JSON document is an OpenAPI 3.1 document.
+func isOpenAPI31Document(data []byte) bool {
+	version, err := jsonparser.GetString(data, "openapi")
+	return err == nil && IsOpenAPI31(version)
+}
+
+// normalizeDocument rewrites the schemas of an OpenAPI 3.1 document that openapi3 can't unmarshal:
+// the numeric exclusive bounds and the boolean schemas. The paths, optional in 3.1, are added.
+func normalizeDocument(data []byte) ([]byte, error) {
+	var doc map[string]interface{}
+	if err := decodeJSON(data, &doc); err != nil {
+		return nil, err
+	}
+
+	if _, ok := doc["paths"]; !ok {
+		doc["paths"] = map[string]interface{}{}
+	}
+
+	visitDocumentSchemas(doc, normalizeSchema)
+
+	return json.Marshal(doc)
+}
+
+// restoreDocument reverts the rewrite of the numeric exclusive bounds of an OpenAPI 3.1 document.
+func restoreDocument(data []byte) ([]byte, error) {
+	var doc map[string]interface{}
+	if err := decodeJSON(data, &doc); err != nil {
+		return nil, err
+	}
+
+	visitDocumentSchemas(doc, restoreSchema)
+
+	return json.Marshal(doc)
+}
+
+// schemaVisitor returns the schema replacing a schema, keyword is the keyword holding the schema.
+type schemaVisitor func(schema interface{}, keyword string) interface{}
+
+// visitDocumentSchemas visits the schemas of the components, parameters, headers and media types.
+func visitDocumentSchemas(node interface{}, visit schemaVisitor) {
+	switch val := node.(type) {
+	case map[string]interface{}:
+		for key, child := range val {
+			switch {
+			case strings.HasPrefix(key, "x-"), key == "example", key == "examples":
+			case key == "schema":
+				val[key] = visitSchema(child, key, visit)
+			case key == "schemas":
+				if schemas, ok := child.(map[string]interface{}); ok {
+					for name, schema := range schemas {
+						schemas[name] = visitSchema(schema, key, visit)
+					}
+				}
+			default:
+				visitDocumentSchemas(child, visit)
+			}
+		}
+	case []interface{}:
+		for _, child := range val {
+			visitDocumentSchemas(child, visit)
+		}
+	}
+}
+
+// visitSchema visits the subschemas of a schema then the schema.
+func visitSchema(schema interface{}, keyword string, visit schemaVisitor) interface{} {
+	if m, ok := schema.(map[string]interface{}); ok {
+		for _, key := range schemaKeywords {
+			if sub, ok := m[key]; ok {
+				if _, isBool := sub.(bool); isBool && key == "additionalProperties" {
+					continue
+				}
+				m[key] = visitSchema(sub, key, visit)
+			}
+		}
+
+		for _, key := range schemaListKeywords {
+			if list, ok := m[key].([]interface{}); ok {
+				for i := range list {
+					list[i] = visitSchema(list[i], key, visit)
+				}
+			}
+		}
+
+		for _, key := range schemaMapKeywords {
+			if subs, ok := m[key].(map[string]interface{}); ok {
+				for name := range subs {
+					subs[name] = visitSchema(subs[name], key, visit)
+				}
+			}
+		}
+	}
+
+	return visit(schema, keyword)
+}
+
+// normalizeSchema rewrites the numeric exclusive bounds and the boolean schemas held by keywords
+// modelled by openapi3.
+func normalizeSchema(schema interface{}, keyword string) interface{} {
+	switch val := schema.(type) {
+	case bool:
+		if !openapi3Keywords[keyword] {
+			return val
+		}
+		return booleanSchema(val)
+	case map[string]interface{}:
+		normalizeExclusiveBound(val, "exclusiveMinimum", "minimum", 1)
+		normalizeExclusiveBound(val, "exclusiveMaximum", "maximum", -1)
+	}
+
+	return schema
+}
+
+// normalizeExclusiveBound converts a numeric exclusive bound to an inclusive bound flagged exclusive,
+// the stricter bound is kept when both are set. sign is 1 for lower bounds and -1 for upper bounds.
+func normalizeExclusiveBound(schema map[string]interface{}, exclusive, inclusive string, sign float64) {
+	bound, ok := schema[exclusive].(json.Number)
+	if !ok {
+		return
+	}
+
+	if current, ok := schema[inclusive].(json.Number); ok {
+		currentValue, err1 := current.Float64()
+		boundValue, err2 := bound.Float64()
+		if err1 == nil && err2 == nil && currentValue*sign > boundValue*sign {
+			delete(schema, exclusive)
+			return
+		}
+	}
+
+	schema[inclusive] = bound
+	schema[exclusive] = true
+}
+
+// restoreSchema converts the inclusive bounds flagged exclusive to numeric exclusive bounds.
+func restoreSchema(schema interface{}, _ string) interface{} {
+	if val, ok := schema.(map[string]interface{}); ok {
+		restoreExclusiveBound(val, "exclusiveMinimum", "minimum")
+		restoreExclusiveBound(val, "exclusiveMaximum", "maximum")
+	}
+
+	return schema
+}
+
+func restoreExclusiveBound(schema map[string]interface{}, exclusive, inclusive string) {
+	isExclusive, ok := schema[exclusive].(bool)
+	if !ok {
+		return
+	}
+
+	bound, hasBound := schema[inclusive]
+	if !isExclusive || !hasBound {
+		delete(schema, exclusive)
+		return
+	}
+
+	schema[exclusive] = bound
+	delete(schema, inclusive)
+}
+
+// downgradeSchema converts a JSON Schema 2020-12 schema to an OpenAPI 3.0 schema.
+func downgradeSchema(schema interface{}, _ string) interface{} {
+	if val, ok := schema.(bool); ok {
+		return booleanSchema(val)
+	}
+
+	s, ok := schema.(map[string]interface{})
+	if !ok {
+		return schema
+	}
+
+	normalizeSchema(s, "")
+
+	if ref, ok := s["$ref"]; ok && len(s) > 1 {
+		delete(s, "$ref")
+		s["allOf"] = append([]interface{}{map[string]interface{}{"$ref": ref}}, listValue(s["allOf"])...)
+	}
+
+	switch types := s["type"].(type) {
+	case string:
+		if types == "null" {
+			delete(s, "type")
+			s["nullable"] = true
+			s["enum"] = []interface{}{nil}
+		}
+	case []interface{}:
+		delete(s, "type")
+
+		var typeSchemas []interface{}
+		for _, typ := range types {
+			if typ == "null" {
+				s["nullable"] = true
+				continue
+			}
+			typeSchemas = append(typeSchemas, map[string]interface{}{"type": typ})
+		}
+
+		switch {
+		case len(typeSchemas) == 0:
+			s["enum"] = []interface{}{nil}
+		case len(typeSchemas) == 1:
+			s["type"] = typeSchemas[0].(map[string]interface{})["type"]
+		case s["anyOf"] == nil:
+			s["anyOf"] = typeSchemas
+		default:
+			s["allOf"] = append(listValue(s["allOf"]), map[string]interface{}{"anyOf": typeSchemas})
+		}
+	}
+
+	if value, ok := s["const"]; ok {
+		s["enum"] = []interface{}{value}
+		delete(s, "const")
+	}
+
+	if examples, ok := s["examples"].([]interface{}); ok {
+		if _, hasExample := s["example"]; !hasExample && len(examples) > 0 {
+			s["example"] = examples[0]
+		}
+		delete(s, "examples")
+	}
+
+	if _, hasFormat := s["format"]; !hasFormat && s["type"] == openapi3.TypeString {
+		switch {
+		case s["contentEncoding"] == "base64":
+			s["format"] = "byte"
+		case s["contentMediaType"] != nil && s["contentEncoding"] == nil:
+			s["format"] = "binary"
+		}
+	}
+
+	for _, keyword := range unsupportedKeywords {
+		delete(s, keyword)
+	}
+
+	if _, hasItems := s["items"]; !hasItems && s["type"] == openapi3.TypeArray {
+		s["items"] = map[string]interface{}{}
+	}
+
+	return s
+}
+
+// booleanSchema returns the schema equivalent to a boolean schema.
+func booleanSchema(valid bool) map[string]interface{} {
+	if valid {
+		return map[string]interface{}{}
+	}
+
+	return map[string]interface{}{"not": map[string]interface{}{}}
+}
+
+// hoistDefs moves the $defs of a component schema to the components, they're named after the
+// schema and the definition, e.g. Pet_Tag. The references to the definitions are added to refs.
+func hoistDefs(schemas map[string]interface{}, name, pointer string, schema interface{}, refs map[string]string) {
+	s, ok := schema.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	defs, ok := s["$defs"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	delete(s, "$defs")
+
+	for _, defName := range sortedKeys(defs) {
+		hoisted := name + "_" + defName
+		defPointer := pointer + "/$defs/" + escapePointer(defName)
+
+		schemas[hoisted] = defs[defName]
+		refs[defPointer] = "#/components/schemas/" + escapePointer(hoisted)
+
+		hoistDefs(schemas, hoisted, defPointer, defs[defName], refs)
+	}
+}
+
+// rewriteRefs replaces the references found in refs.
+func rewriteRefs(node interface{}, refs map[string]string) {
+	switch val := node.(type) {
+	case map[string]interface{}:
+		for key, child := range val {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				if replacement, ok := refs[ref]; ok {
+					val[key] = replacement
+				}
+				continue
+			}
+			rewriteRefs(child, refs)
+		}
+	case []interface{}:
+		for _, child := range val {
+			rewriteRefs(child, refs)
+		}
+	}
+}
+
+// inlinePathItems replaces the references to the path items of the components, which 3.0 doesn't support.
+func inlinePathItems(doc map[string]interface{}, pathItems map[string]interface{}) {
+	for _, key := range []string{"paths", "x-" + keyWebhooks} {
+		items, ok := doc[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for path, item := range items {
+			itemMap, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			ref, _ := itemMap["$ref"].(string)
+			if name := strings.TrimPrefix(ref, "#/components/pathItems/"); name != ref {
+				if resolved, ok := pathItems[unescapePointer(name)]; ok {
+					items[path] = resolved
+				}
+			}
+		}
+	}
+}
+
+// addDefaultResponses adds a default response to the operations without responses, they're optional in 3.1.
+func addDefaultResponses(pathItem interface{}) {
+	item, ok := pathItem.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for method, operation := range item {
+		op, ok := operation.(map[string]interface{})
+		if !ok || !isHTTPMethod(method) {
+			continue
+		}
+
+		if responses, _ := op["responses"].(map[string]interface{}); len(responses) == 0 {
+			op["responses"] = map[string]interface{}{
+				"default": map[string]interface{}{"description": "Default response"},
+			}
+		}
+	}
+}
+
+func isHTTPMethod(key string) bool {
+	switch key {
+	case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+		return true
+	}
+	return false
+}
+
+func listValue(v interface{}) []interface{} {
+	list, _ := v.([]interface{})
+	return list
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func unescapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// decodeJSON decodes JSON keeping the numbers as json.Number.
+func decodeJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("invalid data after top-level value")
+	}
+
+	return nil
+}
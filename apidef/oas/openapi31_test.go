@@ -0,0 +1,159 @@
+package oas
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadOpenAPI31(t *testing.T) (*OAS, []byte) {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/petstore-openapi-3.1.json")
+	require.NoError(t, err)
+
+	doc, err := LoadFromData(openapi3.NewLoader(), data)
+	require.NoError(t, err)
+
+	return &OAS{T: *doc}, data
+}
+
+func TestIsOpenAPI31(t *testing.T) {
+	assert.True(t, IsOpenAPI31("3.1.0"))
+	assert.True(t, IsOpenAPI31("3.1.1"))
+	assert.False(t, IsOpenAPI31("3.0.3"))
+	assert.False(t, IsOpenAPI31(""))
+}
+
+func TestOAS_OpenAPI31(t *testing.T) {
+	t.Run("load", func(t *testing.T) {
+		s, _ := loadOpenAPI31(t)
+		assert.True(t, s.IsOpenAPI31())
+
+		id := s.Paths.Find("/pets/{id}").Parameters[0].Value.Schema.Value
+		require.NotNil(t, id.Min)
+		assert.Equal(t, 0.0, *id.Min)
+		assert.True(t, id.ExclusiveMin)
+
+		pet := s.Components.Schemas["Pet"].Value
+		assert.NotNil(t, pet.Properties["coordinates"].Value.Items.Value.Not)
+		assert.Equal(t, "dog", pet.Properties["kind"].Value.Enum[0])
+		assert.Equal(t, []string{"string", "null"}, pet.Properties["owner"].Value.Type.Slice())
+	})
+
+	t.Run("marshal restores the 3.1 keywords", func(t *testing.T) {
+		var s OAS
+		data, err := os.ReadFile("testdata/petstore-openapi-3.1.json")
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &s))
+
+		out, err := s.MarshalJSON()
+		require.NoError(t, err)
+
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal(out, &doc))
+
+		id := doc["components"].(map[string]interface{})["pathItems"].(map[string]interface{})["Pet"].(map[string]interface{})["parameters"].([]interface{})[0].(map[string]interface{})["schema"]
+		assert.Equal(t, map[string]interface{}{"type": "integer", "exclusiveMinimum": 0.0}, id)
+
+		pet := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})["Pet"].(map[string]interface{})
+		assert.Equal(t, false, pet["unevaluatedProperties"])
+		assert.Contains(t, pet, "$defs")
+		assert.Equal(t, 1.0, pet["properties"].(map[string]interface{})["version"].(map[string]interface{})["const"])
+		assert.Contains(t, doc, "webhooks")
+		assert.Equal(t, "3.1.0", doc["openapi"])
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		s, _ := loadOpenAPI31(t)
+		assert.NoError(t, s.Validate(context.Background()))
+	})
+
+	t.Run("validate document", func(t *testing.T) {
+		_, data := loadOpenAPI31(t)
+		assert.NoError(t, ValidateOASObject(data, "3.1.0"))
+	})
+}
+
+func TestOAS_Downgrade(t *testing.T) {
+	s, _ := loadOpenAPI31(t)
+
+	downgraded, err := s.Downgrade()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultOpenAPI, downgraded.OpenAPI)
+	assert.False(t, downgraded.IsOpenAPI31())
+	assert.NoError(t, downgraded.T.Validate(context.Background()))
+
+	assert.Contains(t, downgraded.Extensions, "x-webhooks")
+	assert.NotContains(t, downgraded.Extensions, "jsonSchemaDialect")
+
+	getPet := downgraded.Paths.Find("/pets/{id}")
+	require.NotNil(t, getPet.Get)
+	assert.NotNil(t, getPet.Get.Responses.Default())
+
+	pet := downgraded.Components.Schemas["Pet"].Value
+	assert.NotContains(t, pet.Extensions, "unevaluatedProperties")
+	assert.NotContains(t, pet.Extensions, "if")
+
+	owner := pet.Properties["owner"].Value
+	assert.Equal(t, []string{"string"}, owner.Type.Slice())
+	assert.True(t, owner.Nullable)
+
+	assert.Equal(t, []interface{}{1.0}, pet.Properties["version"].Value.Enum)
+	assert.Equal(t, "Rex", pet.Properties["name"].Value.Example)
+
+	tag := pet.Properties["tag"]
+	assert.Equal(t, "#/components/schemas/Pet_Tag", tag.Ref)
+	assert.Equal(t, uint64(10), *tag.Value.MaxLength)
+
+	coordinates := pet.Properties["coordinates"].Value
+	assert.NotContains(t, coordinates.Extensions, "prefixItems")
+	assert.NotNil(t, coordinates.Items.Value.Not)
+
+	t.Run("references with siblings", func(t *testing.T) {
+		data, err := DowngradeOpenAPI31([]byte(`{"openapi":"3.1.0","components":{"schemas":{` +
+			`"Tag":{"type":"string"},"Pet":{"properties":{"tag":{"$ref":"#/components/schemas/Tag","description":"tag"}}}}}}`))
+		require.NoError(t, err)
+
+		var doc openapi3.T
+		require.NoError(t, json.Unmarshal(data, &doc))
+
+		tag := doc.Components.Schemas["Pet"].Value.Properties["tag"].Value
+		assert.Equal(t, "tag", tag.Description)
+		require.Len(t, tag.AllOf, 1)
+		assert.Equal(t, "#/components/schemas/Tag", tag.AllOf[0].Ref)
+	})
+
+	t.Run("3.0 documents are copied", func(t *testing.T) {
+		s := &OAS{T: openapi3.T{OpenAPI: "3.0.3", Info: &openapi3.Info{Title: "api", Version: "1"}}}
+
+		downgraded, err := s.Downgrade()
+		require.NoError(t, err)
+		assert.Equal(t, s, downgraded)
+	})
+}
+
+func TestLoadFromData_YAML(t *testing.T) {
+	doc, err := LoadFromData(openapi3.NewLoader(), []byte(`
+openapi: 3.1.0
+info:
+  title: api
+  version: "1"
+components:
+  schemas:
+    Count:
+      type: integer
+      exclusiveMaximum: 10
+`))
+	require.NoError(t, err)
+
+	count := doc.Components.Schemas["Count"].Value
+	assert.Equal(t, 10.0, *count.Max)
+	assert.True(t, count.ExclusiveMax)
+	assert.NotNil(t, doc.Paths)
+}
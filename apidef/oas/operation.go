@@ -871,6 +871,17 @@ type FromOASExamples struct {
 	ContentType string `bson:"contentType,omitempty" json:"contentType,omitempty"`
 	// ExampleName is the default example name among multiple path response examples documented in OAS.
 	ExampleName string `bson:"exampleName,omitempty" json:"exampleName,omitempty"`
+	// Generate synthesizes the response body from the response schema when it has no example, honouring
+	// the formats, enums, bounds and array sizes of the schema. Otherwise, a fixed body is built from the schema.
+	Generate bool `bson:"generate,omitempty" json:"generate,omitempty"`
+	// Seed makes the generated bodies reproducible, the same body is returned for every request.
+	// When not set, a random body is generated for each request.
+	Seed int64 `bson:"seed,omitempty" json:"seed,omitempty"`
+	// Stateful keeps the resources created with POST and PUT on a collection path in memory, and updated with PATCH
+	// or removed with DELETE on its item path. GET on the collection or an item returns the stored resources.
+	// The resources are kept until the API is reloaded, up to 1000 per collection and 10000 per API.
+	// Creating resources past these limits responds with 507 Insufficient Storage.
+	Stateful bool `bson:"stateful,omitempty" json:"stateful,omitempty"`
 }
 
 func (*MockResponse) shouldImport(operation *openapi3.Operation) bool {
@@ -0,0 +1,473 @@
+package oas
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	// ExtensionTykPublicSchema is the media type extension holding the schema of a body as consumers send or
+	// receive it, when the gateway transforms the body from or to the schema of the upstream.
+	ExtensionTykPublicSchema = "x-tyk-public-schema"
+
+	publicErrorSchema             = "TykError"
+	publicResponseUnauthorized    = "TykUnauthorized"
+	publicResponseForbidden       = "TykForbidden"
+	publicResponseTooManyRequests = "TykTooManyRequests"
+
+	hmacSchemeName   = "hmac"
+	customSchemeName = "custom"
+)
+
+// rateLimitHeaders are the headers the gateway adds to the responses of rate limited requests.
+var rateLimitHeaders = []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"}
+
+// PublicViewConfig configures the public view of a Tyk OAS API definition.
+type PublicViewConfig struct {
+	// Servers are the URLs the gateway serves the API on, the servers of the document are kept when empty.
+	Servers openapi3.Servers
+
+	// AllowOperation returns false for the operations a consumer isn't allowed to call, e.g. the operations
+	// out of the allowed URLs of a key or a policy. All the operations are allowed when nil.
+	AllowOperation func(method, path string) bool
+}
+
+// publicView holds what the gateway enforces on the requests to an API.
+type publicView struct {
+	*OAS
+
+	operations       Operations
+	allowList        bool
+	authenticated    bool
+	sessionRateLimit bool
+	apiRateLimit     bool
+}
+
+// PublicView returns the document of the API as consumers see it through the gateway:
+//
+//   - the servers are the gateway URLs,
+//   - the blocked and internal operations, and the ones out of the allow list, are removed,
+//   - the security schemes are the ones the gateway enforces,
+//   - the gateway error responses and the rate limit headers are documented,
+//   - the bodies the gateway transforms are described by their x-tyk-public-schema extension.
+//
+// The Tyk extensions are removed from the returned document, the receiver isn't modified.
+func (s *OAS) PublicView(config PublicViewConfig) (*OAS, error) {
+	clone, err := s.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	view := &publicView{OAS: clone}
+	view.load()
+
+	if len(config.Servers) > 0 {
+		view.Servers = config.Servers
+	}
+
+	view.publicSecurity()
+
+	for path, pathItem := range view.Paths.Map() {
+		for method, operation := range pathItem.Operations() {
+			tykOperation := view.operations[operation.OperationID]
+			if !tykOperation.isPublic(view.allowList) || (config.AllowOperation != nil && !config.AllowOperation(method, path)) {
+				pathItem.SetOperation(method, nil)
+				continue
+			}
+
+			if err := view.publicOperation(operation, tykOperation); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(pathItem.Operations()) == 0 {
+			view.Paths.Delete(path)
+		}
+	}
+
+	for key := range view.Extensions {
+		if strings.HasPrefix(key, "x-tyk-") {
+			delete(view.Extensions, key)
+		}
+	}
+
+	return view.OAS, nil
+}
+
+func (v *publicView) load() {
+	v.operations = v.getTykOperations()
+	for _, operation := range v.operations {
+		if operation != nil && operation.Allow != nil && operation.Allow.Enabled {
+			v.allowList = true
+		}
+	}
+
+	if authentication := v.getTykAuthentication(); authentication != nil {
+		v.authenticated = authentication.Enabled
+	}
+
+	xTykAPIGateway := v.GetTykExtension()
+	if xTykAPIGateway == nil {
+		return
+	}
+
+	skipRateLimit := false
+	if middleware := xTykAPIGateway.Middleware; middleware != nil && middleware.Global != nil {
+		skipRateLimit = middleware.Global.SkipRateLimit
+	}
+
+	v.sessionRateLimit = v.authenticated && !skipRateLimit
+	v.apiRateLimit = xTykAPIGateway.Upstream.RateLimit != nil && xTykAPIGateway.Upstream.RateLimit.Enabled
+}
+
+// publicSecurity limits the security requirements and schemes of the document to the ones the gateway
+// enforces, the Tyk proprietary authentication modes are described as API keys.
+func (v *publicView) publicSecurity() {
+	authentication := v.getTykAuthentication()
+	if !v.authenticated || authentication == nil {
+		v.Security = nil
+		if v.Components != nil {
+			v.Components.SecuritySchemes = nil
+		}
+		return
+	}
+
+	schemes := openapi3.SecuritySchemes{}
+	if v.Components != nil {
+		for name, scheme := range v.Components.SecuritySchemes {
+			if authentication.isSecuritySchemeEnabled(name) {
+				schemes[name] = scheme
+			}
+		}
+	}
+
+	var proprietary []string
+	if authentication.HMAC != nil && authentication.HMAC.Enabled {
+		schemes[hmacSchemeName] = authSourceScheme(authentication.HMAC.AuthSources, "The request is signed with HMAC.")
+		proprietary = append(proprietary, hmacSchemeName)
+	}
+
+	if authentication.Custom != nil && authentication.Custom.Enabled {
+		schemes[customSchemeName] = authSourceScheme(authentication.Custom.AuthSources, "The credentials are checked by a custom authentication plugin.")
+		proprietary = append(proprietary, customSchemeName)
+	}
+
+	var requirements openapi3.SecurityRequirements
+	if authentication.SecurityProcessingMode == SecurityProcessingModeCompliant {
+		candidates := append(openapi3.SecurityRequirements{}, v.Security...)
+		for _, vendorRequirement := range authentication.Security {
+			requirement := openapi3.NewSecurityRequirement()
+			for _, name := range vendorRequirement {
+				requirement[name] = []string{}
+			}
+			candidates = append(candidates, requirement)
+		}
+
+		for _, requirement := range candidates {
+			if len(requirement) > 0 && hasSchemes(schemes, requirement) {
+				requirements = append(requirements, requirement)
+			}
+		}
+	} else {
+		// legacy mode enforces the first requirement, chained with the proprietary modes
+		requirement := openapi3.NewSecurityRequirement()
+		if len(v.Security) > 0 {
+			for name, scopes := range v.Security[0] {
+				if _, ok := schemes[name]; ok {
+					requirement[name] = scopes
+				}
+			}
+		}
+
+		for _, name := range proprietary {
+			requirement[name] = []string{}
+		}
+
+		if len(requirement) > 0 {
+			requirements = append(requirements, requirement)
+		}
+	}
+
+	referenced := openapi3.SecuritySchemes{}
+	for _, requirement := range requirements {
+		for name := range requirement {
+			referenced[name] = schemes[name]
+		}
+	}
+
+	v.Security = requirements
+	if len(referenced) > 0 {
+		v.publicComponents().SecuritySchemes = referenced
+	} else if v.Components != nil {
+		v.Components.SecuritySchemes = nil
+	}
+}
+
+// publicOperation documents the responses of the gateway and the transformed bodies of an operation.
+func (v *publicView) publicOperation(operation *openapi3.Operation, tykOperation *Operation) error {
+	// the gateway doesn't apply the security requirements of the operations
+	operation.Security = nil
+
+	authenticated := v.authenticated
+	if authenticated && tykOperation.ignoresAuthentication() {
+		operation.Security = &openapi3.SecurityRequirements{}
+		authenticated = false
+	}
+
+	rateLimited := v.apiRateLimit || (authenticated && v.sessionRateLimit) || tykOperation.hasRateLimit()
+
+	if requestBody := operation.RequestBody; requestBody != nil && requestBody.Value != nil {
+		content, changed, err := publicContent(requestBody.Value.Content, tykOperation.transformsRequestBody())
+		if err != nil {
+			return err
+		}
+
+		if changed {
+			publicRequestBody := *requestBody.Value
+			publicRequestBody.Content = content
+			operation.RequestBody = &openapi3.RequestBodyRef{Value: &publicRequestBody}
+		}
+	}
+
+	if operation.Responses == nil {
+		operation.Responses = openapi3.NewResponsesWithCapacity(0)
+	}
+
+	for code, response := range operation.Responses.Map() {
+		if response == nil || response.Value == nil {
+			continue
+		}
+
+		content, changed, err := publicContent(response.Value.Content, tykOperation.transformsResponseBody())
+		if err != nil {
+			return err
+		}
+
+		if !changed && !rateLimited {
+			continue
+		}
+
+		publicResponse := *response.Value
+		publicResponse.Content = content
+		if rateLimited {
+			publicResponse.Headers = v.withRateLimitHeaders(publicResponse.Headers)
+		}
+
+		operation.Responses.Set(code, &openapi3.ResponseRef{Value: &publicResponse})
+	}
+
+	if authenticated {
+		v.addResponse(operation, http.StatusUnauthorized, publicResponseUnauthorized, "The request doesn't have valid credentials.", false)
+		v.addResponse(operation, http.StatusForbidden, publicResponseForbidden, "The credentials don't grant access to the operation, or the quota is exceeded.", false)
+	}
+
+	if rateLimited {
+		v.addResponse(operation, http.StatusTooManyRequests, publicResponseTooManyRequests, "The rate limit is exceeded.", true)
+	}
+
+	return nil
+}
+
+// addResponse adds a gateway error response to an operation, unless the operation already documents the status code.
+func (v *publicView) addResponse(operation *openapi3.Operation, code int, name, description string, rateLimited bool) {
+	status := strconv.Itoa(code)
+	if operation.Responses.Value(status) != nil {
+		return
+	}
+
+	components := v.publicComponents()
+	if components.Schemas == nil {
+		components.Schemas = openapi3.Schemas{}
+	}
+
+	if components.Schemas[publicErrorSchema] == nil {
+		components.Schemas[publicErrorSchema] = &openapi3.SchemaRef{
+			Value: openapi3.NewObjectSchema().WithProperty("error", openapi3.NewStringSchema()),
+		}
+	}
+
+	if components.Responses == nil {
+		components.Responses = openapi3.ResponseBodies{}
+	}
+
+	response := components.Responses[name]
+	if response == nil {
+		value := openapi3.NewResponse().WithDescription(description).WithJSONSchemaRef(&openapi3.SchemaRef{
+			Ref:   "#/components/schemas/" + publicErrorSchema,
+			Value: components.Schemas[publicErrorSchema].Value,
+		})
+
+		if rateLimited {
+			value.Headers = v.withRateLimitHeaders(nil)
+		}
+
+		response = &openapi3.ResponseRef{Value: value}
+		components.Responses[name] = response
+	}
+
+	operation.Responses.Set(status, &openapi3.ResponseRef{Ref: "#/components/responses/" + name, Value: response.Value})
+}
+
+// withRateLimitHeaders returns a copy of the response headers with the rate limit headers.
+func (v *publicView) withRateLimitHeaders(headers openapi3.Headers) openapi3.Headers {
+	components := v.publicComponents()
+	if components.Headers == nil {
+		components.Headers = openapi3.Headers{}
+	}
+
+	publicHeaders := maps.Clone(headers)
+	if publicHeaders == nil {
+		publicHeaders = openapi3.Headers{}
+	}
+
+	for _, name := range rateLimitHeaders {
+		if components.Headers[name] == nil {
+			components.Headers[name] = &openapi3.HeaderRef{Value: &openapi3.Header{
+				Parameter: openapi3.Parameter{Schema: openapi3.NewIntegerSchema().NewRef()},
+			}}
+		}
+
+		if _, ok := publicHeaders[name]; !ok {
+			publicHeaders[name] = &openapi3.HeaderRef{Ref: "#/components/headers/" + name, Value: components.Headers[name].Value}
+		}
+	}
+
+	return publicHeaders
+}
+
+func (v *publicView) publicComponents() *openapi3.Components {
+	if v.Components == nil {
+		v.Components = &openapi3.Components{}
+	}
+
+	return v.Components
+}
+
+// publicContent returns the content with the schemas of the bodies consumers send or receive: the schema of the
+// x-tyk-public-schema extension, or no schema when the gateway transforms the body. It returns false if the
+// content is unchanged.
+func publicContent(content openapi3.Content, transformed bool) (openapi3.Content, bool, error) {
+	changed := false
+	public := make(openapi3.Content, len(content))
+
+	for mediaType, media := range content {
+		public[mediaType] = media
+		if media == nil {
+			continue
+		}
+
+		schema, ok := media.Extensions[ExtensionTykPublicSchema]
+		if !ok && !transformed {
+			continue
+		}
+
+		publicMedia := *media
+		publicMedia.Extensions = maps.Clone(media.Extensions)
+		delete(publicMedia.Extensions, ExtensionTykPublicSchema)
+
+		if transformed {
+			publicMedia.Schema = nil
+			publicMedia.Example = nil
+			publicMedia.Examples = nil
+		}
+
+		if ok {
+			data, err := json.Marshal(schema)
+			if err != nil {
+				return nil, false, err
+			}
+
+			publicMedia.Schema = &openapi3.SchemaRef{}
+			if err := publicMedia.Schema.UnmarshalJSON(data); err != nil {
+				return nil, false, err
+			}
+		}
+
+		public[mediaType] = &publicMedia
+		changed = true
+	}
+
+	return public, changed, nil
+}
+
+// authSourceScheme describes a Tyk proprietary authentication mode as an API key sent in a header.
+func authSourceScheme(authSources AuthSources, description string) *openapi3.SecuritySchemeRef {
+	name := defaultAuthSourceName
+	if authSources.Header != nil && authSources.Header.Name != "" {
+		name = authSources.Header.Name
+	}
+
+	return &openapi3.SecuritySchemeRef{Value: &openapi3.SecurityScheme{
+		Type:        typeAPIKey,
+		In:          header,
+		Name:        name,
+		Description: description,
+	}}
+}
+
+func hasSchemes(schemes openapi3.SecuritySchemes, requirement openapi3.SecurityRequirement) bool {
+	for name := range requirement {
+		if _, ok := schemes[name]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isSecuritySchemeEnabled returns true if the gateway enforces the security scheme.
+func (a *Authentication) isSecuritySchemeEnabled(name string) bool {
+	securityScheme, ok := a.SecuritySchemes[name]
+	if !ok {
+		return false
+	}
+
+	data, err := json.Marshal(securityScheme)
+	if err != nil {
+		return false
+	}
+
+	var scheme struct {
+		Enabled *bool `json:"enabled"`
+	}
+
+	if err := json.Unmarshal(data, &scheme); err != nil {
+		return false
+	}
+
+	return scheme.Enabled != nil && *scheme.Enabled
+}
+
+// isPublic returns false if the gateway doesn't serve the operation to consumers.
+func (o *Operation) isPublic(allowList bool) bool {
+	if o == nil {
+		return !allowList
+	}
+
+	if (o.Block != nil && o.Block.Enabled) || (o.Internal != nil && o.Internal.Enabled) {
+		return false
+	}
+
+	return !allowList || (o.Allow != nil && o.Allow.Enabled)
+}
+
+func (o *Operation) ignoresAuthentication() bool {
+	return o != nil && o.IgnoreAuthentication != nil && o.IgnoreAuthentication.Enabled
+}
+
+func (o *Operation) hasRateLimit() bool {
+	return o != nil && o.RateLimit != nil && o.RateLimit.Enabled
+}
+
+func (o *Operation) transformsRequestBody() bool {
+	return o != nil && o.TransformRequestBody != nil && o.TransformRequestBody.Enabled
+}
+
+func (o *Operation) transformsResponseBody() bool {
+	return o != nil && o.TransformResponseBody != nil && o.TransformResponseBody.Enabled
+}
@@ -0,0 +1,188 @@
+package oas
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPublicViewOAS = `{
+  "openapi": "3.0.3",
+  "info": {"title": "pets", "version": "1.0.0"},
+  "servers": [{"url": "http://upstream.internal"}],
+  "security": [{"api_key": []}],
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "listPets",
+        "responses": {"200": {"description": "OK"}}
+      },
+      "post": {
+        "operationId": "createPet",
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {"type": "object", "required": ["pet_name"]},
+              "x-tyk-public-schema": {"type": "object", "required": ["name"]}
+            }
+          }
+        },
+        "responses": {"201": {"description": "Created"}}
+      }
+    },
+    "/pets/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+      "get": {
+        "operationId": "getPet",
+        "security": [{"jwt": []}],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {"application/json": {"schema": {"type": "object"}}}
+          }
+        }
+      },
+      "delete": {
+        "operationId": "deletePet",
+        "responses": {"204": {"description": "Deleted"}}
+      }
+    },
+    "/health": {
+      "get": {
+        "operationId": "health",
+        "responses": {"200": {"description": "OK"}}
+      }
+    }
+  },
+  "components": {
+    "securitySchemes": {
+      "api_key": {"type": "apiKey", "in": "header", "name": "X-API-Key"},
+      "jwt": {"type": "http", "scheme": "bearer", "bearerFormat": "JWT"}
+    }
+  }
+}`
+
+func testPublicViewAPI(t *testing.T, authentication *Authentication, operations Operations) *OAS {
+	t.Helper()
+
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(testPublicViewOAS))
+	require.NoError(t, err)
+
+	s := &OAS{T: *doc}
+	s.SetTykExtension(&XTykAPIGateway{
+		Server:     Server{Authentication: authentication},
+		Middleware: &Middleware{Operations: operations},
+	})
+
+	return s
+}
+
+func TestOAS_PublicView(t *testing.T) {
+	enabled := true
+
+	authentication := &Authentication{
+		Enabled: true,
+		SecuritySchemes: SecuritySchemes{
+			"api_key": &Token{Enabled: &enabled},
+			"jwt":     &JWT{Enabled: false},
+		},
+		HMAC: &HMAC{Enabled: true},
+	}
+
+	operations := Operations{
+		"createPet": {TransformRequestBody: &TransformBody{Enabled: true}},
+		"getPet": {
+			IgnoreAuthentication:  &Allowance{Enabled: true},
+			TransformResponseBody: &TransformBody{Enabled: true},
+		},
+		"deletePet": {Block: &Allowance{Enabled: true}},
+		"health":    {Internal: &Internal{Enabled: true}},
+	}
+
+	t.Run("protected API", func(t *testing.T) {
+		s := testPublicViewAPI(t, authentication, operations)
+
+		view, err := s.PublicView(PublicViewConfig{
+			Servers: openapi3.Servers{{URL: "https://gateway.example.com/pets-api"}},
+		})
+		require.NoError(t, err)
+
+		assert.NotNil(t, s.GetTykExtension())
+		assert.Nil(t, view.GetTykExtension())
+		assert.Equal(t, "http://upstream.internal", s.Servers[0].URL)
+		assert.Equal(t, "https://gateway.example.com/pets-api", view.Servers[0].URL)
+
+		assert.Nil(t, view.Paths.Value("/health"))
+		assert.Nil(t, view.Paths.Value("/pets/{id}").Delete)
+
+		assert.Equal(t, openapi3.SecurityRequirements{{"api_key": []string{}, "hmac": []string{}}}, view.Security)
+		assert.Len(t, view.Components.SecuritySchemes, 2)
+		assert.Equal(t, "Authorization", view.Components.SecuritySchemes["hmac"].Value.Name)
+
+		listPets := view.Paths.Value("/pets").Get
+		assert.Nil(t, listPets.Security)
+		assert.Equal(t, "#/components/responses/TykUnauthorized", listPets.Responses.Status(http.StatusUnauthorized).Ref)
+		assert.Equal(t, "#/components/responses/TykForbidden", listPets.Responses.Status(http.StatusForbidden).Ref)
+		assert.Equal(t, "#/components/responses/TykTooManyRequests", listPets.Responses.Status(http.StatusTooManyRequests).Ref)
+		assert.Contains(t, listPets.Responses.Status(http.StatusOK).Value.Headers, "X-RateLimit-Remaining")
+
+		createPet := view.Paths.Value("/pets").Post
+		media := createPet.RequestBody.Value.Content.Get("application/json")
+		assert.Equal(t, []string{"name"}, media.Schema.Value.Required)
+		assert.NotContains(t, media.Extensions, ExtensionTykPublicSchema)
+		assert.Equal(t, []string{"pet_name"}, s.Paths.Value("/pets").Post.RequestBody.Value.Content.Get("application/json").Schema.Value.Required)
+
+		getPet := view.Paths.Value("/pets/{id}").Get
+		require.NotNil(t, getPet.Security)
+		assert.Empty(t, *getPet.Security)
+		assert.Nil(t, getPet.Responses.Status(http.StatusUnauthorized))
+		assert.Nil(t, getPet.Responses.Status(http.StatusTooManyRequests))
+		assert.Nil(t, getPet.Responses.Status(http.StatusOK).Value.Content.Get("application/json").Schema)
+
+		assert.NoError(t, view.Validate(t.Context()))
+	})
+
+	t.Run("allowed operations", func(t *testing.T) {
+		s := testPublicViewAPI(t, authentication, operations)
+
+		view, err := s.PublicView(PublicViewConfig{
+			AllowOperation: func(method, path string) bool {
+				return method == http.MethodGet && path == "/pets"
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "http://upstream.internal", view.Servers[0].URL)
+		assert.Equal(t, 1, view.Paths.Len())
+		assert.NotNil(t, view.Paths.Value("/pets").Get)
+		assert.Nil(t, view.Paths.Value("/pets").Post)
+	})
+
+	t.Run("allow list", func(t *testing.T) {
+		s := testPublicViewAPI(t, authentication, Operations{"createPet": {Allow: &Allowance{Enabled: true}}})
+
+		view, err := s.PublicView(PublicViewConfig{})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, view.Paths.Len())
+		assert.NotNil(t, view.Paths.Value("/pets").Post)
+	})
+
+	t.Run("keyless API", func(t *testing.T) {
+		s := testPublicViewAPI(t, &Authentication{Enabled: false}, nil)
+
+		view, err := s.PublicView(PublicViewConfig{})
+		require.NoError(t, err)
+
+		assert.Nil(t, view.Security)
+		assert.Empty(t, view.Components.SecuritySchemes)
+
+		listPets := view.Paths.Value("/pets").Get
+		assert.Nil(t, listPets.Responses.Status(http.StatusUnauthorized))
+		assert.Nil(t, listPets.Responses.Status(http.StatusTooManyRequests))
+		assert.NotContains(t, listPets.Responses.Status(http.StatusOK).Value.Headers, "X-RateLimit-Limit")
+	})
+}
@@ -0,0 +1,190 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/TykTechnologies/tyk/internal/service/jsonschema"
+)
+
+const (
+	// requestBodyDocumentURL is the URL of the compiled document, the references are resolved relative to it.
+	requestBodyDocumentURL = "tyk://oas/openapi.json"
+
+	// keyRequestBodySchemas holds the request body schemas in the compiled document.
+	keyRequestBodySchemas = "x-tyk-request-body-schemas"
+
+	// maxRefDepth is the maximum number of references followed to resolve a path item or a request body.
+	maxRefDepth = 10
+)
+
+var errExternalReference = errors.New("external references aren't supported")
+
+// RequestBodyValidator validates the JSON request bodies of an OpenAPI 3.1 document against their
+// schemas with JSON Schema 2020-12, which openapi3 only partially supports.
+type RequestBodyValidator struct {
+	schemas map[string]*jsonschema.Schema
+}
+
+// NewRequestBodyValidator compiles the schemas of the JSON request bodies of the operations.
+func NewRequestBodyValidator(s *OAS) (*RequestBodyValidator, error) {
+	data, err := s.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	if err := decodeJSON(data, &doc); err != nil {
+		return nil, err
+	}
+
+	bodies := map[string]interface{}{}
+	ids := map[string]string{}
+
+	paths, _ := doc["paths"].(map[string]interface{})
+	for _, path := range sortedKeys(paths) {
+		pathItem, _ := resolveLocalRef(doc, paths[path]).(map[string]interface{})
+		for _, method := range sortedKeys(pathItem) {
+			operation, ok := pathItem[method].(map[string]interface{})
+			if !ok || !isHTTPMethod(method) {
+				continue
+			}
+
+			requestBody, _ := resolveLocalRef(doc, operation["requestBody"]).(map[string]interface{})
+			content, _ := requestBody["content"].(map[string]interface{})
+			for _, mediaType := range sortedKeys(content) {
+				media, _ := content[mediaType].(map[string]interface{})
+				schema, ok := media["schema"]
+				if !ok || !isJSONMediaType(mediaType) {
+					continue
+				}
+
+				id := strconv.Itoa(len(bodies))
+				bodies[id] = schema
+				ids[requestBodyKey(path, method, mediaType)] = id
+			}
+		}
+	}
+
+	validator := &RequestBodyValidator{schemas: map[string]*jsonschema.Schema{}}
+	if len(bodies) == 0 {
+		return validator, nil
+	}
+
+	doc[keyRequestBodySchemas] = bodies
+	if data, err = json.Marshal(doc); err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	compiler.AssertFormat = true
+	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("%w: %s", errExternalReference, url)
+	}
+
+	if err := compiler.AddResource(requestBodyDocumentURL, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	for _, key := range sortedKeys(ids) {
+		schema, err := compiler.Compile(requestBodyDocumentURL + "#/" + keyRequestBodySchemas + "/" + ids[key])
+		if err != nil {
+			return nil, fmt.Errorf("request body schema of %s: %w", key, err)
+		}
+		validator.schemas[key] = schema
+	}
+
+	return validator, nil
+}
+
+// Validate validates the body of a request to an operation, path is the path template of the operation
+// and mediaType the key of the request body content. It returns false if the body isn't validated.
+func (v *RequestBodyValidator) Validate(path, method, mediaType string, body []byte) (bool, error) {
+	schema, ok := v.schemas[requestBodyKey(path, method, mediaType)]
+	if !ok {
+		return false, nil
+	}
+
+	var value interface{}
+	if err := decodeJSON(body, &value); err != nil {
+		return true, fmt.Errorf("failed to decode request body: %w", err)
+	}
+
+	err := schema.Validate(value)
+	if err == nil {
+		return true, nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return true, err
+	}
+
+	return true, fmt.Errorf("doesn't match schema: %s", strings.Join(leafErrors(validationErr), "; "))
+}
+
+// leafErrors returns the messages of the errors causing a validation error, prefixed by their location.
+func leafErrors(err *jsonschema.ValidationError) []string {
+	if len(err.Causes) == 0 {
+		location := err.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		return []string{location + ": " + err.Message}
+	}
+
+	var messages []string
+	for _, cause := range err.Causes {
+		messages = append(messages, leafErrors(cause)...)
+	}
+
+	return messages
+}
+
+func requestBodyKey(path, method, mediaType string) string {
+	return strings.ToUpper(method) + " " + path + " " + mediaType
+}
+
+func isJSONMediaType(mediaType string) bool {
+	parsed, _, err := mime.ParseMediaType(mediaType)
+	return err == nil && (parsed == "application/json" || strings.HasSuffix(parsed, "+json"))
+}
+
+// resolveLocalRef follows the local references of a node of the document.
+func resolveLocalRef(doc map[string]interface{}, node interface{}) interface{} {
+	for i := 0; i < maxRefDepth; i++ {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return node
+		}
+
+		ref, ok := m["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return node
+		}
+
+		node = lookupPointer(doc, strings.TrimPrefix(ref, "#/"))
+	}
+
+	return node
+}
+
+func lookupPointer(doc map[string]interface{}, pointer string) interface{} {
+	var node interface{} = doc
+	for _, token := range strings.Split(pointer, "/") {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		node = m[unescapePointer(token)]
+	}
+
+	return node
+}
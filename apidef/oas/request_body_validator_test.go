@@ -0,0 +1,126 @@
+package oas
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestBodyValidator(t *testing.T) {
+	s, _ := loadOpenAPI31(t)
+
+	validator, err := NewRequestBodyValidator(s)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		path      string
+		method    string
+		mediaType string
+		body      string
+		validated bool
+		errMsg    string
+	}{
+		{
+			name:      "valid",
+			path:      "/pets",
+			method:    http.MethodPost,
+			mediaType: "application/json",
+			body:      `{"name":"Rex","kind":"dog","breed":"Collie","owner":null,"tag":"good","coordinates":[1.5,2],"version":1}`,
+			validated: true,
+		},
+		{
+			name:      "if then",
+			path:      "/pets",
+			method:    http.MethodPost,
+			mediaType: "application/json",
+			body:      `{"name":"Rex","kind":"dog"}`,
+			validated: true,
+			errMsg:    "missing properties: 'breed'",
+		},
+		{
+			name:      "unevaluated properties",
+			path:      "/pets",
+			method:    http.MethodPost,
+			mediaType: "application/json",
+			body:      `{"name":"Tom","kind":"cat","color":"black"}`,
+			validated: true,
+			errMsg:    "not allowed",
+		},
+		{
+			name:      "prefix items",
+			path:      "/pets",
+			method:    http.MethodPost,
+			mediaType: "application/json",
+			body:      `{"name":"Tom","kind":"cat","coordinates":[1,2,3]}`,
+			validated: true,
+			errMsg:    "/coordinates/2",
+		},
+		{
+			name:      "const",
+			path:      "/pets",
+			method:    http.MethodPost,
+			mediaType: "application/json",
+			body:      `{"name":"Tom","kind":"cat","version":2}`,
+			validated: true,
+			errMsg:    "/version",
+		},
+		{
+			name:      "defs",
+			path:      "/pets",
+			method:    http.MethodPost,
+			mediaType: "application/json",
+			body:      `{"name":"Tom","kind":"cat","tag":"too long for a tag"}`,
+			validated: true,
+			errMsg:    "/tag",
+		},
+		{
+			name:      "invalid json",
+			path:      "/pets",
+			method:    http.MethodPost,
+			mediaType: "application/json",
+			body:      `{"name":`,
+			validated: true,
+			errMsg:    "failed to decode request body",
+		},
+		{
+			name:      "unknown operation",
+			path:      "/pets/{id}",
+			method:    http.MethodGet,
+			mediaType: "application/json",
+			body:      `{}`,
+		},
+		{
+			name:      "unknown media type",
+			path:      "/pets",
+			method:    http.MethodPost,
+			mediaType: "application/xml",
+			body:      `<pet/>`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validated, err := validator.Validate(tc.path, tc.method, tc.mediaType, []byte(tc.body))
+			assert.Equal(t, tc.validated, validated)
+
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errMsg)
+		})
+	}
+
+	t.Run("external references", func(t *testing.T) {
+		s, _ := loadOpenAPI31(t)
+		s.Components.Schemas["Pet"].Value.Properties["breed"].Ref = "https://example.com/breed.json"
+
+		_, err := NewRequestBodyValidator(s)
+		assert.ErrorIs(t, err, errExternalReference)
+	})
+}
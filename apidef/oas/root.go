@@ -137,18 +137,41 @@ type State struct {
 	//
 	// Tyk classic API definition: `internal`
 	Internal bool `bson:"internal,omitempty" json:"internal,omitempty"`
+	// Schedule configures when the API is active and its recurring maintenance windows.
+	//
+	// Tyk classic API definition: `schedule`
+	Schedule *Schedule `bson:"schedule,omitempty" json:"schedule,omitempty"`
 }
 
 // Fill fills *State from apidef.APIDefinition.
 func (s *State) Fill(api apidef.APIDefinition) {
 	s.Active = api.Active
 	s.Internal = api.Internal
+
+	if s.Schedule == nil {
+		s.Schedule = &Schedule{}
+	}
+
+	s.Schedule.Fill(api)
+
+	if ShouldOmit(s.Schedule) {
+		s.Schedule = nil
+	}
 }
 
 // ExtractTo extracts *State to *apidef.APIDefinition.
 func (s *State) ExtractTo(api *apidef.APIDefinition) {
 	api.Active = s.Active
 	api.Internal = s.Internal
+
+	if s.Schedule == nil {
+		s.Schedule = &Schedule{}
+		defer func() {
+			s.Schedule = nil
+		}()
+	}
+
+	s.Schedule.ExtractTo(api)
 }
 
 // Versioning holds configuration for API versioning.
@@ -0,0 +1,120 @@
+package oas
+
+import (
+	"github.com/TykTechnologies/tyk/apidef"
+)
+
+// Schedule configures when the API is active. Outside of the period between `start` and `end`
+// and during maintenance windows the API replies with the maintenance response.
+//
+// Tyk classic API definition: `schedule`.
+type Schedule struct {
+	// Enabled activates the schedule.
+	//
+	// Tyk classic API definition: `schedule.enabled`.
+	Enabled bool `bson:"enabled" json:"enabled"` // required
+	// Start is the time the API becomes active, in RFC3339 format.
+	//
+	// Tyk classic API definition: `schedule.start`.
+	Start string `bson:"start,omitempty" json:"start,omitempty"`
+	// End is the time the API stops being active, in RFC3339 format.
+	//
+	// Tyk classic API definition: `schedule.end`.
+	End string `bson:"end,omitempty" json:"end,omitempty"`
+	// Timezone is the IANA name of the timezone maintenance windows are evaluated in, UTC when empty.
+	//
+	// Tyk classic API definition: `schedule.timezone`.
+	Timezone string `bson:"timezone,omitempty" json:"timezone,omitempty"`
+	// MaintenanceWindows are the recurring periods the API is inactive.
+	//
+	// Tyk classic API definition: `schedule.maintenance_windows`.
+	MaintenanceWindows []MaintenanceWindow `bson:"maintenanceWindows,omitempty" json:"maintenanceWindows,omitempty"`
+	// MaintenanceResponse is the response of the inactive API, 503 with a JSON error when unset.
+	//
+	// Tyk classic API definition: `schedule.maintenance_response`.
+	MaintenanceResponse *MaintenanceResponse `bson:"maintenanceResponse,omitempty" json:"maintenanceResponse,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period of inactivity.
+type MaintenanceWindow struct {
+	// Cron is the standard five field cron expression of the window start, e.g. `0 2 * * *` for 2am every day.
+	Cron string `bson:"cron" json:"cron"`
+	// Duration is the length of the window, e.g. `2h`.
+	Duration ReadableDuration `bson:"duration" json:"duration"`
+}
+
+// MaintenanceResponse is the response of an inactive API.
+type MaintenanceResponse struct {
+	// Code is the HTTP status code, 503 by default.
+	Code int `bson:"code,omitempty" json:"code,omitempty"`
+	// Body is the response body.
+	Body string `bson:"body,omitempty" json:"body,omitempty"`
+	// Headers are the response headers.
+	Headers Headers `bson:"headers,omitempty" json:"headers,omitempty"`
+}
+
+// Fill fills *Schedule from apidef.APIDefinition.
+func (s *Schedule) Fill(api apidef.APIDefinition) {
+	*s = Schedule{}
+	if api.Schedule == nil {
+		return
+	}
+
+	s.Enabled = api.Schedule.Enabled
+	s.Start = api.Schedule.Start
+	s.End = api.Schedule.End
+	s.Timezone = api.Schedule.Timezone
+
+	for _, window := range api.Schedule.MaintenanceWindows {
+		s.MaintenanceWindows = append(s.MaintenanceWindows, MaintenanceWindow{
+			Cron:     window.Cron,
+			Duration: window.Duration,
+		})
+	}
+
+	if resp := api.Schedule.MaintenanceResponse; resp != nil {
+		s.MaintenanceResponse = &MaintenanceResponse{
+			Code: resp.Code,
+			Body: resp.Body,
+		}
+
+		if len(resp.Headers) > 0 {
+			s.MaintenanceResponse.Headers = NewHeaders(resp.Headers)
+		}
+	}
+}
+
+// ExtractTo extracts *Schedule into *apidef.APIDefinition.
+func (s *Schedule) ExtractTo(api *apidef.APIDefinition) {
+	if ShouldOmit(s) {
+		api.Schedule = nil
+		return
+	}
+
+	schedule := &apidef.ActivationSchedule{
+		Enabled:  s.Enabled,
+		Start:    s.Start,
+		End:      s.End,
+		Timezone: s.Timezone,
+	}
+
+	for _, window := range s.MaintenanceWindows {
+		schedule.MaintenanceWindows = append(schedule.MaintenanceWindows, apidef.MaintenanceWindow{
+			Cron:     window.Cron,
+			Duration: window.Duration,
+		})
+	}
+
+	if resp := s.MaintenanceResponse; resp != nil {
+		schedule.MaintenanceResponse = &apidef.MaintenanceResponse{
+			Code: resp.Code,
+			Body: resp.Body,
+		}
+
+		if len(resp.Headers) > 0 {
+			schedule.MaintenanceResponse.Headers = resp.Headers.Map()
+		}
+	}
+
+	api.Schedule = schedule
+}
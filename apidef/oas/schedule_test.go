@@ -0,0 +1,51 @@
+package oas
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TykTechnologies/tyk/apidef"
+)
+
+func TestSchedule(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		var emptySchedule Schedule
+
+		var convertedAPI apidef.APIDefinition
+		emptySchedule.ExtractTo(&convertedAPI)
+		assert.Nil(t, convertedAPI.Schedule)
+
+		var resultSchedule Schedule
+		resultSchedule.Fill(convertedAPI)
+
+		assert.Equal(t, emptySchedule, resultSchedule)
+	})
+
+	t.Run("filled", func(t *testing.T) {
+		schedule := Schedule{
+			Enabled:  true,
+			Start:    "2024-01-01T00:00:00Z",
+			End:      "2024-06-01T00:00:00Z",
+			Timezone: "Europe/London",
+			MaintenanceWindows: []MaintenanceWindow{
+				{Cron: "0 2 * * 0", Duration: ReadableDuration(2 * time.Hour)},
+			},
+			MaintenanceResponse: &MaintenanceResponse{
+				Code:    503,
+				Body:    `{"message":"down for maintenance"}`,
+				Headers: Headers{{Name: "Retry-After", Value: "7200"}},
+			},
+		}
+
+		var convertedAPI apidef.APIDefinition
+		schedule.ExtractTo(&convertedAPI)
+		assert.Equal(t, map[string]string{"Retry-After": "7200"}, convertedAPI.Schedule.MaintenanceResponse.Headers)
+
+		var resultSchedule Schedule
+		resultSchedule.Fill(convertedAPI)
+
+		assert.Equal(t, schedule, resultSchedule)
+	})
+}
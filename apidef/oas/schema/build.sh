@@ -16,4 +16,5 @@ cat $input \
 	| jq -r '.definitions["X-Tyk-Webhook-Without-ID"].additionalProperties = true' \
 	| jq -r '.definitions["X-Tyk-JSVMEvent"].additionalProperties = true' \
 	| jq -r '.definitions["X-Tyk-LogEvent"].additionalProperties = true' \
+	| jq -r '.definitions["X-Tyk-CloudEventsEvent"].additionalProperties = true' \
 	> $output
@@ -872,6 +872,34 @@ func TestRegenerateServers(t *testing.T) {
 		assert.NotContains(t, urls, "http://edge1.example.com/api", "Should remove edge endpoint URL 1")
 		assert.NotContains(t, urls, "http://edge2.example.com/api", "Should remove edge endpoint URL 2")
 	})
+
+	t.Run("OpenAPI 3.1 document", func(t *testing.T) {
+		t.Parallel()
+
+		newAPI := &apidef.APIDefinition{
+			APIID: "petstore",
+			Proxy: apidef.ProxyConfig{
+				ListenPath: "/petstore",
+			},
+		}
+
+		config := ServerRegenerationConfig{
+			Protocol:    "http://",
+			DefaultHost: "localhost:8080",
+		}
+
+		oas, _ := loadOpenAPI31(t)
+
+		err := oas.RegenerateServers(newAPI, nil, nil, nil, config, "")
+		require.NoError(t, err)
+		require.Len(t, oas.Servers, 2)
+		assert.Equal(t, "http://localhost:8080/petstore", oas.Servers[0].URL)
+
+		data, err := oas.MarshalJSON()
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"openapi":"3.1.0"`)
+		assert.Contains(t, string(data), `"exclusiveMinimum":0`)
+	})
 }
 
 func TestGenerateTykServersBaseAPIWithVersioning(t *testing.T) {
@@ -60,6 +60,10 @@ type Upstream struct {
 	// Proxy contains the configuration for an internal proxy.
 	// Tyk classic API definition: `proxy.proxy_url`
 	Proxy *Proxy `bson:"proxy,omitempty" json:"proxy,omitempty"`
+
+	// GRPCTranscoding contains the configuration for transcoding HTTP/JSON requests to a gRPC upstream.
+	// Tyk classic API definition: `grpc_transcoding`
+	GRPCTranscoding *GRPCTranscoding `bson:"grpcTranscoding,omitempty" json:"grpcTranscoding,omitempty"`
 }
 
 // Fill fills *Upstream from apidef.APIDefinition.
@@ -136,6 +140,14 @@ func (u *Upstream) Fill(api apidef.APIDefinition) {
 		u.Proxy = nil
 	}
 
+	if u.GRPCTranscoding == nil {
+		u.GRPCTranscoding = &GRPCTranscoding{}
+	}
+	u.GRPCTranscoding.Fill(api)
+	if ShouldOmit(u.GRPCTranscoding) {
+		u.GRPCTranscoding = nil
+	}
+
 	u.fillLoadBalancing(api)
 	u.fillPreserveHostHeader(api)
 	u.fillPreserveTrailingSlash(api)
@@ -240,6 +252,14 @@ func (u *Upstream) ExtractTo(api *apidef.APIDefinition) {
 	}
 	u.Proxy.ExtractTo(api)
 
+	if u.GRPCTranscoding == nil {
+		u.GRPCTranscoding = &GRPCTranscoding{}
+		defer func() {
+			u.GRPCTranscoding = nil
+		}()
+	}
+	u.GRPCTranscoding.ExtractTo(api)
+
 	u.preserveHostHeaderExtractTo(api)
 	u.preserveTrailingSlashExtractTo(api)
 }
@@ -216,6 +216,14 @@ func findDefaultVersion(rawVersions []string) string {
 }
 
 func setDefaultVersion() {
+	// documents without a version are Tyk OAS API definitions, validated against their OpenAPI version.
+	if minor, err := getMinorVersion(DefaultOpenAPI); err == nil {
+		if _, ok := oasJSONSchemas[minor]; ok {
+			defaultVersion = minor
+			return
+		}
+	}
+
 	var versions []string
 	for k := range oasJSONSchemas {
 		versions = append(versions, k)
@@ -0,0 +1,182 @@
+package apidef
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	tyktime "github.com/TykTechnologies/tyk/internal/time"
+)
+
+// localTimeLayout is accepted for the start and end times without an offset, they are read in the schedule timezone.
+const localTimeLayout = "2006-01-02T15:04:05"
+
+// ActivationSchedule controls when an API or a policy is active.
+//
+// The resource is active from `start` until `end`, both optional, except during the
+// recurring maintenance windows. The gateway checks schedules periodically and emits
+// one of the following events when a resource changes state:
+//
+// - `APIScheduleActivated` and `APIScheduleDeactivated` for APIs
+// - `PolicyScheduleActivated` and `PolicyScheduleDeactivated` for policies
+//
+// An inactive API replies with the maintenance response. The keys of an inactive
+// policy get the rules of the fallback policy or, if there is none, are denied access.
+type ActivationSchedule struct {
+	// Enabled activates the schedule.
+	Enabled bool `bson:"enabled" json:"enabled"`
+
+	// Start is the time the resource becomes active, in RFC3339 format.
+	Start string `bson:"start" json:"start,omitempty"`
+
+	// End is the time the resource stops being active, in RFC3339 format.
+	End string `bson:"end" json:"end,omitempty"`
+
+	// Timezone is the IANA name of the timezone maintenance windows are evaluated in, UTC when empty.
+	// Start and end times without an offset are read in this timezone.
+	Timezone string `bson:"timezone" json:"timezone,omitempty"`
+
+	// MaintenanceWindows are the recurring periods the resource is inactive.
+	MaintenanceWindows []MaintenanceWindow `bson:"maintenance_windows" json:"maintenance_windows,omitempty"`
+
+	// MaintenanceResponse is the response of an inactive API, 503 with a JSON error when unset.
+	MaintenanceResponse *MaintenanceResponse `bson:"maintenance_response" json:"maintenance_response,omitempty"`
+
+	// FallbackPolicy is the ID of the policy applied in place of an inactive policy.
+	FallbackPolicy string `bson:"fallback_policy" json:"fallback_policy,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period of inactivity.
+type MaintenanceWindow struct {
+	// Cron is the standard five field cron expression of the window start, e.g. `0 2 * * *` for 2am every day.
+	Cron string `bson:"cron" json:"cron"`
+
+	// Duration is the length of the window, e.g. `2h`.
+	Duration tyktime.ReadableDuration `bson:"duration" json:"duration"`
+}
+
+// MaintenanceResponse is the response an inactive API replies with.
+type MaintenanceResponse struct {
+	// Code is the HTTP status code, 503 by default.
+	Code int `bson:"code" json:"code,omitempty"`
+
+	// Body is the response body.
+	Body string `bson:"body" json:"body,omitempty"`
+
+	// Headers are the response headers.
+	Headers map[string]string `bson:"headers" json:"headers,omitempty"`
+}
+
+// StatusCode returns the status code of the response, 503 when unset.
+func (m *MaintenanceResponse) StatusCode() int {
+	if m == nil || m.Code == 0 {
+		return http.StatusServiceUnavailable
+	}
+	return m.Code
+}
+
+// IsZero returns true if the schedule is not enabled (for omitzero support).
+func (s *ActivationSchedule) IsZero() bool {
+	return s == nil || !s.Enabled
+}
+
+// Validate checks the schedule configuration and returns the first error found.
+func (s *ActivationSchedule) Validate() error {
+	if s.IsZero() {
+		return nil
+	}
+
+	_, err := s.parse()
+	return err
+}
+
+// Active reports whether the resource is active at the given time. It's always
+// active when the schedule is disabled.
+func (s *ActivationSchedule) Active(now time.Time) (bool, error) {
+	if s.IsZero() {
+		return true, nil
+	}
+
+	p, err := s.parse()
+	if err != nil {
+		return true, err
+	}
+
+	if !p.start.IsZero() && now.Before(p.start) {
+		return false, nil
+	}
+
+	if !p.end.IsZero() && !now.Before(p.end) {
+		return false, nil
+	}
+
+	now = now.In(p.location)
+	for i, schedule := range p.windows {
+		duration := time.Duration(s.MaintenanceWindows[i].Duration)
+		// The window started at the first occurrence after now - duration if that's not in the future.
+		if !schedule.Next(now.Add(-duration)).After(now) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+type parsedSchedule struct {
+	location   *time.Location
+	start, end time.Time
+	windows    []cron.Schedule
+}
+
+func (s *ActivationSchedule) parse() (*parsedSchedule, error) {
+	p := &parsedSchedule{location: time.UTC}
+
+	if s.Timezone != "" {
+		location, err := time.LoadLocation(s.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule timezone %q: %w", s.Timezone, err)
+		}
+		p.location = location
+	}
+
+	var err error
+	if p.start, err = parseScheduleTime(s.Start, p.location); err != nil {
+		return nil, fmt.Errorf("invalid schedule start: %w", err)
+	}
+	if p.end, err = parseScheduleTime(s.End, p.location); err != nil {
+		return nil, fmt.Errorf("invalid schedule end: %w", err)
+	}
+
+	if !p.start.IsZero() && !p.end.IsZero() && !p.end.After(p.start) {
+		return nil, errors.New("invalid schedule: end must be after start")
+	}
+
+	for _, window := range s.MaintenanceWindows {
+		if window.Duration <= 0 {
+			return nil, fmt.Errorf("invalid maintenance window %q: duration must be positive", window.Cron)
+		}
+
+		schedule, err := cron.ParseStandard(window.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maintenance window %q: %w", window.Cron, err)
+		}
+		p.windows = append(p.windows, schedule)
+	}
+
+	return p, nil
+}
+
+func parseScheduleTime(value string, location *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.ParseInLocation(localTimeLayout, value, location)
+}
@@ -0,0 +1,125 @@
+package apidef
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tyktime "github.com/TykTechnologies/tyk/internal/time"
+)
+
+func TestActivationSchedule_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		schedule  *ActivationSchedule
+		expectErr bool
+	}{
+		{name: "nil schedule", schedule: nil},
+		{name: "disabled schedule", schedule: &ActivationSchedule{Timezone: "Nowhere/Invalid"}},
+		{
+			name: "valid schedule",
+			schedule: &ActivationSchedule{
+				Enabled:  true,
+				Start:    "2024-01-01T00:00:00Z",
+				End:      "2024-12-31T00:00:00",
+				Timezone: "Europe/London",
+				MaintenanceWindows: []MaintenanceWindow{
+					{Cron: "0 2 * * *", Duration: tyktime.ReadableDuration(time.Hour)},
+				},
+			},
+		},
+		{name: "invalid timezone", schedule: &ActivationSchedule{Enabled: true, Timezone: "Nowhere/Invalid"}, expectErr: true},
+		{name: "invalid start", schedule: &ActivationSchedule{Enabled: true, Start: "tomorrow"}, expectErr: true},
+		{
+			name:      "end before start",
+			schedule:  &ActivationSchedule{Enabled: true, Start: "2024-02-01T00:00:00Z", End: "2024-01-01T00:00:00Z"},
+			expectErr: true,
+		},
+		{
+			name: "invalid cron",
+			schedule: &ActivationSchedule{Enabled: true, MaintenanceWindows: []MaintenanceWindow{
+				{Cron: "at 2am", Duration: tyktime.ReadableDuration(time.Hour)},
+			}},
+			expectErr: true,
+		},
+		{
+			name: "missing duration",
+			schedule: &ActivationSchedule{Enabled: true, MaintenanceWindows: []MaintenanceWindow{
+				{Cron: "0 2 * * *"},
+			}},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.schedule.Validate()
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestActivationSchedule_Active(t *testing.T) {
+	schedule := &ActivationSchedule{
+		Enabled:  true,
+		Start:    "2024-01-01T00:00:00Z",
+		End:      "2024-06-01T00:00:00Z",
+		Timezone: "America/New_York",
+		MaintenanceWindows: []MaintenanceWindow{
+			// 2am to 4am New York time every Sunday.
+			{Cron: "0 2 * * 0", Duration: tyktime.ReadableDuration(2 * time.Hour)},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		now    string
+		active bool
+	}{
+		{name: "before start", now: "2023-12-31T23:59:59Z", active: false},
+		{name: "at start", now: "2024-01-01T00:00:00Z", active: true},
+		{name: "before maintenance", now: "2024-03-03T06:59:59Z", active: true},
+		{name: "maintenance start", now: "2024-03-03T07:00:00Z", active: false},
+		{name: "during maintenance", now: "2024-03-03T08:30:00Z", active: false},
+		{name: "maintenance end", now: "2024-03-03T09:00:00Z", active: true},
+		{name: "maintenance on another day", now: "2024-03-04T07:30:00Z", active: true},
+		{name: "at end", now: "2024-06-01T00:00:00Z", active: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			now, err := time.Parse(time.RFC3339, tc.now)
+			require.NoError(t, err)
+
+			active, err := schedule.Active(now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.active, active)
+		})
+	}
+
+	t.Run("disabled schedule", func(t *testing.T) {
+		active, err := (&ActivationSchedule{Start: "2999-01-01T00:00:00Z"}).Active(time.Now())
+		assert.NoError(t, err)
+		assert.True(t, active)
+	})
+
+	t.Run("invalid schedule", func(t *testing.T) {
+		active, err := (&ActivationSchedule{Enabled: true, Start: "tomorrow"}).Active(time.Now())
+		assert.Error(t, err)
+		assert.True(t, active, "an invalid schedule doesn't deactivate the resource")
+	})
+}
+
+func TestMaintenanceResponse_StatusCode(t *testing.T) {
+	var resp *MaintenanceResponse
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode())
+	assert.Equal(t, http.StatusServiceUnavailable, (&MaintenanceResponse{}).StatusCode())
+	assert.Equal(t, http.StatusForbidden, (&MaintenanceResponse{Code: http.StatusForbidden}).StatusCode())
+}
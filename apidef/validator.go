@@ -6,6 +6,8 @@ import (
 	"net"
 	"sort"
 	"strings"
+
+	"github.com/TykTechnologies/tyk/internal/xsd"
 )
 
 type ValidationResult struct {
@@ -59,6 +61,9 @@ var DefaultValidationRuleSet = ValidationRuleSet{
 	&RuleValidateEnforceTimeout{},
 	&RuleUpstreamAuth{},
 	&RuleLoadBalancingTargets{},
+	&RuleActivationSchedule{},
+	&RuleGRPCTranscoding{},
+	&RuleValidateXMLSchemas{},
 }
 
 func Validate(definition *APIDefinition, ruleSet ValidationRuleSet) ValidationResult {
@@ -270,3 +275,44 @@ func (r *RuleLoadBalancingTargets) Validate(apiDef *APIDefinition, validationRes
 		validationResult.AppendError(ErrAllLoadBalancingTargetsZeroWeight)
 	}
 }
+
+type RuleActivationSchedule struct{}
+
+// Validate validates the activation schedule of the API.
+func (r *RuleActivationSchedule) Validate(apiDef *APIDefinition, validationResult *ValidationResult) {
+	if err := apiDef.Schedule.Validate(); err != nil {
+		validationResult.IsValid = false
+		validationResult.AppendError(err)
+	}
+}
+
+type RuleGRPCTranscoding struct{}
+
+// Validate validates the descriptors of gRPC transcoding.
+func (r *RuleGRPCTranscoding) Validate(apiDef *APIDefinition, validationResult *ValidationResult) {
+	if err := apiDef.GRPCTranscoding.Validate(); err != nil {
+		validationResult.IsValid = false
+		validationResult.AppendError(err)
+	}
+}
+
+// ErrInvalidXMLSchema is returned when the XML schema of an XML validation endpoint can't be parsed.
+var ErrInvalidXMLSchema = errors.New("invalid XML schema")
+
+type RuleValidateXMLSchemas struct{}
+
+// Validate validates that the XML schemas of the enabled XML validation endpoints can be parsed.
+func (r *RuleValidateXMLSchemas) Validate(apiDef *APIDefinition, validationResult *ValidationResult) {
+	for _, vInfo := range apiDef.VersionData.Versions {
+		for _, meta := range vInfo.ExtendedPaths.ValidateXML {
+			if meta.Disabled {
+				continue
+			}
+
+			if _, err := xsd.Parse([]byte(meta.Schema)); err != nil {
+				validationResult.IsValid = false
+				validationResult.AppendError(fmt.Errorf("%w for %s %s: %v", ErrInvalidXMLSchema, meta.Method, meta.Path, err))
+			}
+		}
+	}
+}
@@ -583,3 +583,65 @@ func TestRuleLoadBalancingTargets_Validate(t *testing.T) {
 		t.Run(tc.name, runValidationTest(tc.apiDef, ruleSet, tc.result))
 	}
 }
+
+func TestRuleGRPCTranscoding_Validate(t *testing.T) {
+	ruleSet := ValidationRuleSet{
+		&RuleGRPCTranscoding{},
+	}
+
+	t.Run("not configured", runValidationTest(&APIDefinition{}, ruleSet, ValidationResult{IsValid: true}))
+
+	t.Run("disabled", runValidationTest(&APIDefinition{
+		GRPCTranscoding: &GRPCTranscoding{Enabled: false},
+	}, ruleSet, ValidationResult{IsValid: true}))
+
+	t.Run("enabled without descriptor", runValidationTest(&APIDefinition{
+		GRPCTranscoding: &GRPCTranscoding{Enabled: true},
+	}, ruleSet, ValidationResult{IsValid: false, Errors: []error{ErrGRPCTranscodingNoDescriptor}}))
+
+	t.Run("enabled with invalid descriptor", func(t *testing.T) {
+		result := Validate(&APIDefinition{
+			GRPCTranscoding: &GRPCTranscoding{Enabled: true, Descriptor: "bm90IGEgZGVzY3JpcHRvcg=="},
+		}, ruleSet)
+
+		assert.False(t, result.IsValid)
+		assert.ErrorIs(t, result.FirstError(), ErrGRPCTranscodingInvalidDescriptor)
+	})
+}
+
+func TestRuleValidateXMLSchemas_Validate(t *testing.T) {
+	ruleSet := ValidationRuleSet{
+		&RuleValidateXMLSchemas{},
+	}
+
+	getAPIDef := func(validateXML ...ValidateXMLMeta) *APIDefinition {
+		return &APIDefinition{
+			VersionData: VersionData{
+				Versions: map[string]VersionInfo{
+					"Default": {
+						Name:          "Default",
+						ExtendedPaths: ExtendedPathsSet{ValidateXML: validateXML},
+					},
+				},
+			},
+		}
+	}
+
+	const schema = `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:element name="pet" type="xs:string"/></xs:schema>`
+
+	t.Run("valid schema", runValidationTest(getAPIDef(ValidateXMLMeta{
+		Path: "/pets", Method: http.MethodPost, Schema: schema,
+	}), ruleSet, ValidationResult{IsValid: true}))
+
+	t.Run("disabled invalid schema", runValidationTest(getAPIDef(ValidateXMLMeta{
+		Disabled: true, Path: "/pets", Method: http.MethodPost, Schema: "<pet/>",
+	}), ruleSet, ValidationResult{IsValid: true}))
+
+	t.Run("invalid schema", func(t *testing.T) {
+		result := Validate(getAPIDef(ValidateXMLMeta{Path: "/pets", Method: http.MethodPost, Schema: "<pet/>"}), ruleSet)
+
+		assert.False(t, result.IsValid)
+		assert.ErrorIs(t, result.FirstError(), ErrInvalidXMLSchema)
+		assert.Contains(t, result.FirstError().Error(), "POST /pets")
+	})
+}
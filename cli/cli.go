@@ -11,9 +11,12 @@ import (
 	kingpin "github.com/alecthomas/kingpin/v2"
 
 	"github.com/TykTechnologies/tyk/cli/bundler"
+	"github.com/TykTechnologies/tyk/cli/converter"
 	"github.com/TykTechnologies/tyk/cli/importer"
 	"github.com/TykTechnologies/tyk/cli/linter"
 	"github.com/TykTechnologies/tyk/cli/plugin"
+	"github.com/TykTechnologies/tyk/cli/syncer"
+	"github.com/TykTechnologies/tyk/cli/tester"
 	"github.com/TykTechnologies/tyk/cli/version"
 	"github.com/TykTechnologies/tyk/internal/build"
 	logger "github.com/TykTechnologies/tyk/log"
@@ -87,8 +90,9 @@ func setup(confPaths []string) {
 	startCmd.Default()
 
 	// Linter:
-	lintCmd := app.Command("lint", "Runs a linter on Tyk configuration file")
-	lintCmd.Action(func(c *kingpin.ParseContext) error {
+	lintCmd := app.Command("lint", "Runs a linter on Tyk configuration file or API definitions")
+	lintConfCmd := lintCmd.Command("config", "Runs a linter on Tyk configuration file").Default()
+	lintConfCmd.Action(func(c *kingpin.ParseContext) error {
 		confSchema, err := ioutil.ReadFile("cli/linter/schema.json")
 		if err != nil {
 			return err
@@ -109,6 +113,7 @@ func setup(confPaths []string) {
 		os.Exit(1)
 		return nil
 	})
+	linter.AddAPITo(lintCmd)
 
 	// Add version command:
 	version.AddTo(app)
@@ -116,11 +121,20 @@ func setup(confPaths []string) {
 	// Add import command:
 	importer.AddTo(app)
 
+	// Add convert command:
+	converter.AddTo(app)
+
 	// Add bundler commands:
 	bundler.AddTo(app)
 
 	// Add plugin commands:
 	plugin.AddTo(app)
+
+	// Add sync command:
+	syncer.AddTo(app)
+
+	// Add test command:
+	tester.AddTo(app)
 }
 
 // Parse parses the command-line arguments.
@@ -0,0 +1,302 @@
+package converter
+
+//lint:file-ignore faillint This file should be ignored by faillint (fmt in use).
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	kingpin "github.com/alecthomas/kingpin/v2"
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/apidef/asyncapi"
+	"github.com/TykTechnologies/tyk/apidef/oas"
+	"github.com/TykTechnologies/tyk/internal/audit"
+)
+
+const (
+	cmdName = "convert"
+	cmdDesc = "Converts a classic API definition to a Tyk OAS API definition and back, a Tyk Streams API to AsyncAPI, or an OpenAPI 3.1 document to 3.0"
+
+	// FormatOAS is the Tyk OAS API definition format.
+	FormatOAS = "oas"
+	// FormatClassic is the classic API definition format.
+	FormatClassic = "classic"
+	// FormatAsyncAPI is the AsyncAPI 3.0 document of a Tyk Streams API.
+	FormatAsyncAPI = "asyncapi"
+	// FormatOpenAPI30 is the OpenAPI 3.0 equivalent of an OpenAPI 3.1 document.
+	FormatOpenAPI30 = "openapi-3.0"
+)
+
+var (
+	conv *Converter = &Converter{}
+
+	errNoTykExtension = errors.New("the OAS API definition has no x-tyk-api-gateway extension")
+	errUnsupported    = errors.New("some fields couldn't be converted")
+)
+
+// deprecatedFields are the classic fields migrated to their replacements before the conversion,
+// they aren't reported as unsupported.
+var deprecatedFields = []string{
+	"auth",
+	"use_go_plugin_auth",
+	"enable_coprocess_auth",
+	"jwt_scope_to_policy_mapping",
+	"jwt_scope_claim_name",
+	"version_definition.strip_path",
+}
+
+// Converter wraps the convert functionality.
+type Converter struct {
+	input  *string
+	to     *string
+	output *string
+	strict *bool
+	host   *string
+}
+
+// AddTo initializes a converter object.
+func AddTo(app *kingpin.Application) {
+	cmd := app.Command(cmdName, cmdDesc)
+	conv.input = cmd.Arg("input file", "the classic or Tyk OAS API definition").Required().String()
+	conv.to = cmd.Flag("to", "the target format, detected from the input when not set").Enum(FormatOAS, FormatClassic, FormatAsyncAPI, FormatOpenAPI30)
+	conv.output = cmd.Flag("output", "write the converted definition to a file instead of stdout").Short('o').PlaceHolder("FILE").String()
+	conv.strict = cmd.Flag("strict", "fail when some fields couldn't be converted").Bool()
+	conv.host = cmd.Flag("host", "the host the gateway is reached on, for the servers of the AsyncAPI document").Default("localhost:8080").String()
+	cmd.Action(conv.Convert)
+}
+
+// Convert performs the conversion, the fields that couldn't be converted are reported on stderr.
+func (c *Converter) Convert(_ *kingpin.ParseContext) error {
+	if err := c.convert(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return nil
+}
+
+func (c *Converter) convert() error {
+	data, err := os.ReadFile(*c.input)
+	if err != nil {
+		return fmt.Errorf("file load error: %w", err)
+	}
+
+	to := *c.to
+	if to == "" {
+		to = FormatOAS
+		if isOAS(data) {
+			to = FormatClassic
+		}
+	}
+
+	var (
+		result      interface{}
+		unsupported []string
+	)
+
+	switch to {
+	case FormatOAS:
+		var apis []*oas.OAS
+		apis, unsupported, err = ClassicToOAS(data)
+		if len(apis) == 1 {
+			result = apis[0]
+		} else {
+			result = apis
+		}
+	case FormatClassic:
+		result, unsupported, err = OASToClassic(data)
+	case FormatAsyncAPI:
+		result, err = OASToAsyncAPI(data, *c.host)
+	case FormatOpenAPI30:
+		result, err = OASToOpenAPI30(data)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, field := range unsupported {
+		fmt.Fprintf(os.Stderr, "unsupported field: %s\n", field)
+	}
+
+	if len(unsupported) > 0 && *c.strict {
+		return errUnsupported
+	}
+
+	asJSON, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshalling failed: %w", err)
+	}
+
+	if *c.output != "" {
+		return os.WriteFile(*c.output, append(asJSON, '\n'), 0644)
+	}
+
+	fmt.Println(string(asJSON))
+	return nil
+}
+
+// ClassicToOAS converts a classic API definition to Tyk OAS. The versions of a versioned
+// API are converted to version APIs and returned after the base API. It also returns the
+// classic fields that couldn't be represented in Tyk OAS.
+func ClassicToOAS(data []byte) ([]*oas.OAS, []string, error) {
+	var api apidef.APIDefinition
+	if err := json.Unmarshal(data, &api); err != nil {
+		return nil, nil, fmt.Errorf("couldn't decode classic API definition: %w", err)
+	}
+
+	base, versions, err := oas.MigrateAndFillOAS(&api)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		apis        []*oas.OAS
+		unsupported []string
+	)
+
+	for i, def := range append([]oas.APIDef{base}, versions...) {
+		apis = append(apis, def.OAS)
+
+		prefix := ""
+		if i > 0 {
+			prefix = def.Classic.VersionName + ": "
+		}
+
+		for _, field := range unsupportedClassicFields(def) {
+			unsupported = append(unsupported, prefix+field)
+		}
+	}
+
+	return apis, unsupported, nil
+}
+
+// OASToClassic converts a Tyk OAS API definition in JSON or YAML to a classic API definition.
+// It also returns the fields of the Tyk extension that couldn't be represented in the classic
+// API definition.
+func OASToClassic(data []byte) (*apidef.APIDefinition, []string, error) {
+	loader := openapi3.NewLoader()
+	t, err := oas.LoadFromData(loader, data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't decode OAS API definition: %w", err)
+	}
+
+	oasObj := &oas.OAS{T: *t}
+	if oasObj.GetTykExtension() == nil {
+		return nil, nil, errNoTykExtension
+	}
+
+	var api apidef.APIDefinition
+	oasObj.ExtractTo(&api)
+
+	roundtrip, err := oasObj.Clone()
+	if err != nil {
+		return nil, nil, err
+	}
+	roundtrip.RemoveTykExtension()
+	roundtrip.Fill(api)
+
+	var unsupported []string
+	for _, change := range audit.Diff(oasObj.GetTykExtension(), roundtrip.GetTykExtension()) {
+		if isEmpty(change.Before) {
+			continue
+		}
+		unsupported = append(unsupported, oas.ExtensionTykAPIGateway+"."+change.Path)
+	}
+
+	return &api, unsupported, nil
+}
+
+// OASToAsyncAPI exports a Tyk Streams API in JSON or YAML to an AsyncAPI 3.0 document.
+func OASToAsyncAPI(data []byte, host string) (*asyncapi.Document, error) {
+	loader := openapi3.NewLoader()
+	t, err := oas.LoadFromData(loader, data)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode OAS API definition: %w", err)
+	}
+
+	return asyncapi.Export(&oas.OAS{T: *t}, host)
+}
+
+// OASToOpenAPI30 downgrades an OpenAPI 3.1 document in JSON or YAML to OpenAPI 3.0, the JSON Schema
+// 2020-12 keywords without a 3.0 equivalent are removed. Other documents are returned unchanged.
+func OASToOpenAPI30(data []byte) (*oas.OAS, error) {
+	loader := openapi3.NewLoader()
+	t, err := oas.LoadFromData(loader, data)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode OAS API definition: %w", err)
+	}
+
+	oasObj := &oas.OAS{T: *t}
+	return oasObj.Downgrade()
+}
+
+// unsupportedClassicFields returns the fields of the classic API definition that are lost
+// when converting the API definition to Tyk OAS and back.
+func unsupportedClassicFields(def oas.APIDef) []string {
+	oasObj, err := def.OAS.Clone()
+	if err != nil {
+		return nil
+	}
+
+	// The classic API definition is marked as OAS by the conversion.
+	roundtrip := apidef.APIDefinition{IsOAS: def.Classic.IsOAS}
+	oasObj.ExtractTo(&roundtrip)
+
+	var unsupported []string
+	for _, change := range audit.Diff(def.Classic, &roundtrip) {
+		if isEmpty(change.Before) || isDeprecated(change.Path) {
+			continue
+		}
+		unsupported = append(unsupported, change.Path)
+	}
+
+	sort.Strings(unsupported)
+	return unsupported
+}
+
+func isDeprecated(path string) bool {
+	for _, field := range deprecatedFields {
+		if path == field || strings.HasPrefix(path, field+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmpty returns true for the values that weren't set in the source definition.
+func isEmpty(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !val
+	case string:
+		return val == ""
+	case float64:
+		return val == 0
+	case []interface{}:
+		return len(val) == 0
+	case map[string]interface{}:
+		return len(val) == 0
+	}
+	return false
+}
+
+// isOAS returns true if the document is an OpenAPI document, in JSON or YAML.
+func isOAS(data []byte) bool {
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+	}
+	if err := json.Unmarshal(data, &doc); err == nil {
+		return doc.OpenAPI != ""
+	}
+
+	// Classic API definitions are JSON only.
+	return true
+}
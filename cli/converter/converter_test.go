@@ -0,0 +1,155 @@
+package converter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/apidef/asyncapi"
+	"github.com/TykTechnologies/tyk/apidef/oas"
+)
+
+func classicAPI(t *testing.T) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(apidef.APIDefinition{
+		APIID:               "classic",
+		OrgID:               "org",
+		Name:                "classic",
+		Slug:                "classic-slug",
+		Active:              true,
+		UseKeylessAccess:    true,
+		EnableProxyProtocol: true,
+		Proxy: apidef.ProxyConfig{
+			ListenPath:      "/classic/",
+			TargetURL:       "http://upstream",
+			StripListenPath: true,
+		},
+		VersionData: apidef.VersionData{
+			NotVersioned: true,
+			Versions:     map[string]apidef.VersionInfo{"Default": {Name: "Default"}},
+		},
+	})
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestClassicToOAS(t *testing.T) {
+	apis, unsupported, err := ClassicToOAS(classicAPI(t))
+	require.NoError(t, err)
+	require.Len(t, apis, 1)
+
+	xTykAPIGateway := apis[0].GetTykExtension()
+	require.NotNil(t, xTykAPIGateway)
+	assert.Equal(t, "classic", xTykAPIGateway.Info.ID)
+	assert.Equal(t, "/classic/", xTykAPIGateway.Server.ListenPath.Value)
+
+	assert.Contains(t, unsupported, "slug")
+	assert.Contains(t, unsupported, "enable_proxy_protocol")
+	assert.NotContains(t, unsupported, "name")
+	assert.NotContains(t, unsupported, "proxy.listen_path")
+
+	_, _, err = ClassicToOAS([]byte("{"))
+	assert.Error(t, err)
+}
+
+func TestOASToClassic(t *testing.T) {
+	apis, _, err := ClassicToOAS(classicAPI(t))
+	require.NoError(t, err)
+
+	data, err := json.Marshal(apis[0])
+	require.NoError(t, err)
+
+	api, unsupported, err := OASToClassic(data)
+	require.NoError(t, err)
+	assert.Empty(t, unsupported)
+	assert.Equal(t, "classic", api.APIID)
+	assert.Equal(t, "/classic/", api.Proxy.ListenPath)
+
+	apis[0].RemoveTykExtension()
+	data, err = json.Marshal(apis[0])
+	require.NoError(t, err)
+
+	_, _, err = OASToClassic(data)
+	assert.ErrorIs(t, err, errNoTykExtension)
+}
+
+func TestOASToAsyncAPI(t *testing.T) {
+	streamsAPI := `openapi: 3.0.3
+info:
+  title: user events
+  version: 1.0.0
+paths: {}
+x-tyk-api-gateway:
+  info:
+    name: user events
+    state:
+      active: true
+  server:
+    listenPath:
+      value: /events/
+  upstream:
+    url: http://upstream
+x-tyk-streaming:
+  streams:
+    signups:
+      input:
+        kafka:
+          addresses: [localhost:9092]
+          topics: [user.signedup]
+      output:
+        http_server:
+          stream_path: /signups
+`
+
+	doc, err := OASToAsyncAPI([]byte(streamsAPI), "gateway.example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, asyncapi.Version, doc.AsyncAPI)
+	assert.Equal(t, "/events", doc.Servers[asyncapi.ServerGateway].Pathname)
+	assert.Equal(t, "gateway.example.com", doc.Servers[asyncapi.ServerGateway].Host)
+	assert.Equal(t, "user.signedup", doc.Channels["signups"].Address)
+	assert.Equal(t, asyncapi.ActionSend, doc.Operations["signups"].Action)
+
+	_, err = OASToAsyncAPI(classicAPI(t), "gateway.example.com")
+	assert.Error(t, err)
+}
+
+func TestOASToOpenAPI30(t *testing.T) {
+	api := `openapi: 3.1.0
+info:
+  title: pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: owner
+          in: query
+          schema:
+            type: [string, "null"]
+webhooks:
+  newPet:
+    post: {}
+`
+
+	doc, err := OASToOpenAPI30([]byte(api))
+	require.NoError(t, err)
+
+	assert.Equal(t, oas.DefaultOpenAPI, doc.OpenAPI)
+	assert.Contains(t, doc.Extensions, "x-webhooks")
+
+	owner := doc.Paths.Find("/pets").Get.Parameters[0].Value.Schema.Value
+	assert.Equal(t, []string{"string"}, owner.Type.Slice())
+	assert.True(t, owner.Nullable)
+}
+
+func TestIsOAS(t *testing.T) {
+	assert.True(t, isOAS([]byte(`{"openapi":"3.0.3"}`)))
+	assert.True(t, isOAS([]byte("openapi: 3.0.3\n")))
+	assert.False(t, isOAS([]byte(`{"api_id":"classic"}`)))
+}
@@ -3,23 +3,28 @@ package importer
 //lint:file-ignore faillint This file should be ignored by faillint (fmt in use).
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 
 	kingpin "github.com/alecthomas/kingpin/v2"
+	"github.com/getkin/kin-openapi/openapi3"
 
 	"github.com/TykTechnologies/tyk/apidef"
 	"github.com/TykTechnologies/tyk/apidef/importer"
+	"github.com/TykTechnologies/tyk/apidef/oas"
+	"github.com/TykTechnologies/tyk/config"
 )
 
 const (
 	cmdName = "import"
-	cmdDesc = "Imports a BluePrint/Swagger/WSDL file"
+	cmdDesc = "Imports a BluePrint/Swagger/WSDL/OpenAPI/Postman/HAR/gRPC/AsyncAPI file"
 )
 
 var (
@@ -34,6 +39,11 @@ type Importer struct {
 	swaggerMode    *bool
 	bluePrintMode  *bool
 	wsdlMode       *bool
+	postmanMode    *bool
+	harMode        *bool
+	grpcMode       *bool
+	asyncAPIMode   *bool
+	importPaths    *[]string
 	portNames      *string
 	createAPI      *bool
 	orgID          *string
@@ -41,15 +51,33 @@ type Importer struct {
 	asMock         *bool
 	forAPI         *string
 	asVersion      *string
+
+	oasMode                *bool
+	listenPath             *string
+	customDomain           *string
+	apiID                  *string
+	authentication         *bool
+	allowList              *bool
+	validateRequest        *bool
+	mockResponse           *bool
+	securityProcessingMode *string
+
+	// isSet holds the OAS import flags explicitly set, unset flags keep the defaults of the OAS import.
+	isSet map[string]*bool
 }
 
 // AddTo initializes an importer object.
 func AddTo(app *kingpin.Application) {
 	cmd := app.Command(cmdName, cmdDesc)
-	imp.input = cmd.Arg("input file", "e.g. blueprint.json, swagger.json, service.wsdl etc.").String()
+	imp.input = cmd.Arg("input file", "e.g. blueprint.json, swagger.json, service.wsdl, openapi.yaml, collection.json, traffic.har, service.proto, asyncapi.yaml etc.").String()
 	imp.swaggerMode = cmd.Flag("swagger", "Use Swagger mode").Bool()
 	imp.bluePrintMode = cmd.Flag("blueprint", "Use BluePrint mode").Bool()
 	imp.wsdlMode = cmd.Flag("wsdl", "Use WSDL mode").Bool()
+	imp.postmanMode = cmd.Flag("postman", "Use Postman v2.1 collection mode, creates a Tyk OAS API with --oas").Bool()
+	imp.harMode = cmd.Flag("har", "Use HAR mode, creates a Tyk OAS API with --oas").Bool()
+	imp.grpcMode = cmd.Flag("grpc", "Use gRPC mode with a .proto file or FileDescriptorSet, creates a Tyk OAS API with --oas").Bool()
+	imp.asyncAPIMode = cmd.Flag("asyncapi", "Use AsyncAPI 2.x/3.x mode, creates a Tyk Streams API, requires --oas").Bool()
+	imp.importPaths = cmd.Flag("import-path", "directory the imports of a .proto file are resolved from, can be repeated").PlaceHolder("DIR").Strings()
 	imp.portNames = cmd.Flag("port-names", "Specify port name of each service in the WSDL file. Input format is comma separated list of serviceName:portName").String()
 	imp.createAPI = cmd.Flag("create-api", "Creates a new API definition from the blueprint").Bool()
 	imp.orgID = cmd.Flag("org-id", "assign the API Definition to this org_id (required with create-api").String()
@@ -57,6 +85,17 @@ func AddTo(app *kingpin.Application) {
 	imp.asMock = cmd.Flag("as-mock", "creates the API as a mock based on example fields").Bool()
 	imp.forAPI = cmd.Flag("for-api", "adds blueprint to existing API Definition as version").PlaceHolder("PATH").String()
 	imp.asVersion = cmd.Flag("as-version", "the version number to use when inserting").PlaceHolder("VERSION").String()
+
+	imp.oasMode = cmd.Flag("oas", "Use OpenAPI 3 mode, creates a Tyk OAS API").Bool()
+	imp.listenPath = cmd.Flag("listen-path", "set the listen path of the Tyk OAS API").String()
+	imp.customDomain = cmd.Flag("custom-domain", "set the custom domain of the Tyk OAS API").String()
+	imp.apiID = cmd.Flag("api-id", "set the ID of the Tyk OAS API").String()
+	imp.isSet = map[string]*bool{}
+	imp.authentication = imp.oasBoolFlag(cmd, "authentication", "configure the authentication from the security of the OpenAPI document")
+	imp.allowList = imp.oasBoolFlag(cmd, "allow-list", "configure an allow list from the paths of the OpenAPI document")
+	imp.validateRequest = imp.oasBoolFlag(cmd, "validate-request", "enable request validation for the operations with a request body schema")
+	imp.mockResponse = imp.oasBoolFlag(cmd, "mock-response", "configure mock responses from the examples of the OpenAPI document")
+	imp.securityProcessingMode = cmd.Flag("security-processing-mode", "how multiple security requirements are processed").PlaceHolder("legacy|compliant").String()
 	cmd.Action(imp.Import)
 }
 
@@ -77,6 +116,31 @@ func (i *Importer) Import(_ *kingpin.ParseContext) (err error) {
 		if err != nil {
 			log.Fatal(err)
 		}
+	} else if *i.postmanMode {
+		err = i.handleSourceMode(importer.PostmanSource)
+		if err != nil {
+			log.Fatal(err)
+		}
+	} else if *i.harMode {
+		err = i.handleSourceMode(importer.HARSource)
+		if err != nil {
+			log.Fatal(err)
+		}
+	} else if *i.grpcMode {
+		err = i.handleSourceMode(importer.GRPCSource)
+		if err != nil {
+			log.Fatal(err)
+		}
+	} else if *i.asyncAPIMode {
+		err = i.handleSourceMode(importer.AsyncAPISource)
+		if err != nil {
+			log.Fatal(err)
+		}
+	} else if *i.oasMode {
+		err = i.handleOASMode()
+		if err != nil {
+			log.Fatal(err)
+		}
 	} else {
 		log.Fatal(errUnknownMode)
 	}
@@ -273,6 +337,165 @@ func (i *Importer) handleWSDLMode() error {
 	return nil
 }
 
+func (i *Importer) oasBoolFlag(cmd *kingpin.CmdClause, name, help string) *bool {
+	isSet := new(bool)
+	i.isSet[name] = isSet
+	return cmd.Flag(name, help).IsSetByUser(isSet).Bool()
+}
+
+// oasBool returns the value of an OAS import flag, or nil when the flag isn't set.
+func (i *Importer) oasBool(name string, value *bool) *bool {
+	if isSet := i.isSet[name]; isSet == nil || !*isSet {
+		return nil
+	}
+	return value
+}
+
+// handleSourceMode imports a Postman collection, HAR, gRPC or AsyncAPI file as a Tyk OAS API with --oas,
+// or as a classic API definition or version otherwise.
+func (i *Importer) handleSourceMode(source importer.APIImporterSource) error {
+	imp, err := i.loadFile(source, *i.input)
+	if err != nil {
+		return fmt.Errorf("file load error: %w", err)
+	}
+
+	if *i.oasMode {
+		doc, err := imp.(importer.OASImporter).ToOAS()
+		if err != nil {
+			return fmt.Errorf("conversion into OpenAPI failed: %w", err)
+		}
+
+		data, err := doc.MarshalJSON()
+		if err != nil {
+			return err
+		}
+
+		return i.importOAS(data)
+	}
+
+	var def *apidef.APIDefinition
+	if *i.createAPI {
+		if *i.upstreamTarget == "" || *i.orgID == "" {
+			return fmt.Errorf("no upstream target or org ID defined, these are both required")
+		}
+
+		def, err = imp.ToAPIDefinition(*i.orgID, *i.upstreamTarget, *i.asMock)
+		if err != nil {
+			return fmt.Errorf("failed to create API Definition from file: %w", err)
+		}
+	} else {
+		if err := i.validateInput(); err != nil {
+			return err
+		}
+
+		def, err = i.apiDefLoadFile(*i.forAPI)
+		if err != nil {
+			return fmt.Errorf("failed to load and decode file data for API Definition: %w", err)
+		}
+
+		versionData, err := imp.ConvertIntoApiVersion(*i.asMock)
+		if err != nil {
+			return fmt.Errorf("conversion into API Def failed: %w", err)
+		}
+
+		if err := imp.InsertIntoAPIDefinitionAsVersion(versionData, def, *i.asVersion); err != nil {
+			return fmt.Errorf("insertion failed: %w", err)
+		}
+	}
+
+	i.printDef(def)
+	return nil
+}
+
+func (i *Importer) handleOASMode() error {
+	data, err := os.ReadFile(*i.input)
+	if err != nil {
+		return fmt.Errorf("file load error: %w", err)
+	}
+
+	return i.importOAS(data)
+}
+
+// importOAS prints the Tyk OAS API imported from an OpenAPI document.
+func (i *Importer) importOAS(data []byte) error {
+	params := oas.TykExtensionConfigParams{
+		UpstreamURL:            *i.upstreamTarget,
+		ListenPath:             *i.listenPath,
+		CustomDomain:           *i.customDomain,
+		ApiID:                  *i.apiID,
+		Authentication:         i.oasBool("authentication", i.authentication),
+		AllowList:              i.oasBool("allow-list", i.allowList),
+		ValidateRequest:        i.oasBool("validate-request", i.validateRequest),
+		MockResponse:           i.oasBool("mock-response", i.mockResponse),
+		SecurityProcessingMode: *i.securityProcessingMode,
+	}
+	if params.MockResponse == nil && *i.asMock {
+		params.MockResponse = i.asMock
+	}
+
+	oasObj, err := ImportOAS(data, params, *i.orgID)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(oasObj)
+}
+
+// ImportOAS creates a Tyk OAS API from an OpenAPI 3 document in JSON or YAML, the same way
+// as the import endpoint of the gateway API. The API is assigned to orgID when set.
+func ImportOAS(data []byte, params oas.TykExtensionConfigParams, orgID string) (*oas.OAS, error) {
+	loader := openapi3.NewLoader()
+	t, err := oas.LoadFromData(loader, data)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode OpenAPI document: %w", err)
+	}
+
+	oasObj := &oas.OAS{T: *t}
+	if oasObj.GetTykExtension() != nil {
+		return nil, apidef.ErrImportWithTykExtension
+	}
+
+	if err := oasObj.BuildDefaultTykExtension(params, true); err != nil {
+		return nil, err
+	}
+
+	xTykAPIGateway := oasObj.GetTykExtension()
+	xTykAPIGateway.Server.ListenPath.Strip = true
+	if orgID != "" {
+		xTykAPIGateway.Info.OrgID = orgID
+	}
+
+	if err := ValidateOAS(oasObj); err != nil {
+		return nil, err
+	}
+
+	return oasObj, nil
+}
+
+// ValidateOAS validates a Tyk OAS API against the OpenAPI and Tyk extension schemas.
+func ValidateOAS(oasObj *oas.OAS) error {
+	data, err := oasObj.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	if err := oas.ValidateOASObject(data, oasObj.OpenAPI); err != nil {
+		return err
+	}
+
+	return oasObj.Validate(context.Background(), oas.GetValidationOptionsFromConfig(config.OASConfig{})...)
+}
+
+func printJSON(v interface{}) error {
+	asJSON, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshalling failed: %w", err)
+	}
+
+	fmt.Println(string(asJSON))
+	return nil
+}
+
 func (i *Importer) printDef(def *apidef.APIDefinition) {
 	asJSON, err := json.MarshalIndent(def, "", "    ")
 	if err != nil {
@@ -339,6 +562,30 @@ func (i *Importer) bluePrintLoadFile(path string) (*importer.BluePrintAST, error
 	return blueprint.(*importer.BluePrintAST), nil
 }
 
+func (i *Importer) loadFile(source importer.APIImporterSource, path string) (importer.APIImporter, error) {
+	imp, err := importer.GetImporterForSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if grpcImp, ok := imp.(*importer.GRPCDescriptor); ok {
+		grpcImp.Filename = filepath.Base(path)
+		grpcImp.ImportPaths = append([]string{filepath.Dir(path)}, *i.importPaths...)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if err := imp.LoadFrom(f); err != nil {
+		return nil, err
+	}
+
+	return imp, nil
+}
+
 func (i *Importer) apiDefLoadFile(path string) (*apidef.APIDefinition, error) {
 	f, err := os.Open(path)
 	if err != nil {
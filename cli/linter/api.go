@@ -0,0 +1,207 @@
+package linter
+
+//lint:file-ignore faillint This file should be ignored by faillint (fmt in use).
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	kingpin "github.com/alecthomas/kingpin/v2"
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/TykTechnologies/tyk/apidef/oas"
+	"github.com/TykTechnologies/tyk/apidef/oas/lint"
+)
+
+// The output formats of the API linter.
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+var errNoTykExtension = errors.New("the OAS API definition has no x-tyk-api-gateway extension")
+
+// APILinter wraps the lint api command.
+type APILinter struct {
+	paths  *[]string
+	format *string
+	config *string
+	failOn *string
+	output *string
+}
+
+// AddAPITo adds the api subcommand to the lint command.
+func AddAPITo(lintCmd *kingpin.CmdClause) {
+	l := &APILinter{}
+
+	cmd := lintCmd.Command("api", "Lints Tyk OAS API definitions against rules of good practice")
+	l.paths = cmd.Arg("paths", "the API definition files, or directories of JSON and YAML files").Required().Strings()
+	l.format = cmd.Flag("format", "the output format").Default(FormatText).Enum(FormatText, FormatJSON, FormatSARIF)
+	l.config = cmd.Flag("config", "a YAML or JSON file setting the severity of the rules").PlaceHolder("FILE").String()
+	l.failOn = cmd.Flag("fail-on", "the lowest severity of the findings that fail the command").Default(string(lint.SeverityError)).
+		Enum(string(lint.SeverityError), string(lint.SeverityWarning), string(lint.SeverityNote))
+	l.output = cmd.Flag("output", "write the findings to a file instead of stdout").Short('o').PlaceHolder("FILE").String()
+	cmd.Action(l.Lint)
+}
+
+// Lint lints the API definitions and exits with 1 when a finding is at least as severe as --fail-on.
+func (l *APILinter) Lint(_ *kingpin.ParseContext) error {
+	failed, err := l.lint()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if failed {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func (l *APILinter) lint() (bool, error) {
+	var conf lint.Config
+	if *l.config != "" {
+		data, err := os.ReadFile(*l.config)
+		if err != nil {
+			return false, fmt.Errorf("config load error: %w", err)
+		}
+		if conf, err = lint.LoadConfig(data); err != nil {
+			return false, fmt.Errorf("config load error: %w", err)
+		}
+	}
+
+	linter, err := lint.New(conf)
+	if err != nil {
+		return false, err
+	}
+
+	defs, err := loadDefinitions(*l.paths)
+	if err != nil {
+		return false, err
+	}
+
+	findings := linter.Lint(defs)
+
+	var w io.Writer = os.Stdout
+	if *l.output != "" {
+		f, err := os.Create(*l.output)
+		if err != nil {
+			return false, err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := writeFindings(w, *l.format, linter, findings, len(defs)); err != nil {
+		return false, err
+	}
+
+	for _, f := range findings {
+		if f.Severity.AtLeast(lint.Severity(*l.failOn)) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func writeFindings(w io.Writer, format string, linter *lint.Linter, findings []lint.Finding, count int) error {
+	switch format {
+	case FormatJSON:
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		return writeJSON(w, findings)
+	case FormatSARIF:
+		return writeJSON(w, linter.SARIF(findings))
+	}
+
+	if len(findings) == 0 {
+		_, err := fmt.Fprintf(w, "found no issues in %d API definitions\n", count)
+		return err
+	}
+
+	for _, f := range findings {
+		if _, err := fmt.Fprintln(w, f); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "found %d issues in %d API definitions\n", len(findings), count)
+	return err
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	return enc.Encode(v)
+}
+
+// loadDefinitions loads the API definition files, and the JSON and YAML files of the directories.
+// Files of the directories that aren't Tyk OAS API definitions are skipped.
+func loadDefinitions(paths []string) ([]*lint.Definition, error) {
+	var defs []*lint.Definition
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			def, err := loadDefinition(path)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			defs = append(defs, def)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+
+			switch strings.ToLower(filepath.Ext(file)) {
+			case ".json", ".yml", ".yaml":
+			default:
+				return nil
+			}
+
+			if def, err := loadDefinition(file); err == nil {
+				defs = append(defs, def)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return defs, nil
+}
+
+func loadDefinition(file string) (*lint.Definition, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := openapi3.NewLoader()
+	t, err := oas.LoadFromData(loader, data)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode OAS API definition: %w", err)
+	}
+
+	api := &oas.OAS{T: *t}
+	if api.GetTykExtension() == nil {
+		return nil, errNoTykExtension
+	}
+
+	return &lint.Definition{File: file, API: api}, nil
+}
@@ -0,0 +1,84 @@
+//go:build !dev
+// +build !dev
+
+package linter
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef/oas/lint"
+)
+
+const keylessAPI = `openapi: 3.0.3
+info:
+  title: Keyless
+  version: 1.0.0
+paths: {}
+x-tyk-api-gateway:
+  info:
+    name: Keyless
+    state:
+      active: true
+  upstream:
+    url: https://keyless.example.com
+  server:
+    listenPath:
+      value: /keyless/
+`
+
+func TestLoadDefinitions(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "keyless.yml"), []byte(keylessAPI), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plain.json"), []byte(`{"openapi": "3.0.3", "info": {"title": "plain", "version": "1"}, "paths": {}}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# APIs"), 0644))
+
+	defs, err := loadDefinitions([]string{dir})
+	require.NoError(t, err)
+	require.Len(t, defs, 1, "files that aren't Tyk OAS API definitions are skipped")
+	assert.Equal(t, filepath.Join(dir, "keyless.yml"), defs[0].File)
+
+	_, err = loadDefinitions([]string{filepath.Join(dir, "plain.json")})
+	assert.ErrorIs(t, err, errNoTykExtension)
+}
+
+func TestWriteFindings(t *testing.T) {
+	defs, err := loadDefinitions([]string{writeFile(t, "keyless.yml", keylessAPI)})
+	require.NoError(t, err)
+
+	linter, err := lint.New(lint.Config{})
+	require.NoError(t, err)
+	findings := linter.Lint(defs)
+	require.Len(t, findings, 2)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeFindings(&buf, FormatText, linter, findings, len(defs)))
+	assert.Contains(t, buf.String(), "[auth-disabled] Keyless: authentication is disabled")
+	assert.Contains(t, buf.String(), "found 2 issues in 1 API definitions")
+
+	buf.Reset()
+	require.NoError(t, writeFindings(&buf, FormatSARIF, linter, findings, len(defs)))
+
+	var sarif lint.SARIF
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &sarif))
+	assert.Equal(t, lint.SARIFVersion, sarif.Version)
+	assert.Len(t, sarif.Runs[0].Results, 2)
+
+	buf.Reset()
+	require.NoError(t, writeFindings(&buf, FormatJSON, linter, nil, len(defs)))
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
@@ -0,0 +1,192 @@
+package syncer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/apidef/oas"
+	"github.com/TykTechnologies/tyk/header"
+	"github.com/TykTechnologies/tyk/internal/gitops"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+// controlAPI is a Gateway managed through its Control API.
+type controlAPI struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func newControlAPI(gatewayURL, secret string) *controlAPI {
+	return &controlAPI{
+		url:    strings.TrimSuffix(gatewayURL, "/"),
+		secret: secret,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sends a request to the Control API and decodes the JSON response into out, when set.
+func (c *controlAPI) do(method, path string, body []byte, out interface{}) error {
+	req, err := http.NewRequest(method, c.url+"/tyk"+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(header.XTykAuthorization, c.secret)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var msg struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &msg) == nil && msg.Message != "" {
+			return fmt.Errorf("%s %s: %s", method, path, msg.Message)
+		}
+		return fmt.Errorf("%s %s: unexpected status %d", method, path, resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+func (c *controlAPI) State() (*gitops.State, error) {
+	state := &gitops.State{}
+
+	var defs []json.RawMessage
+	if err := c.do(http.MethodGet, "/apis", nil, &defs); err != nil {
+		return nil, err
+	}
+
+	for _, data := range defs {
+		var def apidef.APIDefinition
+		if err := json.Unmarshal(data, &def); err != nil {
+			return nil, err
+		}
+
+		if !def.IsOAS {
+			state.APIs = append(state.APIs, &gitops.API{ID: def.APIID, Definition: data})
+		}
+	}
+
+	var oasDefs []json.RawMessage
+	if err := c.do(http.MethodGet, "/apis/oas", nil, &oasDefs); err != nil {
+		return nil, err
+	}
+
+	for _, data := range oasDefs {
+		var oasObj oas.OAS
+		if err := json.Unmarshal(data, &oasObj); err != nil {
+			return nil, err
+		}
+
+		if ext := oasObj.GetTykExtension(); ext != nil {
+			state.APIs = append(state.APIs, &gitops.API{ID: ext.Info.ID, OAS: true, Definition: data})
+		}
+	}
+
+	var pols []json.RawMessage
+	if err := c.do(http.MethodGet, "/policies", nil, &pols); err != nil {
+		return nil, err
+	}
+
+	for _, data := range pols {
+		var pol user.Policy
+		if err := json.Unmarshal(data, &pol); err != nil {
+			return nil, err
+		}
+		state.Policies = append(state.Policies, &gitops.Policy{ID: pol.ID, Definition: data})
+	}
+
+	var certs struct {
+		CertIDs []string `json:"certs"`
+	}
+	if err := c.do(http.MethodGet, "/certs", nil, &certs); err != nil {
+		return nil, err
+	}
+
+	for _, id := range certs.CertIDs {
+		// certificates are synced without an organisation, those of organisations are left alone
+		if len(id) != sha256.Size*2 {
+			continue
+		}
+		state.Certificates = append(state.Certificates, &gitops.Certificate{ID: id})
+	}
+
+	return state, nil
+}
+
+func (c *controlAPI) PutAPI(api *gitops.API, create bool) error {
+	path := "/apis"
+	if api.OAS {
+		path = "/apis/oas"
+	}
+
+	if create {
+		return c.do(http.MethodPost, path, api.Definition, nil)
+	}
+	return c.do(http.MethodPut, path+"/"+url.PathEscape(api.ID), api.Definition, nil)
+}
+
+func (c *controlAPI) DeleteAPI(api *gitops.API) error {
+	return c.do(http.MethodDelete, "/apis/"+url.PathEscape(api.ID), nil, nil)
+}
+
+func (c *controlAPI) PutPolicy(pol *gitops.Policy, create bool) error {
+	if create {
+		return c.do(http.MethodPost, "/policies", pol.Definition, nil)
+	}
+	return c.do(http.MethodPut, "/policies/"+url.PathEscape(pol.ID), pol.Definition, nil)
+}
+
+func (c *controlAPI) DeletePolicy(pol *gitops.Policy) error {
+	return c.do(http.MethodDelete, "/policies/"+url.PathEscape(pol.ID), nil, nil)
+}
+
+func (c *controlAPI) AddCertificate(cert *gitops.Certificate) error {
+	if cert.PEM == nil {
+		return fmt.Errorf("certificate %s can't be restored, its content isn't known", cert.ID)
+	}
+	return c.do(http.MethodPost, "/certs", cert.PEM, nil)
+}
+
+func (c *controlAPI) DeleteCertificate(cert *gitops.Certificate) error {
+	return c.do(http.MethodDelete, "/certs/"+url.PathEscape(cert.ID), nil, nil)
+}
+
+func (c *controlAPI) Reload() error {
+	return c.do(http.MethodGet, "/reload?block=true", nil, nil)
+}
+
+func (c *controlAPI) LoadedAPIs() (map[string]bool, error) {
+	var defs []struct {
+		APIID string `json:"api_id"`
+	}
+	if err := c.do(http.MethodGet, "/apis", nil, &defs); err != nil {
+		return nil, err
+	}
+
+	loaded := make(map[string]bool, len(defs))
+	for _, def := range defs {
+		loaded[def.APIID] = true
+	}
+	return loaded, nil
+}
@@ -0,0 +1,118 @@
+package syncer
+
+//lint:file-ignore faillint This file should be ignored by faillint (fmt in use).
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	kingpin "github.com/alecthomas/kingpin/v2"
+
+	"github.com/TykTechnologies/tyk/internal/gitops"
+)
+
+const (
+	cmdName = "sync"
+	cmdDesc = "Syncs the APIs, policies and certificates of a Gateway with a directory"
+
+	watchDebounce = time.Second
+)
+
+var (
+	syncer = &Syncer{}
+
+	errInvalidState = errors.New("the directory holds invalid resources")
+)
+
+// Syncer wraps the sync functionality.
+type Syncer struct {
+	dir     *string
+	gateway *string
+	secret  *string
+	dryRun  *bool
+	prune   *bool
+	watch   *bool
+}
+
+// AddTo initializes a syncer object.
+func AddTo(app *kingpin.Application) {
+	cmd := app.Command(cmdName, cmdDesc)
+	syncer.dir = cmd.Arg("dir", "directory holding API definitions, policies and certificates").Required().ExistingDir()
+	syncer.gateway = cmd.Flag("gateway", "URL of the Gateway").Default("http://localhost:8080").PlaceHolder("URL").String()
+	syncer.secret = cmd.Flag("secret", "secret of the Gateway Control API").Envar("TYK_GW_SECRET").String()
+	syncer.dryRun = cmd.Flag("dry-run", "only print the changes that would be applied").Bool()
+	syncer.prune = cmd.Flag("prune", "delete the resources missing from the directory").Bool()
+	syncer.watch = cmd.Flag("watch", "keep the Gateway in sync as the directory changes").Bool()
+	cmd.Action(syncer.Sync)
+}
+
+// Sync validates the directory, prints the plan and applies it unless it's a dry run.
+func (s *Syncer) Sync(_ *kingpin.ParseContext) error {
+	target := newControlAPI(*s.gateway, *s.secret)
+
+	err := s.sync(target)
+	if !*s.watch {
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return nil
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	fmt.Printf("watching %s for changes\n", *s.dir)
+	return gitops.Watch(ctx, *s.dir, watchDebounce, func() {
+		if err := s.sync(target); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	})
+}
+
+func (s *Syncer) sync(target gitops.Target) error {
+	desired, err := gitops.Load(*s.dir)
+	if err != nil {
+		return err
+	}
+
+	errs, warnings := gitops.Validate(desired)
+	for _, f := range warnings {
+		fmt.Fprintln(os.Stderr, f)
+	}
+
+	if len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return errInvalidState
+	}
+
+	current, err := target.State()
+	if err != nil {
+		return err
+	}
+
+	plan := gitops.NewPlan(desired, current, *s.prune)
+	plan.Write(os.Stdout)
+
+	if *s.dryRun || plan.Empty() {
+		return nil
+	}
+
+	if err := gitops.Apply(target, plan); err != nil {
+		return err
+	}
+
+	fmt.Println("Apply complete.")
+	return nil
+}
@@ -0,0 +1,90 @@
+package tester
+
+//lint:file-ignore faillint This file should be ignored by faillint (fmt in use).
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	kingpin "github.com/alecthomas/kingpin/v2"
+
+	"github.com/TykTechnologies/tyk/apidef/oas/contract"
+)
+
+const (
+	cmdName = "test"
+	cmdDesc = "Runs contract tests generated from a Tyk OAS API definition against an in-process gateway"
+)
+
+var errUpstreamAndMock = errors.New("--upstream and --mock can't be used together")
+
+// Options are the options of the test command, the gateway runs the tests when Enabled is set.
+type Options struct {
+	// Enabled is set when the test command is used.
+	Enabled bool
+
+	API      *string
+	Upstream *string
+	Mock     *bool
+	JUnit    *string
+	Fuzz     *int
+	Seed     *int64
+	Headers  *map[string]string
+}
+
+// Opts holds the parsed options of the test command.
+var Opts = &Options{}
+
+// AddTo adds the test command. The in-process gateway needs the Redis storage of the
+// configuration file or of the TYK_GW_STORAGE_* environment variables.
+func AddTo(app *kingpin.Application) {
+	cmd := app.Command(cmdName, cmdDesc)
+	Opts.API = cmd.Arg("api", "the Tyk OAS API definition, or an OpenAPI document imported with the defaults").Required().String()
+	Opts.Upstream = cmd.Flag("upstream", "proxy the requests to this upstream URL instead of the upstream of the API").PlaceHolder("URL").String()
+	Opts.Mock = cmd.Flag("mock", "respond with mocks built from the OAS examples instead of proxying to the upstream").Bool()
+	Opts.JUnit = cmd.Flag("junit", "write a JUnit XML report to a file").PlaceHolder("FILE").String()
+	Opts.Fuzz = cmd.Flag("fuzz", "the number of requests generated from the schemas for each operation").Default("0").Int()
+	Opts.Seed = cmd.Flag("seed", "the seed of the generated requests").Default("1").Int64()
+	Opts.Headers = cmd.Flag("header", "a header added to every request, e.g. credentials of the API").Short('H').PlaceHolder("NAME=VALUE").StringMap()
+
+	cmd.Action(func(_ *kingpin.ParseContext) error {
+		if *Opts.Upstream != "" && *Opts.Mock {
+			return errUpstreamAndMock
+		}
+
+		Opts.Enabled = true
+		return nil
+	})
+}
+
+// WriteReport writes the results of the cases and the summary, and the JUnit report when requested.
+func (o *Options) WriteReport(w io.Writer, report *contract.Report) error {
+	for _, result := range report.Results {
+		status := "ok  "
+		if result.Err != nil {
+			status = "FAIL"
+		}
+
+		fmt.Fprintf(w, "%s %s (%d, %s)", status, result.Case.Name, result.Status, result.Duration.Round(time.Millisecond))
+		if result.Err != nil {
+			fmt.Fprintf(w, ": %v", result.Err)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%d cases, %d failures\n", len(report.Results), report.Failures())
+
+	if o.JUnit == nil || *o.JUnit == "" {
+		return nil
+	}
+
+	f, err := os.Create(*o.JUnit)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return report.WriteJUnit(f)
+}
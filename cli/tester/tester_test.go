@@ -0,0 +1,35 @@
+package tester
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef/oas/contract"
+)
+
+func TestOptions_WriteReport(t *testing.T) {
+	report := &contract.Report{
+		Name: "Pets",
+		Results: []contract.Result{
+			{Case: &contract.Case{Name: "listPets/example", OperationID: "listPets"}, Status: 200},
+			{Case: &contract.Case{Name: "getPet/example", OperationID: "getPet"}, Status: 500, Err: errors.New("undocumented status")},
+		},
+	}
+
+	junit := filepath.Join(t.TempDir(), "junit.xml")
+	opts := &Options{JUnit: &junit}
+
+	var buf bytes.Buffer
+	require.NoError(t, opts.WriteReport(&buf, report))
+	assert.Equal(t, "ok   listPets/example (200, 0s)\nFAIL getPet/example (500, 0s): undocumented status\n2 cases, 1 failures\n", buf.String())
+
+	data, err := os.ReadFile(junit)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<testsuites tests="2" failures="1"`)
+}
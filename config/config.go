@@ -302,6 +302,79 @@ type AccessLogsConfig struct {
 	Template []string `json:"template"`
 }
 
+// MiddlewareTimingConfig configures the per-middleware execution timing breakdown.
+type MiddlewareTimingConfig struct {
+	// Enabled records the execution time of every middleware in the request chain.
+	// When OpenTelemetry is enabled, each middleware execution is also recorded as a
+	// child span of the request span, regardless of the API `detailed_tracing` setting.
+	// When detailed recording is enabled for a request, the analytics record carries a
+	// `tyk-mw-timings` tag with the time spent in each middleware, in microseconds.
+	Enabled bool `json:"enabled"`
+
+	// ServerTimingHeader adds a `Server-Timing` response header with the time spent
+	// in each middleware. It has no effect unless Enabled is set.
+	ServerTimingHeader bool `json:"server_timing_header"`
+
+	// TrustedIPs lists the client IP addresses or CIDR ranges which receive the
+	// `Server-Timing` header. If empty, the header is only sent to loopback clients.
+	TrustedIPs []string `json:"trusted_ips"`
+}
+
+// AuditLogConfig configures the audit trail of the changes made through the Control API.
+type AuditLogConfig struct {
+	// Enabled records every Control API call changing the Gateway state: who made it, from where,
+	// the resource it changed, a diff of the change with secrets redacted, and the result.
+	Enabled bool `json:"enabled"`
+
+	// Sink is where audit records are written: `stdout`, `file` or `redis`. Default: `stdout`.
+	// Records written to a file or a Redis stream can be listed with the `/tyk/audit` endpoint.
+	Sink string `json:"sink"`
+
+	// Path is the file audit records are appended to, one JSON document per line, when Sink is `file`.
+	Path string `json:"path"`
+
+	// Stream is the Redis stream audit records are added to when Sink is `redis`. Default: `tyk-audit-log`.
+	Stream string `json:"stream"`
+
+	// MaxLen caps the number of records kept in the Redis stream, older records are trimmed.
+	// Default: 100000.
+	MaxLen int64 `json:"max_len"`
+}
+
+// SyncConfig configures the declarative sync of APIs, policies and certificates from a directory,
+// typically a git checkout. It requires file based APIs and policies, with `app_path` and `policies.policy_path` set.
+type SyncConfig struct {
+	// Enabled syncs the Gateway with the directory at startup. Every file is validated first and nothing
+	// is applied if any is invalid. If an API fails to load, all the changes are rolled back.
+	Enabled bool `json:"enabled"`
+
+	// Path is the directory holding classic and Tyk OAS API definitions, policies, and certificates as PEM files.
+	Path string `json:"path"`
+
+	// Watch syncs the Gateway again whenever files change in the directory.
+	Watch bool `json:"watch"`
+
+	// Prune deletes the APIs, policies and certificates missing from the directory.
+	Prune bool `json:"prune"`
+}
+
+// RevisionsConfig configures the history of the API definitions and policies changed through the Control API.
+type RevisionsConfig struct {
+	// Enabled keeps the last revisions of every API definition and policy written by the Control API,
+	// they can be listed, compared and rolled back to with the `/tyk/apis/{apiID}/revisions` and
+	// `/tyk/policies/{polID}/revisions` endpoints.
+	Enabled bool `json:"enabled"`
+
+	// Storage is where revisions are kept: `file` or `redis`. Default: `file`.
+	Storage string `json:"storage"`
+
+	// Path is the directory revisions are kept in when Storage is `file`. Default: the `revisions` directory of `app_path`.
+	Path string `json:"path"`
+
+	// Keep is the number of revisions kept for each API definition and policy. Default: 10.
+	Keep int `json:"keep"`
+}
+
 type HealthCheckConfig struct {
 	// Setting this value to `true` will enable the health-check endpoint on /Tyk/health.
 	EnableHealthChecks bool `json:"enable_health_checks"`
@@ -690,6 +763,32 @@ type SecurityConfig struct {
 
 	// CertificateExpiryMonitor configures the certificate expiry monitoring and notification feature
 	CertificateExpiryMonitor CertificateExpiryMonitorConfig `json:"certificate_expiry_monitor"`
+
+	// ControlAPICredentials are named Control API credentials limited to a set of scopes, accepted in the
+	// `X-Tyk-Authorization` header next to `secret`. Credentials can also be managed at runtime with the
+	// `/tyk/credentials` endpoints, which are stored in Redis.
+	ControlAPICredentials []ControlAPICredential `json:"control_api_credentials"`
+}
+
+// ControlAPICredential is a named Control API credential with limited access.
+type ControlAPICredential struct {
+	// Name identifies the credential in logs.
+	Name string `json:"name"`
+
+	// SecretHash is the hex encoded HMAC-SHA256 of the credential secret keyed with the gateway `secret`,
+	// the secret itself isn't stored. Rotating the gateway `secret` invalidates every credential.
+	SecretHash string `json:"secret_hash"`
+
+	// Scopes lists what the credential can do. Supported scopes are `apis:read`, `apis:write`, `keys:read`,
+	// `keys:write`, `policies:read`, `policies:write`, `certs:read`, `certs:write`, `oauth:read`,
+	// `oauth:write`, `reload` and `debug`. A `*` suffix grants both read and write, e.g. `policies:*`.
+	Scopes []string `json:"scopes"`
+
+	// OrgID limits the credential to the resources of an organisation.
+	OrgID string `json:"org_id"`
+
+	// APIIDs limits the credential to the given APIs and the keys and policies granting access to them.
+	APIIDs []string `json:"api_ids"`
 }
 
 type NewRelicConfig struct {
@@ -877,6 +976,9 @@ type Config struct {
 	// See the API section of the Tyk Gateway API for more details.
 	AppPath string `json:"app_path"`
 
+	// Sync keeps the APIs, policies and certificates of the Gateway in sync with a directory.
+	Sync SyncConfig `json:"sync"`
+
 	// If you are a Tyk Pro user, this option will enable polling the Dashboard service for API definitions.
 	// On startup Tyk will attempt to connect and download any relevant application configurations from from your Dashboard instance.
 	// The files are exactly the same as the JSON files on disk with the exception of a BSON ID supplied by the Dashboard service.
@@ -1142,6 +1244,15 @@ type Config struct {
 	// If not configured, the access log is disabled.
 	AccessLogs AccessLogsConfig `json:"access_logs"`
 
+	// MiddlewareTiming configures the per-middleware timing breakdown of each request.
+	MiddlewareTiming MiddlewareTimingConfig `json:"middleware_timing"`
+
+	// AuditLog configures the audit trail of the changes made through the Control API.
+	AuditLog AuditLogConfig `json:"audit_log"`
+
+	// Revisions configures the history of the API definitions and policies changed through the Control API.
+	Revisions RevisionsConfig `json:"revisions"`
+
 	// Section for configuring OpenTracing support
 	// Deprecated: use OpenTelemetry instead.
 	Tracer Tracer `json:"tracing"`
@@ -55,6 +55,10 @@ const (
 	SelfLooping
 	// RequestStartTime holds the time when the request entered the middleware chain
 	RequestStartTime
+	// MiddlewareTimings holds the execution time of each middleware in the chain
+	MiddlewareTimings
+	// ControlAPICredential holds the scoped credential that authenticated a Control API request
+	ControlAPICredential
 )
 
 func ctxSetSession(r *http.Request, s *user.SessionState, scheduleUpdate bool, hashKey bool) {
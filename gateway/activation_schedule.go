@@ -0,0 +1,203 @@
+package gateway
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/internal/event"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+// activationScheduleInterval is how often the activation schedules of the APIs and policies are checked.
+const activationScheduleInterval = 10 * time.Second
+
+// EventAPIScheduleMeta is the metadata structure for the APIScheduleActivated and APIScheduleDeactivated events.
+type EventAPIScheduleMeta struct {
+	EventMetaDefault
+	APIID string `json:"api_id"`
+	Name  string `json:"name"`
+	OrgID string `json:"org_id"`
+}
+
+// EventPolicyScheduleMeta is the metadata structure for the PolicyScheduleActivated and PolicyScheduleDeactivated events.
+type EventPolicyScheduleMeta struct {
+	EventMetaDefault
+	PolicyID       string `json:"policy_id"`
+	Name           string `json:"name"`
+	OrgID          string `json:"org_id"`
+	FallbackPolicy string `json:"fallback_policy,omitempty"`
+}
+
+// activationSchedules holds the state of the scheduled APIs and policies, as of the last check.
+type activationSchedules struct {
+	// checkMu serialises the checks.
+	checkMu sync.Mutex
+
+	mu sync.RWMutex
+	// inactiveAPIs holds the IDs of the inactive APIs.
+	inactiveAPIs map[string]bool
+	// inactivePolicies holds the policies applied in place of the inactive policies, by ID.
+	inactivePolicies map[string]user.Policy
+}
+
+// apiInactive returns true if the API is inactive.
+func (s *activationSchedules) apiInactive(apiID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.inactiveAPIs[apiID]
+}
+
+// policy returns the policy applied in place of an inactive policy.
+func (s *activationSchedules) policy(polID string) (user.Policy, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pol, ok := s.inactivePolicies[polID]
+	return pol, ok
+}
+
+// scheduledPolicies is the policy provider policies are applied to keys from, it replaces
+// the inactive policies with their fallback.
+type scheduledPolicies struct {
+	*Gateway
+}
+
+// PolicyByID returns the policy applied for the given policy ID.
+func (p scheduledPolicies) PolicyByID(id string) (user.Policy, bool) {
+	if pol, ok := p.schedules.policy(id); ok {
+		return pol, true
+	}
+	return p.Gateway.PolicyByID(id)
+}
+
+// checkActivationSchedules evaluates the schedules of the loaded APIs and policies at now,
+// updates their state and fires an event for every API and policy changing state.
+func (gw *Gateway) checkActivationSchedules(now time.Time) {
+	gw.schedules.checkMu.Lock()
+	defer gw.schedules.checkMu.Unlock()
+
+	gw.apisMu.RLock()
+	specs := make([]*APISpec, len(gw.apiSpecs))
+	copy(specs, gw.apiSpecs)
+	gw.apisMu.RUnlock()
+
+	gw.policiesMu.RLock()
+	policies := make(map[string]user.Policy, len(gw.policiesByID))
+	for id, pol := range gw.policiesByID {
+		policies[id] = pol
+	}
+	gw.policiesMu.RUnlock()
+
+	gw.schedules.mu.RLock()
+	wasInactiveAPI := gw.schedules.inactiveAPIs
+	wasInactivePolicy := gw.schedules.inactivePolicies
+	gw.schedules.mu.RUnlock()
+
+	var (
+		inactiveAPIs     = map[string]bool{}
+		inactivePolicies = map[string]user.Policy{}
+		fire             []func()
+	)
+
+	for _, spec := range specs {
+		if scheduleActive(spec.Schedule, now, "api", spec.APIID) {
+			if wasInactiveAPI[spec.APIID] {
+				fire = append(fire, gw.apiScheduleEvent(EventAPIScheduleActivated, spec))
+			}
+			continue
+		}
+
+		inactiveAPIs[spec.APIID] = true
+		if !wasInactiveAPI[spec.APIID] {
+			fire = append(fire, gw.apiScheduleEvent(EventAPIScheduleDeactivated, spec))
+		}
+	}
+
+	for id, pol := range policies {
+		_, wasInactive := wasInactivePolicy[id]
+
+		if scheduleActive(pol.Schedule, now, "policy", id) {
+			if wasInactive {
+				fire = append(fire, gw.policyScheduleEvent(EventPolicyScheduleActivated, id, pol))
+			}
+			continue
+		}
+
+		inactivePolicies[id] = inactivePolicy(pol, policies)
+		if !wasInactive {
+			fire = append(fire, gw.policyScheduleEvent(EventPolicyScheduleDeactivated, id, pol))
+		}
+	}
+
+	gw.schedules.mu.Lock()
+	gw.schedules.inactiveAPIs = inactiveAPIs
+	gw.schedules.inactivePolicies = inactivePolicies
+	gw.schedules.mu.Unlock()
+
+	for _, fn := range fire {
+		fn()
+	}
+}
+
+// runActivationSchedules is the scheduler job checking the activation schedules.
+func (gw *Gateway) runActivationSchedules() error {
+	gw.checkActivationSchedules(time.Now())
+	return nil
+}
+
+// scheduleActive returns true if the resource is active at now, invalid schedules are ignored.
+func scheduleActive(schedule *apidef.ActivationSchedule, now time.Time, kind, id string) bool {
+	active, err := schedule.Active(now)
+	if err != nil {
+		log.WithError(err).WithField(kind, id).Warning("Ignoring invalid activation schedule")
+	}
+	return active
+}
+
+// inactivePolicy returns the policy applied in place of the inactive policy pol: its fallback policy,
+// keeping the ID and organisation of pol, or pol marked inactive so that its keys are denied access.
+func inactivePolicy(pol user.Policy, policies map[string]user.Policy) user.Policy {
+	if fallbackID := pol.Schedule.FallbackPolicy; fallbackID != "" {
+		if fallback, ok := policies[fallbackID]; ok {
+			fallback.ID = pol.ID
+			fallback.OrgID = pol.OrgID
+			fallback.Schedule = nil
+			return fallback
+		}
+
+		log.WithField("policy", pol.ID).Warningf("Fallback policy %q not found, denying access", fallbackID)
+	}
+
+	pol.IsInactive = true
+	return pol
+}
+
+func (gw *Gateway) apiScheduleEvent(name apidef.TykEvent, spec *APISpec) func() {
+	return func() {
+		gw.FireSystemEvent(name, EventAPIScheduleMeta{
+			EventMetaDefault: EventMetaDefault{Message: fmt.Sprintf("%s: %s", event.String(name), spec.Name)},
+			APIID:            spec.APIID,
+			Name:             spec.Name,
+			OrgID:            spec.OrgID,
+		})
+	}
+}
+
+func (gw *Gateway) policyScheduleEvent(name apidef.TykEvent, id string, pol user.Policy) func() {
+	meta := EventPolicyScheduleMeta{
+		EventMetaDefault: EventMetaDefault{Message: fmt.Sprintf("%s: %s", event.String(name), pol.Name)},
+		PolicyID:         id,
+		Name:             pol.Name,
+		OrgID:            pol.OrgID,
+	}
+	if name == EventPolicyScheduleDeactivated && pol.Schedule != nil {
+		meta.FallbackPolicy = pol.Schedule.FallbackPolicy
+	}
+
+	return func() {
+		gw.FireSystemEvent(name, meta)
+	}
+}
@@ -0,0 +1,121 @@
+package gateway
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/test"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+func TestActivationSchedule(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	t.Run("api", func(t *testing.T) {
+		ch := ts.captureSystemEvents(EventAPIScheduleActivated, EventAPIScheduleDeactivated)
+
+		scheduled := func(start string, resp *apidef.MaintenanceResponse) func(*APISpec) {
+			return func(spec *APISpec) {
+				spec.APIID = "scheduled"
+				spec.Name = "scheduled"
+				spec.Proxy.ListenPath = "/scheduled/"
+				spec.Schedule = &apidef.ActivationSchedule{Enabled: true, Start: start, MaintenanceResponse: resp}
+			}
+		}
+
+		ts.Gw.BuildAndLoadAPI(scheduled(future, nil))
+		_, _ = ts.Run(t, test.TestCase{Path: "/scheduled/", Code: http.StatusServiceUnavailable, BodyMatch: ErrAPIInactive.Error()})
+
+		got := collectEvents(ch)
+		require.Len(t, got[EventAPIScheduleDeactivated], 1)
+		assert.Equal(t, "scheduled", got[EventAPIScheduleDeactivated][0].Meta.(EventAPIScheduleMeta).APIID)
+
+		ts.Gw.BuildAndLoadAPI(scheduled(future, &apidef.MaintenanceResponse{
+			Code:    http.StatusOK,
+			Body:    "down for maintenance",
+			Headers: map[string]string{"Retry-After": "3600"},
+		}))
+		_, _ = ts.Run(t, test.TestCase{
+			Path: "/scheduled/", Code: http.StatusOK, BodyMatch: "^down for maintenance$",
+			HeadersMatch: map[string]string{"Retry-After": "3600"},
+		})
+		assert.Empty(t, collectEvents(ch), "the API stays inactive")
+
+		ts.Gw.BuildAndLoadAPI(scheduled(past, nil))
+		_, _ = ts.Run(t, test.TestCase{Path: "/scheduled/", Code: http.StatusOK})
+
+		got = collectEvents(ch)
+		require.Len(t, got[EventAPIScheduleActivated], 1)
+		assert.Empty(t, got[EventAPIScheduleDeactivated])
+	})
+
+	t.Run("policy", func(t *testing.T) {
+		ch := ts.captureSystemEvents(EventPolicyScheduleActivated, EventPolicyScheduleDeactivated)
+
+		ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+			spec.APIID = "policy-scheduled"
+			spec.UseKeylessAccess = false
+			spec.Proxy.ListenPath = "/policy-scheduled/"
+		})
+
+		polID := ts.CreatePolicy(func(p *user.Policy) {
+			p.AccessRights = map[string]user.AccessDefinition{"policy-scheduled": {APIID: "policy-scheduled", Versions: []string{"v1"}}}
+			p.Schedule = &apidef.ActivationSchedule{Enabled: true, End: past}
+		})
+
+		_, key := ts.CreateSession(func(s *user.SessionState) {
+			s.ApplyPolicies = []string{polID}
+		})
+		authHeaders := map[string]string{"Authorization": key}
+
+		ts.Gw.checkActivationSchedules(time.Now())
+		_, _ = ts.Run(t, test.TestCase{Path: "/policy-scheduled/", Headers: authHeaders, Code: http.StatusForbidden})
+
+		got := collectEvents(ch)
+		require.Len(t, got[EventPolicyScheduleDeactivated], 1)
+		assert.Equal(t, polID, got[EventPolicyScheduleDeactivated][0].Meta.(EventPolicyScheduleMeta).PolicyID)
+
+		pol, ok := ts.Gw.PolicyByID(polID)
+		require.True(t, ok)
+		assert.False(t, pol.IsInactive, "the stored policy isn't changed")
+
+		pol.Schedule.End = future
+		ts.Gw.SetPoliciesByID(pol)
+
+		ts.Gw.checkActivationSchedules(time.Now())
+		_, _ = ts.Run(t, test.TestCase{Path: "/policy-scheduled/", Headers: authHeaders, Code: http.StatusOK})
+		require.Len(t, collectEvents(ch)[EventPolicyScheduleActivated], 1)
+	})
+}
+
+func TestInactivePolicy(t *testing.T) {
+	policies := map[string]user.Policy{
+		"fallback": {ID: "fallback", OrgID: "other", Rate: 1, Per: 60},
+	}
+
+	pol := user.Policy{ID: "scheduled", OrgID: "org", Rate: 100, Per: 60, Schedule: &apidef.ActivationSchedule{Enabled: true}}
+
+	denied := inactivePolicy(pol, policies)
+	assert.True(t, denied.IsInactive)
+	assert.Equal(t, float64(100), denied.Rate)
+
+	pol.Schedule.FallbackPolicy = "fallback"
+	fallback := inactivePolicy(pol, policies)
+	assert.False(t, fallback.IsInactive)
+	assert.Equal(t, "scheduled", fallback.ID)
+	assert.Equal(t, "org", fallback.OrgID)
+	assert.Equal(t, float64(1), fallback.Rate)
+	assert.Nil(t, fallback.Schedule)
+
+	pol.Schedule.FallbackPolicy = "missing"
+	assert.True(t, inactivePolicy(pol, policies).IsInactive)
+}
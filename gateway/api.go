@@ -52,6 +52,7 @@ import (
 	"github.com/TykTechnologies/tyk/internal/osutil"
 	"github.com/TykTechnologies/tyk/internal/otel"
 	"github.com/TykTechnologies/tyk/internal/redis"
+	"github.com/TykTechnologies/tyk/internal/revision"
 	"github.com/TykTechnologies/tyk/internal/uuid"
 
 	"github.com/TykTechnologies/tyk/apidef/oas"
@@ -64,6 +65,8 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/apidef/asyncapi"
+	"github.com/TykTechnologies/tyk/apidef/importer"
 	"github.com/TykTechnologies/tyk/ctx"
 	"github.com/TykTechnologies/tyk/header"
 	"github.com/TykTechnologies/tyk/storage"
@@ -323,13 +326,41 @@ func resetAPILimits(accessRights map[string]user.AccessDefinition) {
 }
 
 func (gw *Gateway) doAddOrUpdate(keyName string, newSession *user.SessionState, dontReset bool, isHashed bool) error {
-	// field last_updated plays an important role in in-mem rate limiter
-	// so update last_updated to current timestamp only if suppress_reset wasn't set to 1
-	if !dontReset {
-		newSession.LastUpdated = strconv.Itoa(int(time.Now().Unix()))
+	return gw.doAddOrUpdateIf(keyName, newSession, dontReset, isHashed, "")
+}
+
+// doAddOrUpdateIf stores the session as doAddOrUpdate does. When ifMatch is set, the session is
+// only stored if the stored one matches it, storage.ErrKeyChanged is returned otherwise.
+func (gw *Gateway) doAddOrUpdateIf(keyName string, newSession *user.SessionState, dontReset bool, isHashed bool, ifMatch string) error {
+	lifetime, resetQuota, err := gw.prepareKeySession(keyName, newSession, dontReset, isHashed)
+	if err != nil {
+		return err
 	}
 
-	logger := log.WithFields(logrus.Fields{
+	if ifMatch == "" {
+		if resetQuota {
+			gw.GlobalSessionManager.ResetQuota(keyName, newSession, isHashed)
+		}
+
+		if err := gw.GlobalSessionManager.UpdateSession(keyName, newSession, lifetime, isHashed); err != nil {
+			return err
+		}
+	} else {
+		sessions, ok := gw.GlobalSessionManager.(conditionalSessionHandler)
+		if !ok {
+			return errConditionalWriteUnsupported
+		}
+
+		if err := sessions.UpdateSessionIf(keyName, newSession, lifetime, isHashed, keyETagMatcher(ifMatch)); err != nil {
+			return err
+		}
+
+		if resetQuota {
+			gw.GlobalSessionManager.ResetQuota(keyName, newSession, isHashed)
+		}
+	}
+
+	log.WithFields(logrus.Fields{
 		"prefix":      "api",
 		"key":         gw.obfuscateKey(keyName),
 		"org_id":      newSession.OrgID,
@@ -339,8 +370,29 @@ func (gw *Gateway) doAddOrUpdate(keyName string, newSession *user.SessionState,
 		"user_ip":     "--",
 		"path":        "--",
 		"server_name": "system",
+	}).Info("Key added or updated.")
+	return nil
+}
+
+// prepareKeySession applies the trial period, the policies and the quota renewal of the APIs
+// a key has access to, or of every API for a master key, without storing it. It returns the
+// lifetime to store the key with, and whether its quota counters must be reset.
+func (gw *Gateway) prepareKeySession(keyName string, newSession *user.SessionState, dontReset bool, isHashed bool) (int64, bool, error) {
+	// field last_updated plays an important role in in-mem rate limiter
+	// so update last_updated to current timestamp only if suppress_reset wasn't set to 1
+	if !dontReset {
+		newSession.LastUpdated = strconv.Itoa(int(time.Now().Unix()))
+	}
+
+	logger := log.WithFields(logrus.Fields{
+		"prefix": "api",
+		"key":    gw.obfuscateKey(keyName),
+		"org_id": newSession.OrgID,
 	})
 
+	var specs []*APISpec
+	resetQuota := false
+
 	if len(newSession.AccessRights) > 0 {
 		// reset API-level limit to empty APILimit if any has a zero-value
 		resetAPILimits(newSession.AccessRights)
@@ -350,51 +402,56 @@ func (gw *Gateway) doAddOrUpdate(keyName string, newSession *user.SessionState,
 			apiSpec := gw.getApiSpec(apiId)
 			if apiSpec == nil {
 				logger.WithField("api_id", apiId).Warn("Can't find active API, storing anyway")
-			}
-
-			if apiSpec != nil {
+			} else {
 				gw.checkAndApplyTrialPeriod(keyName, newSession, isHashed)
 			}
 
 			// Lets reset keys if they are edited by admin
-			if apiSpec == nil || !apiSpec.DontSetQuotasOnCreate {
+			if !dontReset && (apiSpec == nil || !apiSpec.DontSetQuotasOnCreate) {
 				// Reset quote by default
-				if !dontReset {
-					gw.GlobalSessionManager.ResetQuota(keyName, newSession, isHashed)
-					newSession.QuotaRenews = time.Now().Unix() + newSession.QuotaRenewalRate
-				}
+				resetQuota = true
+				newSession.QuotaRenews = time.Now().Unix() + newSession.QuotaRenewalRate
 			}
 
-			// apply polices (if any) and save key
-			if err := gw.applyPoliciesAndSave(keyName, newSession, apiSpec, isHashed); err != nil {
-				return err
-			}
+			specs = append(specs, apiSpec)
 		}
 	} else {
 		// nothing defined, add key to ALL
 		if !gw.GetConfig().AllowMasterKeys {
 			logger.Error("Master keys disallowed in configuration, key not added.")
-			return errors.New("Master keys not allowed")
+			return 0, false, errors.New("Master keys not allowed")
 		}
 		logger.Warning("No API Access Rights set, adding key to ALL.")
-		gw.apisMu.RLock()
-		defer gw.apisMu.RUnlock()
 
+		gw.apisMu.RLock()
 		for _, spec := range gw.apisByID {
+			specs = append(specs, spec)
+		}
+		gw.apisMu.RUnlock()
+
+		for range specs {
 			if !dontReset {
-				gw.GlobalSessionManager.ResetQuota(keyName, newSession, isHashed)
+				resetQuota = true
 				newSession.QuotaRenews = time.Now().Unix() + newSession.QuotaRenewalRate
 			}
 			gw.checkAndApplyTrialPeriod(keyName, newSession, isHashed)
-			// apply polices (if any) and save key
-			if err := gw.applyPoliciesAndSave(keyName, newSession, spec, isHashed); err != nil {
-				return err
-			}
 		}
 	}
 
-	logger.Info("Key added or updated.")
-	return nil
+	// apply polices (if any)
+	for _, spec := range specs {
+		mw := &BaseMiddleware{
+			Spec: spec,
+			Gw:   gw,
+		}
+
+		if err := mw.ApplyPolicies(newSession); err != nil {
+			return 0, false, err
+		}
+	}
+
+	// calculate lifetime considering access rights
+	return gw.ApplyLifetime(newSession, specs...), resetQuota, nil
 }
 
 // ---- TODO: This changes the URL structure of the API completely ----
@@ -438,9 +495,90 @@ func (gw *Gateway) basicAuthHashAlgo() string {
 	return algo
 }
 
+// preserveKeyFields keeps the fields of the original key which can't be changed by an update.
+// When suppressReset is set, the quota and rate limit periods aren't reset either.
+func (gw *Gateway) preserveKeyFields(newSession *user.SessionState, originalKey user.SessionState, suppressReset bool) (interface{}, int) {
+	isCertificateChanged := newSession.Certificate != originalKey.Certificate
+	if isCertificateChanged {
+		if newSession.Certificate == "" {
+			log.Error("Key must contain a certificate")
+			return apiError("Key cannot be used without a certificate"), http.StatusBadRequest
+		}
+
+		// check that the certificate exists in the system
+		_, err := gw.CertificateManager.GetRaw(newSession.Certificate)
+		if err != nil {
+			log.Error("Key must contain an existing certificate")
+			return apiError("Key must be used with an existent certificate"), http.StatusBadRequest
+		}
+	}
+
+	// preserve the creation date
+	newSession.DateCreated = originalKey.DateCreated
+
+	// don't change fields related to quota and rate limiting if was passed as "suppress_reset=1"
+	if suppressReset {
+		// save existing quota_renews and last_updated if suppress_reset was passed
+		// (which means don't reset quota or rate counters)
+		// - leaving quota_renews as 0 will force quota limiter to start new renewal period
+		// - setting new last_updated with force rate limiter to start new "per" rating period
+
+		// on session level
+		newSession.QuotaRenews = originalKey.QuotaRenews
+		newSession.LastUpdated = originalKey.LastUpdated
+
+		// on ACL API limit level
+		for apiID, access := range originalKey.AccessRights {
+			if access.Limit.IsEmpty() {
+				continue
+			}
+			if newAccess, ok := newSession.AccessRights[apiID]; ok && !newAccess.Limit.IsEmpty() {
+				newAccess.Limit.QuotaRenews = access.Limit.QuotaRenews
+				newSession.AccessRights[apiID] = newAccess
+			}
+		}
+	}
+
+	return nil, http.StatusOK
+}
+
+// updateKeyCredentials hashes the basic auth password of a created key, or of an updated key when it
+// changed, and keeps the expiry of the original key when the new one is in the past.
+func (gw *Gateway) updateKeyCredentials(newSession *user.SessionState, originalKey user.SessionState, create bool) {
+	//set the original expiry if the content in payload is a past time
+	if time.Now().After(time.Unix(newSession.Expires, 0)) && newSession.Expires > 1 {
+		newSession.Expires = originalKey.Expires
+	}
+
+	// Update our session object (create it)
+	if newSession.IsBasicAuth() {
+		// If we are using a basic auth user, then we need to make the keyname explicit against the OrgId in order to differentiate it
+		// Only if it's NEW
+		if create {
+			// It's a create, so lets hash the password
+			gw.setBasicAuthSessionPassword(newSession)
+		} else if originalKey.BasicAuthData.Password != newSession.BasicAuthData.Password {
+			// passwords dont match assume it's new, lets hash it
+			log.Debug("Passwords dont match, original: ", originalKey.BasicAuthData.Password)
+			log.Debug("New: newSession.BasicAuthData.Password")
+			log.Debug("Changing password")
+			gw.setBasicAuthSessionPassword(newSession)
+		}
+	} else if originalKey.IsBasicAuth() {
+		// preserve basic auth data
+		newSession.BasicAuthData.Hash = originalKey.BasicAuthData.Hash
+		newSession.BasicAuthData.Password = originalKey.BasicAuthData.Password
+	}
+}
+
 func (gw *Gateway) handleAddOrUpdate(keyName string, r *http.Request, isHashed bool) (interface{}, int) {
 	suppressReset := r.URL.Query().Get("suppress_reset") == "1"
 
+	var ifMatch string
+	if r.Method == http.MethodPut {
+		ifMatch = r.Header.Get(header.IfMatch)
+	}
+
 	// decode payload
 	newSession := &user.SessionState{}
 
@@ -468,83 +606,20 @@ func (gw *Gateway) handleAddOrUpdate(keyName string, r *http.Request, isHashed b
 		}
 		originalKey = key.Clone()
 
-		isCertificateChanged := newSession.Certificate != originalKey.Certificate
-		if isCertificateChanged {
-			if newSession.Certificate == "" {
-				log.Error("Key must contain a certificate")
-				return apiError("Key cannot be used without a certificate"), http.StatusBadRequest
-			}
-
-			// check that the certificate exists in the system
-			_, err := gw.CertificateManager.GetRaw(newSession.Certificate)
-			if err != nil {
-				log.Error("Key must contain an existing certificate")
-				return apiError("Key must be used with an existent certificate"), http.StatusBadRequest
-			}
-		}
-
-		// preserve the creation date
-		newSession.DateCreated = originalKey.DateCreated
-
-		// don't change fields related to quota and rate limiting if was passed as "suppress_reset=1"
-		if suppressReset {
-			// save existing quota_renews and last_updated if suppress_reset was passed
-			// (which means don't reset quota or rate counters)
-			// - leaving quota_renews as 0 will force quota limiter to start new renewal period
-			// - setting new last_updated with force rate limiter to start new "per" rating period
-
-			// on session level
-			newSession.QuotaRenews = originalKey.QuotaRenews
-			newSession.LastUpdated = originalKey.LastUpdated
-
-			// on ACL API limit level
-			for apiID, access := range originalKey.AccessRights {
-				if access.Limit.IsEmpty() {
-					continue
-				}
-				if newAccess, ok := newSession.AccessRights[apiID]; ok && !newAccess.Limit.IsEmpty() {
-					newAccess.Limit.QuotaRenews = access.Limit.QuotaRenews
-					newSession.AccessRights[apiID] = newAccess
-				}
-			}
+		if obj, code := gw.preserveKeyFields(newSession, originalKey, suppressReset); code != http.StatusOK {
+			return obj, code
 		}
 	} else {
 		newSession.DateCreated = time.Now()
 		keyName = gw.generateToken(newSession.OrgID, keyName)
 	}
 
-	//set the original expiry if the content in payload is a past time
-	if time.Now().After(time.Unix(newSession.Expires, 0)) && newSession.Expires > 1 {
-		newSession.Expires = originalKey.Expires
-	}
-
-	// Update our session object (create it)
-	if newSession.IsBasicAuth() {
-		// If we are using a basic auth user, then we need to make the keyname explicit against the OrgId in order to differentiate it
-		// Only if it's NEW
-		switch r.Method {
-		case http.MethodPost:
-			// It's a create, so lets hash the password
-			gw.setBasicAuthSessionPassword(newSession)
-		case http.MethodPut:
-			if originalKey.BasicAuthData.Password != newSession.BasicAuthData.Password {
-				// passwords dont match assume it's new, lets hash it
-				log.Debug("Passwords dont match, original: ", originalKey.BasicAuthData.Password)
-				log.Debug("New: newSession.BasicAuthData.Password")
-				log.Debug("Changing password")
-				gw.setBasicAuthSessionPassword(newSession)
-			}
-		}
-	} else if originalKey.IsBasicAuth() {
-		// preserve basic auth data
-		newSession.BasicAuthData.Hash = originalKey.BasicAuthData.Hash
-		newSession.BasicAuthData.Password = originalKey.BasicAuthData.Password
-	}
+	gw.updateKeyCredentials(newSession, originalKey, r.Method == http.MethodPost)
 
 	if r.Method == http.MethodPost || storage.TokenOrg(keyName) != "" {
 		// use new key format if key gets created or updating key with new format
-		if err := gw.doAddOrUpdate(keyName, newSession, suppressReset, isHashed); err != nil {
-			return apiError("Failed to create key, ensure security settings are correct."), http.StatusInternalServerError
+		if err := gw.doAddOrUpdateIf(keyName, newSession, suppressReset, isHashed, ifMatch); err != nil {
+			return keyUpdateError(err)
 		}
 	} else {
 
@@ -557,8 +632,8 @@ func (gw *Gateway) handleAddOrUpdate(keyName string, r *http.Request, isHashed b
 			keyName = newFormatKey
 		}
 
-		if err := gw.doAddOrUpdate(keyName, newSession, suppressReset, isHashed); err != nil {
-			return apiError("Failed to create key, ensure security settings are correct."), http.StatusInternalServerError
+		if err := gw.doAddOrUpdateIf(keyName, newSession, suppressReset, isHashed, ifMatch); err != nil {
+			return keyUpdateError(err)
 		}
 	}
 
@@ -950,6 +1025,11 @@ func (gw *Gateway) handleAddOrUpdatePolicy(polID string, r *http.Request) (inter
 		return apiError(errMsg), http.StatusBadRequest
 	}
 
+	if err := newPol.Schedule.Validate(); err != nil {
+		log.WithError(err).Error("Rejected policy with an invalid schedule")
+		return apiError(err.Error()), http.StatusBadRequest
+	}
+
 	root, err := gw.newPolicyPathRoot()
 	if err != nil {
 		log.WithError(err).Error("Unable to access the policy storage root path.")
@@ -962,6 +1042,8 @@ func (gw *Gateway) handleAddOrUpdatePolicy(polID string, r *http.Request) (inter
 		return apiError("Marshalling failed"), http.StatusInternalServerError
 	}
 
+	gw.recordPreviousRevision(revision.KindPolicy, newPol.ID)
+
 	if err := root.WriteFile(newPol.ID+".json", asByte, 0644); err != nil {
 		log.Error("Failed to create file! - ", err)
 		return apiError("Failed to create file!"), http.StatusInternalServerError
@@ -971,6 +1053,7 @@ func (gw *Gateway) handleAddOrUpdatePolicy(polID string, r *http.Request) (inter
 	if r.Method == http.MethodPost {
 		action = "added"
 	}
+	gw.recordRevision(revision.KindPolicy, newPol.ID, action, 0)
 
 	response := apiModifyKeySuccess{
 		Key:    newPol.ID,
@@ -997,6 +1080,8 @@ func (gw *Gateway) handleDeletePolicy(polID string) (interface{}, int) {
 		return apiError("Delete failed"), http.StatusInternalServerError
 	}
 
+	gw.recordPreviousRevision(revision.KindPolicy, polID)
+
 	if err := root.Remove(defFilePath); err != nil {
 		log.Warningf("Delete failed: %v", err)
 		return apiError("Delete failed"), http.StatusInternalServerError
@@ -1074,6 +1159,29 @@ func (gw *Gateway) handleGetAPIOAS(apiID string, modePublic bool) (interface{},
 
 }
 
+// handleGetAPIAsyncAPI returns the AsyncAPI document of a Tyk Streams API, served on the public
+// host of the gateway, the one of the OAS servers, or the custom domain of the API.
+func (gw *Gateway) handleGetAPIAsyncAPI(apiID string) (interface{}, int) {
+	obj, code := gw.handleGetAPIOAS(apiID, false)
+	apiOAS, ok := obj.(*oas.OAS)
+	if code != http.StatusOK || !ok {
+		return obj, code
+	}
+
+	// export a copy, the loaded API is shared with the proxy
+	apiOAS, err := apiOAS.Clone()
+	if err != nil {
+		return apiError(err.Error()), http.StatusInternalServerError
+	}
+
+	doc, err := asyncapi.Export(apiOAS, buildServerRegenerationConfig(gw.GetConfig()).DefaultHost)
+	if err != nil {
+		return apiError(err.Error()), http.StatusBadRequest
+	}
+
+	return doc, http.StatusOK
+}
+
 func (gw *Gateway) handleAddApi(r *http.Request, fs afero.Fs, oasEndpoint bool) (interface{}, int) {
 	var (
 		newDef apidef.APIDefinition
@@ -1133,7 +1241,13 @@ func (gw *Gateway) handleAddApi(r *http.Request, fs afero.Fs, oasEndpoint bool)
 		if err := gw.handleOASServersForNewAPI(&newDef, &oasObj, versioningParams); err != nil {
 			return apiError(err.Error()), http.StatusBadRequest
 		}
+	}
 
+	if isDryRun(r) {
+		return gw.handleDryRunAPI(&newDef, &oasObj, oasEndpoint)
+	}
+
+	if oasEndpoint {
 		newDef.IsOAS = true
 		oasObj.GetTykExtension().Info.ID = newDef.APIID
 		err, errCode := gw.writeOASAndAPIDefToFile(fs, &newDef, &oasObj)
@@ -1150,6 +1264,8 @@ func (gw *Gateway) handleAddApi(r *http.Request, fs afero.Fs, oasEndpoint bool)
 		}
 	}
 
+	gw.recordRevision(revision.KindAPI, newDef.APIID, revisionActionAdded, 0)
+
 	if !versionParams.IsEmpty(lib.BaseAPIID) {
 		baseAPI := gw.getApiSpec(versionParams.Get(lib.BaseAPIID))
 
@@ -1233,6 +1349,19 @@ func (gw *Gateway) handleUpdateApi(apiID string, r *http.Request, fs afero.Fs, o
 		return *validationErr, http.StatusBadRequest
 	}
 
+	if isDryRun(r) {
+		// the servers are regenerated as they would be stored, the child APIs are left as they are
+		if oasEndpoint {
+			if err := gw.regenerateOASServers(spec, &newDef, &oasObj, nil, ""); err != nil {
+				return apiError(err.Error()), http.StatusBadRequest
+			}
+		}
+
+		return gw.handleDryRunAPI(&newDef, &oasObj, oasEndpoint)
+	}
+
+	gw.recordPreviousRevision(revision.KindAPI, apiID)
+
 	if oasEndpoint && spec.IsOAS {
 		// Handle OAS server regeneration and cascade updates for API update
 		if err := gw.handleOASServersForUpdate(spec, &newDef, &oasObj); err != nil {
@@ -1255,6 +1384,8 @@ func (gw *Gateway) handleUpdateApi(apiID string, r *http.Request, fs afero.Fs, o
 		}
 	}
 
+	gw.recordRevision(revision.KindAPI, newDef.APIID, revisionActionModified, 0)
+
 	response := apiModifyKeySuccess{
 		Key:    newDef.APIID,
 		Status: "ok",
@@ -1328,6 +1459,9 @@ func (gw *Gateway) handleDeleteAPI(apiID string) (interface{}, int) {
 		return apiError("Delete failed"), http.StatusInternalServerError
 	}
 
+	// the history outlives the API, so it can be restored
+	gw.recordPreviousRevision(revision.KindAPI, apiID)
+
 	os.Remove(defFilePath)
 	if spec.IsOAS {
 		os.Remove(defOASFilePath)
@@ -1611,22 +1745,58 @@ func (gw *Gateway) apiOASPatchHandler(w http.ResponseWriter, r *http.Request) {
 
 func (gw *Gateway) apiOASExportHandler(w http.ResponseWriter, r *http.Request) {
 	const (
-		baseFileName       = "TykOasApiDef"
-		baseFileNamePublic = "oas"
-		fileTypeJSON       = "json"
+		baseFileName         = "TykOasApiDef"
+		baseFileNamePublic   = "oas"
+		baseFileNameAsyncAPI = "asyncapi"
+		fileTypeJSON         = "json"
+		modeConsumer         = "consumer"
 	)
 	var (
-		apiID       = mux.Vars(r)["apiID"]
-		fileName    = baseFileName
-		scopePublic = r.URL.Query().Get("mode") == "public"
-		obj         interface{}
-		code        int
+		apiID        = mux.Vars(r)["apiID"]
+		fileName     = baseFileName
+		scopePublic  = r.URL.Query().Get("mode") == "public"
+		viewConsumer = r.URL.Query().Get("mode") == modeConsumer
+		obj          interface{}
+		code         int
 	)
 
-	if scopePublic {
+	if scopePublic || viewConsumer {
 		fileName = baseFileNamePublic
 	}
 
+	if r.URL.Query().Get("format") == baseFileNameAsyncAPI {
+		if apiID == "" {
+			doJSONWrite(w, http.StatusBadRequest, apiError("AsyncAPI export requires an API ID"))
+			return
+		}
+
+		obj, code = gw.handleGetAPIAsyncAPI(apiID)
+		doJSONExport(w, code, obj, fmt.Sprintf("%s-%s.%s", baseFileNameAsyncAPI, apiID, fileTypeJSON))
+		return
+	}
+
+	if viewConsumer {
+		accessRights, err := gw.consumerViewAccessRights(apiID, r.URL.Query())
+		if err != nil {
+			code = http.StatusInternalServerError
+			if errors.Is(err, errConsumerViewKeyNotFound) || errors.Is(err, errConsumerViewPolicyNotFound) {
+				code = http.StatusNotFound
+			}
+			doJSONWrite(w, code, apiError(err.Error()))
+			return
+		}
+
+		if apiID != "" {
+			obj, code = gw.handleGetAPIOASConsumerView(apiID, accessRights)
+			fileName += "-" + apiID
+		} else {
+			obj, code = gw.handleGetAPIListOASConsumerView(accessRights)
+		}
+
+		doJSONExport(w, code, obj, fmt.Sprintf("%s.%s", fileName, fileTypeJSON))
+		return
+	}
+
 	if apiID != "" {
 		log.Debugf("Requesting API definition for %q", apiID)
 		obj, code = gw.handleGetAPIOAS(apiID, scopePublic)
@@ -1663,31 +1833,50 @@ func (gw *Gateway) keyHandler(w http.ResponseWriter, r *http.Request) {
 		obj, code = gw.handleAddOrUpdate(keyName, r, isHashed)
 	case http.MethodPut:
 		obj, code = gw.handleAddOrUpdate(keyName, r, isHashed)
-		if code != http.StatusOK && hashKeyFunction != "" {
+		if code != http.StatusOK && code != http.StatusPreconditionFailed && hashKeyFunction != "" {
 			// try to use legacy key format
 			obj, code = gw.handleAddOrUpdate(origKeyName, r, isHashed)
 		}
 	case http.MethodGet:
 		if keyName != "" {
 			// Return single key detail
+			detailKeyName := keyName
 			obj, code = gw.handleGetDetail(keyName, apiID, orgID, isHashed)
 			if code != http.StatusOK && hashKeyFunction != "" {
 				// try to use legacy key format
+				detailKeyName = origKeyName
 				obj, code = gw.handleGetDetail(origKeyName, apiID, orgID, isHashed)
 			}
+
+			if code == http.StatusOK {
+				etagOrgID := orgID
+				if spec := gw.getApiSpec(apiID); spec != nil {
+					etagOrgID = spec.OrgID
+				}
+				if etag := gw.keyETag(detailKeyName, etagOrgID, isHashed); etag != "" {
+					w.Header().Set(header.ETag, etag)
+				}
+			}
 		} else {
 			// Return list of keys
-			if gwConfig.HashKeys {
-				// get all keys is disabled by default
-				if !gwConfig.EnableHashedKeysListing {
-					doJSONWrite(
-						w,
-						http.StatusNotFound,
-						apiError("Hashed key listing is disabled in config (enable_hashed_keys_listing)"),
-					)
+			// get all keys is disabled by default
+			if gwConfig.HashKeys && !gwConfig.EnableHashedKeysListing {
+				doJSONWrite(
+					w,
+					http.StatusNotFound,
+					apiError("Hashed key listing is disabled in config (enable_hashed_keys_listing)"),
+				)
+				return
+			}
+
+			if isKeySearch(r.URL.Query()) {
+				q, err := parseKeySearchQuery(r.URL.Query())
+				if err != nil {
+					doJSONWrite(w, http.StatusBadRequest, apiError(err.Error()))
 					return
 				}
-
+				obj, code = gw.handleSearchKeys(q)
+			} else if gwConfig.HashKeys {
 				// we don't use filter for hashed keys
 				obj, code = gw.handleGetAllKeys("")
 			} else {
@@ -1698,6 +1887,23 @@ func (gw *Gateway) keyHandler(w http.ResponseWriter, r *http.Request) {
 
 	case http.MethodDelete:
 		// Remove a key
+		if ifMatch := r.Header.Get(header.IfMatch); ifMatch != "" {
+			// changes made with If-Match are serialised, see withETag
+			gw.etagMu.Lock()
+			defer gw.etagMu.Unlock()
+
+			keyNames := []string{keyName}
+			if hashKeyFunction != "" {
+				// try to use legacy key format
+				keyNames = append(keyNames, origKeyName)
+			}
+
+			var ok bool
+			if obj, code, ok = gw.checkKeyIfMatch(ifMatch, orgID, isHashed, keyNames...); !ok {
+				break
+			}
+		}
+
 		if !isHashed {
 			obj, code = gw.handleDeleteKey(keyName, orgID, apiID, true)
 		} else {
@@ -3118,6 +3324,45 @@ func (gw *Gateway) makeImportedOASTykAPI(next http.HandlerFunc) http.HandlerFunc
 	}
 }
 
+// importFromSource converts the Postman collection or HAR file in the request body to an OpenAPI
+// document when the source query parameter is set, so that it's imported as a Tyk OAS API.
+func (gw *Gateway) importFromSource(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		source := strings.TrimSpace(r.URL.Query().Get("source"))
+		if source == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		imp, err := importer.GetImporterForSource(importer.APIImporterSource(source))
+		oasImporter, ok := imp.(importer.OASImporter)
+		if err != nil || !ok {
+			doJSONWrite(w, http.StatusBadRequest, apiError(fmt.Sprintf("Unsupported import source %q", source)))
+			return
+		}
+
+		if err := imp.LoadFrom(r.Body); err != nil {
+			doJSONWrite(w, http.StatusBadRequest, apiError("Couldn't decode the "+source+" import"))
+			return
+		}
+
+		doc, err := oasImporter.ToOAS()
+		if err != nil {
+			doJSONWrite(w, http.StatusBadRequest, apiError(err.Error()))
+			return
+		}
+
+		docInBytes, err := doc.MarshalJSON()
+		if err != nil {
+			doJSONWrite(w, http.StatusInternalServerError, apiError(err.Error()))
+			return
+		}
+
+		r.Body = ioutil.NopCloser(bytes.NewReader(docInBytes))
+		next.ServeHTTP(w, r)
+	}
+}
+
 // ctxSetCacheOptions sets a cache key to use for the http request
 func ctxSetCacheOptions(r *http.Request, options *cacheOptions) {
 	setCtxValue(r, ctx.CacheOptions, options)
@@ -3488,7 +3733,7 @@ func extractOASObjFromReq(reqBody io.Reader) ([]byte, *oas.OAS, error) {
 	}
 
 	loader := openapi3.NewLoader()
-	t, err := loader.LoadFromData(reqBodyInBytes)
+	t, err := oas.LoadFromData(loader, reqBodyInBytes)
 	if err != nil {
 		return nil, nil, ErrRequestMalformed
 	}
@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/TykTechnologies/tyk/config"
+)
+
+// controlAPICredentialCreated is returned once when a credential is created, it's the only time the secret is shown.
+type controlAPICredentialCreated struct {
+	controlAPICredential
+	Secret string `json:"secret"`
+}
+
+// controlAPICredentialsHandler lists the scoped Control API credentials or creates one stored in Redis.
+func (gw *Gateway) controlAPICredentialsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		doJSONWrite(w, http.StatusOK, gw.controlAPICredentials.list())
+		return
+	}
+
+	var conf config.ControlAPICredential
+	if err := json.NewDecoder(r.Body).Decode(&conf); err != nil {
+		doJSONWrite(w, http.StatusBadRequest, apiError("Request malformed"))
+		return
+	}
+
+	cred, secret, err := gw.controlAPICredentials.create(conf)
+	if err != nil {
+		doJSONWrite(w, http.StatusBadRequest, apiError(err.Error()))
+		return
+	}
+
+	mainLog.WithField("credential", cred.Name).Info("Control API credential created")
+	doJSONWrite(w, http.StatusCreated, controlAPICredentialCreated{controlAPICredential: cred.redacted(), Secret: secret})
+}
+
+// controlAPICredentialDeleteHandler deletes a credential stored in Redis.
+func (gw *Gateway) controlAPICredentialDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	credentialID := mux.Vars(r)["credentialID"]
+
+	err := gw.controlAPICredentials.delete(credentialID)
+	switch {
+	case errors.Is(err, ErrControlAPICredentialNotFound):
+		doJSONWrite(w, http.StatusNotFound, apiError(err.Error()))
+		return
+	case err != nil:
+		doJSONWrite(w, http.StatusInternalServerError, apiError("failed to delete control API credential"))
+		return
+	}
+
+	mainLog.WithField("credential_id", credentialID).Info("Control API credential deleted")
+	doJSONWrite(w, http.StatusOK, apiOk("deleted"))
+}
@@ -37,6 +37,7 @@ import (
 	circuit "github.com/TykTechnologies/circuitbreaker"
 
 	"github.com/TykTechnologies/tyk/internal/service/gojsonschema"
+	"github.com/TykTechnologies/tyk/internal/xsd"
 
 	"github.com/TykTechnologies/tyk/apidef"
 	"github.com/TykTechnologies/tyk/config"
@@ -86,6 +87,7 @@ const (
 	GoPlugin
 	PersistGraphQL
 	RateLimit
+	ValidateXMLRequest
 )
 
 // RequestStatus is a custom type to avoid collisions
@@ -121,6 +123,7 @@ const (
 	StatusRequestNotTracked               RequestStatus = "Request Not Tracked"
 	StatusValidateJSON                    RequestStatus = "Validate JSON"
 	StatusValidateRequest                 RequestStatus = "Validate Request"
+	StatusValidateXML                     RequestStatus = "Validate XML"
 	StatusInternal                        RequestStatus = "Internal path"
 	StatusGoPlugin                        RequestStatus = "Go plugin"
 	StatusPersistGraphQL                  RequestStatus = "Persist GraphQL"
@@ -246,6 +249,9 @@ func (s *APISpec) isStreamingAPI() bool {
 // system.
 type APIDefinitionLoader struct {
 	Gw *Gateway `json:"-"`
+
+	// dryRun skips the setup of the event handlers, which would start their transports.
+	dryRun bool
 }
 
 // MakeSpec will generate a flattened URLSpec from and APIDefinitions' VersionInfo data. paths are
@@ -326,11 +332,16 @@ func (a APIDefinitionLoader) MakeSpec(def *model.MergedAPI, logger *logrus.Entry
 	}
 
 	// Set up Event Handlers
-	if len(def.EventHandlers.Events) > 0 {
+	events := def.EventHandlers.Events
+	if a.dryRun {
+		events = nil
+	}
+	if len(events) > 0 {
 		logger.Debug("Initializing event handlers")
 	}
 	spec.EventPaths = make(map[apidef.TykEvent][]config.TykEventHandler)
-	for eventName, eventHandlerConfs := range def.EventHandlers.Events {
+	for eventName, eventHandlerConfs := range events {
+
 		logger.Debug("FOUND EVENTS TO INIT")
 		for _, handlerConf := range eventHandlerConfs {
 			logger.Debug("CREATING EVENT HANDLERS")
@@ -382,6 +393,21 @@ func (a APIDefinitionLoader) MakeSpec(def *model.MergedAPI, logger *logrus.Entry
 	}
 
 	oasSpec := spec.OAS.T
+	if spec.OAS.IsOpenAPI31() {
+		// the router and the validation of the requests use the OpenAPI 3.0 equivalent of the document,
+		// the JSON request bodies are validated with JSON Schema 2020-12.
+		if downgraded, err := spec.OAS.Downgrade(); err != nil {
+			logger.WithError(err).Error("Could not downgrade OpenAPI 3.1 document")
+		} else {
+			oasSpec = downgraded.T
+		}
+
+		spec.oasRequestBodyValidator, err = oas.NewRequestBodyValidator(&spec.OAS)
+		if err != nil {
+			logger.WithError(err).Error("Could not compile the request body schemas")
+		}
+	}
+
 	oasSpec.Servers = openapi3.Servers{
 		{URL: spec.Proxy.ListenPath},
 	}
@@ -391,6 +417,14 @@ func (a APIDefinitionLoader) MakeSpec(def *model.MergedAPI, logger *logrus.Entry
 		logger.WithError(err).Error("Could not create OAS router")
 	}
 
+	if def.GRPCTranscoding != nil && def.GRPCTranscoding.Enabled {
+		spec.grpcTranscoder, err = def.GRPCTranscoding.Transcoder()
+		if err != nil {
+			logger.WithError(err).Error("Could not create gRPC transcoder")
+			return nil, err
+		}
+	}
+
 	return spec, nil
 }
 
@@ -720,7 +754,7 @@ func (a APIDefinitionLoader) loadDefFromFilePath(filePath string) (*APISpec, err
 		loader := openapi3.NewLoader()
 		// use openapi3.ReadFromFile as ReadFromURIFunc since the default implementation cache spec based on file path.
 		loader.ReadFromURIFunc = openapi3.ReadFromFile
-		oasDoc, err := loader.LoadFromFile(a.GetOASFilepath(filePath))
+		oasDoc, err := oas.LoadFromFile(loader, a.GetOASFilepath(filePath))
 		if err == nil {
 			nestDef.OAS = &oas.OAS{T: *oasDoc}
 		}
@@ -1247,6 +1281,31 @@ func (a APIDefinitionLoader) compileValidateJSONPathsSpec(paths []apidef.Validat
 	return urlSpec
 }
 
+func (a APIDefinitionLoader) compileValidateXMLPathsSpec(paths []apidef.ValidateXMLMeta, stat URLStatus, conf config.Config) []URLSpec {
+	var urlSpec []URLSpec
+
+	for _, stringSpec := range paths {
+		if stringSpec.Disabled {
+			continue
+		}
+
+		newSpec := URLSpec{}
+		a.generateRegex(stringSpec.Path, &newSpec, stat, conf)
+		// Extend with method actions
+
+		schema, err := xsd.Parse([]byte(stringSpec.Schema))
+		if err != nil {
+			log.WithError(err).Errorf("Couldn't parse the XML schema of %s %s", stringSpec.Method, stringSpec.Path)
+		}
+
+		stringSpec.SchemaCache = schema
+		newSpec.ValidateXMLMeta = stringSpec
+		urlSpec = append(urlSpec, newSpec)
+	}
+
+	return urlSpec
+}
+
 func (a APIDefinitionLoader) compileUnTrackedEndpointPathsSpec(paths []apidef.TrackEndpointMeta, stat URLStatus, conf config.Config) []URLSpec {
 	urlSpec := []URLSpec{}
 
@@ -1324,6 +1383,7 @@ func (a APIDefinitionLoader) getExtendedPathSpecs(apiVersionDef apidef.VersionIn
 	trackedPaths := a.compileTrackedEndpointPathsSpec(apiVersionDef.ExtendedPaths.TrackEndpoints, RequestTracked, conf)
 	unTrackedPaths := a.compileUnTrackedEndpointPathsSpec(apiVersionDef.ExtendedPaths.DoNotTrackEndpoints, RequestNotTracked, conf)
 	validateJSON := a.compileValidateJSONPathsSpec(apiVersionDef.ExtendedPaths.ValidateJSON, ValidateJSONRequest, conf)
+	validateXML := a.compileValidateXMLPathsSpec(apiVersionDef.ExtendedPaths.ValidateXML, ValidateXMLRequest, conf)
 	internalPaths := a.compileInternalPathsSpec(apiVersionDef.ExtendedPaths.Internal, Internal, conf)
 	goPlugins := a.compileGopluginPathsSpec(apiVersionDef.ExtendedPaths.GoPlugin, GoPlugin, apiSpec, conf)
 	persistGraphQL := a.compilePersistGraphQLPathSpec(apiVersionDef.ExtendedPaths.PersistGraphQL, PersistGraphQL, apiSpec, conf)
@@ -1352,6 +1412,7 @@ func (a APIDefinitionLoader) getExtendedPathSpecs(apiVersionDef apidef.VersionIn
 	combinedPath = append(combinedPath, trackedPaths...)
 	combinedPath = append(combinedPath, unTrackedPaths...)
 	combinedPath = append(combinedPath, validateJSON...)
+	combinedPath = append(combinedPath, validateXML...)
 	combinedPath = append(combinedPath, internalPaths...)
 	combinedPath = append(combinedPath, rateLimitPaths...)
 
@@ -1416,6 +1477,8 @@ func (a *APISpec) getURLStatus(stat URLStatus) RequestStatus {
 		return StatusPersistGraphQL
 	case RateLimit:
 		return StatusRateLimit
+	case ValidateXMLRequest:
+		return StatusValidateXML
 	default:
 		log.Error("URL Status was not one of Ignored, Blacklist or WhiteList! Blocking.")
 		return EndPointNotAllowed
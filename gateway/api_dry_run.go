@@ -0,0 +1,168 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/apidef/oas"
+	"github.com/TykTechnologies/tyk/internal/audit"
+	"github.com/TykTechnologies/tyk/internal/httputil"
+	"github.com/TykTechnologies/tyk/internal/model"
+)
+
+// apiDryRunResult describes how an API definition would be loaded, without storing it.
+type apiDryRunResult struct {
+	Status             string         `json:"status"`
+	Action             string         `json:"action"`
+	Key                string         `json:"key"`
+	Errors             []string       `json:"errors,omitempty"`
+	Warnings           []string       `json:"warnings,omitempty"`
+	ListenPath         string         `json:"listen_path,omitempty"`
+	Middleware         []string       `json:"middleware"`
+	ResponseMiddleware []string       `json:"response_middleware,omitempty"`
+	Changes            []audit.Change `json:"changes"`
+}
+
+// dryRunLogHook collects the warnings and errors logged while loading a spec.
+type dryRunLogHook struct {
+	messages []string
+}
+
+func (h *dryRunLogHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel, logrus.WarnLevel}
+}
+
+func (h *dryRunLogHook) Fire(entry *logrus.Entry) error {
+	msg := entry.Message
+	if err, ok := entry.Data[logrus.ErrorKey].(error); ok {
+		msg += ": " + err.Error()
+	}
+	h.messages = append(h.messages, msg)
+	return nil
+}
+
+// isDryRun reports whether the request only checks the change, with ?dryRun=true.
+func isDryRun(r *http.Request) bool {
+	return r.URL.Query().Get("dryRun") == "true"
+}
+
+// handleDryRunAPI loads the definition as a reload would, against the loaded APIs, without
+// storing it or swapping it in. It returns the errors and warnings of the load, the middleware
+// chain of the API and the changes from the loaded definition.
+func (gw *Gateway) handleDryRunAPI(def *apidef.APIDefinition, oasObj *oas.OAS, oasEndpoint bool) (interface{}, int) {
+	merged := &model.MergedAPI{APIDefinition: def}
+	def.IsOAS = oasEndpoint
+	if oasEndpoint {
+		oasObj.GetTykExtension().Info.ID = def.APIID
+		merged.OAS = oasObj
+	}
+
+	result := &apiDryRunResult{
+		Status:     "ok",
+		Action:     "added",
+		Key:        def.APIID,
+		Middleware: []string{},
+	}
+
+	current := gw.getApiSpec(def.APIID)
+	if current != nil {
+		result.Action = "modified"
+		result.Changes = audit.Diff(current.APIDefinition, def)
+	} else {
+		result.Changes = audit.Diff(nil, def)
+	}
+
+	hook := &dryRunLogHook{}
+	logger := logrus.New()
+	logger.Out = io.Discard
+	logger.AddHook(hook)
+	entry := logrus.NewEntry(logger)
+
+	defer func() {
+		result.Warnings = hook.messages
+	}()
+
+	loader := APIDefinitionLoader{Gw: gw, dryRun: true}
+	spec, err := loader.MakeSpec(merged, entry)
+	if err != nil {
+		return result.fail(err), http.StatusBadRequest
+	}
+
+	// the definition is the loaded one, so is its chain
+	if spec == current {
+		if chain, ok := gw.apisHandlesByID.Load(spec.APIID); ok {
+			result.Middleware = chain.(*ChainObject).Middleware
+		}
+		result.ListenPath = spec.Proxy.ListenPath
+		result.ResponseMiddleware = responseMiddlewareNames(spec)
+		return result, http.StatusOK
+	}
+
+	switch spec.Protocol {
+	case "", "http", "https", "h2c":
+	default:
+		result.ListenPath = spec.Proxy.ListenPath
+		return result, http.StatusOK
+	}
+
+	if err := httputil.ValidatePath(spec.Proxy.ListenPath); err != nil {
+		return result.fail(fmt.Errorf("invalid listen path: %w", err)), http.StatusBadRequest
+	}
+
+	chain, err := gw.dryRunProcessSpec(spec, entry)
+	defer spec.Unload()
+	if err != nil {
+		return result.fail(err), http.StatusBadRequest
+	}
+
+	if chain.Skip && !spec.Internal {
+		return result.fail(errors.New("API definition is invalid, it wouldn't be loaded")), http.StatusBadRequest
+	}
+
+	result.ListenPath = spec.Proxy.ListenPath
+	result.Middleware = chain.Middleware
+	result.ResponseMiddleware = responseMiddlewareNames(spec)
+
+	return result, http.StatusOK
+}
+
+// dryRunProcessSpec builds the chain of the spec, its listen path is checked against the loaded APIs.
+func (gw *Gateway) dryRunProcessSpec(spec *APISpec, logger *logrus.Entry) (chain *ChainObject, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("loading the API failed: %v", r)
+		}
+	}()
+
+	gw.apisMu.RLock()
+	specs := make([]*APISpec, 0, len(gw.apisByID)+1)
+	for apiID, loaded := range gw.apisByID {
+		if apiID != spec.APIID {
+			specs = append(specs, loaded)
+		}
+	}
+	gw.apisMu.RUnlock()
+	specs = append(specs, spec)
+
+	gs := gw.prepareStorage()
+	return gw.processSpec(spec, countApisByListenHash(specs), &gs, logger), nil
+}
+
+func (r *apiDryRunResult) fail(err error) *apiDryRunResult {
+	r.Status = "error"
+	r.Errors = append(r.Errors, err.Error())
+	return r
+}
+
+func responseMiddlewareNames(spec *APISpec) []string {
+	names := make([]string, 0, len(spec.ResponseChain))
+	for _, mw := range spec.ResponseChain {
+		names = append(names, mw.Name())
+	}
+	return names
+}
@@ -0,0 +1,108 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/internal/model"
+	"github.com/TykTechnologies/tyk/test"
+)
+
+func TestAPIDryRun(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+		spec.APIID = "loaded"
+		spec.Name = "loaded"
+		spec.UseKeylessAccess = false
+		spec.Proxy.ListenPath = "/dry-run/"
+	})
+
+	dryRun := func(t *testing.T, method, path string, data interface{}, code int) apiDryRunResult {
+		t.Helper()
+
+		resp, err := ts.Run(t, test.TestCase{Method: method, Path: path, AdminAuth: true, Data: data, Code: code})
+		require.NoError(t, err)
+
+		var result apiDryRunResult
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return result
+	}
+
+	t.Run("create with a listen path collision", func(t *testing.T) {
+		def := BuildAPI(func(spec *APISpec) {
+			spec.APIID = "new"
+			spec.UseKeylessAccess = false
+			spec.Proxy.ListenPath = "/dry-run/"
+		})[0].APIDefinition
+
+		result := dryRun(t, http.MethodPost, "/tyk/apis?dryRun=true", def, http.StatusOK)
+
+		assert.Equal(t, "ok", result.Status)
+		assert.Equal(t, "added", result.Action)
+		assert.Equal(t, "/dry-run/-new", result.ListenPath)
+		assert.Contains(t, result.Warnings, "Listen path collision, changed to /dry-run/-new")
+		assert.Contains(t, result.Middleware, "AuthKey")
+
+		_, err := os.Stat(filepath.Join(ts.Gw.GetConfig().AppPath, "new.json"))
+		assert.True(t, os.IsNotExist(err), "the definition mustn't be stored")
+		assert.Nil(t, ts.Gw.getApiSpec("new"))
+	})
+
+	t.Run("update", func(t *testing.T) {
+		def := *ts.Gw.getApiSpec("loaded").APIDefinition
+		def.Name = "renamed"
+		def.UseKeylessAccess = true
+
+		result := dryRun(t, http.MethodPut, "/tyk/apis/loaded?dryRun=true", def, http.StatusOK)
+
+		assert.Equal(t, "modified", result.Action)
+		assert.Equal(t, "/dry-run/", result.ListenPath)
+		assert.NotContains(t, result.Middleware, "AuthKey")
+
+		var paths []string
+		for _, change := range result.Changes {
+			paths = append(paths, change.Path)
+		}
+		assert.Contains(t, paths, "name")
+		assert.Contains(t, paths, "use_keyless")
+
+		assert.Equal(t, "loaded", ts.Gw.getApiSpec("loaded").Name)
+	})
+
+	t.Run("invalid definition", func(t *testing.T) {
+		def := BuildAPI(func(spec *APISpec) {
+			spec.APIID = "invalid"
+			spec.Proxy.ListenPath = "/dry run/"
+		})[0].APIDefinition
+
+		result := dryRun(t, http.MethodPost, "/tyk/apis?dryRun=true", def, http.StatusBadRequest)
+		assert.Equal(t, "error", result.Status)
+	})
+
+	t.Run("event handlers aren't set up", func(t *testing.T) {
+		def := BuildAPI(func(spec *APISpec) {
+			spec.APIID = "events"
+			spec.Proxy.ListenPath = "/events/"
+			spec.EventHandlers.Events = map[apidef.TykEvent][]apidef.EventHandlerTriggerConfig{
+				EventAuthFailure: {{Handler: EH_LogHandler, HandlerMeta: map[string]interface{}{"prefix": "dry-run"}}},
+			}
+		})[0].APIDefinition
+
+		spec, err := APIDefinitionLoader{Gw: ts.Gw, dryRun: true}.MakeSpec(&model.MergedAPI{APIDefinition: def}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, spec.EventPaths)
+
+		spec, err = APIDefinitionLoader{Gw: ts.Gw}.MakeSpec(&model.MergedAPI{APIDefinition: def}, nil)
+		require.NoError(t, err)
+		assert.Len(t, spec.EventPaths[EventAuthFailure], 1)
+	})
+}
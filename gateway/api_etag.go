@@ -0,0 +1,191 @@
+package gateway
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/TykTechnologies/tyk/header"
+	"github.com/TykTechnologies/tyk/internal/osutil"
+	"github.com/TykTechnologies/tyk/storage"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+// conditionalSessionHandler is implemented by session handlers able to change a key only if
+// the stored session matches, see DefaultSessionManager.
+type conditionalSessionHandler interface {
+	RawSession(keyName string, hashed bool) (string, error)
+	UpdateSessionIf(keyName string, session *user.SessionState, resetTTLTo int64, hashed bool, match func(current string) bool) error
+}
+
+// resourceETag returns the strong entity tag of the stored content of a resource.
+func resourceETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether an If-Match header matches the entity tag of a resource, which is
+// empty when the resource doesn't exist. Weak tags never match, as If-Match uses strong comparison.
+func etagMatches(ifMatch, etag string) bool {
+	if etag == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(ifMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// keyETagMatcher matches stored sessions against an If-Match header.
+func keyETagMatcher(ifMatch string) func(current string) bool {
+	return func(current string) bool {
+		if current == "" {
+			return false
+		}
+		return etagMatches(ifMatch, resourceETag([]byte(current)))
+	}
+}
+
+// keyETag returns the entity tag of a stored key, empty when it doesn't exist.
+func (gw *Gateway) keyETag(keyName, orgID string, hashed bool) string {
+	sessions, ok := gw.GlobalSessionManager.(conditionalSessionHandler)
+	if !ok {
+		return ""
+	}
+
+	session, found := gw.GlobalSessionManager.SessionDetail(orgID, keyName, hashed)
+	if !found {
+		return ""
+	}
+
+	raw, err := sessions.RawSession(session.KeyID, hashed)
+	if err != nil || raw == "" {
+		return ""
+	}
+	return resourceETag([]byte(raw))
+}
+
+// keyUpdateError returns the response of a failed key update.
+func keyUpdateError(err error) (interface{}, int) {
+	switch {
+	case errors.Is(err, storage.ErrKeyChanged):
+		return apiError("Key has changed, If-Match doesn't match"), http.StatusPreconditionFailed
+	case errors.Is(err, errConditionalWriteUnsupported):
+		return apiError("If-Match isn't supported by the key store"), http.StatusNotImplemented
+	}
+	return apiError("Failed to create key, ensure security settings are correct."), http.StatusInternalServerError
+}
+
+// checkKeyIfMatch checks an If-Match header against the stored key, trying each of the key names
+// in turn as the key handler does for the legacy key format. It returns the response to send when
+// the key doesn't exist or doesn't match.
+func (gw *Gateway) checkKeyIfMatch(ifMatch, orgID string, hashed bool, keyNames ...string) (interface{}, int, bool) {
+	if _, ok := gw.GlobalSessionManager.(conditionalSessionHandler); !ok {
+		obj, code := keyUpdateError(errConditionalWriteUnsupported)
+		return obj, code, false
+	}
+
+	var etag string
+	for _, keyName := range keyNames {
+		if etag = gw.keyETag(keyName, orgID, hashed); etag != "" {
+			break
+		}
+	}
+
+	switch {
+	case etag == "":
+		return apiError("There is no such key found"), http.StatusNotFound, false
+	case !etagMatches(ifMatch, etag):
+		obj, code := keyUpdateError(storage.ErrKeyChanged)
+		return obj, code, false
+	}
+	return nil, http.StatusOK, true
+}
+
+// apiETag returns the entity tag of an API, empty when it doesn't exist. It's derived from the
+// stored definition files when there are some, so changes not reloaded yet are taken into account.
+func (gw *Gateway) apiETag(r *http.Request) string {
+	apiID := mux.Vars(r)["apiID"]
+
+	spec := gw.getApiSpec(apiID)
+	if spec == nil {
+		return ""
+	}
+
+	if root, err := osutil.NewRoot(gw.GetConfig().AppPath); err == nil {
+		if data, err := root.ReadFile(apiID + ".json"); err == nil {
+			if spec.IsOAS {
+				oasData, _ := root.ReadFile(apiID + "-oas.json")
+				data = append(data, oasData...)
+			}
+			return resourceETag(data)
+		}
+	}
+
+	data, err := json.Marshal(spec.APIDefinition)
+	if err != nil {
+		return ""
+	}
+	if spec.IsOAS {
+		oasData, _ := json.Marshal(&spec.OAS)
+		data = append(data, oasData...)
+	}
+	return resourceETag(data)
+}
+
+// policyETag returns the entity tag of a policy, empty when it doesn't exist, see apiETag.
+func (gw *Gateway) policyETag(r *http.Request) string {
+	polID := mux.Vars(r)["polID"]
+
+	pol, ok := gw.PolicyByID(polID)
+	if !ok {
+		return ""
+	}
+
+	if root, err := gw.newPolicyPathRoot(); err == nil {
+		if data, err := root.ReadFile(polID + ".json"); err == nil {
+			return resourceETag(data)
+		}
+	}
+
+	data, err := json.Marshal(pol)
+	if err != nil {
+		return ""
+	}
+	return resourceETag(data)
+}
+
+// withETag sets the entity tag of the resource on GET responses, and rejects the PUT, PATCH and
+// DELETE requests whose If-Match header doesn't match it with 412. Changes made with If-Match are
+// serialised, so the resource can't change between the check and the write.
+func (gw *Gateway) withETag(etag func(r *http.Request) string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			if tag := etag(r); tag != "" {
+				w.Header().Set(header.ETag, tag)
+			}
+		case http.MethodPut, http.MethodPatch, http.MethodDelete:
+			if ifMatch := r.Header.Get(header.IfMatch); ifMatch != "" {
+				gw.etagMu.Lock()
+				defer gw.etagMu.Unlock()
+
+				if !etagMatches(ifMatch, etag(r)) {
+					doJSONWrite(w, http.StatusPreconditionFailed, apiError("Resource has changed, If-Match doesn't match"))
+					return
+				}
+			}
+		}
+
+		next(w, r)
+	}
+}
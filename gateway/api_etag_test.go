@@ -0,0 +1,142 @@
+package gateway
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/config"
+	"github.com/TykTechnologies/tyk/header"
+	"github.com/TykTechnologies/tyk/storage"
+	"github.com/TykTechnologies/tyk/test"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+func TestETagMatches(t *testing.T) {
+	etag := resourceETag([]byte("stored"))
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		etag    string
+		want    bool
+	}{
+		{name: "same", ifMatch: etag, etag: etag, want: true},
+		{name: "different", ifMatch: `"stale"`, etag: etag, want: false},
+		{name: "list", ifMatch: `"stale", ` + etag, etag: etag, want: true},
+		{name: "any", ifMatch: "*", etag: etag, want: true},
+		{name: "any on a missing resource", ifMatch: "*", etag: "", want: false},
+		{name: "weak", ifMatch: "W/" + etag, etag: etag, want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, etagMatches(tc.ifMatch, tc.etag))
+		})
+	}
+}
+
+func TestControlAPIETag(t *testing.T) {
+	ts := StartTest(func(cnf *config.Config) {
+		cnf.Policies.PolicyPath = t.TempDir()
+		cnf.Policies.PolicySource = "file"
+	})
+	defer ts.Close()
+
+	ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+		spec.APIID = "etag"
+		spec.UseKeylessAccess = false
+		spec.Proxy.ListenPath = "/etag/"
+	})
+
+	getETag := func(t *testing.T, path string) string {
+		t.Helper()
+
+		resp, err := ts.Run(t, test.TestCase{Method: http.MethodGet, Path: path, AdminAuth: true, Code: http.StatusOK})
+		require.NoError(t, err)
+
+		etag := resp.Header.Get(header.ETag)
+		require.NotEmpty(t, etag)
+		return etag
+	}
+
+	ifMatch := func(etag string) map[string]string {
+		return map[string]string{header.IfMatch: etag}
+	}
+
+	t.Run("api", func(t *testing.T) {
+		etag := getETag(t, "/tyk/apis/etag")
+		def := ts.Gw.getApiSpec("etag").APIDefinition
+
+		_, _ = ts.Run(t, []test.TestCase{
+			{Method: http.MethodPut, Path: "/tyk/apis/etag", AdminAuth: true, Data: def, Headers: ifMatch(`"stale"`), Code: http.StatusPreconditionFailed},
+			{Method: http.MethodPut, Path: "/tyk/apis/etag", AdminAuth: true, Data: def, Headers: ifMatch(etag), Code: http.StatusOK},
+			{Method: http.MethodDelete, Path: "/tyk/apis/etag", AdminAuth: true, Headers: ifMatch(etag), Code: http.StatusPreconditionFailed},
+		}...)
+
+		assert.NotEqual(t, etag, getETag(t, "/tyk/apis/etag"), "the stored definition changed")
+	})
+
+	t.Run("policy", func(t *testing.T) {
+		polID := ts.CreatePolicy()
+		etag := getETag(t, "/tyk/policies/"+polID)
+		pol, _ := ts.Gw.PolicyByID(polID)
+
+		_, _ = ts.Run(t, []test.TestCase{
+			{Method: http.MethodDelete, Path: "/tyk/policies/" + polID, AdminAuth: true, Headers: ifMatch(`"stale"`), Code: http.StatusPreconditionFailed},
+			{Method: http.MethodPut, Path: "/tyk/policies/" + polID, AdminAuth: true, Data: pol, Headers: ifMatch(etag), Code: http.StatusOK},
+			{Method: http.MethodPut, Path: "/tyk/policies/unknown", AdminAuth: true, Data: pol, Headers: ifMatch("*"), Code: http.StatusPreconditionFailed},
+		}...)
+	})
+
+	t.Run("key", func(t *testing.T) {
+		session, key := ts.CreateSession(func(s *user.SessionState) {
+			s.AccessRights = map[string]user.AccessDefinition{"etag": {APIID: "etag", Versions: []string{"v1"}}}
+		})
+		etag := getETag(t, "/tyk/keys/"+key)
+
+		_, _ = ts.Run(t, []test.TestCase{
+			{Method: http.MethodPut, Path: "/tyk/keys/" + key, AdminAuth: true, Data: session, Headers: ifMatch(`"stale"`), Code: http.StatusPreconditionFailed},
+			{Method: http.MethodDelete, Path: "/tyk/keys/" + key, AdminAuth: true, Headers: ifMatch(`"stale"`), Code: http.StatusPreconditionFailed},
+			{Method: http.MethodPut, Path: "/tyk/keys/" + key, AdminAuth: true, Data: session, Headers: ifMatch(etag), Code: http.StatusOK},
+		}...)
+
+		etag = getETag(t, "/tyk/keys/"+key)
+		_, _ = ts.Run(t, test.TestCase{Method: http.MethodDelete, Path: "/tyk/keys/" + key, AdminAuth: true, Headers: ifMatch(etag), Code: http.StatusOK})
+
+		_, found := ts.Gw.GlobalSessionManager.SessionDetail("", key, false)
+		assert.False(t, found)
+	})
+}
+
+func TestDeleteLegacyKeyIfMatch(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	// create the key in the legacy format, without a hash function
+	globalConf := ts.Gw.GetConfig()
+	globalConf.HashKeyFunction = ""
+	ts.Gw.SetConfig(globalConf)
+
+	session := ts.testPrepareBasicAuth(false)
+	_, _ = ts.Run(t, test.TestCase{Method: http.MethodPost, Path: "/tyk/keys/defaultuser", Data: session, AdminAuth: true, Code: http.StatusOK})
+
+	globalConf.HashKeyFunction = storage.HashMurmur64
+	ts.Gw.SetConfig(globalConf)
+
+	const path = "/tyk/keys/defaultuser?username=true&org_id=default"
+
+	resp, err := ts.Run(t, test.TestCase{Method: http.MethodGet, Path: path, AdminAuth: true, Code: http.StatusOK})
+	require.NoError(t, err)
+
+	etag := resp.Header.Get(header.ETag)
+	require.NotEmpty(t, etag)
+
+	_, _ = ts.Run(t, []test.TestCase{
+		{Method: http.MethodDelete, Path: path, AdminAuth: true, Headers: map[string]string{header.IfMatch: `"stale"`}, Code: http.StatusPreconditionFailed},
+		{Method: http.MethodDelete, Path: path, AdminAuth: true, Headers: map[string]string{header.IfMatch: etag}, Code: http.StatusOK, BodyMatch: `"action":"deleted"`},
+		{Method: http.MethodGet, Path: path, AdminAuth: true, Code: http.StatusNotFound},
+	}...)
+}
@@ -0,0 +1,360 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/storage"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+const (
+	keyBulkModeAtomic     = "atomic"
+	keyBulkModeBestEffort = "best_effort"
+
+	keyBulkActionCreate      = "create"
+	keyBulkActionUpdate      = "update"
+	keyBulkActionDelete      = "delete"
+	keyBulkActionApplyPolicy = "apply_policy"
+
+	keyBulkStatusOK      = "ok"
+	keyBulkStatusError   = "error"
+	keyBulkStatusSkipped = "skipped"
+
+	keyBulkMaxOperations = 1000
+)
+
+// keyBatchWriter is implemented by key stores able to write several keys in a single round trip.
+type keyBatchWriter interface {
+	WriteBatch(ops []storage.BatchOp, atomic bool) ([]error, error)
+}
+
+// keyBulkRequest is the body of a bulk key request. In atomic mode either every operation is
+// applied or none is, in best effort mode, the default, each operation is applied on its own.
+type keyBulkRequest struct {
+	Mode       string             `json:"mode"`
+	Operations []keyBulkOperation `json:"operations"`
+}
+
+// keyBulkOperation creates, updates or deletes a key, or replaces its policies.
+type keyBulkOperation struct {
+	Action        string             `json:"action"`
+	Key           string             `json:"key,omitempty"`
+	OrgID         string             `json:"org_id,omitempty"`
+	Session       *user.SessionState `json:"session,omitempty"`
+	Policies      []string           `json:"policies,omitempty"`
+	SuppressReset bool               `json:"suppress_reset,omitempty"`
+}
+
+type keyBulkResponse struct {
+	Status  string          `json:"status"`
+	Results []keyBulkResult `json:"results"`
+}
+
+// keyBulkResult is the outcome of an operation, in the order of the request.
+type keyBulkResult struct {
+	Action  string `json:"action"`
+	Key     string `json:"key,omitempty"`
+	KeyHash string `json:"key_hash,omitempty"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// keyBulkWrite is an operation ready to be written.
+type keyBulkWrite struct {
+	index      int
+	keyName    string
+	hashed     bool
+	session    *user.SessionState
+	resetQuota bool
+	event      apidef.TykEvent
+	message    string
+	op         storage.BatchOp
+}
+
+func (gw *Gateway) keysBulkHandler(w http.ResponseWriter, r *http.Request) {
+	var req keyBulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("Couldn't decode bulk key request: ", err)
+		doJSONWrite(w, http.StatusBadRequest, apiError("Request malformed"))
+		return
+	}
+
+	obj, code := gw.handleKeysBulk(&req, r.URL.Query().Get("hashed") != "")
+	doJSONWrite(w, code, obj)
+}
+
+func (gw *Gateway) handleKeysBulk(req *keyBulkRequest, isHashed bool) (interface{}, int) {
+	switch req.Mode {
+	case "":
+		req.Mode = keyBulkModeBestEffort
+	case keyBulkModeAtomic, keyBulkModeBestEffort:
+	default:
+		return apiError("mode must be atomic or best_effort"), http.StatusBadRequest
+	}
+
+	if len(req.Operations) == 0 || len(req.Operations) > keyBulkMaxOperations {
+		return apiError(fmt.Sprintf("operations must hold between 1 and %d items", keyBulkMaxOperations)), http.StatusBadRequest
+	}
+
+	store := gw.GlobalSessionManager.Store()
+	writer, ok := store.(keyBatchWriter)
+	if !ok {
+		return apiError("Bulk key operations are not supported by the key store"), http.StatusNotImplemented
+	}
+
+	atomic := req.Mode == keyBulkModeAtomic
+	prefix := store.GetKeyPrefix()
+
+	resp := keyBulkResponse{Status: keyBulkStatusOK, Results: make([]keyBulkResult, len(req.Operations))}
+	writes := make([]*keyBulkWrite, 0, len(req.Operations))
+	changed := map[string]int{}
+
+	for i := range req.Operations {
+		op := &req.Operations[i]
+		resp.Results[i] = keyBulkResult{Action: op.Action, Key: op.Key, Status: keyBulkStatusOK}
+
+		write, err := gw.prepareKeyBulkWrite(op, isHashed)
+		if err == nil {
+			// operations are prepared from the stored keys, so a key can only be changed once per batch
+			if first, ok := changed[write.cacheKey(gw)]; ok {
+				err = fmt.Errorf("key is already changed by operation %d", first)
+			}
+		}
+		if err != nil {
+			resp.Status = keyBulkStatusError
+			resp.Results[i].Status = keyBulkStatusError
+			resp.Results[i].Message = err.Error()
+			continue
+		}
+
+		write.index = i
+		write.op.Key = prefix + write.cacheKey(gw)
+		changed[write.cacheKey(gw)] = i
+		writes = append(writes, write)
+		gw.setKeyBulkResultKey(&resp.Results[i], write, op.Action)
+	}
+
+	if atomic && resp.Status != keyBulkStatusOK {
+		skipKeyBulkWrites(resp.Results, writes, "Not applied, another operation failed")
+		return resp, http.StatusBadRequest
+	}
+
+	if len(writes) == 0 {
+		return resp, http.StatusOK
+	}
+
+	ops := make([]storage.BatchOp, len(writes))
+	for i, write := range writes {
+		ops[i] = write.op
+	}
+
+	errs, err := writer.WriteBatch(ops, atomic)
+	if err != nil {
+		log.WithError(err).Error("Failed to write the bulk key operations.")
+		resp.Status = keyBulkStatusError
+		for _, write := range writes {
+			resp.Results[write.index].Status = keyBulkStatusError
+			resp.Results[write.index].Message = "Failed to write key"
+		}
+		return resp, http.StatusInternalServerError
+	}
+
+	var cacheKeys []string
+	for i, write := range writes {
+		if errs[i] != nil {
+			log.WithError(errs[i]).WithField("key", gw.obfuscateKey(write.keyName)).Error("Failed to write key.")
+			resp.Status = keyBulkStatusError
+			resp.Results[write.index].Status = keyBulkStatusError
+			resp.Results[write.index].Message = "Failed to write key"
+			continue
+		}
+
+		if write.resetQuota {
+			gw.GlobalSessionManager.ResetQuota(write.keyName, write.session, write.hashed)
+		}
+
+		cacheKey := write.cacheKey(gw)
+		gw.SessionCache.Delete(cacheKey)
+		cacheKeys = append(cacheKeys, cacheKey)
+
+		gw.FireSystemEvent(write.event, EventTokenMeta{
+			EventMetaDefault: EventMetaDefault{Message: write.message},
+			Org:              write.session.OrgID,
+			Key:              write.keyName,
+		})
+	}
+
+	if len(cacheKeys) > 0 {
+		// notify the gateways in the cluster to flush their cache
+		gw.MainNotifier.Notify(Notification{
+			Command: KeySpaceUpdateNotification,
+			Payload: strings.Join(cacheKeys, ","),
+			Gw:      gw,
+		})
+	}
+
+	log.WithFields(logrus.Fields{
+		"prefix":  "api",
+		"mode":    req.Mode,
+		"status":  resp.Status,
+		"count":   len(req.Operations),
+		"written": len(cacheKeys),
+	}).Info("Applied bulk key operations.")
+
+	return resp, http.StatusOK
+}
+
+// prepareKeyBulkWrite validates an operation and builds the session to write, without storing it.
+func (gw *Gateway) prepareKeyBulkWrite(op *keyBulkOperation, isHashed bool) (*keyBulkWrite, error) {
+	switch op.Action {
+	case keyBulkActionCreate:
+		if op.Session == nil {
+			return nil, errors.New("session is required")
+		}
+		session := op.Session
+
+		mw := &BaseMiddleware{Gw: gw}
+		if err := mw.ApplyPolicies(session); err != nil {
+			return nil, err
+		}
+
+		session.DateCreated = time.Now()
+		keyName := gw.generateToken(session.OrgID, op.Key)
+		gw.updateKeyCredentials(session, user.SessionState{}, true)
+
+		return gw.newKeyBulkWrite(keyName, false, session, false, EventTokenCreated, "Key modified.")
+
+	case keyBulkActionUpdate:
+		if op.Session == nil {
+			return nil, errors.New("session is required")
+		}
+		session := op.Session
+
+		mw := &BaseMiddleware{Gw: gw}
+		if err := mw.ApplyPolicies(session); err != nil {
+			return nil, err
+		}
+
+		original, err := gw.keyBulkSession(session.OrgID, op.Key, isHashed)
+		if err != nil {
+			return nil, err
+		}
+
+		if obj, code := gw.preserveKeyFields(session, original, op.SuppressReset); code != http.StatusOK {
+			if msg, ok := obj.(apiStatusMessage); ok {
+				return nil, errors.New(msg.Message)
+			}
+			return nil, errors.New("invalid key")
+		}
+		gw.updateKeyCredentials(session, original, false)
+
+		return gw.newKeyBulkWrite(original.KeyID, isHashed, session, op.SuppressReset, EventTokenUpdated, "Key modified.")
+
+	case keyBulkActionApplyPolicy:
+		if len(op.Policies) == 0 {
+			return nil, errors.New("policies are required")
+		}
+
+		original, err := gw.keyBulkSession(op.OrgID, op.Key, isHashed)
+		if err != nil {
+			return nil, err
+		}
+
+		session := original.Clone()
+		session.SetPolicies(op.Policies...)
+		session.LastUpdated = strconv.Itoa(int(time.Now().Unix()))
+
+		return gw.newKeyBulkWrite(original.KeyID, isHashed, &session, true, EventTokenUpdated, "Key modified.")
+
+	case keyBulkActionDelete:
+		original, err := gw.keyBulkSession(op.OrgID, op.Key, isHashed)
+		if err != nil {
+			return nil, err
+		}
+
+		return &keyBulkWrite{
+			keyName:    original.KeyID,
+			hashed:     isHashed,
+			session:    &original,
+			resetQuota: true,
+			event:      EventTokenDeleted,
+			message:    "Key deleted.",
+			op:         storage.BatchOp{Delete: true},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown action %q", op.Action)
+}
+
+// keyBulkSession returns the stored session of a key, its KeyID is the name it's stored under.
+func (gw *Gateway) keyBulkSession(orgID, keyName string, isHashed bool) (user.SessionState, error) {
+	if keyName == "" {
+		return user.SessionState{}, errors.New("key is required")
+	}
+
+	session, found := gw.GlobalSessionManager.SessionDetail(orgID, keyName, isHashed)
+	if !found {
+		return user.SessionState{}, errors.New("Key is not found")
+	}
+	return session, nil
+}
+
+func (gw *Gateway) newKeyBulkWrite(keyName string, hashed bool, session *user.SessionState, dontReset bool, event apidef.TykEvent, message string) (*keyBulkWrite, error) {
+	lifetime, resetQuota, err := gw.prepareKeySession(keyName, session, dontReset, hashed)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyBulkWrite{
+		keyName:    keyName,
+		hashed:     hashed,
+		session:    session,
+		resetQuota: resetQuota,
+		event:      event,
+		message:    message,
+		op:         storage.BatchOp{Value: string(value), TTL: lifetime},
+	}, nil
+}
+
+// cacheKey returns the name of the key in the session cache, which is also its stored name without the prefix.
+func (w *keyBulkWrite) cacheKey(gw *Gateway) string {
+	if w.hashed {
+		return w.keyName
+	}
+	return storage.HashKey(w.keyName, gw.GetConfig().HashKeys)
+}
+
+// setKeyBulkResultKey reports the key of a prepared operation, as the key endpoints do.
+func (gw *Gateway) setKeyBulkResultKey(result *keyBulkResult, write *keyBulkWrite, action string) {
+	result.Key = write.keyName
+	if action != keyBulkActionCreate || !gw.GetConfig().HashKeys {
+		return
+	}
+
+	if write.session.IsBasicAuth() {
+		result.Key = ""
+	}
+	result.KeyHash = write.cacheKey(gw)
+}
+
+// skipKeyBulkWrites marks the prepared operations as not applied.
+func skipKeyBulkWrites(results []keyBulkResult, writes []*keyBulkWrite, message string) {
+	for _, write := range writes {
+		results[write.index].Status = keyBulkStatusSkipped
+		results[write.index].Message = message
+	}
+}
@@ -0,0 +1,141 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/test"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+func TestKeysBulk(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+		spec.APIID = "bulk"
+		spec.UseKeylessAccess = false
+		spec.Proxy.ListenPath = "/bulk/"
+	})
+
+	polID := ts.CreatePolicy(func(p *user.Policy) {
+		p.AccessRights = map[string]user.AccessDefinition{"bulk": {APIID: "bulk", Versions: []string{"v1"}}}
+		p.Tags = []string{"bulk-policy"}
+	})
+
+	newSession := func() *user.SessionState {
+		session := CreateStandardSession()
+		session.AccessRights = map[string]user.AccessDefinition{"bulk": {APIID: "bulk", Versions: []string{"v1"}}}
+		return session
+	}
+
+	_, updated := ts.CreateSession(func(s *user.SessionState) {
+		s.AccessRights = newSession().AccessRights
+	})
+	_, deleted := ts.CreateSession(func(s *user.SessionState) {
+		s.AccessRights = newSession().AccessRights
+	})
+	_, withPolicy := ts.CreateSession(func(s *user.SessionState) {
+		s.AccessRights = newSession().AccessRights
+	})
+
+	bulk := func(t *testing.T, req keyBulkRequest, code int) keyBulkResponse {
+		t.Helper()
+
+		resp, err := ts.Run(t, test.TestCase{Method: http.MethodPost, Path: "/tyk/keys/bulk", AdminAuth: true, Data: req, Code: code})
+		require.NoError(t, err)
+
+		var result keyBulkResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.Len(t, result.Results, len(req.Operations))
+		return result
+	}
+
+	t.Run("atomic mode doesn't apply anything when an operation fails", func(t *testing.T) {
+		result := bulk(t, keyBulkRequest{
+			Mode: keyBulkModeAtomic,
+			Operations: []keyBulkOperation{
+				{Action: keyBulkActionCreate, Key: "atomic-key", Session: newSession()},
+				{Action: keyBulkActionDelete, Key: "unknown", OrgID: "default"},
+			},
+		}, http.StatusBadRequest)
+
+		assert.Equal(t, keyBulkStatusError, result.Status)
+		assert.Equal(t, keyBulkStatusSkipped, result.Results[0].Status)
+		assert.Equal(t, keyBulkStatusError, result.Results[1].Status)
+		assert.Equal(t, "Key is not found", result.Results[1].Message)
+
+		_, found := ts.Gw.GlobalSessionManager.SessionDetail("default", "atomic-key", false)
+		assert.False(t, found)
+	})
+
+	t.Run("unknown policies fail the operation", func(t *testing.T) {
+		session := newSession()
+		session.SetPolicies("unknown-policy")
+
+		result := bulk(t, keyBulkRequest{
+			Operations: []keyBulkOperation{
+				{Action: keyBulkActionCreate, Key: "unknown-policy-key", Session: session},
+				{Action: keyBulkActionUpdate, Key: updated, Session: session},
+			},
+		}, http.StatusOK)
+
+		assert.Equal(t, keyBulkStatusError, result.Status)
+		for _, res := range result.Results {
+			assert.Equal(t, keyBulkStatusError, res.Status)
+			assert.Contains(t, res.Message, "policy not found")
+		}
+
+		_, found := ts.Gw.GlobalSessionManager.SessionDetail("default", "unknown-policy-key", false)
+		assert.False(t, found)
+	})
+
+	t.Run("best effort mode", func(t *testing.T) {
+		updatedSession := newSession()
+		updatedSession.Alias = "bulk-updated"
+
+		result := bulk(t, keyBulkRequest{
+			Operations: []keyBulkOperation{
+				{Action: keyBulkActionCreate, Key: "bulk-key", Session: newSession()},
+				{Action: keyBulkActionUpdate, Key: updated, Session: updatedSession},
+				{Action: keyBulkActionDelete, Key: deleted},
+				{Action: keyBulkActionApplyPolicy, Key: withPolicy, Policies: []string{polID}},
+				{Action: keyBulkActionDelete, Key: "unknown"},
+				{Action: keyBulkActionDelete, Key: updated},
+				{Action: "rotate", Key: updated},
+			},
+		}, http.StatusOK)
+
+		assert.Equal(t, keyBulkStatusError, result.Status)
+		for i, status := range []string{keyBulkStatusOK, keyBulkStatusOK, keyBulkStatusOK, keyBulkStatusOK, keyBulkStatusError, keyBulkStatusError, keyBulkStatusError} {
+			assert.Equal(t, status, result.Results[i].Status, "operation %d: %s", i, result.Results[i].Message)
+		}
+		assert.Equal(t, "key is already changed by operation 1", result.Results[5].Message)
+
+		created, found := ts.Gw.GlobalSessionManager.SessionDetail("default", result.Results[0].Key, false)
+		require.True(t, found)
+		assert.False(t, created.DateCreated.IsZero())
+
+		session, found := ts.Gw.GlobalSessionManager.SessionDetail("default", updated, false)
+		require.True(t, found)
+		assert.Equal(t, "bulk-updated", session.Alias)
+
+		_, found = ts.Gw.GlobalSessionManager.SessionDetail("default", deleted, false)
+		assert.False(t, found)
+
+		session, found = ts.Gw.GlobalSessionManager.SessionDetail("default", withPolicy, false)
+		require.True(t, found)
+		assert.Equal(t, []string{polID}, session.PolicyIDs())
+		assert.Contains(t, session.Tags, "bulk-policy")
+	})
+
+	_, _ = ts.Run(t, []test.TestCase{
+		{Method: http.MethodPost, Path: "/tyk/keys/bulk", AdminAuth: true, Data: keyBulkRequest{}, Code: http.StatusBadRequest, BodyMatch: "operations must hold"},
+		{Method: http.MethodPost, Path: "/tyk/keys/bulk", AdminAuth: true, Data: keyBulkRequest{Mode: "all", Operations: []keyBulkOperation{{Action: keyBulkActionDelete}}}, Code: http.StatusBadRequest, BodyMatch: "mode must be"},
+		{Method: http.MethodPost, Path: "/tyk/keys/bulk", AdminAuth: true, Data: "{", Code: http.StatusBadRequest, BodyMatch: "Request malformed"},
+	}...)
+}
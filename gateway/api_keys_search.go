@@ -0,0 +1,279 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/TykTechnologies/tyk/storage"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+const (
+	keySearchDefaultLimit = 100
+	keySearchMaxLimit     = 1000
+
+	// keySearchMaxBatches bounds the scan batches read by a single request, so
+	// selective filters over millions of keys return partial pages instead of timing out.
+	keySearchMaxBatches = 50
+)
+
+// keyScanner is implemented by key stores able to list keys page by page.
+type keyScanner interface {
+	ScanKeysPage(pattern, cursor string, count int64) ([]string, string, error)
+	GetRawMultiKey(keys []string) ([]string, error)
+}
+
+// apiKeySearchResult is a page of keys, NextCursor is empty on the last page.
+type apiKeySearchResult struct {
+	Keys       []apiKeySummary `json:"keys"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// apiKeySummary describes a key without its credentials.
+type apiKeySummary struct {
+	KeyID         string                 `json:"key_id"`
+	Alias         string                 `json:"alias,omitempty"`
+	OrgID         string                 `json:"org_id"`
+	ApplyPolicies []string               `json:"apply_policies,omitempty"`
+	APIs          []string               `json:"apis,omitempty"`
+	Tags          []string               `json:"tags,omitempty"`
+	MetaData      map[string]interface{} `json:"meta_data,omitempty"`
+	Expires       int64                  `json:"expires"`
+	IsInactive    bool                   `json:"is_inactive"`
+}
+
+// keySearchQuery filters keys, every set filter must match.
+type keySearchQuery struct {
+	cursor string
+	limit  int
+
+	alias         string
+	tags          []string
+	metaData      map[string]string
+	policy        string
+	apiID         string
+	orgID         string
+	expiresAfter  int64
+	expiresBefore int64
+	inactive      *bool
+}
+
+// isKeySearch reports whether the key listing request opts in to the paginated search with the
+// cursor parameter, left empty for the first page. Without it the legacy listing is returned,
+// whatever the other parameters are.
+func isKeySearch(query url.Values) bool {
+	return query.Has("cursor")
+}
+
+func parseKeySearchQuery(query url.Values) (*keySearchQuery, error) {
+	q := &keySearchQuery{
+		cursor:   query.Get("cursor"),
+		limit:    keySearchDefaultLimit,
+		alias:    query.Get("alias"),
+		tags:     query["tag"],
+		metaData: map[string]string{},
+		policy:   query.Get("policy"),
+		apiID:    query.Get("api_id"),
+		orgID:    query.Get("org_id"),
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > keySearchMaxLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", keySearchMaxLimit)
+		}
+		q.limit = limit
+	}
+
+	for _, v := range query["meta_data"] {
+		field, value, ok := strings.Cut(v, ":")
+		if !ok || field == "" {
+			return nil, errors.New("meta_data must be formatted as field:value")
+		}
+		q.metaData[field] = value
+	}
+
+	for param, dst := range map[string]*int64{"expires_after": &q.expiresAfter, "expires_before": &q.expiresBefore} {
+		if v := query.Get(param); v != "" {
+			ts, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a Unix timestamp", param)
+			}
+			*dst = ts
+		}
+	}
+
+	if v := query.Get("inactive"); v != "" {
+		inactive, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.New("inactive must be true or false")
+		}
+		q.inactive = &inactive
+	}
+
+	return q, nil
+}
+
+// match reports whether the session matches every filter of the query.
+func (q *keySearchQuery) match(session *user.SessionState) bool {
+	if q.alias != "" && !strings.EqualFold(session.Alias, q.alias) {
+		return false
+	}
+
+	if q.orgID != "" && session.OrgID != q.orgID {
+		return false
+	}
+
+	for _, tag := range q.tags {
+		if !contains(session.Tags, tag) {
+			return false
+		}
+	}
+
+	for field, value := range q.metaData {
+		v, ok := session.MetaData[field]
+		if !ok || fmt.Sprint(v) != value {
+			return false
+		}
+	}
+
+	if q.policy != "" && !contains(session.PolicyIDs(), q.policy) {
+		return false
+	}
+
+	if q.apiID != "" {
+		if _, ok := session.AccessRights[q.apiID]; !ok {
+			return false
+		}
+	}
+
+	// keys that never expire have an expiry of 0
+	if q.expiresAfter != 0 && session.Expires > 0 && session.Expires < q.expiresAfter {
+		return false
+	}
+
+	if q.expiresBefore != 0 && (session.Expires <= 0 || session.Expires > q.expiresBefore) {
+		return false
+	}
+
+	if q.inactive != nil && session.IsInactive != *q.inactive {
+		return false
+	}
+
+	return true
+}
+
+func newAPIKeySummary(keyID string, session *user.SessionState) apiKeySummary {
+	apis := make([]string, 0, len(session.AccessRights))
+	for apiID := range session.AccessRights {
+		apis = append(apis, apiID)
+	}
+	sort.Strings(apis)
+
+	return apiKeySummary{
+		KeyID:         keyID,
+		Alias:         session.Alias,
+		OrgID:         session.OrgID,
+		ApplyPolicies: session.PolicyIDs(),
+		APIs:          apis,
+		Tags:          session.Tags,
+		MetaData:      session.MetaData,
+		Expires:       session.Expires,
+		IsInactive:    session.IsInactive,
+	}
+}
+
+// handleSearchKeys returns a page of the keys matching the query. Pages are read with SCAN,
+// so they may hold a few more keys than the limit, or fewer, even none, with a next cursor
+// when the filters are selective.
+func (gw *Gateway) handleSearchKeys(q *keySearchQuery) (interface{}, int) {
+	store := gw.GlobalSessionManager.Store()
+
+	scanner, ok := store.(keyScanner)
+	if !ok {
+		return apiError("Key search is not supported by the key store"), http.StatusNotImplemented
+	}
+
+	result := apiKeySearchResult{Keys: []apiKeySummary{}}
+	cursor := q.cursor
+	prefix := store.GetKeyPrefix()
+
+	for batch := 0; batch < keySearchMaxBatches && len(result.Keys) < q.limit; batch++ {
+		keys, next, err := scanner.ScanKeysPage("*", cursor, int64(q.limit))
+		if errors.Is(err, storage.ErrInvalidCursor) {
+			return apiError("Invalid cursor"), http.StatusBadRequest
+		}
+		if err != nil {
+			log.WithError(err).Error("Failed to scan keys.")
+			return apiError("Failed to list keys"), http.StatusInternalServerError
+		}
+		cursor = next
+
+		sessionKeys := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if !strings.HasPrefix(key, QuotaKeyPrefix) && !strings.HasPrefix(key, RateLimitKeyPrefix) {
+				sessionKeys = append(sessionKeys, key)
+			}
+		}
+
+		matches, err := matchKeys(scanner, prefix, sessionKeys, q)
+		if err != nil {
+			log.WithError(err).Error("Failed to read keys.")
+			return apiError("Failed to list keys"), http.StatusInternalServerError
+		}
+		result.Keys = append(result.Keys, matches...)
+
+		if cursor == "" {
+			break
+		}
+	}
+
+	result.NextCursor = cursor
+
+	log.WithFields(logrus.Fields{
+		"prefix": "api",
+		"status": "ok",
+		"count":  len(result.Keys),
+	}).Info("Searched keys.")
+
+	return result, http.StatusOK
+}
+
+// matchKeys reads the sessions of the keys and returns those matching the query.
+func matchKeys(scanner keyScanner, prefix string, keys []string, q *keySearchQuery) ([]apiKeySummary, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	rawKeys := make([]string, len(keys))
+	for i, key := range keys {
+		rawKeys[i] = prefix + key
+	}
+
+	values, err := scanner.GetRawMultiKey(rawKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []apiKeySummary
+	for i, value := range values {
+		session := &user.SessionState{}
+		// keys deleted during the scan, or which aren't sessions, are skipped
+		if value == "" || json.Unmarshal([]byte(value), session) != nil {
+			continue
+		}
+
+		if q.match(session) {
+			matches = append(matches, newAPIKeySummary(keys[i], session))
+		}
+	}
+	return matches, nil
+}
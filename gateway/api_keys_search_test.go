@@ -0,0 +1,134 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/test"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+func TestKeySearchQuery_Match(t *testing.T) {
+	session := &user.SessionState{
+		Alias:         "Jane",
+		OrgID:         "org",
+		Tags:          []string{"gold", "eu"},
+		MetaData:      map[string]interface{}{"email": "jane@example.com", "tier": 2},
+		ApplyPolicies: []string{"pol"},
+		AccessRights:  map[string]user.AccessDefinition{"api": {APIID: "api"}},
+		Expires:       2000,
+	}
+
+	for _, tc := range []struct {
+		query string
+		match bool
+	}{
+		{"", true},
+		{"alias=jane", true},
+		{"alias=john", false},
+		{"tag=gold&tag=eu", true},
+		{"tag=gold&tag=us", false},
+		{"meta_data=email:jane@example.com", true},
+		{"meta_data=tier:2", true},
+		{"meta_data=email:john@example.com", false},
+		{"policy=pol", true},
+		{"policy=other", false},
+		{"api_id=api", true},
+		{"api_id=other", false},
+		{"org_id=org", true},
+		{"expires_after=1000&expires_before=3000", true},
+		{"expires_before=1000", false},
+		{"expires_after=3000", false},
+		{"inactive=false", true},
+		{"inactive=true", false},
+	} {
+		values, err := url.ParseQuery(tc.query)
+		require.NoError(t, err)
+
+		q, err := parseKeySearchQuery(values)
+		require.NoError(t, err)
+		assert.Equal(t, tc.match, q.match(session), tc.query)
+	}
+
+	// keys which never expire only match expires_after
+	session.Expires = 0
+	q, err := parseKeySearchQuery(url.Values{"expires_after": {"3000"}})
+	require.NoError(t, err)
+	assert.True(t, q.match(session))
+
+	q, err = parseKeySearchQuery(url.Values{"expires_before": {"3000"}})
+	require.NoError(t, err)
+	assert.False(t, q.match(session))
+
+	for _, query := range []string{"limit=0", "limit=1001", "meta_data=email", "expires_after=tomorrow", "inactive=maybe"} {
+		values, err := url.ParseQuery(query)
+		require.NoError(t, err)
+
+		_, err = parseKeySearchQuery(values)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestKeySearch(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+		spec.APIID = "search"
+		spec.Proxy.ListenPath = "/search/"
+	})
+
+	const email = "search-test@example.com"
+
+	for i := 0; i < 5; i++ {
+		session := CreateStandardSession()
+		session.AccessRights = map[string]user.AccessDefinition{"search": {APIID: "search", Versions: []string{"v1"}}}
+		if i%2 == 0 {
+			session.MetaData = map[string]interface{}{"email": email}
+		}
+
+		_, _ = ts.Run(t, test.TestCase{Method: http.MethodPost, Path: "/tyk/keys/create", AdminAuth: true, Data: session, Code: http.StatusOK})
+	}
+
+	var found []apiKeySummary
+	cursor := ""
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 1000, "search never ended")
+
+		resp, err := ts.Run(t, test.TestCase{
+			Method:    http.MethodGet,
+			Path:      "/tyk/keys?limit=1&meta_data=email:" + email + "&cursor=" + url.QueryEscape(cursor),
+			AdminAuth: true,
+			Code:      http.StatusOK,
+		})
+		require.NoError(t, err)
+
+		var page apiKeySearchResult
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+		found = append(found, page.Keys...)
+
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	require.Len(t, found, 3)
+	for _, key := range found {
+		assert.Equal(t, email, key.MetaData["email"])
+		assert.Equal(t, []string{"search"}, key.APIs)
+	}
+
+	_, _ = ts.Run(t, []test.TestCase{
+		{Method: http.MethodGet, Path: "/tyk/keys?cursor=&limit=0", AdminAuth: true, Code: http.StatusBadRequest},
+		{Method: http.MethodGet, Path: "/tyk/keys?cursor=invalid", AdminAuth: true, Code: http.StatusBadRequest, BodyMatch: "Invalid cursor"},
+		{Method: http.MethodGet, Path: "/tyk/keys", AdminAuth: true, Code: http.StatusOK, BodyNotMatch: "next_cursor"},
+		// filters without a cursor keep the legacy listing
+		{Method: http.MethodGet, Path: "/tyk/keys?api_id=search&org_id=default&limit=1", AdminAuth: true, Code: http.StatusOK, BodyNotMatch: "key_id|next_cursor"},
+	}...)
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"path"
 	"path/filepath"
+	"reflect"
 	"runtime/debug"
 	"sort"
 	"strconv"
@@ -23,6 +24,7 @@ import (
 
 	"github.com/TykTechnologies/tyk/apidef"
 	"github.com/TykTechnologies/tyk/apidef/oas"
+	"github.com/TykTechnologies/tyk/config"
 	"github.com/TykTechnologies/tyk/coprocess"
 	"github.com/TykTechnologies/tyk/rpc"
 	"github.com/TykTechnologies/tyk/storage"
@@ -43,6 +45,8 @@ type ChainObject struct {
 	RateLimitChain http.Handler
 	Open           bool
 	Skip           bool
+	// Middleware are the names of the middleware of ThisHandler, in order.
+	Middleware []string
 }
 
 // ProcessSpecOptions represents options for processSpec method
@@ -301,6 +305,7 @@ func (gw *Gateway) processSpec(
 		logger.Info("Checking security policy: Open")
 	}
 
+	gw.mwAppendEnabled(&chainArray, &ActivationScheduleMiddleware{BaseMiddleware: baseMid.Copy()})
 	gw.mwAppendEnabled(&chainArray, &VersionCheck{BaseMiddleware: baseMid.Copy()})
 	gw.mwAppendEnabled(&chainArray, &CORSMiddleware{BaseMiddleware: baseMid.Copy()})
 
@@ -514,6 +519,7 @@ func (gw *Gateway) processSpec(
 	}
 
 	gw.mwAppendEnabled(&chainArray, &ValidateJSON{BaseMiddleware: baseMid.Copy()})
+	gw.mwAppendEnabled(&chainArray, &ValidateXML{BaseMiddleware: baseMid.Copy()})
 	gw.mwAppendEnabled(&chainArray, &ValidateRequest{BaseMiddleware: baseMid.Copy()})
 	gw.mwAppendEnabled(&chainArray, &PersistGraphQLOperationMiddleware{BaseMiddleware: baseMid.Copy()})
 	gw.mwAppendEnabled(&chainArray, &TransformMiddleware{baseMid.Copy()})
@@ -548,9 +554,11 @@ func (gw *Gateway) processSpec(
 		}
 	}
 	chain = alice.New(chainArray...).Then(&DummyProxyHandler{SH: SuccessHandler{baseMid.Copy()}, Gw: gw})
+	chainDef.Middleware = append([]string(nil), spec.middlewareNames...)
 
 	if !spec.UseKeylessAccess {
 		var simpleArray []alice.Constructor
+		gw.mwAppendEnabled(&simpleArray, &ActivationScheduleMiddleware{BaseMiddleware: baseMid.Copy()})
 		gw.mwAppendEnabled(&simpleArray, &IPWhiteListMiddleware{baseMid.Copy()})
 		gw.mwAppendEnabled(&simpleArray, &IPBlackListMiddleware{BaseMiddleware: baseMid.Copy()})
 		gw.mwAppendEnabled(&simpleArray, &OrganizationMonitor{BaseMiddleware: baseMid.Copy(), mon: Monitor{Gw: gw}})
@@ -1044,10 +1052,75 @@ func listenPathLength(listenPath string) int {
 	return length
 }
 
-// Create the individual API (app) specs based on live configurations and assign middleware
+// apiSpecChanges is the difference between the loaded API specs and the specs of a reload.
+type apiSpecChanges struct {
+	added, updated, removed []*APISpec
+	// rebuilt are specs with an unchanged checksum that are rebuilt anyway, see shouldReloadSpec.
+	rebuilt   []*APISpec
+	unchanged int
+}
+
+// empty reports whether the reload leaves every loaded API as it is.
+func (c *apiSpecChanges) empty() bool {
+	return len(c.added)+len(c.updated)+len(c.removed)+len(c.rebuilt) == 0
+}
+
+// diffAPISpecs compares the specs of a reload with the loaded ones by checksum.
+func (gw *Gateway) diffAPISpecs(specs []*APISpec) apiSpecChanges {
+	gw.apisMu.RLock()
+	defer gw.apisMu.RUnlock()
+
+	var changes apiSpecChanges
+	seen := make(map[string]struct{}, len(specs))
+
+	for _, spec := range specs {
+		seen[spec.APIID] = struct{}{}
+
+		curSpec, ok := gw.apisByID[spec.APIID]
+		switch {
+		case !ok || curSpec == nil:
+			changes.added = append(changes.added, spec)
+		case curSpec.Checksum != spec.Checksum:
+			changes.updated = append(changes.updated, spec)
+		case shouldReloadSpec(curSpec, spec):
+			changes.rebuilt = append(changes.rebuilt, spec)
+		default:
+			changes.unchanged++
+		}
+	}
+
+	for apiID, curSpec := range gw.apisByID {
+		if _, ok := seen[apiID]; !ok {
+			changes.removed = append(changes.removed, curSpec)
+		}
+	}
+
+	return changes
+}
+
+// appsLoadedWith reports whether the current routes were built with the given configuration.
+func (gw *Gateway) appsLoadedWith(conf config.Config) bool {
+	gw.apisMu.RLock()
+	defer gw.apisMu.RUnlock()
+
+	return gw.appsLoadedConfig != nil && reflect.DeepEqual(*gw.appsLoadedConfig, conf)
+}
+
+// loadApps builds the routers of the specs and swaps them in. The routers are rebuilt on every
+// load with changes, while the chains of the specs which don't need a reload are reused as before
+// (see shouldReloadSpec). When no spec was added, updated or removed the current routers are kept.
 func (gw *Gateway) loadApps(specs []*APISpec) {
 	mainLog.Info("Loading API configurations.")
 
+	changes := gw.diffAPISpecs(specs)
+	if changes.empty() && gw.appsLoadedWith(gw.GetConfig()) {
+		mainLog.Infof("API definitions unchanged, keeping the %d loaded APIs", changes.unchanged)
+		return
+	}
+
+	mainLog.Infof("Loading API changes: %d added, %d updated, %d removed, %d rebuilt, %d unchanged",
+		len(changes.added), len(changes.updated), len(changes.removed), len(changes.rebuilt), changes.unchanged)
+
 	tmpSpecRegister := make(map[string]*APISpec)
 	tmpSpecHandles := new(sync.Map)
 
@@ -1163,6 +1236,11 @@ func (gw *Gateway) loadApps(specs []*APISpec) {
 		spec.Unload()
 	}
 
+	// The initial load isn't reported, every API would be reported as added.
+	if gw.performedSuccessfulReload {
+		gw.fireAPIEvents(changes.added, changes.updated, changes.removed)
+	}
+
 	mainLog.Debug("Checker host list")
 
 	// Kick off our host checkers
@@ -1181,6 +1259,12 @@ func (gw *Gateway) loadApps(specs []*APISpec) {
 		mainLog.Warning("All APIs are protected with mTLS, except for the control API. " +
 			"We recommend configuring the control API port or control hostname to ensure consistent security measures")
 	}
+
+	// loading may update the configuration, so it's read once done
+	loadedConf := gw.GetConfig()
+	gw.apisMu.Lock()
+	gw.appsLoadedConfig = &loadedConf
+	gw.apisMu.Unlock()
 }
 
 func recoverFromLoadApiPanic(spec *APISpec, err any) error {
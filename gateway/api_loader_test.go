@@ -9646,3 +9646,72 @@ func TestEnforceOrgDataAgeIfQuotasEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadApps_Incremental(t *testing.T) {
+	ts := StartTest(nil)
+	t.Cleanup(ts.Close)
+
+	api := func(id, name string) func(*APISpec) {
+		return func(spec *APISpec) {
+			spec.APIID = id
+			spec.Name = name
+			spec.Proxy.ListenPath = "/" + id + "/"
+		}
+	}
+
+	ts.Gw.BuildAndLoadAPI(api("api-a", "A"), api("api-b", "B"))
+
+	specA, specB := ts.Gw.getApiSpec("api-a"), ts.Gw.getApiSpec("api-b")
+	chainA, _ := ts.Gw.apisHandlesByID.Load("api-a")
+
+	ts.Gw.BuildAndLoadAPI(api("api-a", "A"), api("api-b", "B changed"), api("api-c", "C"))
+
+	t.Run("unchanged specs keep their state", func(t *testing.T) {
+		assert.Same(t, specA, ts.Gw.getApiSpec("api-a"))
+		newChainA, _ := ts.Gw.apisHandlesByID.Load("api-a")
+		assert.Same(t, chainA, newChainA)
+
+		assert.NotSame(t, specB, ts.Gw.getApiSpec("api-b"))
+		assert.Equal(t, "B changed", ts.Gw.getApiSpec("api-b").Name)
+
+		_, _ = ts.Run(t, []test.TestCase{
+			{Path: "/api-a/", Code: http.StatusOK},
+			{Path: "/api-b/", Code: http.StatusOK},
+			{Path: "/api-c/", Code: http.StatusOK},
+		}...)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		loaded := []*APISpec{ts.Gw.getApiSpec("api-a"), ts.Gw.getApiSpec("api-b")}
+		changed := &APISpec{APIDefinition: &apidef.APIDefinition{APIID: "api-a"}, Checksum: "changed"}
+		added := &APISpec{APIDefinition: &apidef.APIDefinition{APIID: "api-d"}}
+
+		changes := ts.Gw.diffAPISpecs(loaded)
+		assert.Equal(t, 2, changes.unchanged)
+		assert.Len(t, changes.removed, 1)
+		assert.Equal(t, "api-c", changes.removed[0].APIID)
+
+		changes = ts.Gw.diffAPISpecs([]*APISpec{changed, loaded[1], ts.Gw.getApiSpec("api-c"), added})
+		assert.Equal(t, []*APISpec{changed}, changes.updated)
+		assert.Equal(t, []*APISpec{added}, changes.added)
+		assert.Empty(t, changes.removed)
+		assert.Equal(t, 2, changes.unchanged)
+	})
+
+	t.Run("routes are kept when nothing changed", func(t *testing.T) {
+		specs := []*APISpec{ts.Gw.getApiSpec("api-a"), ts.Gw.getApiSpec("api-b"), ts.Gw.getApiSpec("api-c")}
+
+		ts.Gw.loadApps(specs)
+		router := ts.getMainRouter(ts.Gw.DefaultProxyMux)
+
+		ts.Gw.loadApps(specs)
+		assert.Same(t, router, ts.getMainRouter(ts.Gw.DefaultProxyMux))
+
+		conf := ts.Gw.GetConfig()
+		conf.Track404Logs = !conf.Track404Logs
+		ts.Gw.SetConfig(conf)
+
+		ts.Gw.loadApps(specs)
+		assert.NotSame(t, router, ts.getMainRouter(ts.Gw.DefaultProxyMux))
+	})
+}
@@ -0,0 +1,158 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/apidef/oas"
+	"github.com/TykTechnologies/tyk/internal/httputil"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+var (
+	errConsumerViewKeyNotFound    = errors.New("Key not found")
+	errConsumerViewPolicyNotFound = errors.New("Policy not found")
+	errConsumerViewAccessDenied   = errors.New("The key or the policy doesn't grant access to the API")
+)
+
+// consumerViewAccessRights returns the access rights of the key or the policy requested by the query
+// parameters `key` and `policy`, or nil when the view isn't limited to a key or a policy.
+func (gw *Gateway) consumerViewAccessRights(apiID string, query url.Values) (map[string]user.AccessDefinition, error) {
+	if policyID := query.Get("policy"); policyID != "" {
+		policy, ok := gw.PolicyByID(policyID)
+		if !ok {
+			return nil, errConsumerViewPolicyNotFound
+		}
+
+		return policy.AccessRights, nil
+	}
+
+	keyName := query.Get("key")
+	if keyName == "" {
+		return nil, nil
+	}
+
+	orgID := query.Get("org_id")
+	spec := gw.getApiSpec(apiID)
+	if spec != nil {
+		orgID = spec.OrgID
+	}
+
+	session, ok := gw.GlobalSessionManager.SessionDetail(orgID, keyName, query.Get("hashed") != "")
+	if !ok {
+		return nil, errConsumerViewKeyNotFound
+	}
+
+	mw := &BaseMiddleware{Spec: spec, Gw: gw}
+	if err := mw.ApplyPolicies(&session); err != nil {
+		return nil, err
+	}
+
+	if session.AccessRights == nil {
+		return map[string]user.AccessDefinition{}, nil
+	}
+
+	return session.AccessRights, nil
+}
+
+// handleGetAPIOASConsumerView returns the public view of an OAS API, limited to the access rights when set.
+func (gw *Gateway) handleGetAPIOASConsumerView(apiID string, accessRights map[string]user.AccessDefinition) (interface{}, int) {
+	spec := gw.getApiSpec(apiID)
+	if spec == nil {
+		return apiError(apidef.ErrAPINotFound.Error()), http.StatusNotFound
+	}
+
+	if !spec.IsOAS {
+		return apiError(apidef.ErrOASGetForOldAPI.Error()), http.StatusBadRequest
+	}
+
+	if _, ok := accessRights[apiID]; accessRights != nil && !ok {
+		return apiError(errConsumerViewAccessDenied.Error()), http.StatusForbidden
+	}
+
+	view, err := gw.consumerView(spec, accessRights)
+	if err != nil {
+		return apiError(err.Error()), http.StatusInternalServerError
+	}
+
+	return view, http.StatusOK
+}
+
+// handleGetAPIListOASConsumerView returns the public views of the OAS APIs, limited to the APIs of the access rights when set.
+func (gw *Gateway) handleGetAPIListOASConsumerView(accessRights map[string]user.AccessDefinition) (interface{}, int) {
+	gw.apisMu.RLock()
+	specs := make([]*APISpec, 0, len(gw.apisByID))
+	for _, spec := range gw.apisByID {
+		if _, ok := accessRights[spec.APIID]; spec.IsOAS && (accessRights == nil || ok) {
+			specs = append(specs, spec)
+		}
+	}
+	gw.apisMu.RUnlock()
+
+	apisList := []oas.OAS{}
+
+	for _, spec := range specs {
+		view, err := gw.consumerView(spec, accessRights)
+		if err != nil {
+			return apiError(err.Error()), http.StatusInternalServerError
+		}
+
+		apisList = append(apisList, *view)
+	}
+
+	return apisList, http.StatusOK
+}
+
+// consumerView returns the document of an API as its consumers see it through the gateway.
+func (gw *Gateway) consumerView(spec *APISpec, accessRights map[string]user.AccessDefinition) (*oas.OAS, error) {
+	// build the view from a copy, the loaded API is shared with the proxy
+	apiOAS, err := spec.OAS.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	apiOAS.Fill(*spec.APIDefinition)
+
+	var baseAPI *apidef.APIDefinition
+	if spec.IsChildAPI() {
+		if baseSpec := gw.getApiSpec(spec.VersionDefinition.BaseID); baseSpec != nil {
+			baseAPI = baseSpec.APIDefinition
+		}
+	}
+
+	config := oas.PublicViewConfig{
+		Servers: apiOAS.GenerateTykServers(spec.APIDefinition, baseAPI, buildServerRegenerationConfig(gw.GetConfig()), ""),
+	}
+
+	if allowedURLs := accessRights[spec.APIID].AllowedURLs; len(allowedURLs) > 0 {
+		config.AllowOperation = func(method, path string) bool {
+			return gw.allowsOperation(allowedURLs, spec.Proxy.ListenPath, method, path)
+		}
+	}
+
+	return apiOAS.PublicView(config)
+}
+
+// allowsOperation returns true if one of the allowed URLs of a key or a policy grants access to an operation.
+// The URL patterns are matched against the path template of the operation, with and without the listen path.
+func (gw *Gateway) allowsOperation(allowedURLs []user.AccessSpec, listenPath, method, path string) bool {
+	httpServerOptions := gw.GetConfig().HttpServerOptions
+	paths := []string{path, strings.TrimSuffix(listenPath, "/") + path}
+
+	for _, accessSpec := range allowedURLs {
+		if !slices.Contains(accessSpec.Methods, method) {
+			continue
+		}
+
+		pattern := httputil.PreparePathRegexp(accessSpec.URL, httpServerOptions.EnablePathPrefixMatching, httpServerOptions.EnablePathSuffixMatching)
+		if match, err := httputil.MatchPaths(pattern, paths); err == nil && match {
+			return true
+		}
+	}
+
+	return false
+}
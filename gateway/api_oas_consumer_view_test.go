@@ -0,0 +1,125 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef/oas"
+	"github.com/TykTechnologies/tyk/test"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+func TestAPIOASExport_ConsumerView(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	const (
+		apiID    = "consumer-view"
+		document = `{
+  "openapi": "3.0.3",
+  "info": {"title": "pets", "version": "1.0.0"},
+  "servers": [{"url": "http://upstream.internal"}],
+  "paths": {
+    "/pets": {
+      "get": {"operationId": "listPets", "responses": {"200": {"description": "OK"}}}
+    },
+    "/pets/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+      "get": {"operationId": "getPet", "responses": {"200": {"description": "OK"}}},
+      "delete": {"operationId": "deletePet", "responses": {"204": {"description": "Deleted"}}}
+    }
+  },
+  "components": {
+    "securitySchemes": {
+      "api_key": {"type": "apiKey", "in": "header", "name": "X-API-Key"}
+    }
+  },
+  "security": [{"api_key": []}]
+}`
+	)
+
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(document))
+	require.NoError(t, err)
+
+	enabled := true
+	oasAPI := oas.OAS{T: *doc}
+	oasAPI.SetTykExtension(&oas.XTykAPIGateway{
+		Info: oas.Info{ID: apiID, Name: "pets", State: oas.State{Active: true}},
+		Server: oas.Server{
+			ListenPath: oas.ListenPath{Value: "/consumer-view/", Strip: true},
+			Authentication: &oas.Authentication{
+				Enabled: true,
+				SecuritySchemes: oas.SecuritySchemes{
+					"api_key": &oas.Token{Enabled: &enabled},
+				},
+			},
+		},
+		Upstream: oas.Upstream{URL: TestHttpAny},
+		Middleware: &oas.Middleware{
+			Operations: oas.Operations{
+				"deletePet": {Block: &oas.Allowance{Enabled: true}},
+			},
+		},
+	})
+
+	ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+		spec.APIID = apiID
+		oasAPI.ExtractTo(spec.APIDefinition)
+		spec.IsOAS = true
+		spec.OAS = oasAPI
+	})
+
+	policyID := ts.CreatePolicy(func(p *user.Policy) {
+		p.AccessRights = map[string]user.AccessDefinition{
+			apiID: {
+				APIID:       apiID,
+				AllowedURLs: []user.AccessSpec{{URL: "/pets/{id}", Methods: []string{http.MethodGet}}},
+			},
+		}
+	})
+
+	_, otherKey := ts.CreateSession(func(s *user.SessionState) {
+		s.AccessRights = map[string]user.AccessDefinition{"other-api": {APIID: "other-api"}}
+	})
+
+	exportPath := "/tyk/apis/oas/" + apiID + "/export?mode=consumer"
+	matchHeaders := map[string]string{"Content-Type": "application/octet-stream"}
+
+	_, _ = ts.Run(t, []test.TestCase{
+		{
+			AdminAuth: true, Method: http.MethodGet, Path: exportPath, Code: http.StatusOK, HeadersMatch: matchHeaders,
+			BodyNotMatch: `deletePet|upstream\.internal|x-tyk-api-gateway`,
+			BodyMatchFunc: func(body []byte) bool {
+				var view oas.OAS
+				if err := json.Unmarshal(body, &view); err != nil || len(view.Servers) == 0 || view.Components == nil {
+					return false
+				}
+
+				return strings.HasSuffix(view.Servers[0].URL, "/consumer-view/") &&
+					view.Paths.Value("/pets").Get != nil &&
+					view.Components.Responses["TykTooManyRequests"] != nil
+			},
+		},
+		{
+			AdminAuth: true, Method: http.MethodGet, Path: exportPath + "&policy=" + policyID, Code: http.StatusOK,
+			BodyMatch: `"getPet"`, BodyNotMatch: `listPets`,
+		},
+		{
+			AdminAuth: true, Method: http.MethodGet, Path: exportPath + "&key=" + otherKey, Code: http.StatusForbidden,
+			BodyMatch: errConsumerViewAccessDenied.Error(),
+		},
+		{
+			AdminAuth: true, Method: http.MethodGet, Path: exportPath + "&policy=unknown", Code: http.StatusNotFound,
+			BodyMatch: errConsumerViewPolicyNotFound.Error(),
+		},
+		{
+			AdminAuth: true, Method: http.MethodGet, Path: "/tyk/apis/oas/export?mode=consumer&key=" + otherKey, Code: http.StatusOK,
+			BodyMatch: `^\[\]`,
+		},
+	}...)
+}
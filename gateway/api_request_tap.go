@@ -0,0 +1,149 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+
+	"github.com/TykTechnologies/tyk/header"
+)
+
+// requestTapKeepAlive is how often an idle SSE tap sends a comment to keep the connection open.
+const requestTapKeepAlive = 15 * time.Second
+
+// requestTapEnd is the last message of a tap, sent when its duration elapses.
+type requestTapEnd struct {
+	Dropped int64 `json:"dropped"`
+}
+
+// requestTapMessage wraps tap events sent over a WebSocket, Event matches the SSE event names.
+type requestTapMessage struct {
+	Event string      `json:"event"`
+	Data  interface{} `json:"data"`
+}
+
+// requestTapHandler streams the live traffic of an API as Server-Sent Events, or over a WebSocket
+// when the client asks for an upgrade. The stream ends when the tap duration elapses.
+func (gw *Gateway) requestTapHandler(w http.ResponseWriter, r *http.Request) {
+	apiID := mux.Vars(r)["apiID"]
+
+	if gw.getApiSpec(apiID) == nil {
+		doJSONWrite(w, http.StatusNotFound, apiError("API not found"))
+		return
+	}
+
+	opts, err := parseRequestTapOptions(r.URL.Query())
+	if err != nil {
+		doJSONWrite(w, http.StatusBadRequest, apiError(err.Error()))
+		return
+	}
+
+	tap := gw.requestTaps.subscribe(apiID, opts)
+	defer gw.requestTaps.unsubscribe(tap)
+
+	ctx, cancel := context.WithTimeout(r.Context(), opts.Duration)
+	defer cancel()
+
+	log.WithField("api_id", apiID).WithField("duration", opts.Duration).Info("Request tap started")
+	defer func() {
+		log.WithField("api_id", apiID).WithField("dropped", tap.dropped.Load()).Info("Request tap finished")
+	}()
+
+	if websocket.IsWebSocketUpgrade(r) {
+		gw.streamRequestTapWebSocket(ctx, cancel, w, r, tap)
+		return
+	}
+
+	gw.streamRequestTapSSE(ctx, w, tap)
+}
+
+func (gw *Gateway) streamRequestTapSSE(ctx context.Context, w http.ResponseWriter, tap *requestTap) {
+	rc := http.NewResponseController(w)
+	// the stream outlives the control API write timeout
+	_ = rc.SetWriteDeadline(time.Now().Add(tap.opts.Duration + requestTapKeepAlive))
+
+	w.Header().Set(header.ContentType, "text/event-stream")
+	w.Header().Set(header.CacheControl, "no-cache")
+	w.Header().Set(header.Connection, "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if err := rc.Flush(); err != nil {
+		log.WithError(err).Error("Request tap streaming is not supported")
+		return
+	}
+
+	write := func(event string, v interface{}) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	keepAlive := time.NewTicker(requestTapKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case ev := <-tap.events:
+			if err := write("request", ev); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			_ = write("end", requestTapEnd{Dropped: tap.dropped.Load()})
+			return
+		}
+	}
+}
+
+func (gw *Gateway) streamRequestTapWebSocket(ctx context.Context, cancel context.CancelFunc, w http.ResponseWriter, r *http.Request, tap *requestTap) {
+	upgrader := websocket.Upgrader{}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.WithError(err).Error("Request tap websocket upgrade failed")
+		return
+	}
+	defer conn.Close()
+
+	// the hijacked connection may carry the control API deadlines
+	_ = conn.SetReadDeadline(time.Time{})
+	_ = conn.SetWriteDeadline(time.Time{})
+
+	// the tap is read only, reading is only needed to notice the client going away
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case ev := <-tap.events:
+			if err := conn.WriteJSON(requestTapMessage{Event: "request", Data: ev}); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			_ = conn.WriteJSON(requestTapMessage{Event: "end", Data: requestTapEnd{Dropped: tap.dropped.Load()}})
+			_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return
+		}
+	}
+}
@@ -0,0 +1,435 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/apidef/oas"
+	"github.com/TykTechnologies/tyk/internal/audit"
+	"github.com/TykTechnologies/tyk/internal/osutil"
+	"github.com/TykTechnologies/tyk/internal/revision"
+	"github.com/TykTechnologies/tyk/storage"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+// Actions recorded with revisions.
+const (
+	// revisionActionInitial is the version stored before the resource had a history.
+	revisionActionInitial  = "initial"
+	revisionActionAdded    = "added"
+	revisionActionModified = "modified"
+	revisionActionRollback = "rollback"
+)
+
+// apiRevisionDiff is the list of changes between two revisions of a resource.
+type apiRevisionDiff struct {
+	From    int            `json:"from,omitempty"`
+	To      int            `json:"to"`
+	Changes []audit.Change `json:"changes"`
+}
+
+// newRevisionStore returns the store configured for the history of APIs and policies, nil when it's disabled.
+func (gw *Gateway) newRevisionStore() revision.Store {
+	conf := gw.GetConfig().Revisions
+	if !conf.Enabled {
+		return nil
+	}
+
+	switch conf.Storage {
+	case "", revision.StorageFile:
+		path := conf.Path
+		if path == "" {
+			path = filepath.Join(gw.GetConfig().AppPath, "revisions")
+		}
+
+		store, err := revision.NewFileStore(path, conf.Keep)
+		if err != nil {
+			mainLog.WithError(err).Error("Failed to open the revisions directory, the revision history is disabled")
+			return nil
+		}
+		return store
+	case revision.StorageRedis:
+		store := &storage.RedisCluster{ConnectionHandler: gw.StorageConnectionHandler}
+		return revision.NewRedisStore(store.Client, "", conf.Keep)
+	}
+
+	mainLog.Errorf("Unknown revisions storage %q, the revision history is disabled", conf.Storage)
+	return nil
+}
+
+// revisionResourceID returns the ID of the API or policy from the route.
+func revisionResourceID(kind string, r *http.Request) string {
+	if kind == revision.KindPolicy {
+		return mux.Vars(r)["polID"]
+	}
+	return mux.Vars(r)["apiID"]
+}
+
+func (gw *Gateway) revisionListHandler(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		obj, code := gw.handleListRevisions(kind, revisionResourceID(kind, r))
+		doJSONWrite(w, code, obj)
+	}
+}
+
+func (gw *Gateway) revisionGetHandler(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		obj, code := gw.handleGetRevision(kind, revisionResourceID(kind, r), mux.Vars(r)["rev"])
+		doJSONWrite(w, code, obj)
+	}
+}
+
+func (gw *Gateway) revisionDiffHandler(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		obj, code := gw.handleDiffRevisions(kind, revisionResourceID(kind, r), mux.Vars(r)["rev"], r.URL.Query().Get("from"))
+		doJSONWrite(w, code, obj)
+	}
+}
+
+func (gw *Gateway) rollbackHandler(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		obj, code := gw.handleRollback(kind, revisionResourceID(kind, r), mux.Vars(r)["rev"])
+		if code != http.StatusOK {
+			doJSONWrite(w, code, obj)
+			return
+		}
+
+		// the restored revision is live once it's reloaded, as for /tyk/reload the reload is only waited for with block=true
+		var wg sync.WaitGroup
+		if r.URL.Query().Get("block") == "true" {
+			wg.Add(1)
+			gw.reloadURLStructure(wg.Done)
+		} else {
+			gw.reloadURLStructure(nil)
+		}
+		wg.Wait()
+
+		doJSONWrite(w, code, obj)
+	}
+}
+
+func (gw *Gateway) handleListRevisions(kind, id string) (interface{}, int) {
+	if gw.revisions == nil {
+		return apiError("Revision history is disabled"), http.StatusNotImplemented
+	}
+
+	revs, err := gw.revisions.List(kind, id)
+	if err != nil {
+		log.WithError(err).Error("Failed to read the revisions.")
+		return apiError("Failed to read the revisions"), http.StatusInternalServerError
+	}
+
+	// the history of deleted resources is kept, so they can be restored
+	if len(revs) == 0 && !gw.revisionResourceExists(kind, id) {
+		return revisionResourceNotFound(kind)
+	}
+
+	summaries := make([]*revision.Revision, len(revs))
+	for i, rev := range revs {
+		summaries[i] = rev.Summary()
+	}
+	return summaries, http.StatusOK
+}
+
+func (gw *Gateway) handleGetRevision(kind, id, number string) (interface{}, int) {
+	rev, obj, code := gw.findRevision(kind, id, number)
+	if rev == nil {
+		return obj, code
+	}
+	return rev, http.StatusOK
+}
+
+// handleDiffRevisions returns the changes made by a revision, from the revision preceding it or
+// from the given one.
+func (gw *Gateway) handleDiffRevisions(kind, id, number, fromNumber string) (interface{}, int) {
+	to, obj, code := gw.findRevision(kind, id, number)
+	if to == nil {
+		return obj, code
+	}
+
+	var from *revision.Revision
+	if fromNumber != "" {
+		if from, obj, code = gw.findRevision(kind, id, fromNumber); from == nil {
+			return obj, code
+		}
+	} else {
+		revs, err := gw.revisions.List(kind, id)
+		if err != nil {
+			log.WithError(err).Error("Failed to read the revisions.")
+			return apiError("Failed to read the revisions"), http.StatusInternalServerError
+		}
+		from = revision.Previous(revs, to.Number)
+	}
+
+	diff := apiRevisionDiff{To: to.Number}
+
+	var before interface{}
+	if from != nil {
+		diff.From = from.Number
+		before = from.Content()
+	}
+	diff.Changes = audit.Diff(before, to.Content())
+
+	return diff, http.StatusOK
+}
+
+// handleRollback validates a revision and stores it back as the current version of the resource.
+func (gw *Gateway) handleRollback(kind, id, number string) (interface{}, int) {
+	rev, obj, code := gw.findRevision(kind, id, number)
+	if rev == nil {
+		return obj, code
+	}
+
+	if kind == revision.KindPolicy {
+		obj, code = gw.rollbackPolicy(id, rev)
+	} else {
+		obj, code = gw.rollbackAPI(id, rev)
+	}
+	if code != http.StatusOK {
+		return obj, code
+	}
+
+	gw.recordRevision(kind, id, revisionActionRollback, rev.Number)
+
+	log.WithFields(logrus.Fields{
+		"prefix":   "api",
+		"kind":     kind,
+		"id":       id,
+		"revision": rev.Number,
+	}).Info("Rolled back to revision.")
+
+	return apiModifyKeySuccess{
+		Key:    id,
+		Status: "ok",
+		Action: "rolled back",
+	}, http.StatusOK
+}
+
+func (gw *Gateway) rollbackAPI(apiID string, rev *revision.Revision) (interface{}, int) {
+	var def apidef.APIDefinition
+	if err := json.Unmarshal(rev.Definition, &def); err != nil {
+		return apiError("Revision is malformed"), http.StatusBadRequest
+	}
+
+	if def.APIID != apiID {
+		return apiError("Revision APIID does not match the API"), http.StatusBadRequest
+	}
+
+	if validationErr := validateAPIDef(&def); validationErr != nil {
+		return *validationErr, http.StatusBadRequest
+	}
+
+	if len(rev.OAS) > 0 {
+		data, oasObj, err := extractOASObjFromReq(bytes.NewReader(rev.OAS))
+		if err != nil {
+			return apiError(err.Error()), http.StatusBadRequest
+		}
+
+		if err := oas.ValidateOASObject(data, oasObj.OpenAPI); err != nil {
+			return apiError(err.Error()), http.StatusBadRequest
+		}
+
+		if err := oasObj.Validate(context.Background(), oas.GetValidationOptionsFromConfig(gw.GetConfig().OAS)...); err != nil {
+			return apiError(err.Error()), http.StatusBadRequest
+		}
+	}
+
+	root, err := osutil.NewRoot(gw.GetConfig().AppPath)
+	if err != nil {
+		log.WithError(err).Error("Unable to access the API definitions path.")
+		return apiError("Unable to access API storage."), http.StatusInternalServerError
+	}
+
+	if err := root.WriteFile(apiID+".json", rev.Definition, 0644); err != nil {
+		log.Error("Failed to create file! - ", err)
+		return apiError("file object creation failed, write error"), http.StatusInternalServerError
+	}
+
+	oasFile := apiID + "-oas.json"
+	if len(rev.OAS) == 0 {
+		if err := root.Remove(oasFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.WithError(err).Warning("Failed to remove the OAS definition of the classic API revision.")
+		}
+		return nil, http.StatusOK
+	}
+
+	if err := root.WriteFile(oasFile, rev.OAS, 0644); err != nil {
+		log.Error("Failed to create file! - ", err)
+		return apiError("file object creation failed, write error"), http.StatusInternalServerError
+	}
+	return nil, http.StatusOK
+}
+
+func (gw *Gateway) rollbackPolicy(polID string, rev *revision.Revision) (interface{}, int) {
+	if gw.GetConfig().Policies.PolicySource == "service" {
+		log.Error("Rejected policy rollback due to PolicySource = service")
+		return apiError("Due to enabled service policy source, please use the Dashboard API"), http.StatusInternalServerError
+	}
+
+	var pol user.Policy
+	if err := json.Unmarshal(rev.Definition, &pol); err != nil {
+		return apiError("Revision is malformed"), http.StatusBadRequest
+	}
+
+	if pol.ID != polID {
+		return apiError("Revision ID does not match the policy"), http.StatusBadRequest
+	}
+
+	root, err := gw.newPolicyPathRoot()
+	if err != nil {
+		log.WithError(err).Error("Unable to access the policy storage root path.")
+		return apiError("Unable to access policy storage."), http.StatusInternalServerError
+	}
+
+	if err := root.WriteFile(polID+".json", rev.Definition, 0644); err != nil {
+		log.Error("Failed to create file! - ", err)
+		return apiError("Failed to create file!"), http.StatusInternalServerError
+	}
+	return nil, http.StatusOK
+}
+
+// findRevision returns the revision, or the error response when it's not kept.
+func (gw *Gateway) findRevision(kind, id, number string) (*revision.Revision, interface{}, int) {
+	if gw.revisions == nil {
+		return nil, apiError("Revision history is disabled"), http.StatusNotImplemented
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return nil, apiError("Revision must be a number"), http.StatusBadRequest
+	}
+
+	rev, err := revision.Get(gw.revisions, kind, id, n)
+	switch {
+	case errors.Is(err, revision.ErrNotFound):
+		return nil, apiError("Revision not found"), http.StatusNotFound
+	case err != nil:
+		log.WithError(err).Error("Failed to read the revisions.")
+		return nil, apiError("Failed to read the revisions"), http.StatusInternalServerError
+	}
+	return rev, nil, http.StatusOK
+}
+
+func (gw *Gateway) revisionResourceExists(kind, id string) bool {
+	if kind == revision.KindPolicy {
+		_, ok := gw.PolicyByID(id)
+		return ok
+	}
+	return gw.getApiSpec(id) != nil
+}
+
+func revisionResourceNotFound(kind string) (interface{}, int) {
+	if kind == revision.KindPolicy {
+		return apiError("Policy not found"), http.StatusNotFound
+	}
+	return apiError(apidef.ErrAPINotFound.Error()), http.StatusNotFound
+}
+
+// recordRevision stores the stored version of the resource as its next revision.
+func (gw *Gateway) recordRevision(kind, id, action string, rolledBackFrom int) {
+	if gw.revisions == nil {
+		return
+	}
+
+	rev, err := gw.readRevision(kind, id)
+	if err != nil {
+		log.WithError(err).WithField("id", id).Errorf("Failed to read the stored %s for its revision history.", kind)
+		return
+	}
+	rev.Action = action
+	rev.RolledBackFrom = rolledBackFrom
+
+	if err := gw.revisions.Add(kind, id, rev); err != nil {
+		log.WithError(err).WithField("id", id).Errorf("Failed to store the %s revision.", kind)
+	}
+}
+
+// recordPreviousRevision keeps the stored version of the resource before it's changed when it has
+// no history yet, so the version predating the history can be rolled back to.
+func (gw *Gateway) recordPreviousRevision(kind, id string) {
+	if gw.revisions == nil {
+		return
+	}
+
+	revs, err := gw.revisions.List(kind, id)
+	if err != nil || len(revs) > 0 {
+		return
+	}
+
+	rev, err := gw.readRevision(kind, id)
+	if err != nil {
+		// nothing is stored yet
+		return
+	}
+	rev.Action = revisionActionInitial
+
+	if err := gw.revisions.Add(kind, id, rev); err != nil {
+		log.WithError(err).WithField("id", id).Errorf("Failed to store the %s revision.", kind)
+	}
+}
+
+// readRevision reads the stored files of the resource.
+func (gw *Gateway) readRevision(kind, id string) (*revision.Revision, error) {
+	rev := &revision.Revision{CreatedAt: time.Now().UTC()}
+
+	if kind == revision.KindPolicy {
+		root, err := gw.newPolicyPathRoot()
+		if err != nil {
+			return nil, err
+		}
+
+		if rev.Definition, err = root.ReadFile(id + ".json"); err != nil {
+			return nil, err
+		}
+		return rev, nil
+	}
+
+	root, err := osutil.NewRoot(gw.GetConfig().AppPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if rev.Definition, err = root.ReadFile(id + ".json"); err != nil {
+		return nil, err
+	}
+
+	var def struct {
+		IsOAS bool `json:"is_oas"`
+	}
+	if err := json.Unmarshal(rev.Definition, &def); err != nil {
+		return nil, err
+	}
+
+	if def.IsOAS {
+		if rev.OAS, err = root.ReadFile(id + "-oas.json"); err != nil {
+			return nil, err
+		}
+	}
+	return rev, nil
+}
+
+// storedRevisionDefinition returns the stored definition of the API or policy, resource is its audit log type.
+func (gw *Gateway) storedRevisionDefinition(resource, id string) interface{} {
+	kind := revision.KindAPI
+	if resource == "policy" {
+		kind = revision.KindPolicy
+	}
+
+	rev, err := gw.readRevision(kind, id)
+	if err != nil {
+		return nil
+	}
+	return rev.Definition
+}
@@ -0,0 +1,118 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/config"
+	"github.com/TykTechnologies/tyk/internal/revision"
+	"github.com/TykTechnologies/tyk/test"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+func TestRevisions(t *testing.T) {
+	ts := StartTest(func(cnf *config.Config) {
+		cnf.Revisions.Enabled = true
+		cnf.Revisions.Keep = 3
+		cnf.Policies.PolicyPath = t.TempDir()
+		cnf.Policies.PolicySource = "file"
+	})
+	defer ts.Close()
+
+	listRevisions := func(t *testing.T, path string) []revision.Revision {
+		t.Helper()
+
+		resp, err := ts.Run(t, test.TestCase{Method: http.MethodGet, Path: path, AdminAuth: true, Code: http.StatusOK})
+		require.NoError(t, err)
+
+		var revs []revision.Revision
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&revs))
+		return revs
+	}
+
+	t.Run("api", func(t *testing.T) {
+		def := BuildAPI(func(spec *APISpec) {
+			spec.APIID = "revisions"
+			spec.Name = "v1"
+			spec.Proxy.ListenPath = "/revisions/"
+		})[0].APIDefinition
+
+		_, _ = ts.Run(t, test.TestCase{Method: http.MethodPost, Path: "/tyk/apis", AdminAuth: true, Data: def, Code: http.StatusOK})
+		_, _ = ts.Run(t, test.TestCase{Method: http.MethodGet, Path: "/tyk/reload/?block=true", AdminAuth: true, Code: http.StatusOK})
+
+		for _, name := range []string{"v2", "v3"} {
+			def.Name = name
+			_, _ = ts.Run(t, test.TestCase{Method: http.MethodPut, Path: "/tyk/apis/revisions", AdminAuth: true, Data: def, Code: http.StatusOK})
+		}
+
+		revs := listRevisions(t, "/tyk/apis/revisions/revisions")
+		require.Len(t, revs, 3)
+		assert.Equal(t, []int{3, 2, 1}, []int{revs[0].Number, revs[1].Number, revs[2].Number})
+		assert.Equal(t, revisionActionAdded, revs[2].Action)
+		assert.Empty(t, revs[0].Definition, "the list doesn't hold the definitions")
+
+		resp, err := ts.Run(t, test.TestCase{Method: http.MethodGet, Path: "/tyk/apis/revisions/revisions/3/diff", AdminAuth: true, Code: http.StatusOK})
+		require.NoError(t, err)
+
+		var diff apiRevisionDiff
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&diff))
+		assert.Equal(t, 2, diff.From)
+		require.Len(t, diff.Changes, 1)
+		assert.Equal(t, "name", diff.Changes[0].Path)
+		assert.Equal(t, "v2", diff.Changes[0].Before)
+		assert.Equal(t, "v3", diff.Changes[0].After)
+
+		_, _ = ts.Run(t, []test.TestCase{
+			{Method: http.MethodGet, Path: "/tyk/apis/revisions/revisions/1", AdminAuth: true, Code: http.StatusOK, BodyMatch: `"name":"v1"`},
+			{Method: http.MethodGet, Path: "/tyk/apis/revisions/revisions/9", AdminAuth: true, Code: http.StatusNotFound},
+			{Method: http.MethodGet, Path: "/tyk/apis/revisions/revisions/latest", AdminAuth: true, Code: http.StatusBadRequest},
+			{Method: http.MethodGet, Path: "/tyk/apis/unknown/revisions", AdminAuth: true, Code: http.StatusNotFound},
+			{Method: http.MethodPost, Path: "/tyk/apis/revisions/rollback/1?block=true", AdminAuth: true, Code: http.StatusOK, BodyMatch: `"action":"rolled back"`},
+		}...)
+
+		assert.Equal(t, "v1", ts.Gw.getApiSpec("revisions").Name)
+
+		revs = listRevisions(t, "/tyk/apis/revisions/revisions")
+		require.Len(t, revs, 3, "only the last revisions are kept")
+		assert.Equal(t, 4, revs[0].Number)
+		assert.Equal(t, revisionActionRollback, revs[0].Action)
+		assert.Equal(t, 1, revs[0].RolledBackFrom)
+	})
+
+	t.Run("policy", func(t *testing.T) {
+		pol := CreateStandardPolicy()
+		pol.ID = "revisions"
+		pol.Rate = 10
+
+		_, _ = ts.Run(t, test.TestCase{Method: http.MethodPost, Path: "/tyk/policies/revisions", AdminAuth: true, Data: pol, Code: http.StatusOK})
+
+		pol.Rate = 20
+		_, _ = ts.Run(t, test.TestCase{Method: http.MethodPut, Path: "/tyk/policies/revisions", AdminAuth: true, Data: pol, Code: http.StatusOK})
+
+		_, _ = ts.Run(t, []test.TestCase{
+			{Method: http.MethodGet, Path: "/tyk/policies/revisions/revisions/2/diff?from=1", AdminAuth: true, Code: http.StatusOK, BodyMatch: `"path":"rate"`},
+			{Method: http.MethodPost, Path: "/tyk/policies/revisions/rollback/1?block=true", AdminAuth: true, Code: http.StatusOK},
+		}...)
+
+		stored, found := ts.Gw.PolicyByID("revisions")
+		require.True(t, found)
+		assert.Equal(t, float64(10), stored.Rate)
+
+		revs := listRevisions(t, "/tyk/policies/revisions/revisions")
+		require.Len(t, revs, 3)
+		assert.Equal(t, revisionActionRollback, revs[0].Action)
+	})
+
+	t.Run("rollback validates the revision", func(t *testing.T) {
+		pol := user.Policy{ID: "other"}
+		data, err := json.Marshal(pol)
+		require.NoError(t, err)
+		require.NoError(t, ts.Gw.revisions.Add(revision.KindPolicy, "invalid", &revision.Revision{Action: revisionActionAdded, Definition: data}))
+
+		_, _ = ts.Run(t, test.TestCase{Method: http.MethodPost, Path: "/tyk/policies/invalid/rollback/1", AdminAuth: true, Code: http.StatusBadRequest})
+	})
+}
@@ -3699,6 +3699,80 @@ func TestOAS(t *testing.T) {
 			assert.True(t, importedOAS.GetTykMiddleware().Global.TrafficLogs.Enabled)
 		})
 
+		t.Run("import from postman collection", func(t *testing.T) {
+			collection := `{
+				"info": {"name": "postman pets", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+				"item": [{
+					"name": "Get pet",
+					"request": {"method": "GET", "url": "https://pets.example.com/pets/:petId"},
+					"response": [{"name": "found", "code": 200, "_postman_previewlanguage": "json", "body": "{\"id\": 1}"}]
+				}]
+			}`
+
+			importedOASAPIID := testImportOAS(t, ts, test.TestCase{Code: http.StatusOK, Data: collection, AdminAuth: true,
+				QueryParams: map[string]string{"source": "postman"}})
+
+			importT := testGetOASAPI(t, ts, importedOASAPIID, "postman pets", "postman pets")
+			assert.NotNil(t, importT.Paths.Find("/pets/{petId}"))
+			assert.Equal(t, "https://pets.example.com", importT.Servers[len(importT.Servers)-1].URL)
+		})
+
+		t.Run("import from grpc descriptors", func(t *testing.T) {
+			protoSource := `syntax = "proto3";
+				package pets.v1;
+				import "google/api/annotations.proto";
+				service Pets {
+					rpc GetPet(GetPetRequest) returns (Pet) {
+						option (google.api.http) = {get: "/v1/pets/{id}"};
+					}
+				}
+				message GetPetRequest { string id = 1; }
+				message Pet { string id = 1; string name = 2; }`
+
+			importedOASAPIID := testImportOAS(t, ts, test.TestCase{Code: http.StatusOK, Data: protoSource, AdminAuth: true,
+				QueryParams: map[string]string{"source": "grpc", "upstreamURL": "h2c://localhost:50051"}})
+
+			importT := testGetOASAPI(t, ts, importedOASAPIID, "Pets", "Pets")
+			assert.NotNil(t, importT.Paths.Find("/v1/pets/{id}"))
+			assert.NotContains(t, importT.Extensions, oas.ExtensionGRPCDescriptor)
+
+			spec := ts.Gw.getApiSpec(importedOASAPIID)
+			require.NotNil(t, spec)
+			require.NotNil(t, spec.GRPCTranscoding)
+			assert.True(t, spec.GRPCTranscoding.Enabled)
+			assert.NotNil(t, spec.grpcTranscoder)
+		})
+
+		t.Run("import from asyncapi", func(t *testing.T) {
+			asyncAPI := `{
+				"asyncapi": "3.0.0",
+				"info": {"title": "user events", "version": "1.0.0"},
+				"servers": {"kafka": {"host": "localhost:9092", "protocol": "kafka"}},
+				"channels": {"signups": {"address": "user.signedup"}},
+				"operations": {"userSignedUp": {"action": "send", "channel": {"$ref": "#/channels/signups"}}}
+			}`
+
+			// the broker isn't an upstream
+			_, _ = ts.Run(t, test.TestCase{AdminAuth: true, Method: http.MethodPost, Path: "/tyk/apis/oas/import",
+				Data: asyncAPI, QueryParams: map[string]string{"source": "asyncapi"}, Code: http.StatusBadRequest,
+				BodyMatch: "servers"})
+
+			importedOASAPIID := testImportOAS(t, ts, test.TestCase{Code: http.StatusOK, Data: asyncAPI, AdminAuth: true,
+				QueryParams: map[string]string{"source": "asyncapi", "upstreamURL": TestHttpAny}})
+
+			importT := testGetOASAPI(t, ts, importedOASAPIID, "user events", "user events")
+			assert.Contains(t, importT.Extensions, oas.ExtensionTykStreaming)
+			for _, server := range importT.Servers {
+				assert.NotContains(t, server.URL, "kafka://")
+			}
+		})
+
+		t.Run("unsupported import source", func(t *testing.T) {
+			_, _ = ts.Run(t, test.TestCase{AdminAuth: true, Method: http.MethodPost, Path: "/tyk/apis/oas/import",
+				Data: oasCopy(false, nil), QueryParams: map[string]string{"source": "swagger"},
+				BodyMatch: `Unsupported import source`, Code: http.StatusBadRequest})
+		})
+
 		t.Run("block when dashboard app config set to true", func(t *testing.T) {
 			apiInOAS := oasCopy(false, nil)
 
@@ -0,0 +1,34 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// webhookFailedDeliveriesHandler lists the webhook deliveries that exhausted their retries.
+func (gw *Gateway) webhookFailedDeliveriesHandler(w http.ResponseWriter, _ *http.Request) {
+	doJSONWrite(w, http.StatusOK, gw.webhookOutbox.failed())
+}
+
+// webhookReplayDeliveryHandler re-attempts a failed webhook delivery and returns its outcome.
+func (gw *Gateway) webhookReplayDeliveryHandler(w http.ResponseWriter, r *http.Request) {
+	deliveryID := mux.Vars(r)["deliveryID"]
+
+	d, err := gw.webhookOutbox.replay(deliveryID)
+	switch {
+	case errors.Is(err, ErrWebhookDeliveryNotFound):
+		doJSONWrite(w, http.StatusNotFound, apiError(err.Error()))
+		return
+	case errors.Is(err, ErrWebhookDeliveryNotFailed), errors.Is(err, ErrWebhookDeliveryLocked):
+		doJSONWrite(w, http.StatusConflict, apiError(err.Error()))
+		return
+	case err != nil:
+		log.WithError(err).Error("Failed to replay webhook delivery")
+		doJSONWrite(w, http.StatusInternalServerError, apiError("failed to replay webhook delivery"))
+		return
+	}
+
+	doJSONWrite(w, http.StatusOK, d)
+}
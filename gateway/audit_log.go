@@ -0,0 +1,378 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/TykTechnologies/tyk/header"
+	"github.com/TykTechnologies/tyk/internal/audit"
+	"github.com/TykTechnologies/tyk/internal/uuid"
+	"github.com/TykTechnologies/tyk/request"
+	"github.com/TykTechnologies/tyk/storage"
+)
+
+// auditedRoutes maps the Control API route prefixes to the type of resource they change.
+// Routes that aren't listed, and read only routes, aren't audited.
+var auditedRoutes = []struct {
+	prefix   string
+	resource string
+}{
+	{"/apis", "api"},
+	{"/keys", "key"},
+	{"/org/keys", "org_key"},
+	{"/policies", "policy"},
+	{"/certs", "certificate"},
+	{"/oauth", "oauth_client"},
+	{"/cache", "cache"},
+	{"/reload", "gateway"},
+	{"/credentials", "credential"},
+	{"/webhooks", "webhook_delivery"},
+}
+
+// auditedActions overrides the action derived from the request method for some routes.
+var auditedActions = map[string]string{
+	"/reload":                          "reload",
+	"/reload/group":                    "reload",
+	"/keys/policy/{keyName}":           "update",
+	"/keys/bulk":                       "bulk",
+	"/apis/{apiID}/rollback/{rev}":     "rollback",
+	"/policies/{polID}/rollback/{rev}": "rollback",
+	"/oauth/clients/{apiID}/{keyName:[^/]*}/rotate": "rotate",
+	"/oauth/revoke":     "revoke",
+	"/oauth/revoke_all": "revoke",
+	"/webhooks/deliveries/{deliveryID}/replay": "replay",
+}
+
+// auditedResourceVars are the route variables identifying the changed resource, in order of precedence.
+var auditedResourceVars = []string{"keyName", "polID", "certID", "credentialID", "deliveryID", "apiID"}
+
+const (
+	auditDefaultLimit = 50
+	auditMaxLimit     = 1000
+)
+
+// newAuditSink returns the sink configured for the Control API audit log, nil when it's disabled.
+func (gw *Gateway) newAuditSink() audit.Sink {
+	conf := gw.GetConfig().AuditLog
+	if !conf.Enabled {
+		return nil
+	}
+
+	switch conf.Sink {
+	case "", audit.SinkStdout:
+		return audit.NewWriterSink(os.Stdout)
+	case audit.SinkFile:
+		sink, err := audit.NewFileSink(conf.Path)
+		if err != nil {
+			mainLog.WithError(err).Error("Failed to open the audit log file, the audit log is disabled")
+			return nil
+		}
+		return sink
+	case audit.SinkRedis:
+		store := &storage.RedisCluster{ConnectionHandler: gw.StorageConnectionHandler}
+		return audit.NewStreamSink(store.Client, conf.Stream, conf.MaxLen)
+	}
+
+	mainLog.Errorf("Unknown audit log sink %q, the audit log is disabled", conf.Sink)
+	return nil
+}
+
+// auditRoute returns the action and the type of resource of an audited route.
+func auditRoute(template, method string) (action, resource string, ok bool) {
+	if template == "/keys/preview" {
+		return "", "", false
+	}
+
+	for _, ar := range auditedRoutes {
+		if template == ar.prefix || strings.HasPrefix(template, ar.prefix+"/") {
+			resource = ar.resource
+			break
+		}
+	}
+	if resource == "" {
+		return "", "", false
+	}
+
+	if action, ok := auditedActions[template]; ok {
+		return action, resource, true
+	}
+
+	switch method {
+	case http.MethodPost:
+		return "create", resource, true
+	case http.MethodPut, http.MethodPatch:
+		return "update", resource, true
+	case http.MethodDelete:
+		return "delete", resource, true
+	}
+	return "", "", false
+}
+
+// auditLogMiddleware records the Control API calls changing the Gateway state to the audit log.
+func (gw *Gateway) auditLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sink := gw.auditSink
+		if sink == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var template string
+		if route := mux.CurrentRoute(r); route != nil {
+			template, _ = route.GetPathTemplate()
+		}
+
+		action, resource, ok := auditRoute(template, r.Method)
+		// dry runs don't change anything
+		if !ok || isDryRun(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &audit.Record{
+			ID:        uuid.NewHex(),
+			Timestamp: time.Now().UTC(),
+			SourceIP:  request.RealIP(r),
+			Method:    r.Method,
+			Route:     template,
+			Action:    action,
+			Resource:  resource,
+		}
+		rec.ResourceID = gw.auditResourceID(r, template, resource)
+
+		before := gw.auditResourceState(r, template, resource)
+		after := auditRequestBody(r)
+		if action == "delete" {
+			after = nil
+		}
+		if r.Method == http.MethodPatch {
+			before = pruneAuditState(before, after)
+		}
+
+		rw := &customResponseWriter{ResponseWriter: w, copyData: true}
+		next.ServeHTTP(rw, r)
+
+		// the caller is only authenticated by now
+		rec.Actor = gw.auditActor(r)
+		rec.Result = auditResult(rw)
+		if rec.Result.Status == audit.ResultSuccess {
+			if action == "rollback" {
+				// the restored revision is stored by now, it's only loaded after the reload
+				after = gw.storedRevisionDefinition(resource, rec.ResourceID)
+			}
+			rec.Changes = audit.Diff(before, after)
+		}
+		if rec.ResourceID == "" {
+			rec.ResourceID = auditResponseResourceID(rw.data, resource)
+		}
+
+		if err := sink.Write(rec); err != nil {
+			mainLog.WithError(err).Error("Failed to write the audit record")
+		}
+	})
+}
+
+// auditActor returns who made the request, the credential is set when a scoped credential was used.
+func (gw *Gateway) auditActor(r *http.Request) audit.Actor {
+	actor := audit.Actor{Type: audit.ActorAnonymous}
+	if cred := ctxGetControlAPICredential(r); cred != nil {
+		actor = audit.Actor{Type: audit.ActorCredential, Name: cred.Name}
+	} else if r.Header.Get(header.XTykAuthorization) == gw.GetConfig().Secret {
+		actor.Type = audit.ActorSecret
+	}
+
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		actor.CertSubject = r.TLS.PeerCertificates[0].Subject.String()
+	}
+	return actor
+}
+
+// auditResourceID returns the ID of the resource from the route, keys are recorded by their hash.
+func (gw *Gateway) auditResourceID(r *http.Request, template, resource string) string {
+	vars := mux.Vars(r)
+	for _, name := range auditedResourceVars {
+		id := vars[name]
+		if id == "" {
+			continue
+		}
+
+		if name == "keyName" && (resource == "key" || template == "/oauth/refresh/{keyName}") && r.URL.Query().Get("hashed") == "" {
+			return storage.HashStr(id)
+		}
+		return id
+	}
+	return ""
+}
+
+// auditResponseResourceID returns the ID of a created resource from the response.
+func auditResponseResourceID(body []byte, resource string) string {
+	var resp struct {
+		ID      string `json:"id"`
+		Key     string `json:"key"`
+		KeyHash string `json:"key_hash"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return ""
+	}
+
+	switch {
+	case resource == "key" && resp.Key != "":
+		// key_hash is the raw key when hashing is disabled
+		return storage.HashStr(resp.Key)
+	case resource == "key":
+		return resp.KeyHash
+	case resp.ID != "":
+		return resp.ID
+	}
+	return resp.Key
+}
+
+// auditResourceState returns the current state of the resource the request changes, if it's known.
+func (gw *Gateway) auditResourceState(r *http.Request, template, resource string) interface{} {
+	vars := mux.Vars(r)
+	query := r.URL.Query()
+
+	switch resource {
+	case "api":
+		spec := gw.getApiSpec(vars["apiID"])
+		if spec == nil {
+			return nil
+		}
+		if strings.HasPrefix(template, "/apis/oas") && spec.IsOAS {
+			return &spec.OAS
+		}
+		return spec.APIDefinition
+	case "key":
+		keyName := vars["keyName"]
+		if keyName == "" {
+			return nil
+		}
+		if query.Get("username") == "true" && r.Method != http.MethodPost {
+			keyName = gw.generateToken(query.Get("org_id"), keyName)
+		}
+		if session, found := gw.GlobalSessionManager.SessionDetail(query.Get("org_id"), keyName, query.Get("hashed") != ""); found {
+			return session
+		}
+	case "org_key":
+		if vars["keyName"] == "" {
+			return nil
+		}
+		if session, code := gw.handleGetOrgDetail(vars["keyName"]); code == http.StatusOK {
+			return session
+		}
+	case "policy":
+		if pol, found := gw.PolicyByID(vars["polID"]); found {
+			return pol
+		}
+	}
+	return nil
+}
+
+// auditRequestBody decodes the JSON request body, the body is restored for the handler.
+func auditRequestBody(r *http.Request) interface{} {
+	if r.Body == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var v map[string]interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil
+	}
+	return v
+}
+
+// pruneAuditState keeps the top level fields of state that a partial update sets.
+func pruneAuditState(state, update interface{}) interface{} {
+	fields, ok := update.(map[string]interface{})
+	if state == nil || !ok {
+		return state
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return state
+	}
+
+	var current map[string]interface{}
+	if err := json.Unmarshal(data, &current); err != nil {
+		return state
+	}
+
+	for k := range current {
+		if _, ok := fields[k]; !ok {
+			delete(current, k)
+		}
+	}
+	return current
+}
+
+func auditResult(rw *customResponseWriter) audit.Result {
+	code := rw.statusCodeSent
+	if code == 0 {
+		code = http.StatusOK
+	}
+
+	if code < http.StatusBadRequest {
+		return audit.Result{Status: audit.ResultSuccess, Code: code}
+	}
+
+	var msg apiStatusMessage
+	_ = json.Unmarshal(rw.data, &msg)
+	return audit.Result{Status: audit.ResultFailure, Code: code, Message: msg.Message}
+}
+
+// auditLogHandler lists the audit records, most recent first.
+func (gw *Gateway) auditLogHandler(w http.ResponseWriter, r *http.Request) {
+	if gw.auditSink == nil {
+		doJSONWrite(w, http.StatusBadRequest, apiError("Audit log is disabled"))
+		return
+	}
+
+	lister, ok := gw.auditSink.(audit.Lister)
+	if !ok {
+		doJSONWrite(w, http.StatusNotImplemented, apiError(audit.ErrNotQueryable.Error()))
+		return
+	}
+
+	query := r.URL.Query()
+	q := audit.Query{
+		Cursor:     query.Get("cursor"),
+		Limit:      auditDefaultLimit,
+		Actor:      query.Get("actor"),
+		Action:     query.Get("action"),
+		Resource:   query.Get("resource"),
+		ResourceID: query.Get("resource_id"),
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > auditMaxLimit {
+			doJSONWrite(w, http.StatusBadRequest, apiError("limit must be a number between 1 and 1000"))
+			return
+		}
+		q.Limit = n
+	}
+
+	page, err := lister.List(q)
+	if err != nil {
+		mainLog.WithError(err).Error("Failed to list the audit records")
+		doJSONWrite(w, http.StatusInternalServerError, apiError("failed to list audit records"))
+		return
+	}
+
+	doJSONWrite(w, http.StatusOK, page)
+}
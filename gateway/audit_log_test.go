@@ -0,0 +1,130 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/config"
+	"github.com/TykTechnologies/tyk/header"
+	"github.com/TykTechnologies/tyk/internal/audit"
+	"github.com/TykTechnologies/tyk/storage"
+	"github.com/TykTechnologies/tyk/test"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+func TestAuditRoute(t *testing.T) {
+	for _, tc := range []struct {
+		template, method, action, resource string
+	}{
+		{"/apis", http.MethodPost, "create", "api"},
+		{"/apis/oas/{apiID}", http.MethodPatch, "update", "api"},
+		{"/keys/{keyName:[^/]*}", http.MethodDelete, "delete", "key"},
+		{"/keys/policy/{keyName}", http.MethodPost, "update", "key"},
+		{"/org/keys/{keyName:[^/]*}", http.MethodPut, "update", "org_key"},
+		{"/certs", http.MethodPost, "create", "certificate"},
+		{"/reload/group", http.MethodGet, "reload", "gateway"},
+		{"/webhooks/deliveries/{deliveryID}/replay", http.MethodPost, "replay", "webhook_delivery"},
+		{"/apis/{apiID}/rollback/{rev}", http.MethodPost, "rollback", "api"},
+		{"/apis", http.MethodGet, "", ""},
+		{"/keys/preview", http.MethodPost, "", ""},
+		{"/debug", http.MethodPost, "", ""},
+	} {
+		action, resource, ok := auditRoute(tc.template, tc.method)
+		assert.Equal(t, tc.action != "", ok, tc.template)
+		assert.Equal(t, tc.action, action, tc.template)
+		assert.Equal(t, tc.resource, resource, tc.template)
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	const ciSecret = "ci-secret"
+
+	ts := StartTest(func(globalConf *config.Config) {
+		globalConf.AuditLog = config.AuditLogConfig{
+			Enabled: true,
+			Sink:    audit.SinkFile,
+			Path:    filepath.Join(t.TempDir(), "audit.log"),
+		}
+		globalConf.Security.ControlAPICredentials = []config.ControlAPICredential{
+			{Name: "ci", SecretHash: hashControlAPISecret(globalConf.Secret, ciSecret), Scopes: []string{ScopeKeysRead}},
+		}
+	})
+	defer ts.Close()
+
+	ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+		spec.APIID = "audited"
+		spec.Proxy.ListenPath = "/audited/"
+	})
+
+	session := CreateStandardSession()
+	session.JWTData.Secret = "jwt-secret"
+	session.AccessRights = map[string]user.AccessDefinition{"audited": {APIID: "audited", Versions: []string{"v1"}}}
+
+	resp, err := ts.Run(t, test.TestCase{Method: http.MethodPost, Path: "/tyk/keys/create", AdminAuth: true, Data: session, Code: http.StatusOK})
+	require.NoError(t, err)
+
+	var created apiModifyKeySuccess
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+
+	_, _ = ts.Run(t, []test.TestCase{
+		{Method: http.MethodGet, Path: "/tyk/keys/" + created.Key, AdminAuth: true, Code: http.StatusOK},
+		{Method: http.MethodDelete, Path: "/tyk/keys/" + created.Key, Headers: map[string]string{header.XTykAuthorization: ciSecret}, Code: http.StatusForbidden},
+		{Method: http.MethodDelete, Path: "/tyk/keys/" + created.Key, Headers: map[string]string{header.XTykAuthorization: "invalid"}, Code: http.StatusForbidden},
+		{Method: http.MethodDelete, Path: "/tyk/keys/" + created.Key, AdminAuth: true, Code: http.StatusOK},
+		{Method: http.MethodGet, Path: "/tyk/reload", AdminAuth: true, Code: http.StatusOK},
+		{Method: http.MethodGet, Path: "/tyk/audit", Code: http.StatusForbidden},
+		{Method: http.MethodGet, Path: "/tyk/audit?limit=0", AdminAuth: true, Code: http.StatusBadRequest},
+		{Method: http.MethodGet, Path: "/tyk/audit", AdminAuth: true, Code: http.StatusOK, BodyNotMatch: "jwt-secret|" + created.Key},
+	}...)
+
+	list := func(query string) audit.Page {
+		t.Helper()
+		resp, err := ts.Run(t, test.TestCase{Method: http.MethodGet, Path: "/tyk/audit?" + query, AdminAuth: true, Code: http.StatusOK})
+		require.NoError(t, err)
+
+		var page audit.Page
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+		return page
+	}
+
+	page := list("limit=3")
+	require.Len(t, page.Records, 3)
+	require.NotEmpty(t, page.NextCursor)
+
+	reload, deleted, anonymous := page.Records[0], page.Records[1], page.Records[2]
+	assert.Equal(t, "reload", reload.Action)
+	assert.Equal(t, "gateway", reload.Resource)
+
+	keyHash := storage.HashStr(created.Key)
+	assert.Equal(t, "delete", deleted.Action)
+	assert.Equal(t, "key", deleted.Resource)
+	assert.Equal(t, keyHash, deleted.ResourceID)
+	assert.Equal(t, audit.Actor{Type: audit.ActorSecret}, deleted.Actor)
+	assert.Equal(t, audit.ResultSuccess, deleted.Result.Status)
+	assert.NotEmpty(t, deleted.SourceIP)
+	assert.Contains(t, deleted.Changes, audit.Change{Path: "jwt_data.secret", Before: audit.RedactedValue})
+
+	assert.Equal(t, audit.Actor{Type: audit.ActorAnonymous}, anonymous.Actor)
+	assert.Equal(t, audit.Result{Status: audit.ResultFailure, Code: http.StatusForbidden, Message: "Attempted administrative access with invalid or missing key!"}, anonymous.Result)
+	assert.Empty(t, anonymous.Changes)
+
+	page = list("cursor=" + page.NextCursor)
+	require.Len(t, page.Records, 2)
+	assert.Empty(t, page.NextCursor)
+
+	denied, create := page.Records[0], page.Records[1]
+	assert.Equal(t, audit.Actor{Type: audit.ActorCredential, Name: "ci"}, denied.Actor)
+	assert.Equal(t, audit.Result{Status: audit.ResultFailure, Code: http.StatusForbidden, Message: "Credential is missing the keys:write scope"}, denied.Result)
+	assert.Empty(t, denied.Changes)
+
+	assert.Equal(t, "create", create.Action)
+	assert.Equal(t, keyHash, create.ResourceID)
+
+	page = list("action=delete&resource_id=" + keyHash)
+	assert.Len(t, page.Records, 3)
+}
@@ -3,6 +3,7 @@ package gateway
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"strings"
 	"time"
 
@@ -157,6 +158,43 @@ func (b *DefaultSessionManager) RemoveSession(orgID string, keyName string, hash
 	}
 }
 
+// conditionalKeyWriter is implemented by key stores able to change a key only if it didn't change meanwhile.
+type conditionalKeyWriter interface {
+	SetRawKeyIf(keyName, value string, ttl int64, match func(current string) bool) error
+}
+
+var errConditionalWriteUnsupported = errors.New("conditional writes aren't supported by the key store")
+
+// RawSession returns the session as it's stored, keyName is the resolved key ID.
+func (b *DefaultSessionManager) RawSession(keyName string, hashed bool) (string, error) {
+	return b.store.GetRawKey(b.rawSessionKey(keyName, hashed))
+}
+
+// UpdateSessionIf updates the session if match accepts the stored one, empty when there is none.
+// It returns storage.ErrKeyChanged otherwise.
+func (b *DefaultSessionManager) UpdateSessionIf(keyName string, session *user.SessionState, resetTTLTo int64, hashed bool, match func(current string) bool) error {
+	store, ok := b.store.(conditionalKeyWriter)
+	if !ok {
+		return errConditionalWriteUnsupported
+	}
+	defer b.clearCacheForKey(keyName, hashed)
+
+	v, err := json.Marshal(session)
+	if err != nil {
+		log.Error("Error marshalling session for sync update")
+		return err
+	}
+
+	return store.SetRawKeyIf(b.rawSessionKey(keyName, hashed), string(v), resetTTLTo, match)
+}
+
+func (b *DefaultSessionManager) rawSessionKey(keyName string, hashed bool) string {
+	if !hashed {
+		keyName = storage.HashKey(keyName, b.Gw.GetConfig().HashKeys)
+	}
+	return b.store.GetKeyPrefix() + keyName
+}
+
 // SessionDetail returns the session detail using the storage engine (either in memory or Redis)
 func (b *DefaultSessionManager) SessionDetail(orgID string, keyName string, hashed bool) (user.SessionState, bool) {
 	var jsonKeyVal string
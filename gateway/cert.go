@@ -570,6 +570,7 @@ func (gw *Gateway) certHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		gw.fireCertificateEvent(EventCertificateAdded, certID, orgID)
 		doJSONWrite(w, http.StatusOK, &APICertificateStatusMessage{certID, "ok", "Certificate added"})
 	case "GET":
 		if certID == "" {
@@ -621,6 +622,7 @@ func (gw *Gateway) certHandler(w http.ResponseWriter, r *http.Request) {
 			orgID = certID[:len(certID)-sha256.Size*2]
 		}
 		gw.CertificateManager.Delete(certID, orgID)
+		gw.fireCertificateEvent(EventCertificateRemoved, certID, orgID)
 		doJSONWrite(w, http.StatusOK, &apiStatusMessage{"ok", "removed"})
 	}
 }
@@ -0,0 +1,182 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/apidef/oas"
+	"github.com/TykTechnologies/tyk/apidef/oas/contract"
+	"github.com/TykTechnologies/tyk/cli/tester"
+	"github.com/TykTechnologies/tyk/config"
+	"github.com/TykTechnologies/tyk/storage"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+const (
+	contractTestAPIID      = "contract-test"
+	contractStorageTimeout = 5 * time.Second
+)
+
+var errNoContractCases = errors.New("the API has no operations to test")
+
+// RunContractTests runs the contract tests generated from the OAS of an API against an in-process
+// gateway and reports the results. It returns an error when a test fails.
+func RunContractTests(opts *tester.Options) error {
+	api, err := loadContractAPI(opts)
+	if err != nil {
+		return err
+	}
+
+	cases := contract.Generate(api, contract.Options{Fuzz: *opts.Fuzz, Seed: *opts.Seed})
+	if len(cases) == 0 {
+		return errNoContractCases
+	}
+
+	var conf config.Config
+	loaded := config.Load(confPaths, &conf) == nil
+	if !loaded {
+		// the in-process gateway runs with the defaults
+		if err := config.WriteDefault("", &conf); err != nil {
+			return err
+		}
+	}
+
+	// the in-process gateway can't start without its storage
+	if err := checkContractStorage(conf); err != nil {
+		return err
+	}
+
+	ts := StartTest(func(globalConf *config.Config) {
+		if loaded {
+			globalConf.Storage = conf.Storage
+			globalConf.TemplatePath = conf.TemplatePath
+		}
+
+		globalConf.EnableAnalytics = false
+		globalConf.AnalyticsConfig.EnableGeoIP = false
+		globalConf.EnableJSVM = false
+		globalConf.CoProcessOptions.EnableCoProcess = false
+		globalConf.EnableBundleDownloader = false
+	})
+	defer ts.Close()
+
+	var def apidef.APIDefinition
+	api.ExtractTo(&def)
+	def.IsOAS = true
+
+	spec := ts.Gw.LoadAPI(&APISpec{APIDefinition: &def, OAS: *api})[0]
+	if spec == nil {
+		return fmt.Errorf("the API %q couldn't be loaded", def.Name)
+	}
+
+	header := http.Header{}
+	if !spec.UseKeylessAccess {
+		_, key := ts.CreateSession(func(s *user.SessionState) {
+			s.OrgID = spec.OrgID
+			s.AccessRights = map[string]user.AccessDefinition{
+				spec.APIID: {APIID: spec.APIID, APIName: spec.Name},
+			}
+		})
+		// the storage is the one of the gateway config, the key mustn't outlive the run
+		defer ts.Gw.GlobalSessionManager.RemoveSession(spec.OrgID, key, false)
+
+		setContractCredentials(header, api, key)
+	}
+	for name, value := range *opts.Headers {
+		header.Set(name, value)
+	}
+
+	runner := &contract.Runner{BaseURL: ts.URL + spec.Proxy.ListenPath, Header: header}
+	report := runner.Run(context.Background(), spec.Name, cases)
+
+	if err := opts.WriteReport(os.Stdout, report); err != nil {
+		return err
+	}
+
+	if report.Failures() > 0 {
+		return fmt.Errorf("%d of %d contract tests failed", report.Failures(), len(report.Results))
+	}
+
+	return nil
+}
+
+// checkContractStorage checks that the Redis storage of the gateway config is reachable.
+func checkContractStorage(conf config.Config) error {
+	conn, err := storage.NewConnector(storage.DefaultConn, conf)
+	if err != nil {
+		return fmt.Errorf("couldn't create the storage connection: %w", err)
+	}
+	defer conn.Disconnect(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), contractStorageTimeout)
+	defer cancel()
+
+	if err := conn.Ping(ctx); err != nil {
+		return fmt.Errorf("couldn't connect to the storage: %w", err)
+	}
+	return nil
+}
+
+// loadContractAPI loads the API of the test command. OpenAPI documents without the Tyk extension
+// are imported with the defaults, and the upstream or mocks of the options are configured.
+func loadContractAPI(opts *tester.Options) (*oas.OAS, error) {
+	data, err := os.ReadFile(*opts.API)
+	if err != nil {
+		return nil, fmt.Errorf("file load error: %w", err)
+	}
+
+	loader := openapi3.NewLoader()
+	t, err := oas.LoadFromData(loader, data)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode OAS API definition: %w", err)
+	}
+
+	api := &oas.OAS{T: *t}
+	isImport := api.GetTykExtension() == nil
+
+	params := oas.TykExtensionConfigParams{UpstreamURL: *opts.Upstream}
+	if *opts.Mock {
+		params.MockResponse = opts.Mock
+		if isImport && len(api.Servers) == 0 {
+			// the upstream isn't reached, the mocks respond
+			params.UpstreamURL = "http://127.0.0.1"
+		}
+	}
+
+	if err := api.BuildDefaultTykExtension(params, isImport); err != nil {
+		return nil, err
+	}
+
+	xTykAPIGateway := api.GetTykExtension()
+	if xTykAPIGateway.Info.ID == "" {
+		xTykAPIGateway.Info.ID = contractTestAPIID
+	}
+	xTykAPIGateway.Info.State.Active = true
+	// the in-process gateway serves the API on its own host
+	xTykAPIGateway.Server.CustomDomain = nil
+
+	return api, nil
+}
+
+// setContractCredentials sets the key in the headers of the API key security schemes, or in the
+// Authorization header.
+func setContractCredentials(header http.Header, api *oas.OAS, key string) {
+	if api.Components != nil {
+		for _, scheme := range api.Components.SecuritySchemes {
+			if scheme != nil && scheme.Value != nil && scheme.Value.Type == "apiKey" && scheme.Value.In == "header" {
+				header.Set(scheme.Value.Name, key)
+			}
+		}
+	}
+
+	if len(header) == 0 {
+		header.Set("Authorization", key)
+	}
+}
@@ -0,0 +1,93 @@
+package gateway
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/cli/tester"
+	"github.com/TykTechnologies/tyk/config"
+)
+
+const contractTestOAS = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Pets", "version": "1.0.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "listPets",
+        "parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer", "minimum": 1}}],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {"application/json": {
+              "schema": {"type": "array", "items": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}},
+              "example": [{"name": "Tom"}]
+            }}
+          }
+        }
+      }
+    }
+  }
+}`
+
+func contractTestOptions(t *testing.T, upstream string, mock bool) *tester.Options {
+	t.Helper()
+
+	dir := t.TempDir()
+	api := filepath.Join(dir, "pets.json")
+	require.NoError(t, os.WriteFile(api, []byte(contractTestOAS), 0644))
+
+	junit := filepath.Join(dir, "junit.xml")
+	fuzz, seed := 2, int64(1)
+	headers := map[string]string{}
+
+	return &tester.Options{
+		API:      &api,
+		Upstream: &upstream,
+		Mock:     &mock,
+		JUnit:    &junit,
+		Fuzz:     &fuzz,
+		Seed:     &seed,
+		Headers:  &headers,
+	}
+}
+
+func TestRunContractTests(t *testing.T) {
+	t.Run("mocks from the OAS examples", func(t *testing.T) {
+		opts := contractTestOptions(t, "", true)
+		require.NoError(t, RunContractTests(opts))
+
+		junit, err := os.ReadFile(*opts.JUnit)
+		require.NoError(t, err)
+		assert.Contains(t, string(junit), `<testsuites tests="3" failures="0"`)
+		assert.Contains(t, string(junit), `name="listPets/fuzz-2" classname="Pets.listPets"`)
+	})
+
+	t.Run("upstream breaking the contract", func(t *testing.T) {
+		opts := contractTestOptions(t, TestHttpAny, false)
+		assert.EqualError(t, RunContractTests(opts), "3 of 3 contract tests failed")
+
+		junit, err := os.ReadFile(*opts.JUnit)
+		require.NoError(t, err)
+		assert.Contains(t, string(junit), `<testsuites tests="3" failures="3"`)
+	})
+}
+
+func TestCheckContractStorage(t *testing.T) {
+	var conf config.Config
+	require.NoError(t, config.WriteDefault("", &conf))
+	require.NoError(t, checkContractStorage(conf))
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().(*net.TCPAddr)
+	require.NoError(t, l.Close())
+
+	conf.Storage.Host, conf.Storage.Port = addr.IP.String(), addr.Port
+	assert.ErrorContains(t, checkContractStorage(conf), "couldn't connect to the storage")
+}
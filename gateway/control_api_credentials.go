@@ -0,0 +1,667 @@
+package gateway
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/TykTechnologies/tyk/config"
+	"github.com/TykTechnologies/tyk/ctx"
+	"github.com/TykTechnologies/tyk/internal/uuid"
+	"github.com/TykTechnologies/tyk/storage"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+// Control API credential scopes.
+const (
+	ScopeAPIsRead      = "apis:read"
+	ScopeAPIsWrite     = "apis:write"
+	ScopeKeysRead      = "keys:read"
+	ScopeKeysWrite     = "keys:write"
+	ScopePoliciesRead  = "policies:read"
+	ScopePoliciesWrite = "policies:write"
+	ScopeCertsRead     = "certs:read"
+	ScopeCertsWrite    = "certs:write"
+	ScopeOAuthRead     = "oauth:read"
+	ScopeOAuthWrite    = "oauth:write"
+	ScopeReload        = "reload"
+	ScopeDebug         = "debug"
+)
+
+var controlAPIScopes = []string{
+	ScopeAPIsRead, ScopeAPIsWrite,
+	ScopeKeysRead, ScopeKeysWrite,
+	ScopePoliciesRead, ScopePoliciesWrite,
+	ScopeCertsRead, ScopeCertsWrite,
+	ScopeOAuthRead, ScopeOAuthWrite,
+	ScopeReload, ScopeDebug,
+}
+
+// controlAPIRouteScopes maps the Control API route prefixes to the scopes needed to read and write them.
+// Routes that aren't listed can only be called with the Control API secret.
+var controlAPIRouteScopes = []struct {
+	prefix      string
+	read, write string
+}{
+	{"/apis", ScopeAPIsRead, ScopeAPIsWrite},
+	{"/cache", ScopeAPIsWrite, ScopeAPIsWrite},
+	{"/health", ScopeAPIsRead, ScopeAPIsRead},
+	{"/schema", ScopeAPIsRead, ScopeAPIsRead},
+	{"/keys", ScopeKeysRead, ScopeKeysWrite},
+	{"/org/keys", ScopeKeysRead, ScopeKeysWrite},
+	{"/policies", ScopePoliciesRead, ScopePoliciesWrite},
+	{"/certs", ScopeCertsRead, ScopeCertsWrite},
+	{"/oauth", ScopeOAuthRead, ScopeOAuthWrite},
+	{"/reload", ScopeReload, ScopeReload},
+	{"/debug", ScopeDebug, ScopeDebug},
+}
+
+const (
+	controlAPICredentialPrefix = "control-api-credential."
+	controlAPICredentialIndex  = "index"
+
+	controlAPICredentialSourceConfig = "config"
+	controlAPICredentialSourceRedis  = "redis"
+)
+
+var (
+	ErrControlAPICredentialNotFound = errors.New("control API credential not found")
+
+	errControlAPICredentialLimited = errors.New("credential is limited to other resources")
+)
+
+// controlAPICredential is a scoped Control API credential from the configuration or Redis.
+type controlAPICredential struct {
+	config.ControlAPICredential
+	ID        string    `json:"id"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// hashControlAPISecret returns the hex encoded HMAC-SHA256 of the credential secret, keyed with the
+// gateway secret, that credentials are looked up by.
+func hashControlAPISecret(gatewaySecret, secret string) string {
+	mac := hmac.New(sha256.New, []byte(gatewaySecret))
+	mac.Write([]byte(secret))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validControlAPIScope reports whether scope is known, including the `*` wildcard forms.
+func validControlAPIScope(scope string) bool {
+	for _, s := range controlAPIScopes {
+		if s == scope {
+			return true
+		}
+		if resource, _, ok := strings.Cut(s, ":"); ok && scope == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasScope reports whether the credential was granted the scope.
+func (c *controlAPICredential) hasScope(scope string) bool {
+	resource, _, _ := strings.Cut(scope, ":")
+	for _, s := range c.Scopes {
+		if s == scope || s == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+// limited reports whether the credential is limited to an organisation or a set of APIs.
+func (c *controlAPICredential) limited() bool {
+	return c.OrgID != "" || len(c.APIIDs) > 0
+}
+
+func (c *controlAPICredential) allowsOrg(orgID string) bool {
+	return c.OrgID == "" || c.OrgID == orgID
+}
+
+func (c *controlAPICredential) allowsAPI(apiID, orgID string) bool {
+	if !c.allowsOrg(orgID) {
+		return false
+	}
+
+	if len(c.APIIDs) == 0 {
+		return true
+	}
+
+	for _, id := range c.APIIDs {
+		if id == apiID {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsAccessRights reports whether every API in the access rights is within the credential limits.
+func (c *controlAPICredential) allowsAccessRights(orgID string, rights map[string]user.AccessDefinition) bool {
+	if !c.allowsOrg(orgID) {
+		return false
+	}
+
+	if len(c.APIIDs) > 0 && len(rights) == 0 {
+		return false
+	}
+
+	for apiID := range rights {
+		if !c.allowsAPI(apiID, orgID) {
+			return false
+		}
+	}
+	return true
+}
+
+// redacted returns a copy of the credential without its secret hash.
+func (c controlAPICredential) redacted() controlAPICredential {
+	c.SecretHash = ""
+	return c
+}
+
+func ctxSetControlAPICredential(r *http.Request, c *controlAPICredential) {
+	setCtxValue(r, ctx.ControlAPICredential, c)
+}
+
+func ctxGetControlAPICredential(r *http.Request) *controlAPICredential {
+	if v := r.Context().Value(ctx.ControlAPICredential); v != nil {
+		if c, ok := v.(*controlAPICredential); ok {
+			return c
+		}
+	}
+	return nil
+}
+
+// controlAPICredentials looks up the scoped Control API credentials and manages the ones stored in Redis.
+type controlAPICredentials struct {
+	store *storage.RedisCluster
+	Gw    *Gateway
+}
+
+func newControlAPICredentials(gw *Gateway) *controlAPICredentials {
+	return &controlAPICredentials{
+		store: &storage.RedisCluster{KeyPrefix: controlAPICredentialPrefix, ConnectionHandler: gw.StorageConnectionHandler},
+		Gw:    gw,
+	}
+}
+
+// configured returns the credentials from the configuration keyed by secret hash.
+func (c *controlAPICredentials) configured() map[string]*controlAPICredential {
+	creds := map[string]*controlAPICredential{}
+	for _, conf := range c.Gw.GetConfig().Security.ControlAPICredentials {
+		creds[strings.ToLower(conf.SecretHash)] = &controlAPICredential{
+			ControlAPICredential: conf,
+			ID:                   conf.Name,
+			Source:               controlAPICredentialSourceConfig,
+		}
+	}
+	return creds
+}
+
+// lookup returns the credential for the secret, from configured when it's there or Redis otherwise.
+func (c *controlAPICredentials) lookup(configured map[string]*controlAPICredential, secret string) *controlAPICredential {
+	if secret == "" {
+		return nil
+	}
+
+	hash := hashControlAPISecret(c.Gw.GetConfig().Secret, secret)
+	if cred, ok := configured[hash]; ok {
+		return cred
+	}
+
+	cred, err := c.load(hash)
+	if err != nil {
+		return nil
+	}
+	return cred
+}
+
+func (c *controlAPICredentials) load(hash string) (*controlAPICredential, error) {
+	data, err := c.store.GetKey(hash)
+	if err != nil {
+		return nil, ErrControlAPICredentialNotFound
+	}
+
+	var cred controlAPICredential
+	if err := json.Unmarshal([]byte(data), &cred); err != nil {
+		return nil, err
+	}
+	return &cred, nil
+}
+
+// list returns the configured and stored credentials.
+func (c *controlAPICredentials) list() []controlAPICredential {
+	creds := []controlAPICredential{}
+	for _, cred := range c.configured() {
+		creds = append(creds, cred.redacted())
+	}
+
+	hashes, err := c.store.GetSet(controlAPICredentialIndex)
+	if err != nil {
+		return creds
+	}
+
+	for _, hash := range hashes {
+		cred, err := c.load(hash)
+		if err != nil {
+			continue
+		}
+		creds = append(creds, cred.redacted())
+	}
+	return creds
+}
+
+// create stores a new credential in Redis and returns it along with its generated secret.
+func (c *controlAPICredentials) create(conf config.ControlAPICredential) (*controlAPICredential, string, error) {
+	if conf.Name == "" {
+		return nil, "", errors.New("credential name is required")
+	}
+
+	if len(conf.Scopes) == 0 {
+		return nil, "", errors.New("at least one scope is required")
+	}
+
+	for _, scope := range conf.Scopes {
+		if !validControlAPIScope(scope) {
+			return nil, "", fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+
+	secret := "tyk_" + uuid.NewHex() + uuid.NewHex()
+	conf.SecretHash = hashControlAPISecret(c.Gw.GetConfig().Secret, secret)
+
+	cred := &controlAPICredential{
+		ControlAPICredential: conf,
+		ID:                   uuid.NewHex(),
+		Source:               controlAPICredentialSourceRedis,
+		CreatedAt:            time.Now().UTC(),
+	}
+
+	data, err := json.Marshal(cred)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := c.store.SetKey(conf.SecretHash, string(data), 0); err != nil {
+		return nil, "", err
+	}
+	c.store.AddToSet(controlAPICredentialIndex, conf.SecretHash)
+
+	return cred, secret, nil
+}
+
+// delete removes a credential stored in Redis, configured credentials can't be deleted.
+func (c *controlAPICredentials) delete(id string) error {
+	hashes, err := c.store.GetSet(controlAPICredentialIndex)
+	if err != nil {
+		return ErrControlAPICredentialNotFound
+	}
+
+	for _, hash := range hashes {
+		cred, err := c.load(hash)
+		if err != nil || cred.ID != id {
+			continue
+		}
+
+		c.store.DeleteKey(hash)
+		c.store.RemoveFromSet(controlAPICredentialIndex, hash)
+		return nil
+	}
+
+	return ErrControlAPICredentialNotFound
+}
+
+// controlAPIRouteScope returns the scope needed to call the route with the method, if any.
+func controlAPIRouteScope(template, method string) (string, bool) {
+	for _, rs := range controlAPIRouteScopes {
+		if template != rs.prefix && !strings.HasPrefix(template, rs.prefix+"/") {
+			continue
+		}
+
+		if method == http.MethodGet || method == http.MethodHead {
+			return rs.read, true
+		}
+		return rs.write, true
+	}
+	return "", false
+}
+
+// controlAPIScopeMiddleware enforces the scopes and limits of the credential that authenticated
+// the request. Requests authenticated with the Control API secret aren't restricted.
+func (gw *Gateway) controlAPIScopeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cred := ctxGetControlAPICredential(r)
+		if cred == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var template string
+		if route := mux.CurrentRoute(r); route != nil {
+			template, _ = route.GetPathTemplate()
+		}
+
+		scope, ok := controlAPIRouteScope(template, r.Method)
+		if !ok {
+			mainLog.WithField("credential", cred.Name).Warning("Attempted administrative access to an endpoint reserved to the Control API secret")
+			doJSONWrite(w, http.StatusForbidden, apiError("This endpoint requires the Control API secret"))
+			return
+		}
+
+		if !cred.hasScope(scope) {
+			mainLog.WithField("credential", cred.Name).Warningf("Attempted administrative access without the %s scope", scope)
+			doJSONWrite(w, http.StatusForbidden, apiError("Credential is missing the "+scope+" scope"))
+			return
+		}
+
+		if cred.limited() {
+			if err := gw.checkControlAPICredentialLimits(cred, r, template); err != nil {
+				mainLog.WithField("credential", cred.Name).WithError(err).Warning("Attempted administrative access outside of the credential limits")
+				doJSONWrite(w, http.StatusForbidden, apiError(err.Error()))
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkControlAPICredentialLimits checks the resources of the request are within the organisation
+// and APIs the credential is limited to. Listing endpoints and creating APIs aren't available to
+// limited credentials.
+func (gw *Gateway) checkControlAPICredentialLimits(cred *controlAPICredential, r *http.Request, template string) error {
+	vars := mux.Vars(r)
+
+	switch {
+	case vars["apiID"] != "":
+		if err := gw.checkCredentialAPI(cred, vars["apiID"]); err != nil {
+			return err
+		}
+		return gw.checkCredentialAPIDefinition(cred, r, template, vars["apiID"])
+	case template == "/org/keys" || strings.HasPrefix(template, "/org/keys/"):
+		if cred.OrgID == "" || len(cred.APIIDs) > 0 || vars["keyName"] != cred.OrgID {
+			return errControlAPICredentialLimited
+		}
+		return nil
+	case template == "/keys/bulk":
+		return gw.checkCredentialKeyBulk(cred, r)
+	case template == "/keys" || strings.HasPrefix(template, "/keys/"):
+		return gw.checkCredentialKey(cred, r, template)
+	case template == "/policies" || strings.HasPrefix(template, "/policies/"):
+		return gw.checkCredentialPolicy(cred, r)
+	case template == "/certs" || strings.HasPrefix(template, "/certs/"):
+		return checkCredentialCert(cred, r)
+	case template == "/oauth/clients/create":
+		var client NewClientRequest
+		if err := decodeControlAPIBody(r, &client); err != nil {
+			return errControlAPICredentialLimited
+		}
+		return gw.checkCredentialAPI(cred, client.APIID)
+	case r.URL.Query().Get("api_id") != "":
+		return gw.checkCredentialAPI(cred, r.URL.Query().Get("api_id"))
+	case template == "/reload" || template == "/reload/group" || template == "/debug" || template == "/schema":
+		return nil
+	}
+
+	return errControlAPICredentialLimited
+}
+
+func (gw *Gateway) checkCredentialAPI(cred *controlAPICredential, apiID string) error {
+	spec := gw.getApiSpec(apiID)
+	if spec == nil || !cred.allowsAPI(apiID, spec.OrgID) {
+		return errControlAPICredentialLimited
+	}
+	return nil
+}
+
+// controlAPIDefinitionIDs holds the identifiers of a classic or OAS API definition written to the Control API.
+type controlAPIDefinitionIDs struct {
+	APIID string `json:"api_id"`
+	OrgID string `json:"org_id"`
+	XTyk  struct {
+		Info struct {
+			ID    string `json:"id"`
+			OrgID string `json:"orgId"`
+		} `json:"info"`
+	} `json:"x-tyk-api-gateway"`
+}
+
+// checkCredentialAPIDefinition checks the API definition submitted to update an API is within the
+// credential limits, so a limited credential can't move the API to another organisation or ID.
+func (gw *Gateway) checkCredentialAPIDefinition(cred *controlAPICredential, r *http.Request, template, apiID string) error {
+	if template != "/apis/{apiID}" && template != "/apis/oas/{apiID}" {
+		return nil
+	}
+
+	if r.Method != http.MethodPut && r.Method != http.MethodPost && r.Method != http.MethodPatch {
+		return nil
+	}
+
+	var def controlAPIDefinitionIDs
+	if err := decodeControlAPIBody(r, &def); err != nil {
+		return errControlAPICredentialLimited
+	}
+
+	id, orgID := def.APIID, def.OrgID
+	if template == "/apis/oas/{apiID}" {
+		id, orgID = def.XTyk.Info.ID, def.XTyk.Info.OrgID
+	}
+
+	// patches keep the identifiers of the existing API when they don't set them
+	if r.Method == http.MethodPatch {
+		if id == "" {
+			id = apiID
+		}
+		if orgID == "" {
+			orgID = gw.getApiSpec(apiID).OrgID
+		}
+	}
+
+	if !cred.allowsAPI(id, orgID) {
+		return errControlAPICredentialLimited
+	}
+	return nil
+}
+
+// checkCredentialSession checks the session, including the policies it applies, is within the credential limits.
+func (gw *Gateway) checkCredentialSession(cred *controlAPICredential, session *user.SessionState) error {
+	if !cred.allowsOrg(session.OrgID) {
+		return errControlAPICredentialLimited
+	}
+
+	if len(session.AccessRights) > 0 || len(session.ApplyPolicies) == 0 {
+		if !cred.allowsAccessRights(session.OrgID, session.AccessRights) {
+			return errControlAPICredentialLimited
+		}
+	}
+
+	for _, polID := range session.PolicyIDs() {
+		pol, ok := gw.PolicyByID(polID)
+		if !ok || !cred.allowsAccessRights(pol.OrgID, pol.AccessRights) {
+			return errControlAPICredentialLimited
+		}
+	}
+
+	return nil
+}
+
+// checkCredentialKey checks the existing and submitted key are within the credential limits.
+func (gw *Gateway) checkCredentialKey(cred *controlAPICredential, r *http.Request, template string) error {
+	keyName := mux.Vars(r)["keyName"]
+	query := r.URL.Query()
+
+	if keyName == "" && r.Method == http.MethodGet {
+		return errControlAPICredentialLimited
+	}
+
+	if keyName != "" {
+		if query.Get("username") == "true" && r.Method != http.MethodPost {
+			keyName = gw.generateToken(query.Get("org_id"), keyName)
+		}
+
+		existing, found := gw.GlobalSessionManager.SessionDetail(query.Get("org_id"), keyName, query.Get("hashed") != "")
+		switch {
+		case found:
+			if err := gw.checkCredentialSession(cred, &existing); err != nil {
+				return err
+			}
+		case r.Method == http.MethodGet || r.Method == http.MethodDelete:
+			// keys that can't be verified aren't disclosed
+			return errControlAPICredentialLimited
+		}
+	}
+
+	switch r.Method {
+	case http.MethodPost, http.MethodPut:
+		if strings.HasPrefix(template, "/keys/policy/") {
+			var update PolicyUpdateObj
+			if err := decodeControlAPIBody(r, &update); err != nil {
+				return errControlAPICredentialLimited
+			}
+			return gw.checkCredentialSession(cred, &user.SessionState{OrgID: cred.OrgID, ApplyPolicies: update.ApplyPolicies})
+		}
+
+		var session user.SessionState
+		if err := decodeControlAPIBody(r, &session); err != nil {
+			return errControlAPICredentialLimited
+		}
+		return gw.checkCredentialSession(cred, &session)
+	}
+
+	return nil
+}
+
+// checkCredentialKeyBulk checks the existing and submitted keys of every bulk operation are within the credential limits.
+func (gw *Gateway) checkCredentialKeyBulk(cred *controlAPICredential, r *http.Request) error {
+	var req keyBulkRequest
+	if err := decodeControlAPIBody(r, &req); err != nil {
+		return errControlAPICredentialLimited
+	}
+
+	hashed := r.URL.Query().Get("hashed") != ""
+	for _, op := range req.Operations {
+		if op.Action != keyBulkActionCreate {
+			orgID := op.OrgID
+			if op.Session != nil {
+				orgID = op.Session.OrgID
+			}
+
+			existing, found := gw.GlobalSessionManager.SessionDetail(orgID, op.Key, hashed)
+			if !found {
+				// keys that can't be verified aren't disclosed
+				return errControlAPICredentialLimited
+			}
+			if err := gw.checkCredentialSession(cred, &existing); err != nil {
+				return err
+			}
+		}
+
+		if op.Session != nil {
+			if err := gw.checkCredentialSession(cred, op.Session); err != nil {
+				return err
+			}
+		}
+
+		if len(op.Policies) > 0 {
+			if err := gw.checkCredentialSession(cred, &user.SessionState{OrgID: cred.OrgID, ApplyPolicies: op.Policies}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkCredentialPolicy checks the existing and submitted policy are within the credential limits.
+func (gw *Gateway) checkCredentialPolicy(cred *controlAPICredential, r *http.Request) error {
+	polID := mux.Vars(r)["polID"]
+
+	if polID == "" && r.Method != http.MethodPost {
+		return errControlAPICredentialLimited
+	}
+
+	if polID != "" {
+		existing, found := gw.PolicyByID(polID)
+		switch {
+		case found:
+			if !cred.allowsAccessRights(existing.OrgID, existing.AccessRights) {
+				return errControlAPICredentialLimited
+			}
+		case r.Method != http.MethodPost:
+			return errControlAPICredentialLimited
+		}
+	}
+
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		var pol user.Policy
+		if err := decodeControlAPIBody(r, &pol); err != nil || !cred.allowsAccessRights(pol.OrgID, pol.AccessRights) {
+			return errControlAPICredentialLimited
+		}
+	}
+
+	return nil
+}
+
+// checkCredentialCert checks the certificates are owned by the organisation the credential is limited to.
+// Certificate IDs are prefixed with the ID of the organisation owning them.
+func checkCredentialCert(cred *controlAPICredential, r *http.Request) error {
+	if cred.OrgID == "" {
+		return errControlAPICredentialLimited
+	}
+
+	certIDs := mux.Vars(r)["certID"]
+	if certIDs == "" {
+		if r.URL.Query().Get("org_id") != cred.OrgID {
+			return errControlAPICredentialLimited
+		}
+		return nil
+	}
+
+	for _, certID := range strings.Split(certIDs, ",") {
+		if !strings.HasPrefix(certID, cred.OrgID) {
+			return errControlAPICredentialLimited
+		}
+	}
+	return nil
+}
+
+// decodeControlAPIBody decodes the JSON request body into v and restores it for the handler.
+func decodeControlAPIBody(r *http.Request, v interface{}) error {
+	if r.Body == nil {
+		return errors.New("request body is empty")
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	return json.Unmarshal(body, v)
+}
+
+// requireControlAPIScope restricts an endpoint served outside of the Control API router to the
+// Control API secret and credentials having the scope and access to the API.
+func (gw *Gateway) requireControlAPIScope(scope, apiID string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cred := ctxGetControlAPICredential(r); cred != nil {
+			if !cred.hasScope(scope) || (cred.limited() && gw.checkCredentialAPI(cred, apiID) != nil) {
+				doJSONWrite(w, http.StatusForbidden, apiError("Credential can't access this endpoint"))
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,148 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/config"
+	"github.com/TykTechnologies/tyk/header"
+	"github.com/TykTechnologies/tyk/test"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+func TestControlAPIRouteScope(t *testing.T) {
+	for _, tc := range []struct {
+		template, method, scope string
+	}{
+		{"/apis", http.MethodGet, ScopeAPIsRead},
+		{"/apis/oas/{apiID}", http.MethodPut, ScopeAPIsWrite},
+		{"/keys/{keyName:[^/]*}", http.MethodDelete, ScopeKeysWrite},
+		{"/org/keys", http.MethodGet, ScopeKeysRead},
+		{"/policies/{polID}", http.MethodGet, ScopePoliciesRead},
+		{"/certs", http.MethodPost, ScopeCertsWrite},
+		{"/oauth/clients/create", http.MethodPost, ScopeOAuthWrite},
+		{"/reload/group", http.MethodGet, ScopeReload},
+		{"/debug/tap/{apiID}", http.MethodGet, ScopeDebug},
+		{"/credentials", http.MethodGet, ""},
+		{"/webhooks/deliveries/failed", http.MethodGet, ""},
+		{"/apisx", http.MethodGet, ""},
+	} {
+		scope, ok := controlAPIRouteScope(tc.template, tc.method)
+		assert.Equal(t, tc.scope != "", ok, tc.template)
+		assert.Equal(t, tc.scope, scope, tc.template)
+	}
+}
+
+func TestControlAPICredential_HasScope(t *testing.T) {
+	cred := &controlAPICredential{ControlAPICredential: config.ControlAPICredential{Scopes: []string{"policies:*", ScopeKeysRead, ScopeReload}}}
+
+	assert.True(t, cred.hasScope(ScopePoliciesRead))
+	assert.True(t, cred.hasScope(ScopePoliciesWrite))
+	assert.True(t, cred.hasScope(ScopeKeysRead))
+	assert.False(t, cred.hasScope(ScopeKeysWrite))
+	assert.True(t, cred.hasScope(ScopeReload))
+	assert.False(t, cred.hasScope(ScopeDebug))
+
+	assert.True(t, validControlAPIScope("certs:*"))
+	assert.True(t, validControlAPIScope(ScopeDebug))
+	assert.False(t, validControlAPIScope("debug:*"))
+	assert.False(t, validControlAPIScope("keys:delete"))
+}
+
+func TestControlAPICredentials(t *testing.T) {
+	const (
+		ciSecret      = "ci-secret"
+		limitedSecret = "limited-secret"
+	)
+
+	ts := StartTest(func(globalConf *config.Config) {
+		globalConf.Security.ControlAPICredentials = []config.ControlAPICredential{
+			{Name: "ci", SecretHash: hashControlAPISecret(globalConf.Secret, ciSecret), Scopes: []string{ScopeAPIsRead, "keys:*"}},
+			{Name: "limited", SecretHash: hashControlAPISecret(globalConf.Secret, limitedSecret), Scopes: []string{"apis:*", "keys:*"},
+				OrgID: "org-a", APIIDs: []string{"api-a"}},
+		}
+	})
+	defer ts.Close()
+
+	ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+		spec.APIID = "api-a"
+		spec.OrgID = "org-a"
+		spec.Proxy.ListenPath = "/api-a/"
+	}, func(spec *APISpec) {
+		spec.APIID = "api-b"
+		spec.OrgID = "org-a"
+		spec.Proxy.ListenPath = "/api-b/"
+	})
+
+	auth := func(secret string) map[string]string {
+		return map[string]string{header.XTykAuthorization: secret}
+	}
+
+	session := func(apiID string) *user.SessionState {
+		s := CreateStandardSession()
+		s.OrgID = "org-a"
+		s.AccessRights = map[string]user.AccessDefinition{apiID: {APIID: apiID, Versions: []string{"v1"}}}
+		return s
+	}
+
+	t.Run("scopes", func(t *testing.T) {
+		_, _ = ts.Run(t, []test.TestCase{
+			{Method: http.MethodGet, Path: "/tyk/apis", Headers: auth(ciSecret), Code: http.StatusOK},
+			{Method: http.MethodPost, Path: "/tyk/apis", Headers: auth(ciSecret), Code: http.StatusForbidden,
+				BodyMatch: "missing the apis:write scope"},
+			{Method: http.MethodGet, Path: "/tyk/reload", Headers: auth(ciSecret), Code: http.StatusForbidden},
+			{Method: http.MethodGet, Path: "/tyk/credentials", Headers: auth(ciSecret), Code: http.StatusForbidden},
+			{Method: http.MethodPost, Path: "/tyk/keys/create", Headers: auth(ciSecret), Data: session("api-b"), Code: http.StatusOK},
+			{Method: http.MethodGet, Path: "/tyk/apis", Headers: auth("unknown"), Code: http.StatusForbidden},
+		}...)
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		_, _ = ts.Run(t, []test.TestCase{
+			{Method: http.MethodGet, Path: "/tyk/apis/api-a", Headers: auth(limitedSecret), Code: http.StatusOK},
+			{Method: http.MethodGet, Path: "/tyk/apis/api-b", Headers: auth(limitedSecret), Code: http.StatusForbidden},
+			{Method: http.MethodGet, Path: "/tyk/apis", Headers: auth(limitedSecret), Code: http.StatusForbidden},
+			{Method: http.MethodGet, Path: "/tyk/keys", Headers: auth(limitedSecret), Code: http.StatusForbidden},
+			{Method: http.MethodPost, Path: "/tyk/keys/create", Headers: auth(limitedSecret), Data: session("api-a"), Code: http.StatusOK},
+			{Method: http.MethodPost, Path: "/tyk/keys/create", Headers: auth(limitedSecret), Data: session("api-b"), Code: http.StatusForbidden},
+			{Method: http.MethodPut, Path: "/tyk/apis/api-a", Headers: auth(limitedSecret), Code: http.StatusForbidden,
+				Data: map[string]string{"api_id": "api-a", "org_id": "org-b"}},
+			{Method: http.MethodPut, Path: "/tyk/apis/api-a", Headers: auth(limitedSecret), Code: http.StatusForbidden,
+				Data: map[string]string{"api_id": "api-b", "org_id": "org-a"}},
+			{Method: http.MethodPut, Path: "/tyk/apis/oas/api-a", Headers: auth(limitedSecret), Code: http.StatusForbidden,
+				Data: `{"x-tyk-api-gateway":{"info":{"id":"api-a","orgId":"org-b"}}}`},
+			{Method: http.MethodPatch, Path: "/tyk/apis/oas/api-a", Headers: auth(limitedSecret), Code: http.StatusForbidden,
+				Data: `{"x-tyk-api-gateway":{"info":{"orgId":"org-b"}}}`},
+			// patches without identifiers keep those of the API, api-a isn't an OAS API
+			{Method: http.MethodPatch, Path: "/tyk/apis/oas/api-a", Headers: auth(limitedSecret), Code: http.StatusBadRequest,
+				Data: `{"openapi":"3.0.3"}`},
+		}...)
+	})
+
+	t.Run("stored credentials", func(t *testing.T) {
+		resp, err := ts.Run(t, test.TestCase{
+			Method: http.MethodPost, Path: "/tyk/credentials", AdminAuth: true, Code: http.StatusCreated,
+			Data: config.ControlAPICredential{Name: "deployer", Scopes: []string{ScopeReload}},
+		})
+		require.NoError(t, err)
+
+		var created controlAPICredentialCreated
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		require.NotEmpty(t, created.Secret)
+		assert.Empty(t, created.SecretHash)
+
+		_, _ = ts.Run(t, []test.TestCase{
+			{Method: http.MethodPost, Path: "/tyk/credentials", AdminAuth: true, Code: http.StatusBadRequest,
+				Data: config.ControlAPICredential{Name: "bad", Scopes: []string{"everything"}}},
+			{Method: http.MethodGet, Path: "/tyk/credentials", AdminAuth: true, Code: http.StatusOK, BodyMatch: `"name":"deployer"`},
+			{Method: http.MethodGet, Path: "/tyk/reload", Headers: auth(created.Secret), Code: http.StatusOK},
+			{Method: http.MethodGet, Path: "/tyk/apis", Headers: auth(created.Secret), Code: http.StatusForbidden},
+			{Method: http.MethodDelete, Path: "/tyk/credentials/" + created.ID, AdminAuth: true, Code: http.StatusOK},
+			{Method: http.MethodGet, Path: "/tyk/reload", Headers: auth(created.Secret), Code: http.StatusForbidden},
+		}...)
+	})
+}
@@ -0,0 +1,105 @@
+package gateway
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/config"
+	"github.com/TykTechnologies/tyk/internal/cloudevents"
+)
+
+// CloudEventsEventHandler is an event handler that delivers events as
+// CloudEvents 1.0 envelopes to Kafka, NATS, AMQP or an HTTP endpoint.
+type CloudEventsEventHandler struct {
+	conf       apidef.CloudEventsHandlerConf
+	apiID      string // empty for global handlers
+	dispatcher *cloudevents.Dispatcher
+	logger     *logrus.Entry
+	Gw         *Gateway `json:"-"`
+}
+
+// Init initializes the CloudEventsEventHandler instance with the given configuration
+// and starts the delivery worker.
+func (c *CloudEventsEventHandler) Init(handlerConf any) error {
+	c.logger = log.WithField("prefix", "cloudevents")
+
+	if err := c.conf.Scan(handlerConf); err != nil {
+		c.logger.Error("Problem getting configuration, skipping. ", err)
+		return err
+	}
+
+	if c.conf.Disabled {
+		c.logger.Infof("skipping disabled cloudevents handler %s", c.conf.Name)
+		return ErrEventHandlerDisabled
+	}
+
+	transport, err := cloudevents.NewTransport(c.conf, c.httpClient())
+	if err != nil {
+		c.logger.WithError(err).Error("Init failed for this cloudevents handler")
+		return err
+	}
+
+	c.dispatcher = cloudevents.NewDispatcher(transport, cloudevents.Options{
+		QueueSize:      c.conf.QueueSize,
+		MaxRetries:     c.conf.MaxRetries,
+		RetryInterval:  time.Duration(c.conf.RetryInterval) * time.Second,
+		DeadLetterPath: c.conf.DeadLetterPath,
+		Logger:         c.logger.WithField("transport", c.conf.Transport),
+	})
+
+	return nil
+}
+
+// httpClient returns the client for the HTTP binding, honouring the external services configuration.
+func (c *CloudEventsEventHandler) httpClient() *http.Client {
+	if c.conf.Transport != cloudevents.TransportHTTP || c.Gw == nil {
+		return nil
+	}
+
+	cli, err := NewExternalHTTPClientFactory(c.Gw).CreateWebhookClient()
+	if err != nil {
+		c.logger.WithError(err).Debug("Failed to create cloudevents HTTP client, falling back to default")
+		return &http.Client{Timeout: 30 * time.Second}
+	}
+	return cli
+}
+
+// HandleEvent queues the event for delivery as a CloudEvent.
+func (c *CloudEventsEventHandler) HandleEvent(em config.EventMessage) {
+	ce := cloudevents.New(c.conf.Source, string(em.Type), em.Meta)
+	ce.Subject = c.subject(em.Meta)
+
+	if err := c.dispatcher.Publish(ce); err != nil {
+		c.logger.WithError(err).WithField("event", em.Type).Warning("Could not queue cloudevent for delivery")
+	}
+}
+
+// subject returns the `subject` attribute of the event: the API of the handler, or for global
+// handlers the organisation of the key events. Keys are never used as they are credentials.
+func (c *CloudEventsEventHandler) subject(meta any) string {
+	if c.apiID != "" {
+		return c.apiID
+	}
+
+	switch m := meta.(type) {
+	case EventTokenMeta:
+		return m.Org
+	case EventTriggerExceededMeta:
+		return m.OrgID
+	}
+	return ""
+}
+
+// Close stops the delivery worker and releases the broker connection.
+func (c *CloudEventsEventHandler) Close() {
+	if c.dispatcher == nil {
+		return
+	}
+
+	if err := c.dispatcher.Close(); err != nil {
+		c.logger.WithError(err).Warning("Error closing cloudevents transport")
+	}
+}
@@ -0,0 +1,76 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/config"
+	"github.com/TykTechnologies/tyk/internal/cloudevents"
+)
+
+func TestCloudEventsEventHandler_Init(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		h := &CloudEventsEventHandler{}
+		err := h.Init(map[string]any{"disabled": true, "transport": "http"})
+		assert.ErrorIs(t, err, ErrEventHandlerDisabled)
+	})
+
+	t.Run("unknown transport", func(t *testing.T) {
+		h := &CloudEventsEventHandler{}
+		err := h.Init(map[string]any{"transport": "smtp"})
+		assert.ErrorIs(t, err, cloudevents.ErrUnknownTransport)
+	})
+
+	t.Run("missing destination", func(t *testing.T) {
+		h := &CloudEventsEventHandler{}
+		err := h.Init(map[string]any{"transport": "nats"})
+		assert.ErrorIs(t, err, cloudevents.ErrMissingTarget)
+	})
+}
+
+func TestCloudEventsEventHandler_HandleEvent(t *testing.T) {
+	received := make(chan map[string]any, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var envelope map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&envelope)
+		received <- envelope
+	}))
+	defer srv.Close()
+
+	h := &CloudEventsEventHandler{apiID: "api"}
+	err := h.Init(map[string]any{
+		"transport": "http",
+		"source":    "/tyk/test",
+		"http": map[string]any{
+			"url": srv.URL,
+		},
+	})
+	require.NoError(t, err)
+	defer h.Close()
+
+	h.HandleEvent(config.EventMessage{
+		Type: EventHOSTDOWN,
+		Meta: EventHostStatusMeta{
+			HostInfo: HostHealthReport{
+				HostData: HostData{CheckURL: "http://upstream/health"},
+			},
+		},
+	})
+
+	select {
+	case envelope := <-received:
+		assert.Equal(t, "1.0", envelope["specversion"])
+		assert.Equal(t, "/tyk/test", envelope["source"])
+		assert.Equal(t, "io.tyk.gateway.HostDown", envelope["type"])
+		assert.Equal(t, "api", envelope["subject"])
+		assert.NotEmpty(t, envelope["data"])
+	case <-time.After(time.Second):
+		t.Fatal("cloudevent was not delivered")
+	}
+}
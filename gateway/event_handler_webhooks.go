@@ -7,7 +7,6 @@ import (
 	"errors"
 	"fmt"
 	htmltemplate "html/template"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"path/filepath"
@@ -45,6 +44,7 @@ var (
 // WebHookHandler is an event handler that triggers web hooks
 type WebHookHandler struct {
 	conf     apidef.WebHookHandlerConf
+	apiID    string                 // empty for global webhooks
 	template *htmltemplate.Template // non-nil if Init is run without error
 	store    storage.Handler
 
@@ -128,6 +128,18 @@ func (w *WebHookHandler) Init(handlerConf interface{}) error {
 	return nil
 }
 
+// ID returns the ID the outbox finds the webhook by, scoped to the API of the webhook so
+// the same webhook configured on several APIs isn't confused. Webhooks without a configured
+// ID are identified by their name, method, target and template.
+func (w *WebHookHandler) ID() string {
+	h := md5.New()
+	for _, field := range []string{w.apiID, w.conf.ID, w.conf.Name, w.conf.Method, w.conf.TargetPath, w.conf.TemplatePath} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 // hookFired checks if an event has been fired within the EventTimeout setting
 func (w *WebHookHandler) WasHookFired(checksum string) bool {
 	if _, err := w.store.GetKey(checksum); err != nil {
@@ -277,12 +289,6 @@ func (w *WebHookHandler) HandleEvent(em config.EventMessage) {
 		return
 	}
 
-	// Construct request (method, body, params)
-	req, err := w.BuildRequest(reqBody)
-	if err != nil {
-		return
-	}
-
 	// Generate signature for request
 	reqChecksum, err := w.Checksum(em, reqBody)
 	if err != nil {
@@ -297,53 +303,8 @@ func (w *WebHookHandler) HandleEvent(em config.EventMessage) {
 		return
 	}
 
-	// Create HTTP client using factory for webhook service
-	clientFactory := NewExternalHTTPClientFactory(w.Gw)
-	cli, err := clientFactory.CreateWebhookClient()
-	if err != nil {
-		// Check if mTLS is explicitly enabled and error is certificate-related - if so, don't fallback as it would bypass security
-		gwConfig := w.Gw.GetConfig()
-		if gwConfig.ExternalServices.Webhooks.MTLS.Enabled && httpclient.IsMTLSError(err) {
-			log.WithError(err).Error("mTLS configuration failed for webhooks. Webhook delivery will be skipped to maintain security.")
-			// Skip webhook delivery entirely when mTLS is misconfigured
-			return
-		} else {
-			// For other errors (not configured, proxy config), fallback to default client
-			log.WithError(err).Debug("Failed to create webhook HTTP client, falling back to default")
-			log.Debug("[ExternalServices] Falling back to legacy webhook client due to factory error")
-			cli = &http.Client{Timeout: 30 * time.Second}
-		}
-	} else {
-		log.Debugf("[ExternalServices] Using external services webhook client for URL: %s", req.URL.String())
-	}
-
-	resp, err := cli.Do(req)
-	if err != nil {
-		log.WithFields(logrus.Fields{
-			"prefix": "webhooks",
-		}).Error("Webhook request failed: ", err)
-	} else {
-		defer resp.Body.Close()
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			content, err := ioutil.ReadAll(resp.Body)
-			if err == nil {
-				log.WithFields(logrus.Fields{
-					"prefix":       "webhooks",
-					"responseCode": resp.StatusCode,
-				}).Debug(string(content))
-			} else {
-				log.WithFields(logrus.Fields{
-					"prefix": "webhooks",
-				}).Error(err)
-			}
-
-		} else {
-			log.WithFields(logrus.Fields{
-				"prefix":       "webhooks",
-				"responseCode": resp.StatusCode,
-			}).Error("Request to webhook failed")
-		}
-	}
+	// Deliver through the outbox, which signs the request and schedules retries
+	_ = w.Gw.webhookOutbox.deliver(w, newWebhookDelivery(em.Type, w, reqBody))
 
 	if w.dashboardService != nil && em.Type == EventTriggerExceeded {
 		w.dashboardService.NotifyDashboardOfEvent(em.Meta)
@@ -352,6 +313,28 @@ func (w *WebHookHandler) HandleEvent(em config.EventMessage) {
 	w.setHookFired(reqChecksum)
 }
 
+// webhookHTTPClient returns the client used to deliver webhooks, honouring the external services configuration.
+func (gw *Gateway) webhookHTTPClient() (*http.Client, error) {
+	// Create HTTP client using factory for webhook service
+	clientFactory := NewExternalHTTPClientFactory(gw)
+	cli, err := clientFactory.CreateWebhookClient()
+	if err == nil {
+		log.Debug("[ExternalServices] Using external services webhook client")
+		return cli, nil
+	}
+
+	// Check if mTLS is explicitly enabled and error is certificate-related - if so, don't fallback as it would bypass security
+	if gw.GetConfig().ExternalServices.Webhooks.MTLS.Enabled && httpclient.IsMTLSError(err) {
+		log.WithError(err).Error("mTLS configuration failed for webhooks. Webhook delivery will be skipped to maintain security.")
+		return nil, err
+	}
+
+	// For other errors (not configured, proxy config), fallback to default client
+	log.WithError(err).Debug("Failed to create webhook HTTP client, falling back to default")
+	log.Debug("[ExternalServices] Falling back to legacy webhook client due to factory error")
+	return &http.Client{Timeout: 30 * time.Second}, nil
+}
+
 func templateFuncAsRFC3339() func(time.Time) string {
 	return func(t time.Time) string {
 		return t.Format(time.RFC3339)
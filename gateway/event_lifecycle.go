@@ -0,0 +1,146 @@
+package gateway
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/internal/event"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+// Actions reported by EventPolicyMeta.
+const (
+	policyActionAdded   = "added"
+	policyActionUpdated = "updated"
+	policyActionRemoved = "removed"
+)
+
+// EventAPIMeta is the metadata structure for the APIAdded, APIUpdated and APIRemoved events.
+type EventAPIMeta struct {
+	EventMetaDefault
+	APIID      string `json:"api_id"`
+	Name       string `json:"name"`
+	OrgID      string `json:"org_id"`
+	ListenPath string `json:"listen_path"`
+}
+
+// EventPolicyMeta is the metadata structure for the PolicyChanged event.
+type EventPolicyMeta struct {
+	EventMetaDefault
+	PolicyID string `json:"policy_id"`
+	Name     string `json:"name"`
+	OrgID    string `json:"org_id"`
+	Action   string `json:"action"`
+}
+
+// EventReloadMeta is the metadata structure for the ReloadCompleted and ReloadFailed events.
+type EventReloadMeta struct {
+	EventMetaDefault
+	DurationMs int64  `json:"duration_ms"`
+	APIs       int    `json:"apis"`
+	Policies   int    `json:"policies"`
+	Error      string `json:"error,omitempty"`
+}
+
+// EventCertificateMeta is the metadata structure for the CertificateAdded and CertificateRemoved events.
+type EventCertificateMeta struct {
+	EventMetaDefault
+	CertID string `json:"cert_id"`
+	OrgID  string `json:"org_id"`
+}
+
+// EventRPCEmergencyModeMeta is the metadata structure for the RPC emergency mode events.
+type EventRPCEmergencyModeMeta struct {
+	EventMetaDefault
+	NodeID string `json:"node_id"`
+}
+
+// fireAPIEvents fires the lifecycle events for the API definitions changed by a reload.
+func (gw *Gateway) fireAPIEvents(added, updated, removed []*APISpec) {
+	fire := func(name apidef.TykEvent, specs []*APISpec) {
+		for _, spec := range specs {
+			gw.FireSystemEvent(name, EventAPIMeta{
+				EventMetaDefault: EventMetaDefault{Message: fmt.Sprintf("%s: %s", event.String(name), spec.Name)},
+				APIID:            spec.APIID,
+				Name:             spec.Name,
+				OrgID:            spec.OrgID,
+				ListenPath:       spec.Proxy.ListenPath,
+			})
+		}
+	}
+
+	fire(EventAPIAdded, added)
+	fire(EventAPIUpdated, updated)
+	fire(EventAPIRemoved, removed)
+}
+
+// firePolicyEvents fires a PolicyChanged event for every policy that differs between the two sets.
+func (gw *Gateway) firePolicyEvents(oldPolicies, newPolicies map[string]user.Policy) {
+	fire := func(id string, pol user.Policy, action string) {
+		gw.FireSystemEvent(EventPolicyChanged, EventPolicyMeta{
+			EventMetaDefault: EventMetaDefault{Message: fmt.Sprintf("Policy %s: %s", action, pol.Name)},
+			PolicyID:         id,
+			Name:             pol.Name,
+			OrgID:            pol.OrgID,
+			Action:           action,
+		})
+	}
+
+	for id, pol := range newPolicies {
+		oldPol, ok := oldPolicies[id]
+		switch {
+		case !ok:
+			fire(id, pol, policyActionAdded)
+		case !reflect.DeepEqual(oldPol, pol):
+			fire(id, pol, policyActionUpdated)
+		}
+	}
+
+	for id, pol := range oldPolicies {
+		if _, ok := newPolicies[id]; !ok {
+			fire(id, pol, policyActionRemoved)
+		}
+	}
+}
+
+// fireReloadEvent fires ReloadCompleted, or ReloadFailed if err is set, for a reload started at start.
+func (gw *Gateway) fireReloadEvent(start time.Time, err error) {
+	name := EventReloadCompleted
+	meta := EventReloadMeta{
+		DurationMs: time.Since(start).Milliseconds(),
+		APIs:       gw.apisByIDLen(),
+		Policies:   gw.PolicyCount(),
+	}
+
+	if err != nil {
+		name = EventReloadFailed
+		meta.Error = err.Error()
+	}
+	meta.Message = event.String(name)
+
+	gw.FireSystemEvent(name, meta)
+}
+
+// fireCertificateEvent fires CertificateAdded or CertificateRemoved for the certificate.
+func (gw *Gateway) fireCertificateEvent(name apidef.TykEvent, certID, orgID string) {
+	gw.FireSystemEvent(name, EventCertificateMeta{
+		EventMetaDefault: EventMetaDefault{Message: event.String(name)},
+		CertID:           certID,
+		OrgID:            orgID,
+	})
+}
+
+// onRPCEmergencyModeChange fires the RPC emergency mode events, it's registered with the RPC client.
+func (gw *Gateway) onRPCEmergencyModeChange(enabled bool) {
+	name := EventRPCEmergencyModeExited
+	if enabled {
+		name = EventRPCEmergencyModeEntered
+	}
+
+	gw.FireSystemEvent(name, EventRPCEmergencyModeMeta{
+		EventMetaDefault: EventMetaDefault{Message: event.String(name)},
+		NodeID:           gw.GetNodeID(),
+	})
+}
@@ -0,0 +1,160 @@
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/config"
+	"github.com/TykTechnologies/tyk/user"
+)
+
+// captureSystemEvents registers a global handler for the events and returns the channel they're delivered to.
+func (ts *Test) captureSystemEvents(events ...apidef.TykEvent) chan config.EventMessage {
+	ch := make(chan config.EventMessage, 100)
+	handler := &testEventHandler{cb: func(em config.EventMessage) {
+		ch <- em
+	}}
+
+	triggers := map[apidef.TykEvent][]config.TykEventHandler{}
+	for _, e := range events {
+		triggers[e] = []config.TykEventHandler{handler}
+	}
+
+	conf := ts.Gw.GetConfig()
+	conf.SetEventTriggers(triggers)
+	ts.Gw.SetConfig(conf)
+
+	return ch
+}
+
+// collectEvents reads events from ch until no event has arrived for a short while.
+func collectEvents(ch chan config.EventMessage) map[apidef.TykEvent][]config.EventMessage {
+	got := map[apidef.TykEvent][]config.EventMessage{}
+	for {
+		select {
+		case em := <-ch:
+			got[em.Type] = append(got[em.Type], em)
+		case <-time.After(200 * time.Millisecond):
+			return got
+		}
+	}
+}
+
+func TestAPILifecycleEvents(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	api := func(id, name string) func(*APISpec) {
+		return func(spec *APISpec) {
+			spec.APIID = id
+			spec.Name = name
+			spec.Proxy.ListenPath = "/" + id + "/"
+		}
+	}
+
+	ts.Gw.BuildAndLoadAPI(api("api-a", "A"))
+	ch := ts.captureSystemEvents(EventAPIAdded, EventAPIUpdated, EventAPIRemoved, EventReloadCompleted)
+
+	ts.Gw.BuildAndLoadAPI(api("api-a", "A changed"), api("api-b", "B"))
+	got := collectEvents(ch)
+
+	require.Len(t, got[EventAPIAdded], 1)
+	added := got[EventAPIAdded][0].Meta.(EventAPIMeta)
+	assert.Equal(t, "api-b", added.APIID)
+	assert.Equal(t, "/api-b/", added.ListenPath)
+
+	require.Len(t, got[EventAPIUpdated], 1)
+	assert.Equal(t, "A changed", got[EventAPIUpdated][0].Meta.(EventAPIMeta).Name)
+	assert.Empty(t, got[EventAPIRemoved])
+
+	require.Len(t, got[EventReloadCompleted], 1)
+	reload := got[EventReloadCompleted][0].Meta.(EventReloadMeta)
+	assert.Equal(t, 2, reload.APIs)
+	assert.Empty(t, reload.Error)
+
+	ts.Gw.BuildAndLoadAPI(api("api-b", "B"))
+	got = collectEvents(ch)
+
+	require.Len(t, got[EventAPIRemoved], 1)
+	assert.Equal(t, "api-a", got[EventAPIRemoved][0].Meta.(EventAPIMeta).APIID)
+	assert.Empty(t, got[EventAPIAdded])
+	assert.Empty(t, got[EventAPIUpdated])
+}
+
+func TestFirePolicyEvents(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	ch := ts.captureSystemEvents(EventPolicyChanged)
+
+	ts.Gw.firePolicyEvents(map[string]user.Policy{
+		"unchanged": {Name: "unchanged", Rate: 10},
+		"updated":   {Name: "updated", Rate: 10},
+		"removed":   {Name: "removed"},
+	}, map[string]user.Policy{
+		"unchanged": {Name: "unchanged", Rate: 10},
+		"updated":   {Name: "updated", Rate: 20},
+		"added":     {Name: "added", OrgID: "org"},
+	})
+
+	actions := map[string]string{}
+	for _, em := range collectEvents(ch)[EventPolicyChanged] {
+		meta := em.Meta.(EventPolicyMeta)
+		actions[meta.PolicyID] = meta.Action
+	}
+
+	assert.Equal(t, map[string]string{
+		"updated": policyActionUpdated,
+		"removed": policyActionRemoved,
+		"added":   policyActionAdded,
+	}, actions)
+}
+
+func TestRPCEmergencyModeEvents(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	ch := ts.captureSystemEvents(EventRPCEmergencyModeEntered, EventRPCEmergencyModeExited)
+
+	ts.Gw.onRPCEmergencyModeChange(true)
+	ts.Gw.onRPCEmergencyModeChange(false)
+	got := collectEvents(ch)
+
+	require.Len(t, got[EventRPCEmergencyModeEntered], 1)
+	require.Len(t, got[EventRPCEmergencyModeExited], 1)
+	assert.Equal(t, ts.Gw.GetNodeID(), got[EventRPCEmergencyModeEntered][0].Meta.(EventRPCEmergencyModeMeta).NodeID)
+}
+
+func TestLifecycleEventTemplates(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	webhookHandler := &WebHookHandler{Gw: ts.Gw}
+	require.NoError(t, webhookHandler.Init(map[string]interface{}{
+		"method":        "POST",
+		"target_path":   testHttpPost,
+		"template_path": "../templates/default_webhook.json",
+	}))
+
+	for _, em := range []config.EventMessage{
+		{Type: EventAPIAdded, Meta: EventAPIMeta{APIID: "api", Name: "API", ListenPath: "/api/"}},
+		{Type: EventPolicyChanged, Meta: EventPolicyMeta{PolicyID: "pol", Action: policyActionAdded}},
+		{Type: EventReloadFailed, Meta: EventReloadMeta{DurationMs: 12, APIs: 3, Error: "boom"}},
+		{Type: EventCertificateRemoved, Meta: EventCertificateMeta{CertID: "cert"}},
+		{Type: EventRPCEmergencyModeEntered, Meta: EventRPCEmergencyModeMeta{NodeID: "node"}},
+	} {
+		t.Run(string(em.Type), func(t *testing.T) {
+			body, err := webhookHandler.CreateBody(em)
+			require.NoError(t, err)
+
+			var payload map[string]any
+			require.NoError(t, json.Unmarshal([]byte(body), &payload), body)
+			assert.Equal(t, string(em.Type), payload["event"])
+		})
+	}
+}
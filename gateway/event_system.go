@@ -22,6 +22,8 @@ const (
 	// EH_LogHandler is an alias maintained for backwards compatibility.
 	// It is used to register log handler on an event.
 	EH_LogHandler = event.LogHandler
+	// EH_CloudEventsHandler is the handler to deliver an event as a CloudEvent to a message broker.
+	EH_CloudEventsHandler = event.CloudEventsHandler
 )
 
 const (
@@ -65,6 +67,24 @@ const (
 	EventCertificateExpired = event.CertificateExpired
 )
 
+// Gateway lifecycle events, fired to the global event handlers.
+const (
+	EventAPIAdded                  = event.APIAdded
+	EventAPIUpdated                = event.APIUpdated
+	EventAPIRemoved                = event.APIRemoved
+	EventPolicyChanged             = event.PolicyChanged
+	EventReloadCompleted           = event.ReloadCompleted
+	EventReloadFailed              = event.ReloadFailed
+	EventCertificateAdded          = event.CertificateAdded
+	EventCertificateRemoved        = event.CertificateRemoved
+	EventRPCEmergencyModeEntered   = event.RPCEmergencyModeEntered
+	EventRPCEmergencyModeExited    = event.RPCEmergencyModeExited
+	EventAPIScheduleActivated      = event.APIScheduleActivated
+	EventAPIScheduleDeactivated    = event.APIScheduleDeactivated
+	EventPolicyScheduleActivated   = event.PolicyScheduleActivated
+	EventPolicyScheduleDeactivated = event.PolicyScheduleDeactivated
+)
+
 type EventHostStatusMeta struct {
 	EventMetaDefault
 	HostInfo HostHealthReport
@@ -129,7 +149,20 @@ func (gw *Gateway) EventHandlerByName(handlerConf apidef.EventHandlerTriggerConf
 		return h, err
 	case EH_WebHook:
 		h := &WebHookHandler{Gw: gw}
+		if spec != nil {
+			h.apiID = spec.APIID
+		}
+		err := h.Init(conf)
+		return h, err
+	case EH_CloudEventsHandler:
+		h := &CloudEventsEventHandler{Gw: gw}
+		if spec != nil {
+			h.apiID = spec.APIID
+		}
 		err := h.Init(conf)
+		if err == nil && spec != nil {
+			spec.AddUnloadHook(h.Close)
+		}
 		return h, err
 	case EH_JSVMHandler:
 		// Load the globals and file here
@@ -199,6 +232,25 @@ func (gw *Gateway) initGenericEventHandlers() {
 
 		}
 	}
+	previous := conf.GetEventTriggers()
 	conf.SetEventTriggers(handlers)
 	gw.SetConfig(conf)
+
+	closeEventHandlers(previous)
+}
+
+// closeGenericEventHandlers releases the global event handlers, such as the broker connections
+// of the CloudEvents handlers. The handlers of APIs are closed when the APIs are unloaded.
+func (gw *Gateway) closeGenericEventHandlers() {
+	closeEventHandlers(gw.GetConfig().GetEventTriggers())
+}
+
+func closeEventHandlers(handlers map[apidef.TykEvent][]config.TykEventHandler) {
+	for _, eventHandlers := range handlers {
+		for _, handler := range eventHandlers {
+			if c, ok := handler.(interface{ Close() }); ok {
+				c.Close()
+			}
+		}
+	}
 }
@@ -0,0 +1,237 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/apidef/oas"
+	"github.com/TykTechnologies/tyk/internal/gitops"
+)
+
+const syncWatchDebounce = time.Second
+
+var syncLog = log.WithField("prefix", "sync")
+
+var errSyncUnsupported = errors.New("sync requires file based APIs and policies, with app_path and policies.policy_path set")
+
+// syncTarget applies plans to the Gateway in process, through the same
+// files the Control API writes in the app and policy directories.
+type syncTarget struct {
+	gw *Gateway
+}
+
+func (t *syncTarget) State() (*gitops.State, error) {
+	state := &gitops.State{}
+
+	t.gw.apisMu.RLock()
+	for _, spec := range t.gw.apisByID {
+		var (
+			data   []byte
+			apiOAS *oas.OAS
+			err    error
+		)
+
+		if spec.IsOAS {
+			// fill a copy, the loaded spec is shared with the requests being served
+			if apiOAS, err = spec.OAS.Clone(); err == nil {
+				apiOAS.Fill(*spec.APIDefinition)
+				data, err = json.Marshal(apiOAS)
+			}
+		} else {
+			data, err = json.Marshal(spec.APIDefinition)
+		}
+
+		if err != nil {
+			t.gw.apisMu.RUnlock()
+			return nil, err
+		}
+
+		state.APIs = append(state.APIs, &gitops.API{ID: spec.APIID, OAS: spec.IsOAS, Definition: data})
+	}
+	t.gw.apisMu.RUnlock()
+
+	t.gw.policiesMu.RLock()
+	for id, pol := range t.gw.policiesByID {
+		data, err := json.Marshal(pol)
+		if err != nil {
+			t.gw.policiesMu.RUnlock()
+			return nil, err
+		}
+
+		state.Policies = append(state.Policies, &gitops.Policy{ID: id, Definition: data})
+	}
+	t.gw.policiesMu.RUnlock()
+
+	for _, id := range t.gw.CertificateManager.ListAllIds("") {
+		// certificates are synced without an organisation, those of organisations are left alone
+		if len(id) != sha256.Size*2 {
+			continue
+		}
+		state.Certificates = append(state.Certificates, &gitops.Certificate{ID: id})
+	}
+
+	return state, nil
+}
+
+func (t *syncTarget) PutAPI(api *gitops.API, _ bool) error {
+	fs := afero.NewOsFs()
+
+	if !api.OAS {
+		var def apidef.APIDefinition
+		if err := json.Unmarshal(api.Definition, &def); err != nil {
+			return err
+		}
+
+		err, _ := t.gw.writeToFile(fs, &def, def.APIID)
+		return err
+	}
+
+	var oasObj oas.OAS
+	if err := json.Unmarshal(api.Definition, &oasObj); err != nil {
+		return err
+	}
+
+	var def apidef.APIDefinition
+	oasObj.ExtractTo(&def)
+	def.IsOAS = true
+
+	err, _ := t.gw.writeOASAndAPIDefToFile(fs, &def, &oasObj)
+	return err
+}
+
+func (t *syncTarget) DeleteAPI(api *gitops.API) error {
+	appPath := t.gw.GetConfig().AppPath
+
+	for _, name := range []string{api.ID + ".json", api.ID + "-oas.json"} {
+		if err := os.Remove(filepath.Join(appPath, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *syncTarget) PutPolicy(pol *gitops.Policy, _ bool) error {
+	root, err := t.gw.newPolicyPathRoot()
+	if err != nil {
+		return err
+	}
+
+	return root.WriteFile(pol.ID+".json", pol.Definition, 0644)
+}
+
+func (t *syncTarget) DeletePolicy(pol *gitops.Policy) error {
+	root, err := t.gw.newPolicyPathRoot()
+	if err != nil {
+		return err
+	}
+
+	return root.Remove(pol.ID + ".json")
+}
+
+func (t *syncTarget) AddCertificate(cert *gitops.Certificate) error {
+	if cert.PEM == nil {
+		return errors.New("certificate " + cert.ID + " can't be restored, its content isn't known")
+	}
+
+	_, err := t.gw.CertificateManager.Add(cert.PEM, "")
+	return err
+}
+
+func (t *syncTarget) DeleteCertificate(cert *gitops.Certificate) error {
+	t.gw.CertificateManager.Delete(cert.ID, "")
+	return nil
+}
+
+func (t *syncTarget) Reload() error {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	t.gw.reloadURLStructure(wg.Done)
+	wg.Wait()
+	return nil
+}
+
+func (t *syncTarget) LoadedAPIs() (map[string]bool, error) {
+	t.gw.apisMu.RLock()
+	defer t.gw.apisMu.RUnlock()
+
+	loaded := make(map[string]bool, len(t.gw.apisByID))
+	for id := range t.gw.apisByID {
+		loaded[id] = true
+	}
+	return loaded, nil
+}
+
+// syncFromDir brings the Gateway in line with the sync directory. Nothing
+// is applied when a file is invalid, and every change is rolled back when
+// an API fails to load.
+func (gw *Gateway) syncFromDir() error {
+	conf := gw.GetConfig()
+
+	desired, err := gitops.Load(conf.Sync.Path)
+	if err != nil {
+		return err
+	}
+
+	errs, warnings := gitops.Validate(desired)
+	for _, f := range warnings {
+		syncLog.Warning(f.String())
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	target := &syncTarget{gw: gw}
+
+	current, err := target.State()
+	if err != nil {
+		return err
+	}
+
+	plan := gitops.NewPlan(desired, current, conf.Sync.Prune)
+	if plan.Empty() {
+		syncLog.Debug("Gateway is in sync")
+		return nil
+	}
+
+	for _, c := range plan.Changes {
+		syncLog.WithField("path", c.Path()).Infof("%s %s %s", c.Op, c.Kind, c.ID)
+	}
+
+	return gitops.Apply(target, plan)
+}
+
+// runSync syncs the Gateway with the sync directory, then again whenever it changes if watching is enabled.
+func (gw *Gateway) runSync(ctx context.Context) {
+	conf := gw.GetConfig()
+
+	if conf.UseDBAppConfigs || conf.SlaveOptions.UseRPC || conf.Policies.PolicySource == "service" || conf.AppPath == "" || conf.Policies.PolicyPath == "" {
+		syncLog.WithError(errSyncUnsupported).Error("Sync is disabled")
+		return
+	}
+
+	run := func() {
+		if err := gw.syncFromDir(); err != nil {
+			syncLog.WithError(err).Error("Sync failed")
+		}
+	}
+
+	run()
+
+	if !conf.Sync.Watch {
+		return
+	}
+
+	if err := gitops.Watch(ctx, conf.Sync.Path, syncWatchDebounce, run); err != nil {
+		syncLog.WithError(err).Error("Watching the sync directory failed")
+	}
+}
@@ -0,0 +1,76 @@
+package gateway
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/config"
+	"github.com/TykTechnologies/tyk/test"
+)
+
+const syncTestAPI = `{
+  "api_id": "synced",
+  "name": "synced",
+  "active": true,
+  "proxy": {"listen_path": "/synced/", "target_url": "` + TestHttpAny + `", "strip_listen_path": true},
+  "version_data": {"not_versioned": true, "versions": {"Default": {"name": "Default"}}}
+}`
+
+const syncTestPolicy = `{
+  "id": "synced-policy",
+  "name": "synced",
+  "rate": 100,
+  "per": 1,
+  "quota_max": -1,
+  "access_rights": {"synced": {"api_id": "synced", "versions": ["Default"]}}
+}`
+
+func TestSyncFromDir(t *testing.T) {
+	syncDir := t.TempDir()
+
+	ts := StartTest(func(conf *config.Config) {
+		conf.Policies.PolicySource = "file"
+		conf.Policies.PolicyPath = t.TempDir()
+		conf.Sync.Path = syncDir
+		conf.Sync.Prune = true
+	})
+	defer ts.Close()
+
+	require.NoError(t, os.WriteFile(filepath.Join(syncDir, "api.json"), []byte(syncTestAPI), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(syncDir, "policy.json"), []byte(syncTestPolicy), 0600))
+
+	require.NoError(t, ts.Gw.syncFromDir())
+
+	assert.NotNil(t, ts.Gw.getApiSpec("synced"))
+	_, ok := ts.Gw.PolicyByID("synced-policy")
+	assert.True(t, ok)
+
+	_, _ = ts.Run(t, []test.TestCase{
+		{Path: "/synced/", Code: http.StatusOK},
+	}...)
+
+	t.Run("invalid files aren't applied", func(t *testing.T) {
+		invalid := filepath.Join(syncDir, "invalid.json")
+		require.NoError(t, os.WriteFile(invalid, []byte(`{"api_id": "invalid", "proxy": {"listen_path": "/synced/"}}`), 0600))
+		defer os.Remove(invalid)
+
+		assert.ErrorContains(t, ts.Gw.syncFromDir(), "invalid.json")
+		assert.Nil(t, ts.Gw.getApiSpec("invalid"))
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		require.NoError(t, os.Remove(filepath.Join(syncDir, "api.json")))
+		require.NoError(t, os.Remove(filepath.Join(syncDir, "policy.json")))
+
+		require.NoError(t, ts.Gw.syncFromDir())
+
+		assert.Nil(t, ts.Gw.getApiSpec("synced"))
+		_, ok := ts.Gw.PolicyByID("synced-policy")
+		assert.False(t, ok)
+	})
+}
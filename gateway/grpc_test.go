@@ -16,7 +16,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/TykTechnologies/tyk/apidef"
 	"github.com/TykTechnologies/tyk/certs"
+	"github.com/TykTechnologies/tyk/internal/transcoding"
 
 	"github.com/TykTechnologies/tyk/config"
 
@@ -28,6 +30,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	pbexample "google.golang.org/grpc/examples/helloworld/helloworld"
+	"google.golang.org/protobuf/reflect/protoreflect"
 
 	"github.com/TykTechnologies/tyk/test"
 	"github.com/TykTechnologies/tyk/user"
@@ -138,6 +141,81 @@ func TestGRPC_H2C(t *testing.T) {
 	}
 }
 
+func TestGRPC_Transcoding_H2C(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	target, s := startGRPCServerH2C(t, setupHelloSVC)
+	defer target.Close()
+	defer s.Stop()
+
+	descriptor, err := transcoding.DescriptorSet([]protoreflect.FileDescriptor{
+		pbexample.File_examples_helloworld_helloworld_helloworld_proto,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+		spec.Name = "grpc_transcoding_api"
+		spec.Proxy.ListenPath = "/greeter/"
+		spec.Proxy.StripListenPath = true
+		spec.UseKeylessAccess = true
+		spec.Proxy.TargetURL = toTarget(t, "h2c", target)
+		spec.GRPCTranscoding = &apidef.GRPCTranscoding{
+			Enabled:    true,
+			Descriptor: base64.StdEncoding.EncodeToString(descriptor),
+		}
+	})
+
+	_, _ = ts.Run(t, []test.TestCase{
+		{
+			Method:    http.MethodPost,
+			Path:      "/greeter/helloworld.Greeter/SayHello",
+			Data:      `{"name": "Josh"}`,
+			Code:      http.StatusOK,
+			BodyMatch: `"message":\s*"Hello Josh"`,
+			HeadersMatch: map[string]string{
+				"Content-Type": "application/json",
+			},
+		},
+		{
+			Method:    http.MethodPost,
+			Path:      "/greeter/helloworld.Greeter/SayHello",
+			Data:      `{"name": `,
+			Code:      http.StatusBadRequest,
+			BodyMatch: `"code":\s*3`,
+		},
+		{
+			Method: http.MethodGet,
+			Path:   "/greeter/helloworld.Greeter/SayHello",
+			Code:   http.StatusNotFound,
+		},
+	}...)
+
+	t.Run("listen path not stripped", func(t *testing.T) {
+		ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+			spec.Name = "grpc_transcoding_api"
+			spec.Proxy.ListenPath = "/greeter/"
+			spec.Proxy.StripListenPath = false
+			spec.UseKeylessAccess = true
+			spec.Proxy.TargetURL = toTarget(t, "h2c", target) + "/base"
+			spec.GRPCTranscoding = &apidef.GRPCTranscoding{
+				Enabled:    true,
+				Descriptor: base64.StdEncoding.EncodeToString(descriptor),
+			}
+		})
+
+		_, _ = ts.Run(t, test.TestCase{
+			Method:    http.MethodPost,
+			Path:      "/greeter/helloworld.Greeter/SayHello",
+			Data:      `{"name": "Josh"}`,
+			Code:      http.StatusOK,
+			BodyMatch: `"message":\s*"Hello Josh"`,
+		})
+	})
+}
+
 // For gRPC, we should be sure that HTTP/2 works with Tyk.
 func TestHTTP2_TLS(t *testing.T) {
 
@@ -261,6 +261,9 @@ func (e *ErrorHandler) HandleError(w http.ResponseWriter, r *http.Request, errMs
 		rawRequest := ""
 		rawResponse := ""
 		if recordDetail(r, e.Spec) {
+			if timings := ctxGetMiddlewareTimings(r); timings != nil && e.Spec.GlobalConfig.MiddlewareTiming.Enabled {
+				record.Tags = append(record.Tags, timings.Tag())
+			}
 
 			// Get the wire format representation
 
@@ -313,6 +316,7 @@ func (e *ErrorHandler) HandleError(w http.ResponseWriter, r *http.Request, errMs
 	}
 
 	e.RecordAccessLog(r, response, analytics.Latency{})
+	e.Gw.publishRequestTap(e.Spec, r, response, analytics.Latency{})
 
 	// Report in health check
 	reportHealthValue(e.Spec, BlockedRequestLog, "-1")
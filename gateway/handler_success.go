@@ -230,6 +230,10 @@ func (s *SuccessHandler) RecordHit(r *http.Request, timing analytics.Latency, co
 		rawResponse := ""
 
 		if recordDetail(r, s.Spec) {
+			if timings := ctxGetMiddlewareTimings(r); timings != nil && s.Spec.GlobalConfig.MiddlewareTiming.Enabled {
+				tags = append(tags, timings.Tag())
+			}
+
 			// Get the wire format representation
 			var wireFormatReq bytes.Buffer
 			r.Write(&wireFormatReq)
@@ -391,6 +395,7 @@ func (s *SuccessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) *http
 	s.Spec.SanitizeProxyPaths(r)
 
 	addVersionHeader(w, r, s.Spec.GlobalConfig)
+	addServerTimingHeader(w, r, &s.Spec.GlobalConfig)
 
 	t1 := time.Now()
 	resp := s.Proxy.ServeHTTP(w, r)
@@ -422,6 +427,7 @@ func (s *SuccessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) *http
 		}
 		s.RecordHit(r, latency, resp.Response.StatusCode, resp.Response, false)
 		s.RecordAccessLog(r, resp.Response, latency)
+		s.Gw.publishRequestTap(s.Spec, r, resp.Response, latency)
 	}
 	log.Debug("Done proxy")
 
@@ -436,6 +442,8 @@ func (s *SuccessHandler) ServeHTTPWithCache(w http.ResponseWriter, r *http.Reque
 	// Make sure we get the correct target URL
 	s.Spec.SanitizeProxyPaths(r)
 
+	addServerTimingHeader(w, r, &s.Spec.GlobalConfig)
+
 	t1 := time.Now()
 	inRes := s.Proxy.ServeHTTPForCache(w, r)
 	t2 := time.Now()
@@ -464,6 +472,7 @@ func (s *SuccessHandler) ServeHTTPWithCache(w http.ResponseWriter, r *http.Reque
 			Gateway:  totalMs - upstreamMs,
 		}
 		s.RecordHit(r, latency, inRes.Response.StatusCode, inRes.Response, false)
+		s.Gw.publishRequestTap(s.Spec, r, inRes.Response, latency)
 	}
 
 	return inRes
@@ -79,7 +79,7 @@ func (tr TraceMiddleware) ProcessRequest(w http.ResponseWriter, r *http.Request,
 		if cfg.OpenTelemetry.Enabled {
 			otel.AddTraceID(r.Context(), w)
 			var span otel.Span
-			if baseMw.Spec.DetailedTracing {
+			if baseMw.Spec.DetailedTracing || cfg.MiddlewareTiming.Enabled {
 				var ctx context.Context
 				ctx, span = baseMw.Gw.TracerProvider.Tracer().Start(r.Context(), tr.Name())
 				defer span.End()
@@ -128,6 +128,7 @@ func (gw *Gateway) createMiddleware(actualMW TykMiddleware) func(http.Handler) h
 
 	spec := mw.GetSpec()
 	spec.AddUnloadHook(actualMW.Unload)
+	spec.middlewareNames = append(spec.middlewareNames, mw.Name())
 
 	// Pull the configuration
 	mwConf, err := mw.Config()
@@ -168,7 +169,16 @@ func (gw *Gateway) createMiddleware(actualMW TykMiddleware) func(http.Handler) h
 				return
 			}
 
-			err, errCode := mw.ProcessRequest(w, r, mwConf)
+			mwWriter := w
+			if mw.Base().Spec.GlobalConfig.MiddlewareTiming.ServerTimingHeader {
+				mwWriter = &serverTimingWriter{ResponseWriter: w, r: r, conf: &mw.Base().Spec.GlobalConfig, name: mw.Name(), start: startTime}
+			}
+
+			err, errCode := mw.ProcessRequest(mwWriter, r, mwConf)
+
+			if mw.Base().Spec.GlobalConfig.MiddlewareTiming.Enabled || gw.requestTaps.active(spec.APIID) {
+				ctxAddMiddlewareTiming(r, mw.Name(), time.Since(startTime))
+			}
 
 			if err != nil {
 				writeResponse := true
@@ -177,6 +187,10 @@ func (gw *Gateway) createMiddleware(actualMW TykMiddleware) func(http.Handler) h
 					writeResponse = false
 				}
 
+				if writeResponse {
+					addServerTimingHeader(w, r, &mw.Base().Spec.GlobalConfig)
+				}
+
 				handler := ErrorHandler{mw.Base()}
 				handler.HandleError(w, r, err.Error(), errCode, writeResponse)
 
@@ -435,7 +449,7 @@ func (t *BaseMiddleware) ApplyPolicies(session *user.SessionState) error {
 	if t.Spec != nil {
 		orgID = &t.Spec.OrgID
 	}
-	store := policy.New(orgID, t.Gw, log)
+	store := policy.New(orgID, scheduledPolicies{t.Gw}, log)
 	return store.Apply(session)
 }
 
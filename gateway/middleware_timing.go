@@ -0,0 +1,201 @@
+package gateway
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TykTechnologies/tyk/config"
+	"github.com/TykTechnologies/tyk/ctx"
+	"github.com/TykTechnologies/tyk/request"
+)
+
+const (
+	// serverTimingHeader is the response header carrying the middleware timings.
+	serverTimingHeader = "Server-Timing"
+
+	// middlewareTimingsTag prefixes the analytics tag carrying the middleware timings.
+	middlewareTimingsTag = "tyk-mw-timings:"
+)
+
+// middlewareTiming is the time spent executing a single middleware.
+type middlewareTiming struct {
+	Name     string
+	Duration time.Duration
+}
+
+// middlewareTimings collects the execution time of each middleware in a
+// request chain. Repeated executions of the same middleware are summed up.
+type middlewareTimings struct {
+	mu      sync.Mutex
+	entries []middlewareTiming
+}
+
+// Add records the duration spent in the middleware with the given name.
+func (m *middlewareTimings) Add(name string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.entries {
+		if m.entries[i].Name == name {
+			m.entries[i].Duration += d
+			return
+		}
+	}
+
+	m.entries = append(m.entries, middlewareTiming{Name: name, Duration: d})
+}
+
+// Entries returns a copy of the recorded timings in execution order.
+func (m *middlewareTimings) Entries() []middlewareTiming {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := make([]middlewareTiming, len(m.entries))
+	copy(entries, m.entries)
+	return entries
+}
+
+// ServerTiming formats the timings as a Server-Timing header value,
+// e.g. `AuthKey;dur=0.153, RateLimitAndQuotaCheck;dur=0.021`.
+func (m *middlewareTimings) ServerTiming() string {
+	return formatServerTiming(m.Entries())
+}
+
+func formatServerTiming(entries []middlewareTiming) string {
+	metrics := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ms := float64(e.Duration.Nanoseconds()) / float64(time.Millisecond)
+		metrics = append(metrics, fmt.Sprintf("%s;dur=%s", e.Name, strconv.FormatFloat(ms, 'f', 3, 64)))
+	}
+	return strings.Join(metrics, ", ")
+}
+
+// Tag formats the timings as a compact analytics tag,
+// e.g. `tyk-mw-timings:AuthKey=153,RateLimitAndQuotaCheck=21` with values in microseconds.
+func (m *middlewareTimings) Tag() string {
+	entries := m.Entries()
+	pairs := make([]string, 0, len(entries))
+	for _, e := range entries {
+		pairs = append(pairs, e.Name+"="+strconv.FormatInt(e.Duration.Microseconds(), 10))
+	}
+	return middlewareTimingsTag + strings.Join(pairs, ",")
+}
+
+func ctxGetMiddlewareTimings(r *http.Request) *middlewareTimings {
+	if v := r.Context().Value(ctx.MiddlewareTimings); v != nil {
+		if timings, ok := v.(*middlewareTimings); ok {
+			return timings
+		}
+	}
+	return nil
+}
+
+// ctxAddMiddlewareTiming records the time spent in a middleware, creating the
+// request timings on the first call.
+func ctxAddMiddlewareTiming(r *http.Request, name string, d time.Duration) {
+	timings := ctxGetMiddlewareTimings(r)
+	if timings == nil {
+		timings = &middlewareTimings{}
+		setCtxValue(r, ctx.MiddlewareTimings, timings)
+	}
+	timings.Add(name, d)
+}
+
+// addServerTimingHeader adds the Server-Timing header to the response if it is
+// enabled and the client is trusted to see the middleware timings. Running holds
+// the timing of a middleware writing the response itself.
+func addServerTimingHeader(w http.ResponseWriter, r *http.Request, conf *config.Config, running ...middlewareTiming) {
+	if !conf.MiddlewareTiming.Enabled || !conf.MiddlewareTiming.ServerTimingHeader {
+		return
+	}
+
+	var entries []middlewareTiming
+	if timings := ctxGetMiddlewareTimings(r); timings != nil {
+		entries = timings.Entries()
+	}
+	entries = append(entries, running...)
+	if len(entries) == 0 {
+		return
+	}
+
+	if !isServerTimingTrusted(request.RealIP(r), conf.MiddlewareTiming.TrustedIPs) {
+		return
+	}
+
+	w.Header().Add(serverTimingHeader, formatServerTiming(entries))
+}
+
+// serverTimingWriter adds the Server-Timing header to the responses written by a
+// middleware itself, such as mocks and cache hits, which bypass the rest of the chain.
+type serverTimingWriter struct {
+	http.ResponseWriter
+	r           *http.Request
+	conf        *config.Config
+	name        string
+	start       time.Time
+	wroteHeader bool
+}
+
+func (w *serverTimingWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		addServerTimingHeader(w.ResponseWriter, w.r, w.conf, middlewareTiming{Name: w.name, Duration: time.Since(w.start)})
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *serverTimingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *serverTimingWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *serverTimingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+// isServerTimingTrusted reports whether the client ip matches one of the trusted
+// IP addresses or CIDR ranges. An empty list only trusts loopback clients.
+func isServerTimingTrusted(ip string, trusted []string) bool {
+	remoteIP := net.ParseIP(ip)
+	if remoteIP == nil {
+		return false
+	}
+
+	if len(trusted) == 0 {
+		return remoteIP.IsLoopback()
+	}
+
+	for _, t := range trusted {
+		if _, trustedNet, err := net.ParseCIDR(t); err == nil {
+			if trustedNet.Contains(remoteIP) {
+				return true
+			}
+			continue
+		}
+
+		if trustedIP := net.ParseIP(t); trustedIP != nil && trustedIP.Equal(remoteIP) {
+			return true
+		}
+	}
+
+	return false
+}
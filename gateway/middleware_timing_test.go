@@ -0,0 +1,129 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/config"
+	"github.com/TykTechnologies/tyk/test"
+)
+
+func TestMiddlewareTimings(t *testing.T) {
+	timings := &middlewareTimings{}
+	timings.Add("AuthKey", 1500*time.Microsecond)
+	timings.Add("RateLimitAndQuotaCheck", 20*time.Microsecond)
+	timings.Add("AuthKey", 500*time.Microsecond)
+
+	entries := timings.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "AuthKey", entries[0].Name)
+	assert.Equal(t, 2*time.Millisecond, entries[0].Duration)
+
+	assert.Equal(t, "AuthKey;dur=2.000, RateLimitAndQuotaCheck;dur=0.020", timings.ServerTiming())
+	assert.Equal(t, "tyk-mw-timings:AuthKey=2000,RateLimitAndQuotaCheck=20", timings.Tag())
+}
+
+func TestCtxAddMiddlewareTiming(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, ctxGetMiddlewareTimings(r))
+
+	ctxAddMiddlewareTiming(r, "VersionCheck", time.Millisecond)
+	ctxAddMiddlewareTiming(r, "AuthKey", time.Millisecond)
+
+	timings := ctxGetMiddlewareTimings(r)
+	require.NotNil(t, timings)
+	assert.Len(t, timings.Entries(), 2)
+}
+
+func TestIsServerTimingTrusted(t *testing.T) {
+	testcases := []struct {
+		name    string
+		ip      string
+		trusted []string
+		expect  bool
+	}{
+		{"no trusted list", "10.0.0.1", nil, false},
+		{"no trusted list, loopback", "127.0.0.1", nil, true},
+		{"exact match", "10.0.0.1", []string{"10.0.0.1"}, true},
+		{"cidr match", "10.0.0.1", []string{"10.0.0.0/8"}, true},
+		{"no match", "192.168.1.1", []string{"10.0.0.0/8", "10.0.0.1"}, false},
+		{"invalid ip", "invalid", []string{"10.0.0.0/8"}, false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, isServerTimingTrusted(tc.ip, tc.trusted))
+		})
+	}
+}
+
+func TestServerTimingHeader(t *testing.T) {
+	ts := StartTest(func(globalConf *config.Config) {
+		globalConf.MiddlewareTiming.Enabled = true
+		globalConf.MiddlewareTiming.ServerTimingHeader = true
+	})
+	defer ts.Close()
+
+	ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+		spec.UseKeylessAccess = false
+		spec.Proxy.ListenPath = "/"
+	})
+
+	key := CreateSession(ts.Gw)
+
+	t.Run("successful request", func(t *testing.T) {
+		resp, err := ts.Run(t, test.TestCase{
+			Path: "/", Headers: map[string]string{"Authorization": key}, Code: http.StatusOK,
+		})
+		require.NoError(t, err)
+		assert.True(t, strings.Contains(resp.Header.Get(serverTimingHeader), "AuthKey;dur="))
+	})
+
+	t.Run("failed request", func(t *testing.T) {
+		resp, err := ts.Run(t, test.TestCase{
+			Path: "/", Code: http.StatusUnauthorized,
+		})
+		require.NoError(t, err)
+		assert.True(t, strings.Contains(resp.Header.Get(serverTimingHeader), "AuthKey;dur="))
+	})
+
+	t.Run("cached response", func(t *testing.T) {
+		ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+			spec.Proxy.ListenPath = "/cached/"
+			spec.CacheOptions.CacheTimeout = 60
+			spec.CacheOptions.EnableCache = true
+			spec.CacheOptions.CacheAllSafeRequests = true
+		})
+
+		_, _ = ts.Run(t, test.TestCase{Path: "/cached/", Code: http.StatusOK})
+
+		resp, err := ts.Run(t, test.TestCase{
+			Path: "/cached/", Code: http.StatusOK, HeadersMatch: map[string]string{cachedResponseHeader: "1"},
+		})
+		require.NoError(t, err)
+		assert.True(t, strings.Contains(resp.Header.Get(serverTimingHeader), "RedisCacheMiddleware;dur="))
+	})
+
+	t.Run("untrusted client", func(t *testing.T) {
+		globalConf := ts.Gw.GetConfig()
+		globalConf.MiddlewareTiming.TrustedIPs = []string{"10.10.10.10"}
+		ts.Gw.SetConfig(globalConf)
+
+		ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+			spec.UseKeylessAccess = false
+			spec.Proxy.ListenPath = "/"
+		})
+
+		resp, err := ts.Run(t, test.TestCase{
+			Path: "/", Headers: map[string]string{"Authorization": key}, Code: http.StatusOK,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Header.Get(serverTimingHeader))
+	})
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/TykTechnologies/tyk/config"
 	"github.com/TykTechnologies/tyk/ctx"
 	"github.com/TykTechnologies/tyk/internal/graphengine"
+	"github.com/TykTechnologies/tyk/internal/transcoding"
 )
 
 // APISpec represents a path specification for an API, to avoid enumerating multiple nested lists, a single
@@ -58,11 +59,20 @@ type APISpec struct {
 
 	unloadHooks []func()
 
+	// middlewareNames are the names of the middleware created for the spec, in order.
+	middlewareNames []string
+
 	network analytics.NetworkStats
 
 	GraphEngine graphengine.Engine
 
 	oasRouter routers.Router
+
+	// oasRequestBodyValidator validates the JSON request bodies of OpenAPI 3.1 APIs with JSON Schema 2020-12.
+	oasRequestBodyValidator *oas.RequestBodyValidator
+
+	// grpcTranscoder transcodes the requests to a gRPC upstream, when gRPC transcoding is enabled.
+	grpcTranscoder *transcoding.Transcoder
 }
 
 // CheckSpecMatchesStatus checks if a URL spec has a specific status.
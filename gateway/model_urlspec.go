@@ -33,6 +33,7 @@ type URLSpec struct {
 	TrackEndpoint             apidef.TrackEndpointMeta
 	DoNotTrackEndpoint        apidef.TrackEndpointMeta
 	ValidatePathMeta          apidef.ValidatePathMeta
+	ValidateXMLMeta           apidef.ValidateXMLMeta
 	Internal                  apidef.InternalMeta
 	GoPluginMeta              GoPluginMiddleware
 	PersistGraphQL            apidef.PersistGraphQLMeta
@@ -79,6 +80,8 @@ func (u *URLSpec) modeSpecificSpec(mode URLStatus) (interface{}, bool) {
 		return &u.DoNotTrackEndpoint, true
 	case ValidateJSONRequest:
 		return &u.ValidatePathMeta, true
+	case ValidateXMLRequest:
+		return &u.ValidateXMLMeta, true
 	case Internal:
 		return &u.Internal, true
 	case GoPlugin:
@@ -127,6 +130,8 @@ func (u *URLSpec) matchesMethod(method string) bool {
 		return method == u.DoNotTrackEndpoint.Method
 	case ValidateJSONRequest:
 		return method == u.ValidatePathMeta.Method
+	case ValidateXMLRequest:
+		return method == u.ValidateXMLMeta.Method
 	case Internal:
 		return method == u.Internal.Method
 	case GoPlugin:
@@ -0,0 +1,50 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/TykTechnologies/tyk/internal/middleware"
+)
+
+// ErrAPIInactive is returned while an API is inactive due to its activation schedule.
+var ErrAPIInactive = errors.New("API is unavailable due to scheduled maintenance")
+
+// ActivationScheduleMiddleware replies with the maintenance response while the API is inactive.
+// The state of the API is updated by the activation schedule checks.
+type ActivationScheduleMiddleware struct {
+	*BaseMiddleware
+}
+
+func (m *ActivationScheduleMiddleware) Name() string {
+	return "ActivationScheduleMiddleware"
+}
+
+func (m *ActivationScheduleMiddleware) EnabledForSpec() bool {
+	return !m.Spec.Schedule.IsZero()
+}
+
+// ProcessRequest will run any checks on the request on the way through the system, return an error to have the chain fail
+func (m *ActivationScheduleMiddleware) ProcessRequest(w http.ResponseWriter, r *http.Request, _ interface{}) (error, int) {
+	if !m.Gw.schedules.apiInactive(m.Spec.APIID) {
+		return nil, http.StatusOK
+	}
+
+	resp := m.Spec.Schedule.MaintenanceResponse
+	if resp == nil {
+		return ErrAPIInactive, resp.StatusCode()
+	}
+
+	for name, value := range resp.Headers {
+		w.Header().Set(name, value)
+	}
+
+	if resp.Body == "" {
+		return ErrAPIInactive, resp.StatusCode()
+	}
+
+	w.WriteHeader(resp.StatusCode())
+	_, _ = w.Write([]byte(resp.Body))
+
+	return nil, middleware.StatusRespond
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/TykTechnologies/tyk/apidef/oas"
 	"github.com/TykTechnologies/tyk/common/option"
@@ -21,11 +22,15 @@ var _ TykMiddleware = (*mockResponseMiddleware)(nil)
 
 type mockResponseMiddleware struct {
 	*BaseMiddleware
+
+	// store keeps the resources of the stateful mocks.
+	store *mockResourceStore
 }
 
 func newMockResponseMiddleware(base *BaseMiddleware, opts ...option.Option[mockResponseMiddleware]) TykMiddleware {
 	return option.New(opts).Build(mockResponseMiddleware{
 		BaseMiddleware: base,
+		store:          newMockResourceStore(),
 	})
 }
 
@@ -102,6 +107,13 @@ func (m *mockResponseMiddleware) mockResponse(r *http.Request) (*http.Response,
 		return nil, nil
 	}
 
+	if fromOASExamples := mockResponse.FromOASExamples; fromOASExamples != nil && fromOASExamples.Enabled && fromOASExamples.Stateful {
+		if res := m.store.respond(r, operation.route, operation.pathParams); res != nil {
+			m.Spec.sendRateLimitHeaders(ctxGetSession(r), res)
+			return res, nil
+		}
+	}
+
 	res := &http.Response{Header: http.Header{}}
 
 	var code int
@@ -242,7 +254,15 @@ func mockFromOAS(r *http.Request, operation *openapi3.Operation, fromOASExamples
 		}
 	}
 
-	// Nil check: if no example found, return error
+	// If no example found, generate one from the schema or build a fixed one
+	if example == nil && fromOASExamples.Generate {
+		seed := fromOASExamples.Seed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		example = oas.NewExampleGenerator(seed).Generate(media.Schema)
+	}
+
 	if example == nil {
 		example = oas.ExampleExtractor(media.Schema)
 	}
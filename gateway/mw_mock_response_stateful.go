@@ -0,0 +1,319 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+
+	"github.com/TykTechnologies/tyk/header"
+)
+
+const (
+	// mockMaxCollectionItems is the number of resources a collection of the stateful mocks keeps.
+	mockMaxCollectionItems = 1000
+	// mockMaxItems is the number of resources the stateful mocks of an API keep across collections.
+	mockMaxItems = 10000
+)
+
+// mockResourceStore keeps the resources of the stateful mocks in memory, by collection path.
+// The number of resources is capped, creating resources past the caps is rejected.
+type mockResourceStore struct {
+	mu          sync.Mutex
+	collections map[string]*mockCollection
+	size        int
+
+	maxCollectionItems int
+	maxItems           int
+}
+
+// mockCollection is the resources of a collection, in the order they were created.
+type mockCollection struct {
+	ids   []string
+	items map[string]map[string]interface{}
+	next  int
+}
+
+func newMockResourceStore() *mockResourceStore {
+	return &mockResourceStore{
+		collections:        map[string]*mockCollection{},
+		maxCollectionItems: mockMaxCollectionItems,
+		maxItems:           mockMaxItems,
+	}
+}
+
+// collection returns the collection of the path. Collections are only kept while they have resources,
+// an empty collection is returned for paths without resources.
+func (s *mockResourceStore) collection(path string) *mockCollection {
+	if c, ok := s.collections[path]; ok {
+		return c
+	}
+	return &mockCollection{items: map[string]map[string]interface{}{}}
+}
+
+// put creates or replaces a resource of a collection, it returns false when the resource is new and a cap is reached.
+func (s *mockResourceStore) put(path string, c *mockCollection, id string, item map[string]interface{}) bool {
+	if _, exists := c.items[id]; !exists {
+		if len(c.ids) >= s.maxCollectionItems || s.size >= s.maxItems {
+			return false
+		}
+
+		c.ids = append(c.ids, id)
+		s.size++
+		s.collections[path] = c
+	}
+
+	c.items[id] = item
+	return true
+}
+
+func (s *mockResourceStore) delete(path string, c *mockCollection, id string) bool {
+	if _, ok := c.items[id]; !ok {
+		return false
+	}
+
+	delete(c.items, id)
+	for i, existing := range c.ids {
+		if existing == id {
+			c.ids = append(c.ids[:i], c.ids[i+1:]...)
+			break
+		}
+	}
+
+	s.size--
+	if len(c.ids) == 0 {
+		delete(s.collections, path)
+	}
+	return true
+}
+
+func (c *mockCollection) list() []interface{} {
+	list := make([]interface{}, 0, len(c.ids))
+	for _, id := range c.ids {
+		list = append(list, c.items[id])
+	}
+	return list
+}
+
+// respond returns the response of a stateful mock, or nil when the operation isn't a collection
+// or item operation and the response is mocked from the OAS examples.
+//
+// A path ending with a path parameter, e.g. /pets/{id}, is an item of the collection of its parent
+// path. Other paths are collections, their GET operations are collection operations when they
+// respond with an array.
+func (s *mockResourceStore) respond(r *http.Request, route *routers.Route, pathParams map[string]string) *http.Response {
+	if route == nil || route.Operation == nil {
+		return nil
+	}
+
+	collectionPath, id, isItem := mockResourcePath(route.Path, pathParams)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collection(collectionPath)
+
+	switch {
+	case !isItem && r.Method == http.MethodGet:
+		if !mockRespondsWithArray(route.Operation) {
+			return nil
+		}
+		return mockJSONResponse(mockSuccessCode(route.Operation, http.StatusOK), c.list())
+
+	case !isItem && r.Method == http.MethodPost:
+		item, ok := mockRequestObject(r)
+		if !ok {
+			return nil
+		}
+
+		if value, ok := item["id"]; ok && value != nil {
+			id = fmt.Sprint(value)
+		} else {
+			c.next++
+			for c.items[strconv.Itoa(c.next)] != nil {
+				c.next++
+			}
+			id = strconv.Itoa(c.next)
+			item["id"] = mockID(route.Operation, id)
+		}
+
+		if !s.put(collectionPath, c, id, item) {
+			return mockStoreFull()
+		}
+		return mockJSONResponse(mockSuccessCode(route.Operation, http.StatusCreated), item)
+
+	case isItem && r.Method == http.MethodGet:
+		item, ok := c.items[id]
+		if !ok {
+			return mockNotFound()
+		}
+		return mockJSONResponse(mockSuccessCode(route.Operation, http.StatusOK), item)
+
+	case isItem && r.Method == http.MethodPut:
+		item, ok := mockRequestObject(r)
+		if !ok {
+			return nil
+		}
+
+		item["id"] = mockID(route.Operation, id)
+		if !s.put(collectionPath, c, id, item) {
+			return mockStoreFull()
+		}
+		return mockJSONResponse(mockSuccessCode(route.Operation, http.StatusOK), item)
+
+	case isItem && r.Method == http.MethodPatch:
+		existing, ok := c.items[id]
+		if !ok {
+			return mockNotFound()
+		}
+
+		patch, ok := mockRequestObject(r)
+		if !ok {
+			return nil
+		}
+
+		for key, value := range patch {
+			if key != "id" {
+				existing[key] = value
+			}
+		}
+		return mockJSONResponse(mockSuccessCode(route.Operation, http.StatusOK), existing)
+
+	case isItem && r.Method == http.MethodDelete:
+		if !s.delete(collectionPath, c, id) {
+			return mockNotFound()
+		}
+		return mockJSONResponse(mockSuccessCode(route.Operation, http.StatusNoContent), nil)
+	}
+
+	return nil
+}
+
+// mockResourcePath returns the collection path of the request, and the item ID when the path ends
+// with a path parameter.
+func mockResourcePath(path string, pathParams map[string]string) (collection string, id string, isItem bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = pathParams[strings.Trim(segment, "{}")]
+		}
+	}
+
+	last := path[strings.LastIndex(strings.TrimSuffix(path, "/"), "/")+1:]
+	if strings.HasPrefix(last, "{") && strings.HasSuffix(strings.TrimSuffix(last, "/"), "}") && len(segments) > 0 {
+		return "/" + strings.Join(segments[:len(segments)-1], "/"), segments[len(segments)-1], true
+	}
+
+	return "/" + strings.Join(segments, "/"), "", false
+}
+
+// mockRequestObject returns the JSON object of the request body.
+func mockRequestObject(r *http.Request) (map[string]interface{}, bool) {
+	if r.Body == nil {
+		return nil, false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil, false
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(body, &obj); err != nil || obj == nil {
+		return nil, false
+	}
+
+	return obj, true
+}
+
+// mockID returns the ID as an integer when the id property of the request body schema is an integer.
+func mockID(op *openapi3.Operation, id string) interface{} {
+	if op.RequestBody == nil || op.RequestBody.Value == nil {
+		return id
+	}
+
+	media := op.RequestBody.Value.Content.Get("application/json")
+	if media == nil || media.Schema == nil || media.Schema.Value == nil {
+		return id
+	}
+
+	prop := media.Schema.Value.Properties["id"]
+	if prop == nil || prop.Value == nil || !prop.Value.Type.Is(openapi3.TypeInteger) {
+		return id
+	}
+
+	if n, err := strconv.Atoi(id); err == nil {
+		return n
+	}
+	return id
+}
+
+// mockSuccessCode returns the first documented 2xx code of the operation, or the default code.
+func mockSuccessCode(op *openapi3.Operation, defaultCode int) int {
+	if op.Responses == nil {
+		return defaultCode
+	}
+
+	var codes []int
+	for key := range op.Responses.Map() {
+		if code, err := strconv.Atoi(key); err == nil && code >= 200 && code < 300 {
+			codes = append(codes, code)
+		}
+	}
+	sort.Ints(codes)
+
+	for _, code := range codes {
+		if code == defaultCode {
+			return code
+		}
+	}
+	if len(codes) > 0 {
+		return codes[0]
+	}
+
+	return defaultCode
+}
+
+// mockRespondsWithArray returns true if the successful JSON response of the operation is an array.
+func mockRespondsWithArray(op *openapi3.Operation) bool {
+	if op.Responses == nil {
+		return false
+	}
+
+	response := op.Responses.Value(strconv.Itoa(mockSuccessCode(op, http.StatusOK)))
+	if response == nil || response.Value == nil {
+		return false
+	}
+
+	media := response.Value.Content.Get("application/json")
+	return media != nil && media.Schema != nil && media.Schema.Value != nil && media.Schema.Value.Type.Is(openapi3.TypeArray)
+}
+
+func mockJSONResponse(code int, body interface{}) *http.Response {
+	res := &http.Response{StatusCode: code, Header: http.Header{}, Body: http.NoBody}
+	if body == nil || code == http.StatusNoContent {
+		return res
+	}
+
+	data, _ := json.Marshal(body)
+	res.Header.Set(header.ContentType, header.ApplicationJSON)
+	res.Body = io.NopCloser(bytes.NewReader(data))
+	return res
+}
+
+func mockNotFound() *http.Response {
+	return mockJSONResponse(http.StatusNotFound, map[string]string{"error": "resource not found"})
+}
+
+func mockStoreFull() *http.Response {
+	return mockJSONResponse(http.StatusInsufficientStorage, map[string]string{"error": "the mock resource limit is reached"})
+}
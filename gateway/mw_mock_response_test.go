@@ -2,10 +2,14 @@ package gateway
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -665,3 +669,158 @@ func TestMockResponseWithInternalRedirect(t *testing.T) {
 		})
 	})
 }
+
+func TestMockFromOAS_Generate(t *testing.T) {
+	minimum := 1.0
+	schema := &openapi3.SchemaRef{Value: &openapi3.Schema{
+		Type:     &openapi3.Types{openapi3.TypeObject},
+		Required: []string{"id", "email", "tags"},
+		Properties: openapi3.Schemas{
+			"id":    {Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeInteger}, Min: &minimum}},
+			"email": {Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}, Format: "email"}},
+			"tags":  {Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, MinItems: 1, Items: &openapi3.SchemaRef{Value: openapi3.NewStringSchema()}}},
+		},
+	}}
+
+	operation := openapi3.NewOperation()
+	responses := openapi3.NewResponses()
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: &openapi3.Response{
+			Content: openapi3.Content{
+				"application/json": {Schema: schema},
+			},
+		},
+	})
+	operation.Responses = responses
+
+	generate := func(fromOASExamples *oas.FromOASExamples) []byte {
+		t.Helper()
+
+		code, contentType, body, _, err := mockFromOAS(&http.Request{Header: http.Header{}}, operation, fromOASExamples)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "application/json", contentType)
+
+		var value interface{}
+		require.NoError(t, json.Unmarshal(body, &value))
+		assert.NoError(t, schema.Value.VisitJSON(value))
+
+		return body
+	}
+
+	t.Run("seeded", func(t *testing.T) {
+		fromOASExamples := &oas.FromOASExamples{Enabled: true, Generate: true, Seed: 42}
+		assert.Equal(t, generate(fromOASExamples), generate(fromOASExamples))
+	})
+
+	t.Run("random", func(t *testing.T) {
+		generate(&oas.FromOASExamples{Enabled: true, Generate: true})
+	})
+}
+
+func TestMockResponse_Stateful(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`{
+  "openapi": "3.0.3",
+  "info": {"title": "Pets", "version": "1.0.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "listPets",
+        "responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}}}}
+      },
+      "post": {
+        "operationId": "createPet",
+        "requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}},
+        "responses": {"201": {"description": "Created", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+      }
+    },
+    "/pets/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+      "get": {
+        "operationId": "getPet",
+        "responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+      },
+      "patch": {
+        "operationId": "updatePet",
+        "requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}},
+        "responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+      },
+      "delete": {
+        "operationId": "deletePet",
+        "responses": {"204": {"description": "Deleted"}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": "string"}}}
+    }
+  }
+}`))
+	require.NoError(t, err)
+
+	oasDoc := oas.OAS{T: *doc}
+
+	operations := oas.Operations{}
+	for _, operationID := range []string{"listPets", "createPet", "getPet", "updatePet", "deletePet"} {
+		operations[operationID] = &oas.Operation{
+			MockResponse: &oas.MockResponse{
+				Enabled:         true,
+				FromOASExamples: &oas.FromOASExamples{Enabled: true, Stateful: true},
+			},
+		}
+	}
+
+	oasDoc.SetTykExtension(&oas.XTykAPIGateway{
+		Middleware: &oas.Middleware{Operations: operations},
+	})
+
+	ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+		spec.Proxy.ListenPath = "/"
+		spec.IsOAS = true
+		spec.OAS = oasDoc
+	})
+
+	_, _ = ts.Run(t, []test.TestCase{
+		{Method: http.MethodGet, Path: "/pets", Code: http.StatusOK, BodyMatch: `^\[\]$`},
+		{Method: http.MethodPost, Path: "/pets", Data: `{"name":"Tom"}`, Code: http.StatusCreated, BodyMatch: `{"id":1,"name":"Tom"}`},
+		{Method: http.MethodGet, Path: "/pets/1", Code: http.StatusOK, BodyMatch: `{"id":1,"name":"Tom"}`},
+		{Method: http.MethodPatch, Path: "/pets/1", Data: `{"name":"Jerry"}`, Code: http.StatusOK, BodyMatch: `{"id":1,"name":"Jerry"}`},
+		{Method: http.MethodGet, Path: "/pets", Code: http.StatusOK, BodyMatch: `\[{"id":1,"name":"Jerry"}\]`},
+		{Method: http.MethodDelete, Path: "/pets/1", Code: http.StatusNoContent},
+		{Method: http.MethodGet, Path: "/pets/1", Code: http.StatusNotFound, BodyMatch: `"error":"resource not found"`},
+		{Method: http.MethodDelete, Path: "/pets/1", Code: http.StatusNotFound},
+	}...)
+}
+
+func TestMockResourceStore_Limits(t *testing.T) {
+	store := newMockResourceStore()
+	store.maxCollectionItems, store.maxItems = 2, 3
+
+	respond := func(method, path, route string, pathParams map[string]string) int {
+		r := httptest.NewRequest(method, path, strings.NewReader(`{"name":"Tom"}`))
+		res := store.respond(r, &routers.Route{Path: route, Operation: &openapi3.Operation{}}, pathParams)
+		require.NotNil(t, res)
+		return res.StatusCode
+	}
+
+	assert.Equal(t, http.StatusCreated, respond(http.MethodPost, "/pets", "/pets", nil))
+	assert.Equal(t, http.StatusCreated, respond(http.MethodPost, "/pets", "/pets", nil))
+	assert.Equal(t, http.StatusInsufficientStorage, respond(http.MethodPost, "/pets", "/pets", nil))
+	assert.Equal(t, http.StatusInsufficientStorage, respond(http.MethodPut, "/pets/3", "/pets/{id}", map[string]string{"id": "3"}))
+	assert.Equal(t, http.StatusOK, respond(http.MethodPut, "/pets/1", "/pets/{id}", map[string]string{"id": "1"}))
+
+	assert.Equal(t, http.StatusCreated, respond(http.MethodPost, "/owners", "/owners", nil))
+	assert.Equal(t, http.StatusInsufficientStorage, respond(http.MethodPost, "/owners", "/owners", nil))
+
+	// deleting a resource makes room for a new one
+	assert.Equal(t, http.StatusNoContent, respond(http.MethodDelete, "/pets/1", "/pets/{id}", map[string]string{"id": "1"}))
+	assert.Equal(t, http.StatusCreated, respond(http.MethodPost, "/owners", "/owners", nil))
+
+	// reads don't create collections
+	assert.Equal(t, http.StatusNotFound, respond(http.MethodGet, "/users/1", "/users/{id}", map[string]string{"id": "1"}))
+	assert.Len(t, store.collections, 2)
+}
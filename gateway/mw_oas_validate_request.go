@@ -1,8 +1,11 @@
 package gateway
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"strings"
 	"time"
@@ -95,16 +98,30 @@ func (k *ValidateRequest) ProcessRequest(w http.ResponseWriter, r *http.Request,
 
 	normalizeHeaders(r.Header)
 
+	if err := validateForm(r, operation.route.Operation); err != nil {
+		return fmt.Errorf("request validation error: %w", err), errResponseCode
+	}
+
+	options := &openapi3filter.Options{
+		AuthenticationFunc: func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+			return nil
+		},
+	}
+
+	if k.Spec.oasRequestBodyValidator != nil {
+		validated, err := k.validateRequestBody(r, operation)
+		if err != nil {
+			return fmt.Errorf("request validation error: request body has an error: %w", err), errResponseCode
+		}
+		options.ExcludeRequestBody = validated
+	}
+
 	// Validate request
 	requestValidationInput := &openapi3filter.RequestValidationInput{
 		Request:    r,
 		PathParams: operation.pathParams,
 		Route:      operation.route,
-		Options: &openapi3filter.Options{
-			AuthenticationFunc: func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
-				return nil
-			},
-		},
+		Options:    options,
 	}
 
 	err := openapi3filter.ValidateRequest(r.Context(), requestValidationInput)
@@ -116,6 +133,54 @@ func (k *ValidateRequest) ProcessRequest(w http.ResponseWriter, r *http.Request,
 	return nil, http.StatusOK
 }
 
+// validateRequestBody validates the JSON request bodies of OpenAPI 3.1 APIs with JSON Schema 2020-12.
+// It returns false if the body is left to the OpenAPI 3.0 validation.
+func (k *ValidateRequest) validateRequestBody(r *http.Request, operation *Operation) (bool, error) {
+	requestBody := operation.route.Operation.RequestBody
+	if r.Body == nil || requestBody == nil || requestBody.Value == nil {
+		return false, nil
+	}
+
+	mediaType := requestBodyMediaType(requestBody.Value.Content, r.Header.Get(header.ContentType))
+	if mediaType == "" {
+		return false, nil
+	}
+
+	nopCloseRequestBody(r)
+	body, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil || len(body) == 0 {
+		return false, err
+	}
+
+	return k.Spec.oasRequestBodyValidator.Validate(operation.route.Path, operation.route.Method, mediaType, body)
+}
+
+// requestBodyMediaType returns the key of the content matching the content type, the exact media type is
+// preferred over the media ranges.
+func requestBodyMediaType(content openapi3.Content, contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	candidates := []string{mediaType}
+	if i := strings.Index(mediaType, "/"); i > 0 {
+		candidates = append(candidates, mediaType[:i]+"/*")
+	}
+	candidates = append(candidates, "*/*")
+
+	for _, candidate := range candidates {
+		for key := range content {
+			if parsed, _, err := mime.ParseMediaType(key); err == nil && parsed == candidate {
+				return key
+			}
+		}
+	}
+
+	return ""
+}
+
 // normalizeHeaders prepares HTTP headers for OpenAPI validation by joining multiple values with commas.
 // Headers in the skipHeaderNormalization map are excluded from this process.
 func normalizeHeaders(headers http.Header) {
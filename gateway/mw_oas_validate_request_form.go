@@ -0,0 +1,171 @@
+package gateway
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/TykTechnologies/tyk/header"
+)
+
+const (
+	contentTypeMultipartForm  = "multipart/form-data"
+	contentTypeURLEncodedForm = "application/x-www-form-urlencoded"
+
+	// extensionMaxFileSize is the schema extension limiting the size in bytes of a file part,
+	// it takes precedence over the maxLength of binary parts.
+	extensionMaxFileSize = "x-tyk-max-file-size"
+)
+
+// validateForm validates the parts of multipart/form-data and the fields of application/x-www-form-urlencoded
+// request bodies that the JSON schema validation can't express: the required parts, the content types
+// of the parts listed by the encoding of the media type, and the size of the file parts.
+func validateForm(r *http.Request, operation *openapi3.Operation) error {
+	if r.Body == nil || operation.RequestBody == nil || operation.RequestBody.Value == nil {
+		return nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get(header.ContentType))
+	if err != nil || (mediaType != contentTypeMultipartForm && mediaType != contentTypeURLEncodedForm) {
+		return nil
+	}
+
+	media := operation.RequestBody.Value.Content.Get(mediaType)
+	if media == nil || media.Schema == nil || media.Schema.Value == nil {
+		return nil
+	}
+
+	nopCloseRequestBody(r)
+	body, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	if len(body) == 0 {
+		return nil
+	}
+
+	present := map[string]bool{}
+
+	if mediaType == contentTypeURLEncodedForm {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return fmt.Errorf("request body has an error: %w", err)
+		}
+		for name := range values {
+			present[name] = true
+		}
+	} else {
+		reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("request body has an error: %w", err)
+			}
+
+			name := part.FormName()
+			present[name] = true
+
+			if err := validateFormPart(part, media.Schema.Value.Properties[name], media.Encoding[name]); err != nil {
+				return fmt.Errorf("request body has an error: part %s: %w", name, err)
+			}
+		}
+	}
+
+	for _, name := range media.Schema.Value.Required {
+		if !present[name] {
+			return fmt.Errorf("request body has an error: part %s: required part is missing", name)
+		}
+	}
+
+	return nil
+}
+
+// validateFormPart validates the content type and the size of a part.
+func validateFormPart(part *multipart.Part, schema *openapi3.SchemaRef, encoding *openapi3.Encoding) error {
+	contentType := part.Header.Get(header.ContentType)
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+
+	if encoding != nil && encoding.ContentType != "" && !matchContentType(contentType, encoding.ContentType) {
+		return fmt.Errorf("content type %q is not one of %s", contentType, encoding.ContentType)
+	}
+
+	if schema == nil || schema.Value == nil {
+		return nil
+	}
+
+	fileSchema := schema.Value
+	if fileSchema.Type.Is(openapi3.TypeArray) && fileSchema.Items != nil && fileSchema.Items.Value != nil {
+		fileSchema = fileSchema.Items.Value
+	}
+
+	limit, ok := maxFileSize(fileSchema)
+	if !ok {
+		return nil
+	}
+
+	size, err := io.Copy(io.Discard, io.LimitReader(part, limit+1))
+	if err != nil {
+		return err
+	}
+
+	if size > limit {
+		return fmt.Errorf("file size exceeds the limit of %d bytes", limit)
+	}
+
+	return nil
+}
+
+// maxFileSize returns the size limit of a file part, from the x-tyk-max-file-size extension or the
+// maxLength of a binary string.
+func maxFileSize(schema *openapi3.Schema) (int64, bool) {
+	switch limit := schema.Extensions[extensionMaxFileSize].(type) {
+	case float64:
+		return int64(limit), true
+	case int64:
+		return limit, true
+	case int:
+		return int64(limit), true
+	}
+
+	if schema.MaxLength != nil && schema.Format == "binary" {
+		return int64(*schema.MaxLength), true
+	}
+
+	return 0, false
+}
+
+// matchContentType returns true if the content type matches one of a comma separated list of
+// media ranges, e.g. image/png, image/*.
+func matchContentType(contentType, allowed string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, allowedType := range strings.Split(allowed, ",") {
+		allowedType = strings.ToLower(strings.TrimSpace(allowedType))
+		switch {
+		case allowedType == "*/*", allowedType == mediaType:
+			return true
+		case strings.HasSuffix(allowedType, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(allowedType, "*")):
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,8 +1,15 @@
 package gateway
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
 	"testing"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -407,3 +414,232 @@ func TestValidateRequest_NormalizeHeaders(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRequest_Form(t *testing.T) {
+	const spec = `{
+  "openapi": "3.0.0",
+  "info": {"title": "upload", "version": "1.0.0"},
+  "paths": {
+    "/upload": {
+      "post": {
+        "requestBody": {
+          "content": {
+            "multipart/form-data": {
+              "schema": {
+                "type": "object",
+                "required": ["name", "file"],
+                "properties": {
+                  "name": {"type": "string"},
+                  "file": {"type": "string", "format": "binary", "maxLength": 8},
+                  "attachments": {"type": "array", "items": {"type": "string", "format": "binary", "x-tyk-max-file-size": 4}}
+                }
+              },
+              "encoding": {
+                "file": {"contentType": "image/png, image/jpeg"},
+                "attachments": {"contentType": "text/*"}
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "type": "object",
+                "required": ["name"],
+                "properties": {
+                  "name": {"type": "string"}
+                }
+              }
+            }
+          }
+        },
+        "responses": {"200": {"description": "OK"}}
+      }
+    }
+  }
+}`
+
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	assert.NoError(t, err)
+	operation := doc.Paths.Find("/upload").Post
+
+	type part struct {
+		name, contentType, content string
+	}
+
+	multipartRequest := func(parts ...part) *http.Request {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		for _, p := range parts {
+			h := textproto.MIMEHeader{}
+			h.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename="%s.bin"`, p.name, p.name))
+			if p.contentType != "" {
+				h.Set(header.ContentType, p.contentType)
+			}
+			w, _ := writer.CreatePart(h)
+			_, _ = w.Write([]byte(p.content))
+		}
+		_ = writer.Close()
+
+		r := httptest.NewRequest(http.MethodPost, "/upload", &body)
+		r.Header.Set(header.ContentType, writer.FormDataContentType())
+		return r
+	}
+
+	urlEncodedRequest := func(body string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(body))
+		r.Header.Set(header.ContentType, contentTypeURLEncodedForm)
+		return r
+	}
+
+	cases := []struct {
+		name    string
+		request *http.Request
+		err     string
+	}{
+		{
+			name: "valid multipart",
+			request: multipartRequest(
+				part{name: "name", content: "tyk"},
+				part{name: "file", contentType: "image/png", content: "12345678"},
+				part{name: "attachments", contentType: "text/plain", content: "abcd"},
+			),
+		},
+		{
+			name:    "missing part",
+			request: multipartRequest(part{name: "name", content: "tyk"}),
+			err:     "request body has an error: part file: required part is missing",
+		},
+		{
+			name: "part content type",
+			request: multipartRequest(
+				part{name: "name", content: "tyk"},
+				part{name: "file", contentType: "image/gif", content: "1234"},
+			),
+			err: `request body has an error: part file: content type "image/gif" is not one of image/png, image/jpeg`,
+		},
+		{
+			name: "file size from maxLength",
+			request: multipartRequest(
+				part{name: "name", content: "tyk"},
+				part{name: "file", contentType: "image/jpeg", content: "123456789"},
+			),
+			err: "request body has an error: part file: file size exceeds the limit of 8 bytes",
+		},
+		{
+			name: "file size from extension",
+			request: multipartRequest(
+				part{name: "name", content: "tyk"},
+				part{name: "file", contentType: "image/jpeg", content: "1234"},
+				part{name: "attachments", contentType: "text/csv", content: "abcde"},
+			),
+			err: "request body has an error: part attachments: file size exceeds the limit of 4 bytes",
+		},
+		{
+			name:    "valid urlencoded",
+			request: urlEncodedRequest("name=tyk"),
+		},
+		{
+			name:    "missing field",
+			request: urlEncodedRequest("other=tyk"),
+			err:     "request body has an error: part name: required part is missing",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateForm(tc.request, operation)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+
+			// the body must remain readable by the next validation steps
+			body, err := io.ReadAll(tc.request.Body)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, body)
+		})
+	}
+}
+
+func TestValidateRequest_OpenAPI31(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	const spec = `{
+  "openapi": "3.1.0",
+  "info": {"title": "pets", "version": "1.0.0"},
+  "paths": {
+    "/pets": {
+      "post": {
+        "operationId": "createPet",
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {"$ref": "#/components/schemas/Pet"}
+            }
+          }
+        },
+        "responses": {"200": {"description": "OK"}}
+      }
+    },
+    "/pets/{id}": {
+      "get": {
+        "operationId": "getPet",
+        "parameters": [
+          {"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "exclusiveMinimum": 0}}
+        ],
+        "responses": {"200": {"description": "OK"}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "required": ["name"],
+        "properties": {
+          "name": {"type": "string"},
+          "owner": {"type": ["string", "null"]},
+          "kind": {"const": "cat"}
+        },
+        "unevaluatedProperties": false
+      }
+    }
+  }
+}`
+
+	doc, err := oas.LoadFromData(openapi3.NewLoader(), []byte(spec))
+	assert.NoError(t, err)
+
+	oasAPI := oas.OAS{T: *doc}
+	oasAPI.SetTykExtension(&oas.XTykAPIGateway{
+		Middleware: &oas.Middleware{
+			Operations: oas.Operations{
+				"createPet": {ValidateRequest: &oas.ValidateRequest{Enabled: true}},
+				"getPet":    {ValidateRequest: &oas.ValidateRequest{Enabled: true}},
+			},
+		},
+	})
+	assert.NoError(t, oasAPI.Validate(context.Background()))
+
+	var def apidef.APIDefinition
+	oasAPI.ExtractTo(&def)
+
+	ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+		spec.VersionData = def.VersionData
+		spec.OAS = oasAPI
+		spec.IsOAS = true
+		spec.Proxy.ListenPath = "/"
+		spec.UseKeylessAccess = true
+	})
+
+	headers := map[string]string{header.ContentType: "application/json"}
+
+	_, _ = ts.Run(t, []test.TestCase{
+		{Method: http.MethodPost, Path: "/pets", Headers: headers, Data: `{"name":"Tom","owner":null,"kind":"cat"}`, Code: http.StatusOK},
+		{Method: http.MethodPost, Path: "/pets", Headers: headers, Data: `{"name":"Tom","kind":"dog"}`, Code: http.StatusUnprocessableEntity},
+		{Method: http.MethodPost, Path: "/pets", Headers: headers, Data: `{"name":"Tom","color":"black"}`, Code: http.StatusUnprocessableEntity},
+		{Method: http.MethodPost, Path: "/pets", Headers: headers, Data: `{"owner":"Jerry"}`, Code: http.StatusUnprocessableEntity},
+		{Method: http.MethodGet, Path: "/pets/1", Code: http.StatusOK},
+		{Method: http.MethodGet, Path: "/pets/0", Code: http.StatusUnprocessableEntity},
+	}...)
+}
@@ -1023,6 +1023,63 @@ func TestStreamingAPIGarbageCollection(t *testing.T) {
 	require.Equal(t, 0, streamManagersAfterGC)
 }
 
+func TestStreaming_AsyncAPIExport(t *testing.T) {
+	ts := StartTest(func(globalConf *config.Config) {
+		globalConf.Streaming.Enabled = true
+	})
+	t.Cleanup(ts.Close)
+
+	oasAPI, err := setupOASForStreamAPI(bentoHTTPServerTemplate)
+	require.NoError(t, err)
+
+	ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+		spec.APIID = "streams-api"
+		spec.Proxy.ListenPath = "/streams-api/"
+		spec.UseKeylessAccess = true
+		spec.IsOAS = true
+		spec.OAS = oasAPI
+		spec.OAS.Fill(*spec.APIDefinition)
+	}, func(spec *APISpec) {
+		spec.APIID = "http-api"
+		spec.Proxy.ListenPath = "/http-api/"
+		spec.IsOAS = true
+		spec.OAS = oas.OAS{T: openapi3.T{OpenAPI: "3.0.3", Info: &openapi3.Info{Title: "http", Version: "1"}, Paths: openapi3.NewPaths()}}
+		spec.OAS.Fill(*spec.APIDefinition)
+	})
+
+	_, _ = ts.Run(t, []test.TestCase{
+		{AdminAuth: true, Method: http.MethodGet, Path: "/tyk/apis/oas/streams-api/export?format=asyncapi", Code: http.StatusOK,
+			HeadersMatch: map[string]string{"Content-Disposition": `attachment;filename="asyncapi-streams-api.json"`},
+			BodyMatchFunc: func(body []byte) bool {
+				doc := struct {
+					AsyncAPI string `json:"asyncapi"`
+					Servers  map[string]struct {
+						Host     string `json:"host"`
+						Pathname string `json:"pathname"`
+					} `json:"servers"`
+					Operations map[string]struct {
+						Action string `json:"action"`
+					} `json:"operations"`
+					Channels map[string]struct {
+						Address string `json:"address"`
+					} `json:"channels"`
+				}{}
+				require.NoError(t, json.Unmarshal(body, &doc))
+
+				assert.Equal(t, "3.0.0", doc.AsyncAPI)
+				assert.Equal(t, buildServerRegenerationConfig(ts.Gw.GetConfig()).DefaultHost, doc.Servers["gateway"].Host)
+				assert.Equal(t, "/streams-api", doc.Servers["gateway"].Pathname)
+				assert.Equal(t, "send", doc.Operations["test"].Action)
+				assert.Equal(t, "/post", doc.Channels["test"].Address)
+				return true
+			}},
+		{AdminAuth: true, Method: http.MethodGet, Path: "/tyk/apis/oas/http-api/export?format=asyncapi", Code: http.StatusBadRequest,
+			BodyMatch: "the API has no streams served over HTTP"},
+		{AdminAuth: true, Method: http.MethodGet, Path: "/tyk/apis/oas/export?format=asyncapi", Code: http.StatusBadRequest,
+			BodyMatch: "AsyncAPI export requires an API ID"},
+	}...)
+}
+
 func TestStreaming_HttpOutputPaths(t *testing.T) {
 	ts := StartTest(func(globalConf *config.Config) {
 		globalConf.Streaming.Enabled = true
@@ -0,0 +1,71 @@
+package gateway
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/internal/xsd"
+)
+
+// ValidateXML validates the XML request bodies, or the payload of SOAP envelopes, against XML schemas.
+type ValidateXML struct {
+	*BaseMiddleware
+}
+
+func (k *ValidateXML) Name() string {
+	return "ValidateXML"
+}
+
+func (k *ValidateXML) EnabledForSpec() bool {
+	for _, v := range k.Spec.VersionData.Versions {
+		if len(v.ExtendedPaths.ValidateXML) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ProcessRequest will run any checks on the request on the way through the system, return an error to have the chain fail
+func (k *ValidateXML) ProcessRequest(_ http.ResponseWriter, r *http.Request, _ interface{}) (error, int) {
+	versionInfo, _ := k.Spec.Version(r)
+	versionPaths := k.Spec.RxPaths[versionInfo.Name]
+	found, meta := k.Spec.CheckSpecMatchesStatus(r, versionPaths, ValidateXMLRequest)
+	if !found {
+		return nil, http.StatusOK
+	}
+
+	vPathMeta := meta.(*apidef.ValidateXMLMeta)
+	if vPathMeta.SchemaCache == nil {
+		return errors.New("no schemas to validate against"), http.StatusInternalServerError
+	}
+
+	nopCloseRequestBody(r)
+	bodyBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err, http.StatusBadRequest
+	}
+	r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+	defer r.Body.Close()
+
+	err = vPathMeta.SchemaCache.Validate(bodyBytes, vPathMeta.Element)
+	if err == nil {
+		return nil, http.StatusOK
+	}
+
+	// Handle Failure
+	var validationErrs xsd.Errors
+	if !errors.As(err, &validationErrs) {
+		return fmt.Errorf("XML parsing error: %w", err), http.StatusBadRequest
+	}
+
+	if vPathMeta.ErrorResponseCode == 0 {
+		vPathMeta.ErrorResponseCode = http.StatusUnprocessableEntity
+	}
+
+	return validationErrs, vPathMeta.ErrorResponseCode
+}
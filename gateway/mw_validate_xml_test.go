@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/test"
+)
+
+const testXMLSchema = `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/stock" elementFormDefault="qualified">
+  <xs:element name="GetPrice">
+    <xs:complexType>
+      <xs:sequence>
+        <xs:element name="Symbol" type="xs:string"/>
+        <xs:element name="Quantity" type="xs:positiveInteger" minOccurs="0"/>
+      </xs:sequence>
+    </xs:complexType>
+  </xs:element>
+</xs:schema>`
+
+func (ts *Test) testPrepareValidateXMLSchema(enabled bool) {
+	ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+		UpdateAPIVersion(spec, "v1", func(v *apidef.VersionInfo) {
+			v.ExtendedPaths.ValidateXML = []apidef.ValidateXMLMeta{
+				{
+					Disabled: !enabled,
+					Path:     "/v",
+					Method:   http.MethodPost,
+					Schema:   testXMLSchema,
+					Element:  "GetPrice",
+				},
+			}
+		})
+
+		spec.Proxy.ListenPath = "/"
+	})
+}
+
+func TestValidateXMLSchema(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	ts.testPrepareValidateXMLSchema(true)
+
+	_, _ = ts.Run(t, []test.TestCase{
+		{Method: http.MethodPost, Path: "/without_validation", Data: "<not_valid>", Code: http.StatusOK},
+		{Method: http.MethodPost, Path: "/v", Data: `<GetPrice><Symbol>TYK</Symbol><Quantity>5</Quantity></GetPrice>`, Code: http.StatusOK},
+		{
+			Method: http.MethodPost, Path: "/v",
+			Data: `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><GetPrice><Symbol>TYK</Symbol></GetPrice></soap:Body></soap:Envelope>`,
+			Code: http.StatusOK,
+		},
+		{Method: http.MethodPost, Path: "/v", Data: `<GetPrice><Quantity>5</Quantity></GetPrice>`, BodyMatch: `unexpected element Quantity, expected Symbol`, Code: http.StatusUnprocessableEntity},
+		{Method: http.MethodPost, Path: "/v", Data: `<GetPrice><Symbol>TYK</Symbol><Quantity>0</Quantity></GetPrice>`, BodyMatch: `is not a valid positiveInteger`, Code: http.StatusUnprocessableEntity},
+		{Method: http.MethodPost, Path: "/v", Data: `<GetPrice>`, BodyMatch: `XML parsing error`, Code: http.StatusBadRequest},
+	}...)
+
+	t.Run("disabled", func(t *testing.T) {
+		ts.testPrepareValidateXMLSchema(false)
+
+		_, _ = ts.Run(t, []test.TestCase{
+			{Method: http.MethodPost, Path: "/v", Data: `<GetPrice/>`, Code: http.StatusOK},
+		}...)
+	})
+}
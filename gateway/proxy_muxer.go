@@ -30,7 +30,8 @@ import (
 
 // handleWrapper's only purpose is to allow router to be dynamically replaced
 type handleWrapper struct {
-	router http.Handler // *mux.Router
+	// router is swapped atomically on reloads, in-flight requests keep the router they started with.
+	router atomic.Pointer[mux.Router]
 
 	maxContentLength   int64
 	maxRequestBodySize int64
@@ -94,11 +95,12 @@ func (h *handleWrapper) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Test don't provide a router
-	if h.router == nil {
+	router := h.router.Load()
+	if router == nil {
 		return
 	}
 
-	h.router.ServeHTTP(w, r)
+	router.ServeHTTP(w, r)
 }
 
 type proxy struct {
@@ -403,9 +405,9 @@ func (m *proxyMux) swap(new *proxyMux, gw *Gateway) {
 			if match.httpServer != nil {
 				switch e := match.httpServer.Handler.(type) {
 				case *handleWrapper:
-					e.router = newP.router
+					e.router.Store(newP.router)
 				case *h2cWrapper:
-					e.w.router = newP.router
+					e.w.router.Store(newP.router)
 				}
 			}
 		}
@@ -450,9 +452,9 @@ func (m *proxyMux) serve(gw *Gateway) {
 			}
 
 			h := &handleWrapper{
-				router:             p.router,
 				maxRequestBodySize: conf.HttpServerOptions.MaxRequestBodySize,
 			}
+			h.router.Store(p.router)
 
 			// by default enabling h2c by wrapping handler in h2c. This ensures all features including tracing work
 			// in h2c services.
@@ -0,0 +1,430 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+
+	"github.com/TykTechnologies/tyk/header"
+	"github.com/TykTechnologies/tyk/request"
+	"github.com/TykTechnologies/tyk/storage"
+)
+
+const (
+	// requestTapDefaultDuration is how long a tap streams for when no duration is requested.
+	requestTapDefaultDuration = time.Minute
+	// requestTapMaxDuration is the longest a single tap can stream for.
+	requestTapMaxDuration = 10 * time.Minute
+	// requestTapDefaultRate is the number of events per second streamed when no rate is requested.
+	requestTapDefaultRate = 10
+	// requestTapMaxRate is the highest number of events per second a single tap can stream.
+	requestTapMaxRate = 100
+	// requestTapBufferSize is the number of events buffered for a slow tap consumer before they're dropped.
+	requestTapBufferSize = 64
+
+	requestTapRedactedValue = "<redacted>"
+)
+
+// requestTapSensitiveHeaders are always redacted from tapped requests and responses.
+var requestTapSensitiveHeaders = []string{
+	header.Authorization,
+	"Proxy-Authorization",
+	header.Cookie,
+	header.SetCookie,
+	header.XTykAuthorization,
+}
+
+var (
+	errRequestTapStatus     = errors.New("status must be a status code or a class such as 5xx")
+	errRequestTapSampleRate = errors.New("sample_rate must be greater than 0 and at most 1")
+	errRequestTapDuration   = fmt.Errorf("duration must be positive and at most %s", requestTapMaxDuration)
+	errRequestTapRate       = fmt.Errorf("rate must be between 1 and %d events per second", requestTapMaxRate)
+)
+
+// requestTapOptions holds the filters and bounds of a request tap.
+type requestTapOptions struct {
+	// Path matches the request path, unset matches all paths.
+	Path *regexp.Regexp
+	// Status matches an exact response status code.
+	Status int
+	// StatusClass matches a response status class, e.g. 5 for 5xx.
+	StatusClass int
+	// KeyHash matches the hash of the key used by the request.
+	KeyHash string
+	// SampleRate is the fraction of matching requests that are streamed.
+	SampleRate float64
+	// Duration is how long the tap streams for.
+	Duration time.Duration
+	// Rate is the maximum number of events streamed per second.
+	Rate int
+}
+
+// parseRequestTapOptions reads the tap options from the query string, applying defaults and bounds.
+func parseRequestTapOptions(q url.Values) (requestTapOptions, error) {
+	opts := requestTapOptions{
+		SampleRate: 1,
+		Duration:   requestTapDefaultDuration,
+		Rate:       requestTapDefaultRate,
+	}
+
+	if v := q.Get("path"); v != "" {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid path filter: %w", err)
+		}
+		opts.Path = re
+	}
+
+	if v := strings.ToLower(q.Get("status")); v != "" {
+		if len(v) == 3 && strings.HasSuffix(v, "xx") && v[0] >= '1' && v[0] <= '5' {
+			opts.StatusClass = int(v[0] - '0')
+		} else {
+			code, err := strconv.Atoi(v)
+			if err != nil || code < 100 || code > 599 {
+				return opts, errRequestTapStatus
+			}
+			opts.Status = code
+		}
+	}
+
+	opts.KeyHash = q.Get("key_hash")
+
+	if v := q.Get("sample_rate"); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil || rate <= 0 || rate > 1 {
+			return opts, errRequestTapSampleRate
+		}
+		opts.SampleRate = rate
+	}
+
+	if v := q.Get("duration"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			// plain numbers are seconds
+			secs, serr := strconv.Atoi(v)
+			if serr != nil {
+				return opts, errRequestTapDuration
+			}
+			d = time.Duration(secs) * time.Second
+		}
+		if d <= 0 || d > requestTapMaxDuration {
+			return opts, errRequestTapDuration
+		}
+		opts.Duration = d
+	}
+
+	if v := q.Get("rate"); v != "" {
+		rate, err := strconv.Atoi(v)
+		if err != nil || rate < 1 || rate > requestTapMaxRate {
+			return opts, errRequestTapRate
+		}
+		opts.Rate = rate
+	}
+
+	return opts, nil
+}
+
+// match reports whether the event passes the path, status and key hash filters.
+func (o *requestTapOptions) match(ev *requestTapEvent) bool {
+	if o.Path != nil && !o.Path.MatchString(ev.Request.Path) {
+		return false
+	}
+
+	if o.Status != 0 && ev.Response.Status != o.Status {
+		return false
+	}
+
+	if o.StatusClass != 0 && ev.Response.Status/100 != o.StatusClass {
+		return false
+	}
+
+	if o.KeyHash != "" && ev.KeyHash != o.KeyHash {
+		return false
+	}
+
+	return true
+}
+
+// requestTapEvent is a single tapped request as streamed to the client.
+type requestTapEvent struct {
+	Timestamp  time.Time                 `json:"timestamp"`
+	APIID      string                    `json:"api_id"`
+	KeyHash    string                    `json:"key_hash,omitempty"`
+	Request    requestTapRequest         `json:"request"`
+	Response   requestTapResponse        `json:"response"`
+	Latency    requestTapLatency         `json:"latency"`
+	Middleware []requestTapMiddlewareRun `json:"middleware"`
+}
+
+type requestTapRequest struct {
+	Method        string      `json:"method"`
+	Path          string      `json:"path"`
+	Query         string      `json:"query,omitempty"`
+	Headers       http.Header `json:"headers"`
+	RemoteAddr    string      `json:"remote_addr"`
+	ContentLength int64       `json:"content_length"`
+}
+
+type requestTapResponse struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers"`
+}
+
+type requestTapLatency struct {
+	TotalMs    int64 `json:"total_ms"`
+	UpstreamMs int64 `json:"upstream_ms"`
+	GatewayMs  int64 `json:"gateway_ms"`
+}
+
+// requestTapMiddlewareRun is a middleware the request went through, in execution order.
+type requestTapMiddlewareRun struct {
+	Name       string `json:"name"`
+	DurationUs int64  `json:"duration_us"`
+}
+
+// newRequestTapEvent builds a redacted tap event from the request and its response.
+func newRequestTapEvent(spec *APISpec, r *http.Request, resp *http.Response, latency analytics.Latency) *requestTapEvent {
+	token := ctxGetAuthToken(r)
+	u := requestTapURL(r)
+
+	ev := &requestTapEvent{
+		Timestamp: time.Now(),
+		APIID:     spec.APIID,
+		Request: requestTapRequest{
+			Method:        r.Method,
+			Path:          u.Path,
+			Query:         redactRequestTapQuery(u.Query(), token),
+			Headers:       redactRequestTapHeaders(r.Header, token),
+			RemoteAddr:    request.RealIP(r),
+			ContentLength: r.ContentLength,
+		},
+		Latency: requestTapLatency{
+			TotalMs:    latency.Total,
+			UpstreamMs: latency.Upstream,
+			GatewayMs:  latency.Gateway,
+		},
+		Middleware: []requestTapMiddlewareRun{},
+	}
+
+	if token != "" {
+		ev.KeyHash = storage.HashStr(token)
+	}
+
+	if resp != nil {
+		ev.Response.Status = resp.StatusCode
+		ev.Response.Headers = redactRequestTapHeaders(resp.Header, token)
+	}
+
+	if ev.Latency.TotalMs == 0 {
+		if start := ctxGetRequestStartTime(r); !start.IsZero() {
+			ev.Latency.TotalMs = time.Since(start).Milliseconds()
+			ev.Latency.GatewayMs = ev.Latency.TotalMs - ev.Latency.UpstreamMs
+		}
+	}
+
+	if timings := ctxGetMiddlewareTimings(r); timings != nil {
+		for _, e := range timings.Entries() {
+			ev.Middleware = append(ev.Middleware, requestTapMiddlewareRun{Name: e.Name, DurationUs: e.Duration.Microseconds()})
+		}
+	}
+
+	return ev
+}
+
+// requestTapURL returns the URL as requested by the client, before listen path stripping and rewrites.
+func requestTapURL(r *http.Request) *url.URL {
+	if u := ctxGetOrigRequestURL(r); u != nil {
+		return u
+	}
+
+	if u, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		return u
+	}
+
+	return r.URL
+}
+
+// redactRequestTapHeaders copies the headers, hiding credentials and any value carrying the key.
+func redactRequestTapHeaders(in http.Header, token string) http.Header {
+	out := in.Clone()
+	if out == nil {
+		return http.Header{}
+	}
+
+	for _, name := range requestTapSensitiveHeaders {
+		if _, ok := out[name]; ok {
+			out[name] = []string{requestTapRedactedValue}
+		}
+	}
+
+	if token != "" {
+		for name, values := range out {
+			for i, v := range values {
+				if strings.Contains(v, token) {
+					out[name][i] = requestTapRedactedValue
+				}
+			}
+		}
+	}
+
+	return out
+}
+
+// redactRequestTapQuery encodes the query string, hiding any value carrying the key.
+func redactRequestTapQuery(q url.Values, token string) string {
+	if token != "" {
+		for name, values := range q {
+			for i, v := range values {
+				if strings.Contains(v, token) {
+					q[name][i] = requestTapRedactedValue
+				}
+			}
+		}
+	}
+
+	return q.Encode()
+}
+
+// requestTap is a single subscriber to the live traffic of an API.
+type requestTap struct {
+	apiID  string
+	opts   requestTapOptions
+	events chan *requestTapEvent
+
+	mu          sync.Mutex
+	windowStart time.Time
+	windowSent  int
+
+	dropped atomic.Int64
+}
+
+// allow applies the per second rate limit of the tap.
+func (t *requestTap) allow(now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.windowStart) >= time.Second {
+		t.windowStart = now
+		t.windowSent = 0
+	}
+
+	if t.windowSent >= t.opts.Rate {
+		return false
+	}
+
+	t.windowSent++
+	return true
+}
+
+// offer queues the event if it matches the filters, is sampled and within the rate.
+// It never blocks, events that can't be queued are counted as dropped.
+func (t *requestTap) offer(ev *requestTapEvent) {
+	if !t.opts.match(ev) {
+		return
+	}
+
+	if t.opts.SampleRate < 1 && rand.Float64() >= t.opts.SampleRate {
+		return
+	}
+
+	if !t.allow(time.Now()) {
+		t.dropped.Add(1)
+		return
+	}
+
+	select {
+	case t.events <- ev:
+	default:
+		t.dropped.Add(1)
+	}
+}
+
+// requestTaps keeps the active request taps of a gateway by API ID.
+type requestTaps struct {
+	mu    sync.RWMutex
+	taps  map[string]map[*requestTap]struct{}
+	count atomic.Int32
+}
+
+func newRequestTaps() *requestTaps {
+	return &requestTaps{taps: map[string]map[*requestTap]struct{}{}}
+}
+
+// subscribe registers a new tap for the API, it must be released with unsubscribe.
+func (rt *requestTaps) subscribe(apiID string, opts requestTapOptions) *requestTap {
+	tap := &requestTap{
+		apiID:  apiID,
+		opts:   opts,
+		events: make(chan *requestTapEvent, requestTapBufferSize),
+	}
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if rt.taps[apiID] == nil {
+		rt.taps[apiID] = map[*requestTap]struct{}{}
+	}
+	rt.taps[apiID][tap] = struct{}{}
+	rt.count.Add(1)
+
+	return tap
+}
+
+func (rt *requestTaps) unsubscribe(tap *requestTap) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if _, ok := rt.taps[tap.apiID][tap]; !ok {
+		return
+	}
+
+	delete(rt.taps[tap.apiID], tap)
+	if len(rt.taps[tap.apiID]) == 0 {
+		delete(rt.taps, tap.apiID)
+	}
+	rt.count.Add(-1)
+}
+
+// active reports whether the API has any tap, it's cheap when no taps are open.
+func (rt *requestTaps) active(apiID string) bool {
+	if rt == nil || rt.count.Load() == 0 {
+		return false
+	}
+
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+	return len(rt.taps[apiID]) > 0
+}
+
+func (rt *requestTaps) subscribers(apiID string) []*requestTap {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+
+	taps := make([]*requestTap, 0, len(rt.taps[apiID]))
+	for tap := range rt.taps[apiID] {
+		taps = append(taps, tap)
+	}
+	return taps
+}
+
+// publishRequestTap streams the finished request to the taps open for the API.
+func (gw *Gateway) publishRequestTap(spec *APISpec, r *http.Request, resp *http.Response, latency analytics.Latency) {
+	if gw == nil || spec == nil || !gw.requestTaps.active(spec.APIID) {
+		return
+	}
+
+	ev := newRequestTapEvent(spec, r, resp, latency)
+	for _, tap := range gw.requestTaps.subscribers(spec.APIID) {
+		tap.offer(ev)
+	}
+}
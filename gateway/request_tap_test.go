@@ -0,0 +1,161 @@
+package gateway
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/header"
+	"github.com/TykTechnologies/tyk/test"
+)
+
+func TestParseRequestTapOptions(t *testing.T) {
+	opts, err := parseRequestTapOptions(url.Values{})
+	require.NoError(t, err)
+	assert.Equal(t, requestTapDefaultDuration, opts.Duration)
+	assert.Equal(t, requestTapDefaultRate, opts.Rate)
+	assert.Equal(t, 1.0, opts.SampleRate)
+
+	opts, err = parseRequestTapOptions(url.Values{
+		"path":        {"^/users/"},
+		"status":      {"5XX"},
+		"key_hash":    {"abc"},
+		"sample_rate": {"0.25"},
+		"duration":    {"30"},
+		"rate":        {"5"},
+	})
+	require.NoError(t, err)
+	assert.True(t, opts.Path.MatchString("/users/1"))
+	assert.Equal(t, 5, opts.StatusClass)
+	assert.Equal(t, "abc", opts.KeyHash)
+	assert.Equal(t, 0.25, opts.SampleRate)
+	assert.Equal(t, 30*time.Second, opts.Duration)
+	assert.Equal(t, 5, opts.Rate)
+
+	for name, q := range map[string]url.Values{
+		"invalid path":        {"path": {"("}},
+		"invalid status":      {"status": {"6xx"}},
+		"invalid sample rate": {"sample_rate": {"0"}},
+		"too long":            {"duration": {"1h"}},
+		"too fast":            {"rate": {"1000"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseRequestTapOptions(q)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRequestTap_Offer(t *testing.T) {
+	ev := func(path string, status int) *requestTapEvent {
+		return &requestTapEvent{Request: requestTapRequest{Path: path}, Response: requestTapResponse{Status: status}, KeyHash: "hash"}
+	}
+
+	opts, err := parseRequestTapOptions(url.Values{"path": {"^/match"}, "status": {"2xx"}, "key_hash": {"hash"}, "rate": {"2"}})
+	require.NoError(t, err)
+
+	taps := newRequestTaps()
+	assert.False(t, taps.active("api"))
+
+	tap := taps.subscribe("api", opts)
+	assert.True(t, taps.active("api"))
+	assert.False(t, taps.active("other"))
+
+	tap.offer(ev("/other", 200))
+	tap.offer(ev("/match", 500))
+	tap.offer(&requestTapEvent{Request: requestTapRequest{Path: "/match"}, Response: requestTapResponse{Status: 200}})
+	assert.Len(t, tap.events, 0)
+
+	// the rate allows two events per second
+	for i := 0; i < 3; i++ {
+		tap.offer(ev("/match", 201))
+	}
+	assert.Len(t, tap.events, 2)
+	assert.EqualValues(t, 1, tap.dropped.Load())
+
+	taps.unsubscribe(tap)
+	assert.False(t, taps.active("api"))
+}
+
+func TestRedactRequestTapHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set(header.Authorization, "Bearer secret-key")
+	h.Set(header.Cookie, "session=1")
+	h.Set("X-Custom-Key", "secret-key")
+	h.Set(header.ContentType, "application/json")
+
+	redacted := redactRequestTapHeaders(h, "secret-key")
+	assert.Equal(t, requestTapRedactedValue, redacted.Get(header.Authorization))
+	assert.Equal(t, requestTapRedactedValue, redacted.Get(header.Cookie))
+	assert.Equal(t, requestTapRedactedValue, redacted.Get("X-Custom-Key"))
+	assert.Equal(t, "application/json", redacted.Get(header.ContentType))
+	assert.Equal(t, "Bearer secret-key", h.Get(header.Authorization), "original headers should be untouched")
+
+	assert.Equal(t, "authorization=%3Credacted%3E&page=1",
+		redactRequestTapQuery(url.Values{"authorization": {"secret-key"}, "page": {"1"}}, "secret-key"))
+}
+
+func TestRequestTapAPI(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	ts.Gw.BuildAndLoadAPI(func(spec *APISpec) {
+		spec.APIID = "tapped"
+		spec.Proxy.ListenPath = "/tapped/"
+		spec.UseKeylessAccess = true
+	})
+
+	_, _ = ts.Run(t, []test.TestCase{
+		{Path: "/tyk/debug/tap/tapped", Code: http.StatusForbidden},
+		{Path: "/tyk/debug/tap/unknown", AdminAuth: true, Code: http.StatusNotFound},
+		{Path: "/tyk/debug/tap/tapped?status=abc", AdminAuth: true, Code: http.StatusBadRequest},
+	}...)
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/tyk/debug/tap/tapped?duration=1s&path=^/tapped/match", nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(ts.withAuth(req))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get(header.ContentType))
+
+	_, _ = ts.Run(t, []test.TestCase{
+		{Path: "/tapped/match?page=1", Headers: map[string]string{header.Cookie: "session=1"}, Code: http.StatusOK},
+		{Path: "/tapped/other", Code: http.StatusOK},
+	}...)
+
+	// the stream ends once the duration elapses
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var events []string
+	var tapped requestTapEvent
+	for _, msg := range strings.Split(strings.TrimSpace(string(body)), "\n\n") {
+		lines := strings.SplitN(msg, "\n", 2)
+		require.Len(t, lines, 2, msg)
+
+		event := strings.TrimPrefix(lines[0], "event: ")
+		events = append(events, event)
+		if event == "request" {
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[1], "data: ")), &tapped))
+		}
+	}
+
+	assert.Equal(t, []string{"request", "end"}, events)
+	assert.Equal(t, "tapped", tapped.APIID)
+	assert.Equal(t, "/tapped/match", tapped.Request.Path)
+	assert.Equal(t, "page=1", tapped.Request.Query)
+	assert.Equal(t, requestTapRedactedValue, tapped.Request.Headers.Get(header.Cookie))
+	assert.Equal(t, http.StatusOK, tapped.Response.Status)
+	assert.NotEmpty(t, tapped.Middleware)
+	assert.False(t, ts.Gw.requestTaps.active("tapped"))
+}
@@ -982,7 +982,7 @@ func handleInMemoryLoop(handler http.Handler, r *http.Request) (resp *http.Respo
 	return memConnClient.Do(r)
 }
 
-func (p *ReverseProxy) handleOutboundRequest(roundTripper *TykRoundTripper, outreq *http.Request, w http.ResponseWriter) (res *http.Response, hijacked bool, latency time.Duration, err error) {
+func (p *ReverseProxy) handleOutboundRequest(roundTripper *TykRoundTripper, req, outreq *http.Request, w http.ResponseWriter) (res *http.Response, hijacked bool, latency time.Duration, err error) {
 	begin := time.Now()
 	defer func() {
 		latency = time.Since(begin)
@@ -993,6 +993,12 @@ func (p *ReverseProxy) handleOutboundRequest(roundTripper *TykRoundTripper, outr
 		return
 	}
 
+	if p.TykAPISpec.grpcTranscoder != nil {
+		// the methods are matched on the API path, whatever the listen path stripping and upstream path
+		res, err = p.TykAPISpec.grpcTranscoder.RoundTrip(roundTripper, outreq, p.TykAPISpec.StripListenPath(req.URL.Path))
+		return
+	}
+
 	res, err = p.sendRequestToUpstream(roundTripper, outreq)
 	return
 }
@@ -1271,14 +1277,14 @@ func (p *ReverseProxy) WrappedServeHTTP(rw http.ResponseWriter, req *http.Reques
 		}
 		p.logger.Debug("ON REQUEST: Circuit Breaker is in CLOSED or HALF-OPEN state")
 
-		res, isHijacked, upstreamLatency, err = p.handleOutboundRequest(roundTripper, outreq, rw)
+		res, isHijacked, upstreamLatency, err = p.handleOutboundRequest(roundTripper, req, outreq, rw)
 		if err != nil || res.StatusCode/100 == 5 {
 			breakerConf.CB.Fail()
 		} else {
 			breakerConf.CB.Success()
 		}
 	} else {
-		res, isHijacked, upstreamLatency, err = p.handleOutboundRequest(roundTripper, outreq, rw)
+		res, isHijacked, upstreamLatency, err = p.handleOutboundRequest(roundTripper, req, outreq, rw)
 	}
 
 	if err != nil {
@@ -145,6 +145,8 @@ func (r *RPCStorageHandler) Connect() bool {
 		DNSMonitorInterval:    slaveOptions.DNSMonitor.CheckInterval,
 	}
 
+	rpc.SetEmergencyModeChangeCallback(r.Gw.onRPCEmergencyModeChange)
+
 	return rpc.Connect(
 		rpcConfig,
 		r.SuppressRegister,
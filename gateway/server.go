@@ -40,9 +40,11 @@ import (
 	"github.com/sirupsen/logrus"
 	logrussyslog "github.com/sirupsen/logrus/hooks/syslog"
 
+	"github.com/TykTechnologies/tyk/internal/audit"
 	"github.com/TykTechnologies/tyk/internal/crypto"
 	"github.com/TykTechnologies/tyk/internal/httputil"
 	"github.com/TykTechnologies/tyk/internal/otel"
+	"github.com/TykTechnologies/tyk/internal/revision"
 	"github.com/TykTechnologies/tyk/internal/scheduler"
 	"github.com/TykTechnologies/tyk/test"
 
@@ -59,6 +61,7 @@ import (
 	"github.com/TykTechnologies/tyk/certs"
 	"github.com/TykTechnologies/tyk/checkup"
 	"github.com/TykTechnologies/tyk/cli"
+	"github.com/TykTechnologies/tyk/cli/tester"
 	"github.com/TykTechnologies/tyk/config"
 	"github.com/TykTechnologies/tyk/dnscache"
 	"github.com/TykTechnologies/tyk/header"
@@ -159,6 +162,8 @@ type Gateway struct {
 	apiSpecs        []*APISpec
 	apisByID        map[string]*APISpec
 	apisHandlesByID *sync.Map
+	// appsLoadedConfig is the configuration the current API routes were built with.
+	appsLoadedConfig *config.Config
 
 	policiesMu   sync.RWMutex
 	policiesByID map[string]user.Policy
@@ -211,6 +216,27 @@ type Gateway struct {
 
 	healthCheckInfo atomic.Value
 
+	// webhookOutbox persists webhook deliveries for retries and replays.
+	webhookOutbox *webhookOutbox
+
+	// requestTaps holds the live request taps opened on the control API.
+	requestTaps *requestTaps
+
+	// controlAPICredentials holds the scoped control API credentials.
+	controlAPICredentials *controlAPICredentials
+
+	// auditSink receives the audit records of the control API, it's nil when the audit log is disabled.
+	auditSink audit.Sink
+
+	// revisions keeps the history of the APIs and policies, it's nil when the revision history is disabled.
+	revisions revision.Store
+
+	// etagMu serialises the control API changes made with If-Match, between the check and the write.
+	etagMu sync.Mutex
+
+	// schedules holds the state of the APIs and policies with an activation schedule.
+	schedules activationSchedules
+
 	dialCtxFn test.DialContext
 }
 
@@ -249,6 +275,9 @@ func NewGateway(config config.Config, ctx context.Context) *Gateway {
 	gw.TestBundles = map[string]map[string]string{}
 
 	gw.StorageConnectionHandler = storage.NewConnectionHandler(ctx)
+	gw.webhookOutbox = newWebhookOutbox(gw)
+	gw.requestTaps = newRequestTaps()
+	gw.controlAPICredentials = newControlAPICredentials(gw)
 
 	gw.SetNodeID("solo-" + uuid.New())
 	gw.SessionID = uuid.New()
@@ -431,6 +460,9 @@ func (gw *Gateway) setupGlobals() {
 
 	gw.initHealthCheck(gw.ctx)
 
+	gw.auditSink = gw.newAuditSink()
+	gw.revisions = gw.newRevisionStore()
+
 	redisStore := &storage.RedisCluster{KeyPrefix: "apikey-", HashKeys: gwConfig.HashKeys, ConnectionHandler: gw.StorageConnectionHandler}
 	redisStore.Connect()
 
@@ -600,6 +632,12 @@ func (gw *Gateway) syncAPISpecs() (int, error) {
 	}
 	var filter []*APISpec
 	for _, v := range s {
+		// specs reused from the registry were validated when they were first loaded
+		if gw.getApiSpec(v.APIID) == v {
+			filter = append(filter, v)
+			continue
+		}
+
 		if err := v.Validate(gw.GetConfig().OAS); err != nil {
 			mainLog.WithError(err).WithField("spec", v.Name).Error("Skipping loading spec because it failed validation")
 			continue
@@ -663,8 +701,14 @@ func (gw *Gateway) syncPolicies() (count int, err error) {
 	}
 
 	gw.policiesMu.Lock()
-	defer gw.policiesMu.Unlock()
+	oldPols := gw.policiesByID
 	gw.policiesByID = pols
+	gw.policiesMu.Unlock()
+
+	// The initial load isn't reported, every policy would be reported as added.
+	if gw.performedSuccessfulReload {
+		gw.firePolicyEvents(oldPols, pols)
+	}
 
 	return len(pols), nil
 }
@@ -721,7 +765,7 @@ func (gw *Gateway) loadControlAPIEndpoints(muxer *mux.Router) {
 
 	r := mux.NewRouter()
 	muxer.PathPrefix("/tyk/").Handler(http.StripPrefix("/tyk",
-		stripSlashes(gw.checkIsAPIOwner(gw.controlAPICheckClientCertificate("/gateway/client", InstrumentationMW(r)))),
+		stripSlashes(gw.controlAPICheckClientCertificate("/gateway/client", InstrumentationMW(r))),
 	))
 
 	if hostname != "" {
@@ -734,7 +778,13 @@ func (gw *Gateway) loadControlAPIEndpoints(muxer *mux.Router) {
 		muxer.HandleFunc("/debug/pprof/{_:.*}", pprofhttp.Index)
 	}
 
-	r.MethodNotAllowedHandler = MethodNotAllowedHandler{}
+	// the owner check runs within the audit log so denied calls are recorded, the routes
+	// that don't match are checked too so they aren't disclosed
+	r.NotFoundHandler = gw.checkIsAPIOwner(http.NotFoundHandler())
+	r.MethodNotAllowedHandler = gw.checkIsAPIOwner(MethodNotAllowedHandler{})
+	r.Use(gw.auditLogMiddleware)
+	r.Use(gw.checkIsAPIOwner)
+	r.Use(gw.controlAPIScopeMiddleware)
 
 	mainLog.Info("Initialising Tyk REST API Endpoints")
 
@@ -748,26 +798,35 @@ func (gw *Gateway) loadControlAPIEndpoints(muxer *mux.Router) {
 		r.HandleFunc("/org/keys/{keyName:[^/]*}", gw.orgHandler).Methods("POST", "PUT", "GET", "DELETE")
 		r.HandleFunc("/keys/policy/{keyName}", gw.policyUpdateHandler).Methods("POST")
 		r.HandleFunc("/keys/create", gw.createKeyHandler).Methods("POST")
+		r.HandleFunc("/keys/bulk", gw.keysBulkHandler).Methods(http.MethodPost)
 		r.HandleFunc("/apis", gw.apiHandler).Methods(http.MethodGet)
 		r.HandleFunc("/apis", gw.blockInDashboardMode(gw.apiHandler)).Methods(http.MethodPost)
 		r.HandleFunc("/apis/oas", gw.apiOASGetHandler).Methods(http.MethodGet)
 		r.HandleFunc("/apis/oas", gw.blockInDashboardMode(gw.validateOAS(gw.apiOASPostHandler))).Methods(http.MethodPost)
-		r.HandleFunc("/apis/{apiID}", gw.apiHandler).Methods(http.MethodGet)
+		r.HandleFunc("/apis/{apiID}", gw.withETag(gw.apiETag, gw.apiHandler)).Methods(http.MethodGet)
 		r.HandleFunc("/apis/{apiID}", gw.blockInDashboardMode(gw.apiHandler)).Methods(http.MethodPost)
-		r.HandleFunc("/apis/{apiID}", gw.blockInDashboardMode(gw.apiHandler)).Methods(http.MethodPut)
-		r.HandleFunc("/apis/{apiID}", gw.apiHandler).Methods(http.MethodDelete)
+		r.HandleFunc("/apis/{apiID}", gw.blockInDashboardMode(gw.withETag(gw.apiETag, gw.apiHandler))).Methods(http.MethodPut)
+		r.HandleFunc("/apis/{apiID}", gw.withETag(gw.apiETag, gw.apiHandler)).Methods(http.MethodDelete)
 		r.HandleFunc("/apis/{apiID}/versions", versionsHandler.ServeHTTP).Methods(http.MethodGet)
+		r.HandleFunc("/apis/{apiID}/revisions", gw.revisionListHandler(revision.KindAPI)).Methods(http.MethodGet)
+		r.HandleFunc("/apis/{apiID}/revisions/{rev}", gw.revisionGetHandler(revision.KindAPI)).Methods(http.MethodGet)
+		r.HandleFunc("/apis/{apiID}/revisions/{rev}/diff", gw.revisionDiffHandler(revision.KindAPI)).Methods(http.MethodGet)
+		r.HandleFunc("/apis/{apiID}/rollback/{rev}", gw.blockInDashboardMode(gw.rollbackHandler(revision.KindAPI))).Methods(http.MethodPost)
 		r.HandleFunc("/apis/oas/export", gw.apiOASExportHandler).Methods("GET")
-		r.HandleFunc("/apis/oas/import", gw.blockInDashboardMode(gw.validateOAS(gw.makeImportedOASTykAPI(gw.apiOASPostHandler)))).Methods(http.MethodPost)
-		r.HandleFunc("/apis/oas/{apiID}", gw.apiOASGetHandler).Methods(http.MethodGet)
-		r.HandleFunc("/apis/oas/{apiID}", gw.blockInDashboardMode(gw.validateOAS(gw.apiOASPutHandler))).Methods(http.MethodPut)
-		r.HandleFunc("/apis/oas/{apiID}", gw.blockInDashboardMode(gw.validateOAS(gw.apiOASPatchHandler))).Methods(http.MethodPatch)
-		r.HandleFunc("/apis/oas/{apiID}", gw.blockInDashboardMode(gw.apiHandler)).Methods(http.MethodDelete)
+		r.HandleFunc("/apis/oas/import", gw.blockInDashboardMode(gw.importFromSource(gw.validateOAS(gw.makeImportedOASTykAPI(gw.apiOASPostHandler))))).Methods(http.MethodPost)
+		r.HandleFunc("/apis/oas/{apiID}", gw.withETag(gw.apiETag, gw.apiOASGetHandler)).Methods(http.MethodGet)
+		r.HandleFunc("/apis/oas/{apiID}", gw.blockInDashboardMode(gw.withETag(gw.apiETag, gw.validateOAS(gw.apiOASPutHandler)))).Methods(http.MethodPut)
+		r.HandleFunc("/apis/oas/{apiID}", gw.blockInDashboardMode(gw.withETag(gw.apiETag, gw.validateOAS(gw.apiOASPatchHandler)))).Methods(http.MethodPatch)
+		r.HandleFunc("/apis/oas/{apiID}", gw.blockInDashboardMode(gw.withETag(gw.apiETag, gw.apiHandler))).Methods(http.MethodDelete)
 		r.HandleFunc("/apis/oas/{apiID}/versions", versionsHandler.ServeHTTP).Methods(http.MethodGet)
 		r.HandleFunc("/apis/oas/{apiID}/export", gw.apiOASExportHandler).Methods("GET")
 		r.HandleFunc("/health", gw.healthCheckhandler).Methods("GET")
 		r.HandleFunc("/policies", gw.polHandler).Methods("GET", "POST", "PUT", "DELETE")
-		r.HandleFunc("/policies/{polID}", gw.polHandler).Methods("GET", "POST", "PUT", "DELETE")
+		r.HandleFunc("/policies/{polID}", gw.withETag(gw.policyETag, gw.polHandler)).Methods("GET", "POST", "PUT", "DELETE")
+		r.HandleFunc("/policies/{polID}/revisions", gw.revisionListHandler(revision.KindPolicy)).Methods(http.MethodGet)
+		r.HandleFunc("/policies/{polID}/revisions/{rev}", gw.revisionGetHandler(revision.KindPolicy)).Methods(http.MethodGet)
+		r.HandleFunc("/policies/{polID}/revisions/{rev}/diff", gw.revisionDiffHandler(revision.KindPolicy)).Methods(http.MethodGet)
+		r.HandleFunc("/policies/{polID}/rollback/{rev}", gw.blockInDashboardMode(gw.rollbackHandler(revision.KindPolicy))).Methods(http.MethodPost)
 		r.HandleFunc("/oauth/clients/create", gw.createOauthClient).Methods("POST")
 		r.HandleFunc("/oauth/clients/{apiID}/{keyName:[^/]*}", gw.oAuthClientHandler).Methods("PUT")
 		r.HandleFunc("/oauth/clients/{apiID}/{keyName:[^/]*}/rotate", gw.rotateOauthClientHandler).Methods("PUT")
@@ -781,6 +840,7 @@ func (gw *Gateway) loadControlAPIEndpoints(muxer *mux.Router) {
 	}
 
 	r.HandleFunc("/debug", gw.traceHandler).Methods("POST")
+	r.HandleFunc("/debug/tap/{apiID}", gw.requestTapHandler).Methods("GET")
 	r.HandleFunc("/cache/jwks/{apiID}", gw.invalidateJWKSCacheForAPIID).Methods("DELETE")
 	r.HandleFunc("/cache/jwks", gw.invalidateJWKSCacheForAllAPIs).Methods("DELETE")
 	r.HandleFunc("/cache/{apiID}", gw.invalidateCacheHandler).Methods("DELETE")
@@ -794,8 +854,16 @@ func (gw *Gateway) loadControlAPIEndpoints(muxer *mux.Router) {
 	r.HandleFunc("/oauth/clients/{apiID}/{keyName}/tokens", gw.oAuthClientTokensHandler).Methods("GET")
 	r.HandleFunc("/oauth/tokens", gw.oAuthTokensHandler).Methods(http.MethodDelete)
 
+	r.HandleFunc("/webhooks/deliveries/failed", gw.webhookFailedDeliveriesHandler).Methods(http.MethodGet)
+	r.HandleFunc("/webhooks/deliveries/{deliveryID}/replay", gw.webhookReplayDeliveryHandler).Methods(http.MethodPost)
+
 	r.HandleFunc("/schema", gw.schemaHandler).Methods(http.MethodGet)
 
+	r.HandleFunc("/credentials", gw.controlAPICredentialsHandler).Methods(http.MethodGet, http.MethodPost)
+	r.HandleFunc("/credentials/{credentialID}", gw.controlAPICredentialDeleteHandler).Methods(http.MethodDelete)
+
+	r.HandleFunc("/audit", gw.auditLogHandler).Methods(http.MethodGet)
+
 	mainLog.Debug("Loaded API Endpoints")
 }
 
@@ -803,16 +871,24 @@ func (gw *Gateway) loadControlAPIEndpoints(muxer *mux.Router) {
 // correct security credentials - this is a shared secret between the
 // client and the owner and is set in the tyk.conf file. This should
 // never be made public!
+//
+// Scoped credentials are accepted too, the credential is added to the request context
+// and its scopes are enforced by the routes.
 func (gw *Gateway) checkIsAPIOwner(next http.Handler) http.Handler {
 	secret := gw.GetConfig().Secret
+	credentials := gw.controlAPICredentials.configured()
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		tykAuthKey := r.Header.Get(header.XTykAuthorization)
 		if tykAuthKey != secret {
-			// Error
-			mainLog.Warning("Attempted administrative access with invalid or missing key!")
+			cred := gw.controlAPICredentials.lookup(credentials, tykAuthKey)
+			if cred == nil {
+				// Error
+				mainLog.Warning("Attempted administrative access with invalid or missing key!")
 
-			doJSONWrite(w, http.StatusForbidden, apiError("Attempted administrative access with invalid or missing key!"))
-			return
+				doJSONWrite(w, http.StatusForbidden, apiError("Attempted administrative access with invalid or missing key!"))
+				return
+			}
+			ctxSetControlAPICredential(r, cred)
 		}
 		next.ServeHTTP(w, r)
 	})
@@ -866,7 +942,8 @@ func (gw *Gateway) addOAuthHandlers(spec *APISpec, muxer *mux.Router) *OAuthMana
 
 	wrapWithCORS := createCORSWrapper(spec)
 
-	muxer.Handle(apiAuthorizePath, gw.checkIsAPIOwner(allowMethods(oauthHandlers.HandleGenerateAuthCodeData, "POST")))
+	muxer.Handle(apiAuthorizePath, gw.checkIsAPIOwner(gw.requireControlAPIScope(ScopeOAuthWrite, spec.APIID,
+		allowMethods(oauthHandlers.HandleGenerateAuthCodeData, "POST"))))
 	muxer.HandleFunc(clientAuthPath, wrapWithCORS(allowMethods(oauthHandlers.HandleAuthorizePassthrough, "GET", "POST")))
 	muxer.HandleFunc(clientAccessPath, wrapWithCORS(addSecureAndCacheHeaders(allowMethods(oauthHandlers.HandleAccessRequest, "GET", "POST"))))
 	muxer.HandleFunc(revokeToken, wrapWithCORS(oauthHandlers.HandleRevokeToken))
@@ -1088,6 +1165,8 @@ func (gw *Gateway) DoReload() {
 	gw.reloadMu.Lock()
 	defer gw.reloadMu.Unlock()
 
+	start := time.Now()
+
 	// Initialize/reset the JSVM
 	if gw.GetConfig().EnableJSVM {
 		gw.GlobalEventsJSVM.DeInit()
@@ -1097,12 +1176,14 @@ func (gw *Gateway) DoReload() {
 	// Load the API Policies
 	if _, err := syncResourcesWithReload("policies", gw.GetConfig(), gw.syncPolicies); err != nil {
 		mainLog.Error("Error during syncing policies")
+		gw.fireReloadEvent(start, err)
 		return
 	}
 
 	// load the specs
 	if count, err := syncResourcesWithReload("apis", gw.GetConfig(), gw.syncAPISpecs); err != nil {
 		mainLog.Error("Error during syncing apis")
+		gw.fireReloadEvent(start, err)
 		return
 	} else {
 		// skip re-loading only if dashboard service reported 0 APIs
@@ -1110,14 +1191,18 @@ func (gw *Gateway) DoReload() {
 		if count == 0 && gw.apisByIDLen() == 0 {
 			mainLog.Warning("No API Definitions found, not reloading")
 			gw.performedSuccessfulReload = true
+			gw.checkActivationSchedules(time.Now())
+			gw.fireReloadEvent(start, nil)
 			return
 		}
 	}
 
 	gw.loadGlobalApps()
+	gw.checkActivationSchedules(time.Now())
 
 	gw.performedSuccessfulReload = true
 	mainLog.Info("API reload complete")
+	gw.fireReloadEvent(start, nil)
 }
 
 func createCORSWrapper(spec *APISpec) func(handler http.HandlerFunc) http.HandlerFunc {
@@ -1800,6 +1885,16 @@ func Start() {
 	// Initialize everything else as normal
 	cli.Init(confPaths)
 	cli.Parse()
+	// Run the contract tests of an API with an in-process gateway:
+	if tester.Opts.Enabled {
+		// the gateway logs would be mixed with the results
+		log.SetLevel(logrus.ErrorLevel)
+		if err := RunContractTests(tester.Opts); err != nil {
+			mainLog.WithError(err).Error("Contract tests failed")
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	// Stop gateway process if not running in "start" mode:
 	if !cli.DefaultMode {
 		os.Exit(0)
@@ -1994,6 +2089,15 @@ func (gw *Gateway) start() {
 	oauthTokensPurger := scheduler.NewScheduler(log)
 	go oauthTokensPurger.Start(gw.ctx, purgeJob)
 
+	schedulesJob := scheduler.NewJob("activation-schedules", gw.runActivationSchedules, activationScheduleInterval)
+	schedulesJob.Quiet = true
+
+	activationScheduler := scheduler.NewScheduler(log)
+	go activationScheduler.Start(gw.ctx, schedulesJob)
+
+	// Retry failed webhook deliveries from the outbox
+	go gw.webhookOutbox.run(gw.ctx)
+
 	if slaveOptions := conf.SlaveOptions; slaveOptions.UseRPC {
 		mainLog.Debug("Starting RPC reload listener")
 		gw.RPCListener = RPCStorageHandler{
@@ -2017,6 +2121,10 @@ func (gw *Gateway) start() {
 	// interval counts from the start of one reload to the next.
 	go gw.reloadLoop(time.Tick(reloadInterval))
 	go gw.reloadQueueLoop()
+
+	if conf.Sync.Enabled {
+		go gw.runSync(gw.ctx)
+	}
 }
 
 func dashboardServiceInit(gw *Gateway) {
@@ -2248,6 +2356,7 @@ func (gw *Gateway) gracefulShutdown(ctx context.Context) error {
 	// Close all cache stores and other resources
 	mainLog.Info("Closing cache stores and other resources...")
 	gw.cacheClose()
+	gw.closeGenericEventHandlers()
 
 	// Check if there were any errors during shutdown
 	close(errChan)
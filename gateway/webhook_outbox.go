@@ -0,0 +1,413 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/config"
+	"github.com/TykTechnologies/tyk/internal/uuid"
+	"github.com/TykTechnologies/tyk/internal/webhook"
+	"github.com/TykTechnologies/tyk/storage"
+)
+
+const (
+	webhookOutboxPrefix = "webhook.outbox."
+
+	webhookDeliveryKeyPrefix = "delivery."
+	webhookLockKeyPrefix     = "lock."
+	webhookPollLock          = "poll"
+	webhookPendingSet        = "pending"
+	webhookFailedSet         = "failed"
+
+	// webhookOutboxRetention is how long (in seconds) a delivery is kept in the outbox.
+	webhookOutboxRetention = 7 * 24 * 60 * 60
+	// webhookOutboxPollInterval is how often the outbox is checked for due retries.
+	webhookOutboxPollInterval = time.Second
+	// webhookDeliveryLockTimeout bounds how long a delivery is claimed by a gateway.
+	webhookDeliveryLockTimeout = time.Minute
+
+	webhookDefaultRetryInterval = 5 * time.Second
+	webhookMaxRetryInterval     = time.Hour
+)
+
+var (
+	// ErrWebhookDeliveryNotFound is returned when a delivery isn't in the outbox.
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
+	// ErrWebhookDeliveryNotFailed is returned when replaying a delivery that hasn't failed.
+	ErrWebhookDeliveryNotFailed = errors.New("webhook delivery has not failed")
+
+	// ErrWebhookDeliveryLocked is returned when a delivery is being attempted by another worker.
+	ErrWebhookDeliveryLocked = errors.New("webhook delivery is in progress")
+
+	// ErrWebhookHandlerNotFound is returned when the webhook of a delivery is no longer loaded.
+	ErrWebhookHandlerNotFound = errors.New("webhook handler not found")
+)
+
+type webhookDeliveryStatus string
+
+const (
+	webhookDeliveryPending   webhookDeliveryStatus = "pending"
+	webhookDeliveryFailed    webhookDeliveryStatus = "failed"
+	webhookDeliveryDelivered webhookDeliveryStatus = "delivered"
+)
+
+// webhookDelivery is a webhook request as persisted in the outbox, so it can
+// be retried after a failure or a restart and replayed once it has failed.
+// Only the payload is stored, the request is built from the loaded webhook
+// handler on each attempt so its headers and signing secret never reach Redis.
+type webhookDelivery struct {
+	ID            string                `json:"id"`
+	Event         apidef.TykEvent       `json:"event"`
+	HandlerID     string                `json:"handler_id"`
+	HandlerName   string                `json:"handler_name,omitempty"`
+	Body          string                `json:"body"`
+	MaxRetries    int                   `json:"max_retries"`
+	RetryInterval int64                 `json:"retry_interval,omitempty"`
+	Attempts      int                   `json:"attempts"`
+	Status        webhookDeliveryStatus `json:"status"`
+	LastError     string                `json:"last_error,omitempty"`
+	CreatedAt     time.Time             `json:"created_at"`
+	LastAttemptAt time.Time             `json:"last_attempt_at,omitempty"`
+	NextAttemptAt time.Time             `json:"next_attempt_at,omitempty"`
+}
+
+// newWebhookDelivery creates a delivery of the body rendered by the webhook handler.
+func newWebhookDelivery(event apidef.TykEvent, w *WebHookHandler, body string) *webhookDelivery {
+	return &webhookDelivery{
+		ID:            "msg_" + uuid.NewHex(),
+		Event:         event,
+		HandlerID:     w.ID(),
+		HandlerName:   w.conf.Name,
+		Body:          body,
+		MaxRetries:    w.conf.MaxRetries,
+		RetryInterval: w.conf.RetryInterval,
+		Status:        webhookDeliveryPending,
+		CreatedAt:     time.Now().UTC(),
+	}
+}
+
+// backoff returns the delay before the next attempt, doubling the retry interval for each attempt made.
+func (d *webhookDelivery) backoff() time.Duration {
+	interval := time.Duration(d.RetryInterval) * time.Second
+	if interval <= 0 {
+		interval = webhookDefaultRetryInterval
+	}
+
+	for i := 1; i < d.Attempts; i++ {
+		interval *= 2
+		if interval >= webhookMaxRetryInterval {
+			return webhookMaxRetryInterval
+		}
+	}
+	return interval
+}
+
+// webhookOutbox persists webhook deliveries in Redis and retries failed
+// deliveries with exponential backoff. Deliveries that exhaust their retries
+// are kept in the failed set until they are replayed or expire.
+type webhookOutbox struct {
+	store *storage.RedisCluster
+	Gw    *Gateway
+}
+
+func newWebhookOutbox(gw *Gateway) *webhookOutbox {
+	return &webhookOutbox{
+		store: &storage.RedisCluster{KeyPrefix: webhookOutboxPrefix, ConnectionHandler: gw.StorageConnectionHandler},
+		Gw:    gw,
+	}
+}
+
+func (o *webhookOutbox) logger() *logrus.Entry {
+	return log.WithField("prefix", "webhooks")
+}
+
+// deliver makes the first attempt of a new delivery through its webhook handler. Deliveries
+// with retries are written to the outbox before the attempt so they survive a restart.
+func (o *webhookOutbox) deliver(w *WebHookHandler, d *webhookDelivery) error {
+	if d.MaxRetries > 0 {
+		if !o.lock(d.ID) {
+			return ErrWebhookDeliveryLocked
+		}
+		defer o.unlock(d.ID)
+
+		d.NextAttemptAt = d.CreatedAt
+		if err := o.save(d); err != nil {
+			o.logger().WithError(err).Warning("Could not persist webhook delivery, retries are disabled for it")
+		} else {
+			o.store.AddToSet(webhookPendingSet, d.ID)
+		}
+	}
+
+	return o.attempt(w, d)
+}
+
+// attempt sends the delivery and records the outcome in the outbox.
+// A nil handler fails the attempt, the webhook was removed since the delivery was created.
+func (o *webhookOutbox) attempt(w *WebHookHandler, d *webhookDelivery) error {
+	d.Attempts++
+	d.LastAttemptAt = time.Now().UTC()
+
+	err := ErrWebhookHandlerNotFound
+	if w != nil {
+		err = o.send(w, d)
+	}
+	if err == nil {
+		d.Status = webhookDeliveryDelivered
+		d.LastError = ""
+		o.remove(d.ID)
+		return nil
+	}
+
+	d.LastError = err.Error()
+	logger := o.logger().WithError(err).WithFields(logrus.Fields{
+		"id":       d.ID,
+		"attempts": d.Attempts,
+	})
+
+	if d.Attempts > d.MaxRetries {
+		logger.Error("Request to webhook failed")
+		o.fail(d)
+		return err
+	}
+
+	d.Status = webhookDeliveryPending
+	d.NextAttemptAt = d.LastAttemptAt.Add(d.backoff())
+	logger.Warningf("Request to webhook failed, retrying at %s", d.NextAttemptAt.Format(time.RFC3339))
+
+	if saveErr := o.save(d); saveErr != nil {
+		logger.WithField("outbox_error", saveErr.Error()).Error("Could not schedule webhook retry")
+		return err
+	}
+	o.store.AddToSet(webhookPendingSet, d.ID)
+	return err
+}
+
+// send makes a single delivery attempt, signing the request with a fresh timestamp.
+func (o *webhookOutbox) send(w *WebHookHandler, d *webhookDelivery) error {
+	req, err := w.BuildRequest(d.Body)
+	if err != nil {
+		return err
+	}
+
+	if w.conf.SigningSecret != "" {
+		if err := webhook.SignRequest(req, w.conf.SigningSecret, d.ID, time.Now(), []byte(d.Body)); err != nil {
+			return err
+		}
+	}
+
+	cli, err := o.Gw.webhookHTTPClient()
+	if err != nil {
+		return err
+	}
+
+	resp, err := cli.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	if err != nil {
+		o.logger().Error(err)
+	} else {
+		o.logger().WithField("responseCode", resp.StatusCode).Debug(string(content))
+	}
+	return nil
+}
+
+// fail moves the delivery to the failed set.
+func (o *webhookOutbox) fail(d *webhookDelivery) {
+	d.Status = webhookDeliveryFailed
+	d.NextAttemptAt = time.Time{}
+
+	if err := o.save(d); err != nil {
+		o.logger().WithError(err).WithField("id", d.ID).Error("Could not record failed webhook delivery")
+		return
+	}
+
+	o.store.RemoveFromSet(webhookPendingSet, d.ID)
+	o.store.AddToSet(webhookFailedSet, d.ID)
+}
+
+func (o *webhookOutbox) save(d *webhookDelivery) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return o.store.SetKey(webhookDeliveryKeyPrefix+d.ID, string(data), webhookOutboxRetention)
+}
+
+func (o *webhookOutbox) load(id string) (*webhookDelivery, error) {
+	data, err := o.store.GetKey(webhookDeliveryKeyPrefix + id)
+	if err != nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+
+	d := &webhookDelivery{}
+	if err := json.Unmarshal([]byte(data), d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (o *webhookOutbox) remove(id string) {
+	o.store.DeleteKey(webhookDeliveryKeyPrefix + id)
+	o.store.RemoveFromSet(webhookPendingSet, id)
+	o.store.RemoveFromSet(webhookFailedSet, id)
+}
+
+// lock claims the delivery so that only one gateway attempts it at a time.
+func (o *webhookOutbox) lock(id string) bool {
+	ok, err := o.store.Lock(o.lockKey(id), webhookDeliveryLockTimeout)
+	return err == nil && ok
+}
+
+func (o *webhookOutbox) unlock(id string) {
+	o.store.DeleteRawKey(o.lockKey(id))
+}
+
+func (o *webhookOutbox) lockKey(id string) string {
+	return o.store.KeyPrefix + webhookLockKeyPrefix + id
+}
+
+func (o *webhookOutbox) members(set string) []string {
+	members, err := o.store.GetSet(set)
+	if err != nil {
+		return nil
+	}
+
+	ids := make([]string, 0, len(members))
+	for _, id := range members {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// claimPoll claims the poll of the outbox so that a single gateway drains it per poll
+// interval. The claim isn't released, it expires with the interval.
+func (o *webhookOutbox) claimPoll() bool {
+	ok, err := o.store.Lock(o.lockKey(webhookPollLock), webhookOutboxPollInterval)
+	return err == nil && ok
+}
+
+// processPending attempts the pending deliveries that are due.
+func (o *webhookOutbox) processPending() {
+	now := time.Now()
+
+	for _, id := range o.members(webhookPendingSet) {
+		d, err := o.load(id)
+		if err != nil {
+			// the delivery expired or is unreadable, there's nothing left to retry
+			o.store.RemoveFromSet(webhookPendingSet, id)
+			continue
+		}
+
+		if d.NextAttemptAt.After(now) || !o.lock(id) {
+			continue
+		}
+
+		_ = o.attempt(o.Gw.webhookHandler(d.HandlerID), d)
+		o.unlock(id)
+	}
+}
+
+// run retries pending deliveries until ctx is cancelled.
+func (o *webhookOutbox) run(ctx context.Context) {
+	ticker := time.NewTicker(webhookOutboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if o.claimPoll() {
+				o.processPending()
+			}
+		}
+	}
+}
+
+// failed returns the failed deliveries, most recent first.
+func (o *webhookOutbox) failed() []webhookDelivery {
+	deliveries := []webhookDelivery{}
+
+	for _, id := range o.members(webhookFailedSet) {
+		d, err := o.load(id)
+		if err != nil {
+			o.store.RemoveFromSet(webhookFailedSet, id)
+			continue
+		}
+		deliveries = append(deliveries, *d)
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+	return deliveries
+}
+
+// replay re-attempts a failed delivery with a fresh set of retries.
+func (o *webhookOutbox) replay(id string) (*webhookDelivery, error) {
+	d, err := o.load(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Status != webhookDeliveryFailed {
+		return nil, ErrWebhookDeliveryNotFailed
+	}
+
+	if !o.lock(id) {
+		return nil, ErrWebhookDeliveryLocked
+	}
+	defer o.unlock(id)
+
+	o.store.RemoveFromSet(webhookFailedSet, id)
+	d.Attempts = 0
+	d.LastError = ""
+
+	_ = o.attempt(o.Gw.webhookHandler(d.HandlerID), d)
+	return d, nil
+}
+
+// webhookHandler returns the loaded webhook handler with the given ID, global or of an API, or nil.
+func (gw *Gateway) webhookHandler(id string) *WebHookHandler {
+	if w := findWebhookHandler(gw.GetConfig().GetEventTriggers(), id); w != nil {
+		return w
+	}
+
+	gw.apisMu.RLock()
+	defer gw.apisMu.RUnlock()
+
+	for _, spec := range gw.apisByID {
+		if w := findWebhookHandler(spec.EventPaths, id); w != nil {
+			return w
+		}
+	}
+	return nil
+}
+
+func findWebhookHandler(handlers map[apidef.TykEvent][]config.TykEventHandler, id string) *WebHookHandler {
+	for _, eventHandlers := range handlers {
+		for _, handler := range eventHandlers {
+			if w, ok := handler.(*WebHookHandler); ok && w.ID() == id {
+				return w
+			}
+		}
+	}
+	return nil
+}
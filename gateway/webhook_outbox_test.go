@@ -0,0 +1,229 @@
+package gateway
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/config"
+	"github.com/TykTechnologies/tyk/internal/webhook"
+	"github.com/TykTechnologies/tyk/test"
+)
+
+// webhookReceiver is a test webhook endpoint that responds with the given status.
+type webhookReceiver struct {
+	*httptest.Server
+	status   atomic.Int32
+	requests atomic.Int32
+	last     atomic.Pointer[http.Request]
+	lastBody atomic.Pointer[[]byte]
+}
+
+func newWebhookReceiver(status int) *webhookReceiver {
+	rcv := &webhookReceiver{}
+	rcv.status.Store(int32(status))
+	rcv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rcv.requests.Add(1)
+		rcv.last.Store(r)
+		rcv.lastBody.Store(&body)
+		w.WriteHeader(int(rcv.status.Load()))
+	}))
+	return rcv
+}
+
+// newOutboxHandler loads a global webhook handler so the outbox can find it for retries and replays.
+func (ts *Test) newOutboxHandler(t *testing.T, conf apidef.WebHookHandlerConf) (*WebHookHandler, *webhookDelivery) {
+	t.Helper()
+
+	conf.Method = http.MethodPost
+	conf.HeaderList = map[string]string{"X-Tyk-Test": "outbox"}
+	w := &WebHookHandler{conf: conf, Gw: ts.Gw}
+
+	gwConf := ts.Gw.GetConfig()
+	gwConf.SetEventTriggers(map[apidef.TykEvent][]config.TykEventHandler{EventKeyExpired: {w}})
+	ts.Gw.SetConfig(gwConf)
+
+	return w, newWebhookDelivery(EventKeyExpired, w, `{"event":"KeyExpired"}`)
+}
+
+func TestWebhookOutbox_Signing(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	rcv := newWebhookReceiver(http.StatusOK)
+	defer rcv.Close()
+
+	const secret = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	w, d := ts.newOutboxHandler(t, apidef.WebHookHandlerConf{TargetPath: rcv.URL, SigningSecret: secret})
+
+	require.NoError(t, ts.Gw.webhookOutbox.deliver(w, d))
+	assert.Equal(t, webhookDeliveryDelivered, d.Status)
+
+	req := rcv.last.Load()
+	require.NotNil(t, req)
+	assert.Equal(t, "outbox", req.Header.Get("X-Tyk-Test"))
+	assert.Equal(t, d.ID, req.Header.Get(webhook.HeaderID))
+
+	ts64, err := strconv.ParseInt(req.Header.Get(webhook.HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+
+	ok, err := webhook.Verify(secret, d.ID, time.Unix(ts64, 0), *rcv.lastBody.Load(), req.Header.Get(webhook.HeaderSignature))
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestWebhookOutbox_Retry(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	rcv := newWebhookReceiver(http.StatusServiceUnavailable)
+	defer rcv.Close()
+
+	outbox := ts.Gw.webhookOutbox
+	w, d := ts.newOutboxHandler(t, apidef.WebHookHandlerConf{TargetPath: rcv.URL, MaxRetries: 2, RetryInterval: 60, SigningSecret: "secret"})
+
+	assert.Error(t, outbox.deliver(w, d))
+	assert.Contains(t, outbox.members(webhookPendingSet), d.ID)
+
+	raw, err := outbox.store.GetKey(webhookDeliveryKeyPrefix + d.ID)
+	require.NoError(t, err)
+	assert.NotContains(t, raw, "secret")
+	assert.NotContains(t, raw, "X-Tyk-Test")
+
+	stored, err := outbox.load(d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.Equal(t, webhookDeliveryPending, stored.Status)
+	assert.WithinDuration(t, stored.LastAttemptAt.Add(time.Minute), stored.NextAttemptAt, time.Second)
+
+	// not due yet
+	outbox.processPending()
+	assert.EqualValues(t, 1, rcv.requests.Load())
+
+	// make the retry due, it succeeds and is removed from the outbox
+	stored.NextAttemptAt = time.Now().Add(-time.Second)
+	require.NoError(t, outbox.save(stored))
+	rcv.status.Store(http.StatusOK)
+
+	outbox.processPending()
+	assert.EqualValues(t, 2, rcv.requests.Load())
+	assert.NotContains(t, outbox.members(webhookPendingSet), d.ID)
+
+	_, err = outbox.load(d.ID)
+	assert.ErrorIs(t, err, ErrWebhookDeliveryNotFound)
+}
+
+func TestWebhookOutbox_ClaimPoll(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	outbox := ts.Gw.webhookOutbox
+	other := newWebhookOutbox(ts.Gw)
+
+	// a single gateway polls the outbox per interval, the claim expires with it
+	require.Eventually(t, outbox.claimPoll, 3*webhookOutboxPollInterval, webhookOutboxPollInterval/10)
+	assert.False(t, other.claimPoll())
+}
+
+func TestWebHookHandler_ID(t *testing.T) {
+	conf := apidef.WebHookHandlerConf{Name: "hook", Method: http.MethodPost, TargetPath: "http://example.com"}
+
+	global := &WebHookHandler{conf: conf}
+	api := &WebHookHandler{conf: conf, apiID: "api"}
+	other := &WebHookHandler{conf: conf, apiID: "other"}
+
+	assert.NotEqual(t, global.ID(), api.ID())
+	assert.NotEqual(t, api.ID(), other.ID())
+	assert.Equal(t, api.ID(), (&WebHookHandler{conf: conf, apiID: "api"}).ID())
+}
+
+func TestWebhookDelivery_Backoff(t *testing.T) {
+	d := &webhookDelivery{RetryInterval: 10}
+
+	for attempts, expected := range map[int]time.Duration{
+		1:  10 * time.Second,
+		2:  20 * time.Second,
+		3:  40 * time.Second,
+		20: webhookMaxRetryInterval,
+	} {
+		d.Attempts = attempts
+		assert.Equal(t, expected, d.backoff(), "attempts %d", attempts)
+	}
+
+	assert.Equal(t, webhookDefaultRetryInterval, (&webhookDelivery{Attempts: 1}).backoff())
+}
+
+func TestWebhookFailedDeliveriesAPI(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	rcv := newWebhookReceiver(http.StatusInternalServerError)
+	defer rcv.Close()
+
+	w, d := ts.newOutboxHandler(t, apidef.WebHookHandlerConf{TargetPath: rcv.URL, SigningSecret: "secret"})
+	assert.Error(t, ts.Gw.webhookOutbox.deliver(w, d))
+
+	resp, err := ts.Run(t, test.TestCase{
+		Method: http.MethodGet, Path: "/tyk/webhooks/deliveries/failed", AdminAuth: true, Code: http.StatusOK,
+	})
+	require.NoError(t, err)
+
+	var failed []webhookDelivery
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&failed))
+
+	var found *webhookDelivery
+	for i := range failed {
+		if failed[i].ID == d.ID {
+			found = &failed[i]
+		}
+	}
+	require.NotNil(t, found, "failed delivery should be listed")
+	assert.Equal(t, webhookDeliveryFailed, found.Status)
+	assert.Equal(t, w.ID(), found.HandlerID)
+	assert.Contains(t, found.LastError, "500")
+
+	rcv.status.Store(http.StatusOK)
+
+	_, _ = ts.Run(t, []test.TestCase{
+		{Method: http.MethodPost, Path: "/tyk/webhooks/deliveries/unknown/replay", AdminAuth: true, Code: http.StatusNotFound},
+		{Method: http.MethodPost, Path: "/tyk/webhooks/deliveries/" + d.ID + "/replay", AdminAuth: true, Code: http.StatusOK,
+			BodyMatch: `"status":"delivered"`},
+		{Method: http.MethodGet, Path: "/tyk/webhooks/deliveries/failed", AdminAuth: true, Code: http.StatusOK, BodyNotMatch: d.ID},
+	}...)
+
+	assert.EqualValues(t, 2, rcv.requests.Load())
+	assert.Equal(t, "outbox", rcv.last.Load().Header.Get("X-Tyk-Test"))
+}
+
+func TestWebhookOutbox_HandlerNotFound(t *testing.T) {
+	ts := StartTest(nil)
+	defer ts.Close()
+
+	rcv := newWebhookReceiver(http.StatusInternalServerError)
+	defer rcv.Close()
+
+	outbox := ts.Gw.webhookOutbox
+	w, d := ts.newOutboxHandler(t, apidef.WebHookHandlerConf{TargetPath: rcv.URL})
+	assert.Error(t, outbox.deliver(w, d))
+
+	// the webhook is removed before the delivery is replayed
+	gwConf := ts.Gw.GetConfig()
+	gwConf.SetEventTriggers(nil)
+	ts.Gw.SetConfig(gwConf)
+
+	replayed, err := outbox.replay(d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, webhookDeliveryFailed, replayed.Status)
+	assert.Equal(t, ErrWebhookHandlerNotFound.Error(), replayed.LastError)
+	assert.EqualValues(t, 1, rcv.requests.Load())
+}
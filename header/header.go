@@ -18,6 +18,8 @@ const (
 	Cookie                  = "Cookie"
 	TransferEncoding        = "Transfer-Encoding"
 	Host                    = "Host"
+	ETag                    = "ETag"
+	IfMatch                 = "If-Match"
 )
 
 const (
@@ -0,0 +1,162 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// RedactedValue replaces the values of sensitive fields in a diff.
+const RedactedValue = "<redacted>"
+
+// sensitiveFields are the field names, or parts of field names, whose values are never recorded.
+// Header names carrying credentials are included, for the header maps that aren't redacted as a whole.
+var sensitiveFields = []string{"secret", "password", "private_key", "token", "hmac_string", "jwt_source", "oauth_keys",
+	"authorization", "api-key", "api_key", "apikey", "cookie"}
+
+// headerMapFields are the fields holding header maps, such as the webhook headers and the global
+// headers injected upstream, whose values are never recorded as they may carry credentials.
+var headerMapFields = []string{"header_map", "global_headers", "add_headers", "headers"}
+
+// Change is a field changed by an audited call. Path is the dot separated path of the field,
+// Before and After are nil when the field was added or removed.
+type Change struct {
+	Path   string      `json:"path"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// Diff returns the fields changed between the JSON representations of before and after,
+// with the values of sensitive fields redacted. Missing and zero values are considered equal,
+// so creating a resource lists the fields it sets and deleting it lists the fields it had.
+func Diff(before, after interface{}) []Change {
+	b, a := normalize(before), normalize(after)
+
+	changes := []Change{}
+	diff("", b, a, false, &changes)
+	return changes
+}
+
+// normalize converts v to the generic types it would decode to from JSON.
+func normalize(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+func diff(path string, before, after interface{}, sensitive bool, changes *[]Change) {
+	bm, bIsMap := before.(map[string]interface{})
+	am, aIsMap := after.(map[string]interface{})
+
+	if (bIsMap || isZero(before)) && (aIsMap || isZero(after)) && (bIsMap || aIsMap) {
+		keys := map[string]struct{}{}
+		for k := range bm {
+			keys[k] = struct{}{}
+		}
+		for k := range am {
+			keys[k] = struct{}{}
+		}
+
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		for _, k := range sorted {
+			diff(join(path, k), bm[k], am[k], sensitive || isSensitive(k), changes)
+		}
+		return
+	}
+
+	if isZero(before) && isZero(after) || reflect.DeepEqual(before, after) {
+		return
+	}
+
+	*changes = append(*changes, Change{
+		Path:   path,
+		Before: redact(before, sensitive),
+		After:  redact(after, sensitive),
+	})
+}
+
+// redact returns v with the values of sensitive fields replaced, zero values are dropped.
+func redact(v interface{}, sensitive bool) interface{} {
+	if isZero(v) {
+		return nil
+	}
+
+	if sensitive {
+		return RedactedValue
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = redact(item, isSensitive(k))
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = redact(item, false)
+		}
+		return out
+	}
+
+	return v
+}
+
+func isSensitive(field string) bool {
+	field = strings.ToLower(field)
+	for _, f := range headerMapFields {
+		if field == f {
+			return true
+		}
+	}
+
+	for _, s := range sensitiveFields {
+		if strings.Contains(field, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func isZero(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !val
+	case string:
+		return val == ""
+	case float64:
+		return val == 0
+	case []interface{}:
+		return len(val) == 0
+	case map[string]interface{}:
+		return len(val) == 0
+	}
+	return false
+}
+
+func join(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	type session struct {
+		OrgID    string            `json:"org_id"`
+		Rate     float64           `json:"rate"`
+		Tags     []string          `json:"tags"`
+		Inactive bool              `json:"is_inactive"`
+		MetaData map[string]string `json:"meta_data"`
+		JWTData  struct {
+			Secret string `json:"secret"`
+		} `json:"jwt_data"`
+	}
+
+	before := session{OrgID: "org", Rate: 10, Tags: []string{"a"}}
+	before.JWTData.Secret = "old-secret"
+
+	after := before
+	after.Rate = 20
+	after.Tags = []string{"a", "b"}
+	after.MetaData = map[string]string{"team": "x", "api_token": "t"}
+	after.JWTData.Secret = "new-secret"
+
+	assert.Equal(t, []Change{
+		{Path: "jwt_data.secret", Before: RedactedValue, After: RedactedValue},
+		{Path: "meta_data.api_token", After: RedactedValue},
+		{Path: "meta_data.team", After: "x"},
+		{Path: "rate", Before: 10.0, After: 20.0},
+		{Path: "tags", Before: []interface{}{"a"}, After: []interface{}{"a", "b"}},
+	}, Diff(before, after))
+
+	assert.Empty(t, Diff(before, before))
+
+	t.Run("create", func(t *testing.T) {
+		assert.Equal(t, []Change{
+			{Path: "jwt_data.secret", After: RedactedValue},
+			{Path: "org_id", After: "org"},
+			{Path: "rate", After: 10.0},
+			{Path: "tags", After: []interface{}{"a"}},
+		}, Diff(nil, before))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.Len(t, Diff(before, nil), 4)
+	})
+
+	t.Run("nested secrets", func(t *testing.T) {
+		changes := Diff(nil, map[string]interface{}{
+			"auth_configs": []interface{}{map[string]interface{}{"name": "jwt", "signing_secret": "s"}},
+		})
+		assert.Equal(t, []Change{{
+			Path:  "auth_configs",
+			After: []interface{}{map[string]interface{}{"name": "jwt", "signing_secret": RedactedValue}},
+		}}, changes)
+	})
+
+	t.Run("headers", func(t *testing.T) {
+		changes := Diff(nil, map[string]interface{}{
+			"global_headers": map[string]interface{}{"X-Upstream-Key": "k"},
+			"event_handlers": []interface{}{map[string]interface{}{"header_map": map[string]interface{}{"X-Hook": "h"}}},
+			"meta_data":      map[string]interface{}{"Authorization": "Bearer t", "X-Api-Key": "k", "Cookie": "c", "team": "x"},
+		})
+		assert.Equal(t, []Change{
+			{Path: "event_handlers", After: []interface{}{map[string]interface{}{"header_map": RedactedValue}}},
+			{Path: "global_headers.X-Upstream-Key", After: RedactedValue},
+			{Path: "meta_data.Authorization", After: RedactedValue},
+			{Path: "meta_data.Cookie", After: RedactedValue},
+			{Path: "meta_data.X-Api-Key", After: RedactedValue},
+			{Path: "meta_data.team", After: "x"},
+		}, changes)
+	})
+}
+
+func TestQuery_Match(t *testing.T) {
+	rec := &Record{Actor: Actor{Type: ActorCredential, Name: "ci"}, Action: "create", Resource: "key", ResourceID: "abc"}
+
+	assert.True(t, Query{}.Match(rec))
+	assert.True(t, Query{Actor: "ci", Action: "create", Resource: "key", ResourceID: "abc"}.Match(rec))
+	assert.True(t, Query{Actor: ActorCredential}.Match(rec))
+	assert.False(t, Query{Actor: ActorSecret}.Match(rec))
+	assert.False(t, Query{Action: "delete"}.Match(rec))
+	assert.False(t, Query{Resource: "api"}.Match(rec))
+	assert.False(t, Query{ResourceID: "def"}.Match(rec))
+}
@@ -0,0 +1,98 @@
+// Package audit records the changes made through the Gateway Control API
+// and writes them to a sink they can be listed from.
+package audit
+
+import (
+	"errors"
+	"time"
+)
+
+// Actor types.
+const (
+	ActorSecret     = "secret"
+	ActorCredential = "credential"
+	// ActorAnonymous is a call made without a valid secret or credential, it's always denied.
+	ActorAnonymous = "anonymous"
+)
+
+// Results of an audited action.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// ErrNotQueryable is returned when listing the records of a sink that can't be read back.
+var ErrNotQueryable = errors.New("audit log sink can't be queried")
+
+// Record is an audited Control API call.
+type Record struct {
+	ID         string    `json:"id"`
+	Timestamp  time.Time `json:"timestamp"`
+	Actor      Actor     `json:"actor"`
+	SourceIP   string    `json:"source_ip"`
+	Method     string    `json:"method"`
+	Route      string    `json:"route"`
+	Action     string    `json:"action"`
+	Resource   string    `json:"resource"`
+	ResourceID string    `json:"resource_id,omitempty"`
+	Changes    []Change  `json:"changes,omitempty"`
+	Result     Result    `json:"result"`
+}
+
+// Actor is who made the call: the Control API secret, a scoped credential or anonymous, along
+// with the subject of the client certificate when mutual TLS is used.
+type Actor struct {
+	Type        string `json:"type"`
+	Name        string `json:"name,omitempty"`
+	CertSubject string `json:"cert_subject,omitempty"`
+}
+
+// Result is the outcome of the call.
+type Result struct {
+	Status  string `json:"status"`
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// Query filters and paginates the records returned by a Lister, most recent first.
+type Query struct {
+	// Cursor is the NextCursor of the previous page, empty for the first page.
+	Cursor string
+	Limit  int
+
+	Actor      string
+	Action     string
+	Resource   string
+	ResourceID string
+}
+
+// Match reports whether the record matches the query filters.
+func (q Query) Match(rec *Record) bool {
+	switch {
+	case q.Actor != "" && q.Actor != rec.Actor.Name && q.Actor != rec.Actor.Type:
+		return false
+	case q.Action != "" && q.Action != rec.Action:
+		return false
+	case q.Resource != "" && q.Resource != rec.Resource:
+		return false
+	case q.ResourceID != "" && q.ResourceID != rec.ResourceID:
+		return false
+	}
+	return true
+}
+
+// Page is a page of records, NextCursor is empty on the last page.
+type Page struct {
+	Records    []*Record `json:"records"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// Sink writes audit records.
+type Sink interface {
+	Write(rec *Record) error
+}
+
+// Lister lists the records written to a sink.
+type Lister interface {
+	List(q Query) (Page, error)
+}
@@ -0,0 +1,248 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/TykTechnologies/tyk/internal/redis"
+)
+
+// Sink names.
+const (
+	SinkStdout = "stdout"
+	SinkFile   = "file"
+	SinkRedis  = "redis"
+)
+
+const (
+	// DefaultStream is the Redis stream records are added to when none is configured.
+	DefaultStream = "tyk-audit-log"
+	// DefaultMaxLen is the number of records kept in the Redis stream when no limit is configured.
+	DefaultMaxLen = 100000
+
+	// streamRecordField is the stream entry field holding the JSON encoded record.
+	streamRecordField = "record"
+	// streamBatchSize is the minimum number of entries read from the stream at once when listing.
+	streamBatchSize = 100
+)
+
+// WriterSink writes records to w as JSON lines, it's used for stdout.
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink returns a sink writing to w.
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// Write writes the record.
+func (s *WriterSink) Write(rec *Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
+
+// FileSink appends records to a file as JSON lines.
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileSink returns a sink appending to the file at path, the file is created when missing.
+func NewFileSink(path string) (*FileSink, error) {
+	if path == "" {
+		return nil, errors.New("audit log file path is required")
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileSink{path: path}, f.Close()
+}
+
+// Write appends the record to the file.
+func (s *FileSink) Write(rec *Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// List reads the file and returns the records matching the query, the cursor is a record ID.
+func (s *FileSink) List(q Query) (Page, error) {
+	s.mu.Lock()
+	f, err := os.Open(s.path)
+	if err != nil {
+		s.mu.Unlock()
+		return Page{}, err
+	}
+
+	var records []*Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		records = append(records, &rec)
+	}
+	err = scanner.Err()
+	f.Close()
+	s.mu.Unlock()
+
+	if err != nil {
+		return Page{}, err
+	}
+
+	page := Page{Records: []*Record{}}
+	skip := q.Cursor != ""
+	for i := len(records) - 1; i >= 0; i-- {
+		rec := records[i]
+		if skip {
+			skip = rec.ID != q.Cursor
+			continue
+		}
+
+		if !q.Match(rec) {
+			continue
+		}
+
+		if len(page.Records) == q.Limit {
+			page.NextCursor = page.Records[len(page.Records)-1].ID
+			break
+		}
+		page.Records = append(page.Records, rec)
+	}
+
+	return page, nil
+}
+
+// StreamSink adds records to a Redis stream capped to MaxLen entries.
+type StreamSink struct {
+	client func() (redis.UniversalClient, error)
+	stream string
+	maxLen int64
+}
+
+// NewStreamSink returns a sink adding records to the stream, client returns the Redis client to use.
+func NewStreamSink(client func() (redis.UniversalClient, error), stream string, maxLen int64) *StreamSink {
+	if stream == "" {
+		stream = DefaultStream
+	}
+	if maxLen <= 0 {
+		maxLen = DefaultMaxLen
+	}
+	return &StreamSink{client: client, stream: stream, maxLen: maxLen}
+}
+
+// Write adds the record to the stream.
+func (s *StreamSink) Write(rec *Record) error {
+	client, err := s.client()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	return client.XAdd(context.Background(), &redis.XAddArgs{
+		Stream: s.stream,
+		MaxLen: s.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{streamRecordField: string(data)},
+	}).Err()
+}
+
+// List returns the stream records matching the query, the cursor is a stream entry ID.
+func (s *StreamSink) List(q Query) (Page, error) {
+	client, err := s.client()
+	if err != nil {
+		return Page{}, err
+	}
+
+	page := Page{Records: []*Record{}}
+	end := "+"
+	if q.Cursor != "" {
+		end = "(" + q.Cursor
+	}
+
+	// entries not matching the filters are skipped, so the stream is read in batches until the page is full
+	batch := int64(q.Limit)
+	if batch < streamBatchSize {
+		batch = streamBatchSize
+	}
+
+	var last string
+	for {
+		msgs, err := client.XRevRangeN(context.Background(), s.stream, end, "-", batch).Result()
+		if err != nil {
+			return Page{}, err
+		}
+
+		for _, msg := range msgs {
+			end = "(" + msg.ID
+
+			rec, ok := streamRecord(msg)
+			if !ok || !q.Match(rec) {
+				continue
+			}
+
+			if len(page.Records) == q.Limit {
+				page.NextCursor = last
+				return page, nil
+			}
+			page.Records = append(page.Records, rec)
+			last = msg.ID
+		}
+
+		if int64(len(msgs)) < batch {
+			return page, nil
+		}
+	}
+}
+
+func streamRecord(msg redis.XMessage) (*Record, bool) {
+	data, ok := msg.Values[streamRecordField].(string)
+	if !ok {
+		return nil, false
+	}
+
+	var rec Record
+	if err := json.Unmarshal([]byte(data), &rec); err != nil {
+		return nil, false
+	}
+	return &rec, true
+}
@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewWriterSink(&buf)
+
+	require.NoError(t, sink.Write(&Record{ID: "1", Action: "create"}))
+	require.NoError(t, sink.Write(&Record{ID: "2", Action: "delete"}))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var rec Record
+	require.NoError(t, json.Unmarshal(lines[1], &rec))
+	assert.Equal(t, "2", rec.ID)
+
+	var sinkI Sink = sink
+	_, ok := sinkI.(Lister)
+	assert.False(t, ok, "stdout can't be queried")
+}
+
+func TestFileSink(t *testing.T) {
+	_, err := NewFileSink("")
+	assert.Error(t, err)
+
+	sink, err := NewFileSink(filepath.Join(t.TempDir(), "audit.log"))
+	require.NoError(t, err)
+
+	for i := 1; i <= 5; i++ {
+		action := "create"
+		if i%2 == 0 {
+			action = "delete"
+		}
+		require.NoError(t, sink.Write(&Record{ID: fmt.Sprint(i), Action: action}))
+	}
+
+	ids := func(page Page) []string {
+		var out []string
+		for _, rec := range page.Records {
+			out = append(out, rec.ID)
+		}
+		return out
+	}
+
+	page, err := sink.List(Query{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"5", "4"}, ids(page))
+	assert.Equal(t, "4", page.NextCursor)
+
+	page, err = sink.List(Query{Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3", "2"}, ids(page))
+
+	page, err = sink.List(Query{Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, ids(page))
+	assert.Empty(t, page.NextCursor)
+
+	page, err = sink.List(Query{Limit: 10, Action: "create"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"5", "3", "1"}, ids(page))
+	assert.Empty(t, page.NextCursor)
+}
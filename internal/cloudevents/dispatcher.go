@@ -0,0 +1,224 @@
+package cloudevents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultQueueSize is the number of events buffered for delivery by default.
+	DefaultQueueSize = 1000
+	// DefaultMaxRetries is the number of delivery retries by default.
+	DefaultMaxRetries = 3
+	// DefaultRetryInterval is the delay before the first retry by default.
+	DefaultRetryInterval = time.Second
+
+	// sendTimeout bounds a single delivery attempt.
+	sendTimeout = 10 * time.Second
+)
+
+var (
+	// ErrQueueFull is returned by Publish when the delivery queue is full.
+	ErrQueueFull = errors.New("cloudevents delivery queue is full")
+
+	// ErrClosed is returned by Publish after the dispatcher has been closed.
+	ErrClosed = errors.New("cloudevents dispatcher is closed")
+)
+
+// Options configure a Dispatcher. Zero values are replaced with defaults.
+type Options struct {
+	// QueueSize is the capacity of the delivery queue.
+	QueueSize int
+	// MaxRetries is the number of retries after a failed delivery, a negative value disables retries.
+	MaxRetries int
+	// RetryInterval is the delay before the first retry, doubled on each retry.
+	RetryInterval time.Duration
+	// DeadLetterPath is the file undeliverable events are appended to.
+	DeadLetterPath string
+	// Logger is the logger used to report delivery failures.
+	Logger *logrus.Entry
+}
+
+// Dispatcher delivers events through a Transport from a bounded queue,
+// retrying failed deliveries with exponential backoff. Events that can't be
+// queued or delivered are appended to the dead-letter file.
+type Dispatcher struct {
+	transport Transport
+	opts      Options
+
+	queue  chan Event
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	closeOnce sync.Once
+	closed    chan struct{}
+
+	deadLetterMu sync.Mutex
+}
+
+// NewDispatcher creates a dispatcher and starts its delivery worker.
+func NewDispatcher(transport Transport, opts Options) *Dispatcher {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	} else if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultMaxRetries
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = DefaultRetryInterval
+	}
+	if opts.Logger == nil {
+		opts.Logger = logrus.NewEntry(logrus.StandardLogger())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Dispatcher{
+		transport: transport,
+		opts:      opts,
+		queue:     make(chan Event, opts.QueueSize),
+		ctx:       ctx,
+		cancel:    cancel,
+		closed:    make(chan struct{}),
+	}
+
+	d.wg.Add(1)
+	go d.run()
+
+	return d
+}
+
+// Publish queues the event for delivery without blocking. If the queue is
+// full, the event is dead-lettered and ErrQueueFull is returned.
+func (d *Dispatcher) Publish(e Event) error {
+	select {
+	case <-d.closed:
+		return ErrClosed
+	default:
+	}
+
+	select {
+	case d.queue <- e:
+		return nil
+	default:
+		d.deadLetter(e, ErrQueueFull)
+		return ErrQueueFull
+	}
+}
+
+// Close stops the delivery worker and closes the transport. Events still
+// queued are dead-lettered.
+func (d *Dispatcher) Close() error {
+	var err error
+	d.closeOnce.Do(func() {
+		close(d.closed)
+		d.cancel()
+		d.wg.Wait()
+
+		for len(d.queue) > 0 {
+			d.deadLetter(<-d.queue, ErrClosed)
+		}
+
+		err = d.transport.Close()
+	})
+	return err
+}
+
+func (d *Dispatcher) run() {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case e := <-d.queue:
+			d.deliver(e)
+		}
+	}
+}
+
+// deliver sends the event, retrying with exponential backoff, and dead-letters
+// it once all attempts have failed.
+func (d *Dispatcher) deliver(e Event) {
+	payload, err := e.Marshal()
+	if err != nil {
+		d.deadLetter(e, err)
+		return
+	}
+
+	backoff := d.opts.RetryInterval
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(d.ctx, sendTimeout)
+		err = d.transport.Send(ctx, e, payload)
+		cancel()
+
+		if err == nil {
+			return
+		}
+
+		if attempt >= d.opts.MaxRetries {
+			break
+		}
+
+		d.opts.Logger.WithError(err).WithField("id", e.ID).Debugf("cloudevent delivery failed, retrying in %s", backoff)
+
+		select {
+		case <-d.ctx.Done():
+			d.deadLetter(e, err)
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	d.deadLetter(e, err)
+}
+
+// deadLetterRecord is a line of the dead-letter file.
+type deadLetterRecord struct {
+	Error string `json:"error"`
+	Event Event  `json:"event"`
+}
+
+func (d *Dispatcher) deadLetter(e Event, cause error) {
+	logger := d.opts.Logger.WithError(cause).WithFields(logrus.Fields{
+		"id":   e.ID,
+		"type": e.Type,
+	})
+
+	if d.opts.DeadLetterPath == "" {
+		logger.Error("cloudevent could not be delivered")
+		return
+	}
+
+	line, err := json.Marshal(deadLetterRecord{Error: cause.Error(), Event: e})
+	if err != nil {
+		logger.Error("cloudevent could not be delivered nor dead-lettered")
+		return
+	}
+
+	d.deadLetterMu.Lock()
+	defer d.deadLetterMu.Unlock()
+
+	f, err := os.OpenFile(d.opts.DeadLetterPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		logger.WithField("dead_letter_error", err.Error()).Error("cloudevent could not be delivered nor dead-lettered")
+		return
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		logger.WithField("dead_letter_error", err.Error()).Error("cloudevent could not be delivered nor dead-lettered")
+		return
+	}
+
+	logger.Warning("cloudevent could not be delivered, written to dead-letter file")
+}
@@ -0,0 +1,133 @@
+package cloudevents
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTransport struct {
+	mu       sync.Mutex
+	failures int
+	sent     []Event
+	attempts int
+	block    chan struct{}
+}
+
+func (m *mockTransport) Send(_ context.Context, e Event, _ []byte) error {
+	if m.block != nil {
+		<-m.block
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.attempts++
+	if m.failures != 0 {
+		if m.failures > 0 {
+			m.failures--
+		}
+		return errors.New("broker unavailable")
+	}
+
+	m.sent = append(m.sent, e)
+	return nil
+}
+
+func (m *mockTransport) Close() error {
+	return nil
+}
+
+func (m *mockTransport) counts() (sent, attempts int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sent), m.attempts
+}
+
+func readDeadLetters(t *testing.T, path string) []deadLetterRecord {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var records []deadLetterRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record deadLetterRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestDispatcher_Retry(t *testing.T) {
+	transport := &mockTransport{failures: 2}
+	d := NewDispatcher(transport, Options{RetryInterval: time.Millisecond})
+	defer d.Close()
+
+	require.NoError(t, d.Publish(New("", "HostDown", nil)))
+
+	assert.Eventually(t, func() bool {
+		sent, attempts := transport.counts()
+		return sent == 1 && attempts == 3
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestDispatcher_DeadLetter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letter.jsonl")
+
+	transport := &mockTransport{failures: -1}
+	d := NewDispatcher(transport, Options{
+		MaxRetries:     1,
+		RetryInterval:  time.Millisecond,
+		DeadLetterPath: path,
+	})
+	defer d.Close()
+
+	e := New("", "HostDown", nil)
+	require.NoError(t, d.Publish(e))
+
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+
+	records := readDeadLetters(t, path)
+	require.Len(t, records, 1)
+	assert.Equal(t, e.ID, records[0].Event.ID)
+	assert.Equal(t, "broker unavailable", records[0].Error)
+
+	_, attempts := transport.counts()
+	assert.Equal(t, 2, attempts)
+}
+
+func TestDispatcher_QueueFull(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letter.jsonl")
+
+	transport := &mockTransport{block: make(chan struct{})}
+	d := NewDispatcher(transport, Options{QueueSize: 1, DeadLetterPath: path})
+
+	// the first event is picked up by the blocked worker, the second fills the queue
+	require.NoError(t, d.Publish(New("", "HostDown", nil)))
+	assert.Eventually(t, func() bool {
+		return len(d.queue) == 0
+	}, time.Second, time.Millisecond)
+	require.NoError(t, d.Publish(New("", "HostDown", nil)))
+
+	assert.ErrorIs(t, d.Publish(New("", "HostDown", nil)), ErrQueueFull)
+	assert.Len(t, readDeadLetters(t, path), 1)
+
+	close(transport.block)
+	require.NoError(t, d.Close())
+	assert.ErrorIs(t, d.Publish(New("", "HostDown", nil)), ErrClosed)
+}
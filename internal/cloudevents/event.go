@@ -0,0 +1,59 @@
+// Package cloudevents delivers gateway events as CloudEvents 1.0 structured
+// JSON envelopes to message brokers, with a bounded retry queue and a
+// dead-letter file for events that could not be delivered.
+package cloudevents
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/TykTechnologies/tyk/internal/uuid"
+)
+
+const (
+	// SpecVersion is the CloudEvents specification version of the envelopes.
+	SpecVersion = "1.0"
+
+	// ContentType is the media type of a structured mode CloudEvent.
+	ContentType = "application/cloudevents+json"
+
+	// DefaultSource is the `source` attribute used when none is configured.
+	DefaultSource = "/tyk/gateway"
+
+	// TypePrefix prefixes the Tyk event name to form the `type` attribute.
+	TypePrefix = "io.tyk.gateway."
+)
+
+// Event is a CloudEvents 1.0 envelope in structured JSON format.
+type Event struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            any       `json:"data,omitempty"`
+}
+
+// New creates an event for the Tyk event name with a unique ID and the current time.
+func New(source, name string, data any) Event {
+	if source == "" {
+		source = DefaultSource
+	}
+
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              uuid.New(),
+		Source:          source,
+		Type:            TypePrefix + name,
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+}
+
+// Marshal encodes the event as a structured mode JSON envelope.
+func (e Event) Marshal() ([]byte, error) {
+	return json.Marshal(e)
+}
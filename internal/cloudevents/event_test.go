@@ -0,0 +1,40 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	e := New("", "HostDown", map[string]string{"host": "upstream:8080"})
+
+	assert.Equal(t, SpecVersion, e.SpecVersion)
+	assert.Equal(t, DefaultSource, e.Source)
+	assert.Equal(t, "io.tyk.gateway.HostDown", e.Type)
+	assert.NotEmpty(t, e.ID)
+	assert.False(t, e.Time.IsZero())
+
+	other := New("/tyk/prod", "HostDown", nil)
+	assert.Equal(t, "/tyk/prod", other.Source)
+	assert.NotEqual(t, e.ID, other.ID)
+}
+
+func TestEvent_Marshal(t *testing.T) {
+	e := New("/tyk/prod", "QuotaExceeded", map[string]string{"key": "abc"})
+
+	data, err := e.Marshal()
+	require.NoError(t, err)
+
+	var envelope map[string]any
+	require.NoError(t, json.Unmarshal(data, &envelope))
+
+	assert.Equal(t, "1.0", envelope["specversion"])
+	assert.Equal(t, "/tyk/prod", envelope["source"])
+	assert.Equal(t, "io.tyk.gateway.QuotaExceeded", envelope["type"])
+	assert.Equal(t, "application/json", envelope["datacontenttype"])
+	assert.Equal(t, map[string]any{"key": "abc"}, envelope["data"])
+	assert.NotContains(t, envelope, "subject")
+}
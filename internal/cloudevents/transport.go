@@ -0,0 +1,299 @@
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/nats-io/nats.go"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/TykTechnologies/tyk/apidef"
+)
+
+// Transport names supported by NewTransport.
+const (
+	TransportKafka = "kafka"
+	TransportNATS  = "nats"
+	TransportAMQP  = "amqp"
+	TransportHTTP  = "http"
+)
+
+var (
+	// ErrUnknownTransport is returned for an unsupported transport name.
+	ErrUnknownTransport = errors.New("unknown cloudevents transport")
+
+	// ErrMissingTarget is returned when the transport has no destination configured.
+	ErrMissingTarget = errors.New("cloudevents transport destination is not configured")
+)
+
+// Transport delivers encoded events to a message broker. Connections are
+// established lazily on the first Send and re-established after a failure.
+type Transport interface {
+	Send(ctx context.Context, e Event, payload []byte) error
+	Close() error
+}
+
+// NewTransport creates the transport configured in conf. The HTTP client is
+// used by the HTTP binding and may be nil to use http.DefaultClient.
+func NewTransport(conf apidef.CloudEventsHandlerConf, client *http.Client) (Transport, error) {
+	switch conf.Transport {
+	case TransportKafka:
+		if len(conf.Kafka.Brokers) == 0 || conf.Kafka.Topic == "" {
+			return nil, fmt.Errorf("%w: kafka brokers and topic are required", ErrMissingTarget)
+		}
+		return &kafkaTransport{conf: conf.Kafka}, nil
+	case TransportNATS:
+		if conf.NATS.URL == "" || conf.NATS.Subject == "" {
+			return nil, fmt.Errorf("%w: nats url and subject are required", ErrMissingTarget)
+		}
+		return &natsTransport{conf: conf.NATS}, nil
+	case TransportAMQP:
+		if conf.AMQP.URL == "" {
+			return nil, fmt.Errorf("%w: amqp url is required", ErrMissingTarget)
+		}
+		return &amqpTransport{conf: conf.AMQP}, nil
+	case TransportHTTP:
+		if conf.HTTP.URL == "" {
+			return nil, fmt.Errorf("%w: http url is required", ErrMissingTarget)
+		}
+		if client == nil {
+			client = http.DefaultClient
+		}
+		return &httpTransport{conf: conf.HTTP, client: client}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownTransport, conf.Transport)
+}
+
+// kafkaTransport produces events to a Kafka topic in structured content
+// mode, with the envelope as the record value.
+type kafkaTransport struct {
+	conf apidef.CloudEventsKafkaConf
+
+	mu       sync.Mutex
+	producer sarama.AsyncProducer
+}
+
+// Send produces the event and waits for it to be acknowledged, or for ctx to be done. The
+// acknowledgements of events whose Send was cancelled are skipped by the following ones.
+func (k *kafkaTransport) Send(ctx context.Context, e Event, payload []byte) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if k.producer == nil {
+		cfg := sarama.NewConfig()
+		cfg.Producer.Return.Successes = true
+		if deadline, ok := ctx.Deadline(); ok {
+			cfg.Net.DialTimeout = time.Until(deadline)
+		}
+
+		producer, err := sarama.NewAsyncProducer(k.conf.Brokers, cfg)
+		if err != nil {
+			return err
+		}
+		k.producer = producer
+	}
+
+	msg := &sarama.ProducerMessage{
+		Topic: k.conf.Topic,
+		Key:   sarama.StringEncoder(e.ID),
+		Value: sarama.ByteEncoder(payload),
+		Headers: []sarama.RecordHeader{
+			{Key: []byte("content-type"), Value: []byte(ContentType)},
+		},
+		Metadata: e.ID,
+	}
+
+	select {
+	case k.producer.Input() <- msg:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	for {
+		select {
+		case res := <-k.producer.Successes():
+			if res.Metadata == e.ID {
+				return nil
+			}
+		case res := <-k.producer.Errors():
+			if res.Msg.Metadata == e.ID {
+				_ = k.producer.Close()
+				k.producer = nil
+				return res.Err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (k *kafkaTransport) Close() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.producer == nil {
+		return nil
+	}
+
+	err := k.producer.Close()
+	k.producer = nil
+	return err
+}
+
+// natsTransport publishes events to a NATS subject.
+type natsTransport struct {
+	conf apidef.CloudEventsNATSConf
+
+	mu   sync.Mutex
+	conn *nats.Conn
+}
+
+// Send publishes the event and flushes it to the server within the deadline of ctx.
+func (n *natsTransport) Send(ctx context.Context, _ Event, payload []byte) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if n.conn == nil || n.conn.IsClosed() {
+		var opts []nats.Option
+		if deadline, ok := ctx.Deadline(); ok {
+			opts = append(opts, nats.Timeout(time.Until(deadline)))
+		}
+
+		conn, err := nats.Connect(n.conf.URL, opts...)
+		if err != nil {
+			return err
+		}
+		n.conn = conn
+	}
+
+	if err := n.conn.Publish(n.conf.Subject, payload); err != nil {
+		return err
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		// flushing with a context requires a deadline
+		return n.conn.Flush()
+	}
+	return n.conn.FlushWithContext(ctx)
+}
+
+func (n *natsTransport) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.conn != nil {
+		n.conn.Close()
+		n.conn = nil
+	}
+	return nil
+}
+
+// amqpTransport publishes events to an AMQP 0.9.1 exchange.
+type amqpTransport struct {
+	conf apidef.CloudEventsAMQPConf
+
+	mu      sync.Mutex
+	conn    *amqp.Connection
+	channel *amqp.Channel
+}
+
+func (a *amqpTransport) Send(ctx context.Context, e Event, payload []byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.conn == nil || a.conn.IsClosed() {
+		conn, err := amqp.Dial(a.conf.URL)
+		if err != nil {
+			return err
+		}
+
+		channel, err := conn.Channel()
+		if err != nil {
+			_ = conn.Close()
+			return err
+		}
+
+		a.conn, a.channel = conn, channel
+	}
+
+	err := a.channel.PublishWithContext(ctx, a.conf.Exchange, a.conf.RoutingKey, false, false, amqp.Publishing{
+		ContentType:  ContentType,
+		MessageId:    e.ID,
+		Timestamp:    e.Time,
+		Type:         e.Type,
+		DeliveryMode: amqp.Persistent,
+		Body:         payload,
+	})
+	if err != nil {
+		a.reset()
+	}
+	return err
+}
+
+func (a *amqpTransport) reset() {
+	if a.channel != nil {
+		_ = a.channel.Close()
+	}
+	if a.conn != nil {
+		_ = a.conn.Close()
+	}
+	a.conn, a.channel = nil, nil
+}
+
+func (a *amqpTransport) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.reset()
+	return nil
+}
+
+// httpTransport POSTs events to an HTTP endpoint in structured content mode.
+type httpTransport struct {
+	conf   apidef.CloudEventsHTTPConf
+	client *http.Client
+}
+
+func (h *httpTransport) Send(ctx context.Context, _ Event, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.conf.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	for k, v := range h.conf.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", ContentType)
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("cloudevents http delivery failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (h *httpTransport) Close() error {
+	return nil
+}
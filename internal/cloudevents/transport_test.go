@@ -0,0 +1,117 @@
+package cloudevents
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef"
+)
+
+func TestNewTransport(t *testing.T) {
+	testcases := []struct {
+		name string
+		conf apidef.CloudEventsHandlerConf
+		err  error
+	}{
+		{
+			name: "kafka",
+			conf: apidef.CloudEventsHandlerConf{Transport: TransportKafka, Kafka: apidef.CloudEventsKafkaConf{Brokers: []string{"localhost:9092"}, Topic: "events"}},
+		},
+		{
+			name: "kafka without topic",
+			conf: apidef.CloudEventsHandlerConf{Transport: TransportKafka, Kafka: apidef.CloudEventsKafkaConf{Brokers: []string{"localhost:9092"}}},
+			err:  ErrMissingTarget,
+		},
+		{
+			name: "nats",
+			conf: apidef.CloudEventsHandlerConf{Transport: TransportNATS, NATS: apidef.CloudEventsNATSConf{URL: "nats://localhost:4222", Subject: "events"}},
+		},
+		{
+			name: "amqp without url",
+			conf: apidef.CloudEventsHandlerConf{Transport: TransportAMQP},
+			err:  ErrMissingTarget,
+		},
+		{
+			name: "http",
+			conf: apidef.CloudEventsHandlerConf{Transport: TransportHTTP, HTTP: apidef.CloudEventsHTTPConf{URL: "http://localhost"}},
+		},
+		{
+			name: "unknown",
+			conf: apidef.CloudEventsHandlerConf{Transport: "carrier-pigeon"},
+			err:  ErrUnknownTransport,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			transport, err := NewTransport(tc.conf, nil)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NoError(t, transport.Close())
+		})
+	}
+}
+
+func TestHTTPTransport_Send(t *testing.T) {
+	var (
+		gotContentType string
+		gotAuth        string
+		gotBody        []byte
+	)
+
+	status := http.StatusAccepted
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		gotAuth = r.Header.Get("Authorization")
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	transport, err := NewTransport(apidef.CloudEventsHandlerConf{
+		Transport: TransportHTTP,
+		HTTP: apidef.CloudEventsHTTPConf{
+			URL:     srv.URL,
+			Headers: map[string]string{"Authorization": "Bearer token"},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	e := New("", "HostUp", nil)
+	payload, err := e.Marshal()
+	require.NoError(t, err)
+
+	require.NoError(t, transport.Send(context.Background(), e, payload))
+	assert.Equal(t, ContentType, gotContentType)
+	assert.Equal(t, "Bearer token", gotAuth)
+	assert.Equal(t, payload, gotBody)
+
+	status = http.StatusInternalServerError
+	assert.Error(t, transport.Send(context.Background(), e, payload))
+}
+
+func TestTransport_SendCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, conf := range []apidef.CloudEventsHandlerConf{
+		{Transport: TransportKafka, Kafka: apidef.CloudEventsKafkaConf{Brokers: []string{"localhost:9092"}, Topic: "events"}},
+		{Transport: TransportNATS, NATS: apidef.CloudEventsNATSConf{URL: "nats://localhost:4222", Subject: "events"}},
+	} {
+		transport, err := NewTransport(conf, nil)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, transport.Send(ctx, New("", "HostUp", nil), []byte("{}")), context.Canceled, conf.Transport)
+		assert.NoError(t, transport.Close())
+	}
+}
@@ -62,11 +62,59 @@ const (
 	RateLimitSmoothingDown Event = "RateLimitSmoothingDown"
 )
 
+// Gateway lifecycle events, fired to the global event handlers.
+const (
+	// APIAdded is the event triggered when an API definition is loaded for the first time.
+	APIAdded Event = "APIAdded"
+	// APIUpdated is the event triggered when a changed API definition is reloaded.
+	APIUpdated Event = "APIUpdated"
+	// APIRemoved is the event triggered when an API definition is unloaded.
+	APIRemoved Event = "APIRemoved"
+	// PolicyChanged is the event triggered when a policy is added, updated or removed.
+	PolicyChanged Event = "PolicyChanged"
+	// ReloadCompleted is the event triggered when the gateway has reloaded its APIs and policies.
+	ReloadCompleted Event = "ReloadCompleted"
+	// ReloadFailed is the event triggered when the gateway failed to sync APIs or policies during a reload.
+	ReloadFailed Event = "ReloadFailed"
+	// CertificateAdded is the event triggered when a certificate is added to the certificate store.
+	CertificateAdded Event = "CertificateAdded"
+	// CertificateRemoved is the event triggered when a certificate is removed from the certificate store.
+	CertificateRemoved Event = "CertificateRemoved"
+	// RPCEmergencyModeEntered is the event triggered when the gateway loses its RPC connection and enters emergency mode.
+	RPCEmergencyModeEntered Event = "RPCEmergencyModeEntered"
+	// RPCEmergencyModeExited is the event triggered when the RPC connection is restored and emergency mode ends.
+	RPCEmergencyModeExited Event = "RPCEmergencyModeExited"
+	// APIScheduleActivated is the event triggered when a scheduled API becomes active.
+	APIScheduleActivated Event = "APIScheduleActivated"
+	// APIScheduleDeactivated is the event triggered when a scheduled API becomes inactive.
+	APIScheduleDeactivated Event = "APIScheduleDeactivated"
+	// PolicyScheduleActivated is the event triggered when a scheduled policy becomes active.
+	PolicyScheduleActivated Event = "PolicyScheduleActivated"
+	// PolicyScheduleDeactivated is the event triggered when a scheduled policy becomes inactive.
+	PolicyScheduleDeactivated Event = "PolicyScheduleDeactivated"
+)
+
 // eventMap contains a map of events to a readable title for the event.
 // The title value should not contain ending punctuation.
 var eventMap = map[Event]string{
 	RateLimitSmoothingUp:   "Rate limit increased with smoothing",
 	RateLimitSmoothingDown: "Rate limit decreased with smoothing",
+
+	APIAdded:                "API definition added",
+	APIUpdated:              "API definition updated",
+	APIRemoved:              "API definition removed",
+	PolicyChanged:           "Policy changed",
+	ReloadCompleted:         "Gateway reload completed",
+	ReloadFailed:            "Gateway reload failed",
+	CertificateAdded:        "Certificate added",
+	CertificateRemoved:      "Certificate removed",
+	RPCEmergencyModeEntered: "Gateway entered RPC emergency mode",
+	RPCEmergencyModeExited:  "Gateway exited RPC emergency mode",
+
+	APIScheduleActivated:      "Scheduled API activated",
+	APIScheduleDeactivated:    "Scheduled API deactivated",
+	PolicyScheduleActivated:   "Scheduled policy activated",
+	PolicyScheduleDeactivated: "Scheduled policy deactivated",
 }
 
 // String will return the description for the event if any.
@@ -91,6 +139,8 @@ const (
 	JSVMHandler HandlerName = "eh_dynamic_handler"
 	// CoProcessHandler is the HandlerName used in classic API definition for coprocess event handler.
 	CoProcessHandler HandlerName = "cp_dynamic_handler"
+	// CloudEventsHandler is the HandlerName used in classic API definition for CloudEvents event handler.
+	CloudEventsHandler HandlerName = "eh_cloud_events_handler"
 )
 
 // Kind is the action to be performed when an event is triggered, to be used in OAS API definition.
@@ -103,6 +153,8 @@ const (
 	JSVMKind Kind = "custom"
 	// LogKind represents a log action to be performed when an event is triggered.
 	LogKind Kind = "log"
+	// CloudEventsKind represents delivery of the event as a CloudEvent to a message broker.
+	CloudEventsKind Kind = "cloudevents"
 )
 
 type contextKey string
@@ -0,0 +1,145 @@
+package gitops
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Target is a Gateway a plan is applied to, through the Control API or in process.
+type Target interface {
+	// State returns the APIs, policies and certificates of the Gateway.
+	State() (*State, error)
+
+	// PutAPI creates or updates an API, PutPolicy a policy.
+	PutAPI(api *API, create bool) error
+	DeleteAPI(api *API) error
+	PutPolicy(pol *Policy, create bool) error
+	DeletePolicy(pol *Policy) error
+	AddCertificate(cert *Certificate) error
+	DeleteCertificate(cert *Certificate) error
+
+	// Reload reloads the Gateway and returns once it's done.
+	Reload() error
+	// LoadedAPIs returns the IDs of the APIs the Gateway serves.
+	LoadedAPIs() (map[string]bool, error)
+}
+
+// ErrRolledBack is returned when a plan failed to apply and the changes were reverted.
+var ErrRolledBack = errors.New("changes were rolled back")
+
+// Apply applies the plan to the target, reloads it and checks every desired API is loaded.
+// When a change fails or an API doesn't load, the changes already made are reverted and
+// the target is reloaded again, so the Gateway is left as it was. Certificates can't be
+// read back from a Gateway, so they're only deleted once the other changes succeeded.
+func Apply(target Target, plan *Plan) error {
+	var applied, certDeletes []Change
+
+	err := func() error {
+		for _, c := range plan.Changes {
+			if c.Op == OpDelete && c.Kind == KindCertificate {
+				certDeletes = append(certDeletes, c)
+				continue
+			}
+
+			if err := applyChange(target, c); err != nil {
+				return fmt.Errorf("%s %s %s: %w", c.Op, c.Kind, c.ID, err)
+			}
+			applied = append(applied, c)
+		}
+
+		if err := target.Reload(); err != nil {
+			return fmt.Errorf("reload: %w", err)
+		}
+
+		loaded, err := target.LoadedAPIs()
+		if err != nil {
+			return err
+		}
+
+		var missing []string
+		for _, id := range plan.apis {
+			if !loaded[id] {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return fmt.Errorf("APIs failed to load: %s", strings.Join(missing, ", "))
+		}
+		return nil
+	}()
+	if err != nil {
+		if rbErr := rollback(target, applied); rbErr != nil {
+			return fmt.Errorf("%w, rollback failed: %v", err, rbErr)
+		}
+		return fmt.Errorf("%w, %w", err, ErrRolledBack)
+	}
+
+	var errs []error
+	for _, c := range certDeletes {
+		if err := applyChange(target, c); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s %s: %w", c.Op, c.Kind, c.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func applyChange(target Target, c Change) error {
+	switch after := c.After.(type) {
+	case *API:
+		if before, ok := c.Before.(*API); ok && before.OAS != after.OAS {
+			// an API can't be converted between classic and OAS in place
+			if err := target.DeleteAPI(before); err != nil {
+				return err
+			}
+			return target.PutAPI(after, true)
+		}
+		return target.PutAPI(after, c.Op == OpCreate)
+	case *Policy:
+		return target.PutPolicy(after, c.Op == OpCreate)
+	case *Certificate:
+		return target.AddCertificate(after)
+	}
+
+	switch before := c.Before.(type) {
+	case *API:
+		return target.DeleteAPI(before)
+	case *Policy:
+		return target.DeletePolicy(before)
+	case *Certificate:
+		return target.DeleteCertificate(before)
+	}
+
+	return fmt.Errorf("unknown change %s %s", c.Op, c.Kind)
+}
+
+// rollback reverts the applied changes in reverse order and reloads the target.
+func rollback(target Target, applied []Change) error {
+	var errs []error
+
+	for i := len(applied) - 1; i >= 0; i-- {
+		c := applied[i]
+
+		var err error
+		switch c.Op {
+		case OpCreate:
+			err = applyChange(target, Change{Op: OpDelete, Kind: c.Kind, ID: c.ID, Before: c.After})
+		case OpUpdate:
+			err = applyChange(target, Change{Op: OpUpdate, Kind: c.Kind, ID: c.ID, Before: c.After, After: c.Before})
+		case OpDelete:
+			err = applyChange(target, Change{Op: OpCreate, Kind: c.Kind, ID: c.ID, After: c.Before})
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", c.Kind, c.ID, err))
+		}
+	}
+
+	if err := target.Reload(); err != nil {
+		errs = append(errs, fmt.Errorf("reload: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,168 @@
+package gitops
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTarget keeps the resources in memory, APIs listed in broken never load.
+type fakeTarget struct {
+	apis     map[string]*API
+	policies map[string]*Policy
+	certs    map[string]*Certificate
+	loaded   map[string]bool
+	broken   map[string]bool
+	failPut  string
+	reloads  int
+}
+
+func newFakeTarget(state *State) *fakeTarget {
+	t := &fakeTarget{
+		apis:     map[string]*API{},
+		policies: map[string]*Policy{},
+		certs:    map[string]*Certificate{},
+		broken:   map[string]bool{},
+	}
+	for _, api := range state.APIs {
+		t.apis[api.ID] = api
+	}
+	for _, pol := range state.Policies {
+		t.policies[pol.ID] = pol
+	}
+	for _, cert := range state.Certificates {
+		t.certs[cert.ID] = cert
+	}
+	_ = t.Reload()
+	t.reloads = 0
+	return t
+}
+
+func (t *fakeTarget) State() (*State, error) {
+	state := &State{}
+	for _, api := range t.apis {
+		state.APIs = append(state.APIs, api)
+	}
+	for _, pol := range t.policies {
+		state.Policies = append(state.Policies, pol)
+	}
+	for _, cert := range t.certs {
+		state.Certificates = append(state.Certificates, cert)
+	}
+	state.sort()
+	return state, nil
+}
+
+func (t *fakeTarget) PutAPI(api *API, _ bool) error {
+	if api.ID == t.failPut {
+		return errors.New("write failed")
+	}
+	t.apis[api.ID] = api
+	return nil
+}
+
+func (t *fakeTarget) DeleteAPI(api *API) error {
+	delete(t.apis, api.ID)
+	return nil
+}
+
+func (t *fakeTarget) PutPolicy(pol *Policy, _ bool) error {
+	t.policies[pol.ID] = pol
+	return nil
+}
+
+func (t *fakeTarget) DeletePolicy(pol *Policy) error {
+	delete(t.policies, pol.ID)
+	return nil
+}
+
+func (t *fakeTarget) AddCertificate(cert *Certificate) error {
+	t.certs[cert.ID] = cert
+	return nil
+}
+
+func (t *fakeTarget) DeleteCertificate(cert *Certificate) error {
+	delete(t.certs, cert.ID)
+	return nil
+}
+
+func (t *fakeTarget) Reload() error {
+	t.reloads++
+	t.loaded = map[string]bool{}
+	for id := range t.apis {
+		if !t.broken[id] {
+			t.loaded[id] = true
+		}
+	}
+	return nil
+}
+
+func (t *fakeTarget) LoadedAPIs() (map[string]bool, error) {
+	return t.loaded, nil
+}
+
+func TestApply(t *testing.T) {
+	current := &State{
+		APIs: []*API{
+			{ID: "a", Definition: json.RawMessage(`{"api_id": "a", "name": "v1"}`)},
+			{ID: "b", Definition: json.RawMessage(`{"api_id": "b"}`)},
+		},
+		Policies: []*Policy{
+			{ID: "gold", Definition: json.RawMessage(`{"id": "gold"}`)},
+		},
+		Certificates: []*Certificate{
+			{ID: "old-cert"},
+		},
+	}
+
+	desired := &State{
+		APIs: []*API{
+			{ID: "a", Definition: json.RawMessage(`{"api_id": "a", "name": "v2"}`)},
+			{ID: "c", Definition: json.RawMessage(`{"api_id": "c"}`)},
+		},
+		Certificates: []*Certificate{
+			{ID: "new-cert", PEM: []byte("pem")},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		target := newFakeTarget(current)
+
+		require.NoError(t, Apply(target, NewPlan(desired, current, true)))
+
+		state, err := target.State()
+		require.NoError(t, err)
+		assert.Equal(t, desired, state)
+		assert.Equal(t, 1, target.reloads)
+	})
+
+	t.Run("API fails to load", func(t *testing.T) {
+		target := newFakeTarget(current)
+		target.broken["c"] = true
+
+		err := Apply(target, NewPlan(desired, current, true))
+		assert.ErrorIs(t, err, ErrRolledBack)
+		assert.ErrorContains(t, err, "APIs failed to load: c")
+
+		state, err := target.State()
+		require.NoError(t, err)
+		assert.Equal(t, current, state)
+		assert.Equal(t, 2, target.reloads)
+	})
+
+	t.Run("change fails", func(t *testing.T) {
+		target := newFakeTarget(current)
+		target.failPut = "c"
+
+		err := Apply(target, NewPlan(desired, current, true))
+		assert.ErrorIs(t, err, ErrRolledBack)
+		assert.ErrorContains(t, err, "create api c: write failed")
+
+		state, err := target.State()
+		require.NoError(t, err)
+		assert.Equal(t, current, state)
+	})
+}
@@ -0,0 +1,188 @@
+package gitops
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Operations of a change.
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+// Change is a resource to create, update or delete. Before is the resource on the Gateway,
+// nil when it's created, and After the desired resource, nil when it's deleted.
+type Change struct {
+	Op   string
+	Kind string
+	ID   string
+
+	Before interface{}
+	After  interface{}
+}
+
+// Path returns the file of the desired resource.
+func (c Change) Path() string {
+	switch r := c.After.(type) {
+	case *API:
+		return r.Path
+	case *Policy:
+		return r.Path
+	case *Certificate:
+		return r.Path
+	}
+	return ""
+}
+
+// Plan is the list of changes bringing a Gateway to the desired state.
+type Plan struct {
+	Changes []Change
+
+	// apis are the IDs of the desired APIs, they must all be loaded once the plan is applied.
+	apis []string
+}
+
+// Empty reports whether the Gateway is already in the desired state.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Count returns the number of changes with the operation.
+func (p *Plan) Count(op string) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Op == op {
+			n++
+		}
+	}
+	return n
+}
+
+// Write prints the plan to w, one line per change followed by a summary.
+func (p *Plan) Write(w io.Writer) {
+	symbols := map[string]string{OpCreate: "+", OpUpdate: "~", OpDelete: "-"}
+
+	for _, c := range p.Changes {
+		line := fmt.Sprintf("%s %s %s", symbols[c.Op], c.Kind, c.ID)
+		if path := c.Path(); path != "" {
+			line += " (" + path + ")"
+		}
+		fmt.Fprintln(w, line)
+	}
+
+	fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to delete.\n", p.Count(OpCreate), p.Count(OpUpdate), p.Count(OpDelete))
+}
+
+// NewPlan compares the desired state with the current state of a Gateway. A resource is
+// updated when a field set in its desired definition differs on the Gateway, so fields
+// the Gateway fills with defaults don't cause changes. Resources missing from the desired
+// state are only deleted when prune is set.
+func NewPlan(desired, current *State, prune bool) *Plan {
+	plan := &Plan{}
+
+	// certificates are identified by their content, so they're never updated
+	for _, cert := range desired.Certificates {
+		if current.certificate(cert.ID) == nil {
+			plan.Changes = append(plan.Changes, Change{Op: OpCreate, Kind: KindCertificate, ID: cert.ID, After: cert})
+		}
+	}
+
+	for _, api := range desired.APIs {
+		plan.apis = append(plan.apis, api.ID)
+
+		cur := current.api(api.ID)
+		switch {
+		case cur == nil:
+			plan.Changes = append(plan.Changes, Change{Op: OpCreate, Kind: KindAPI, ID: api.ID, After: api})
+		case cur.OAS != api.OAS || !contains(cur.Definition, api.Definition):
+			plan.Changes = append(plan.Changes, Change{Op: OpUpdate, Kind: KindAPI, ID: api.ID, Before: cur, After: api})
+		}
+	}
+
+	for _, pol := range desired.Policies {
+		cur := current.policy(pol.ID)
+		switch {
+		case cur == nil:
+			plan.Changes = append(plan.Changes, Change{Op: OpCreate, Kind: KindPolicy, ID: pol.ID, After: pol})
+		case !contains(cur.Definition, pol.Definition):
+			plan.Changes = append(plan.Changes, Change{Op: OpUpdate, Kind: KindPolicy, ID: pol.ID, Before: cur, After: pol})
+		}
+	}
+
+	if !prune {
+		return plan
+	}
+
+	// resources are deleted in the reverse order, policies first as they refer to APIs
+	for _, pol := range current.Policies {
+		if desired.policy(pol.ID) == nil {
+			plan.Changes = append(plan.Changes, Change{Op: OpDelete, Kind: KindPolicy, ID: pol.ID, Before: pol})
+		}
+	}
+
+	for _, api := range current.APIs {
+		if desired.api(api.ID) == nil {
+			plan.Changes = append(plan.Changes, Change{Op: OpDelete, Kind: KindAPI, ID: api.ID, Before: api})
+		}
+	}
+
+	for _, cert := range current.Certificates {
+		if desired.certificate(cert.ID) == nil {
+			plan.Changes = append(plan.Changes, Change{Op: OpDelete, Kind: KindCertificate, ID: cert.ID, Before: cert})
+		}
+	}
+
+	return plan
+}
+
+// contains reports whether every field set in the desired JSON document has the same value in current.
+func contains(current, desired json.RawMessage) bool {
+	var cur, want interface{}
+	if json.Unmarshal(current, &cur) != nil || json.Unmarshal(desired, &want) != nil {
+		return false
+	}
+	return containsValue(cur, want)
+}
+
+func containsValue(current, desired interface{}) bool {
+	if want, ok := desired.(map[string]interface{}); ok {
+		cur, _ := current.(map[string]interface{})
+		if cur == nil && current != nil {
+			return false
+		}
+
+		for k, v := range want {
+			if !containsValue(cur[k], v) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if isZero(desired) && isZero(current) {
+		return true
+	}
+	return reflect.DeepEqual(current, desired)
+}
+
+func isZero(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !val
+	case string:
+		return val == ""
+	case float64:
+		return val == 0
+	case []interface{}:
+		return len(val) == 0
+	case map[string]interface{}:
+		return len(val) == 0
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package gitops
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPlan(t *testing.T) {
+	desired := &State{
+		APIs: []*API{
+			{ID: "new", Definition: json.RawMessage(`{"api_id": "new"}`), Path: "new.json"},
+			{ID: "same", Definition: json.RawMessage(`{"api_id": "same", "proxy": {"listen_path": "/same/"}, "tags": []}`)},
+			{ID: "changed", Definition: json.RawMessage(`{"api_id": "changed", "proxy": {"listen_path": "/v2/"}}`)},
+			{ID: "converted", OAS: true, Definition: json.RawMessage(`{"openapi": "3.0.3"}`)},
+		},
+		Policies: []*Policy{
+			{ID: "gold", Definition: json.RawMessage(`{"id": "gold", "rate": 100}`)},
+		},
+		Certificates: []*Certificate{
+			{ID: "cert", Path: "cert.pem"},
+		},
+	}
+
+	current := &State{
+		APIs: []*API{
+			{ID: "same", Definition: json.RawMessage(`{"api_id": "same", "proxy": {"listen_path": "/same/", "strip_listen_path": false}, "active": true}`)},
+			{ID: "changed", Definition: json.RawMessage(`{"api_id": "changed", "proxy": {"listen_path": "/v1/"}}`)},
+			{ID: "converted", Definition: json.RawMessage(`{"api_id": "converted"}`)},
+			{ID: "removed", Definition: json.RawMessage(`{"api_id": "removed"}`)},
+		},
+		Policies: []*Policy{
+			{ID: "gold", Definition: json.RawMessage(`{"id": "gold", "rate": 10, "per": 1}`)},
+			{ID: "silver", Definition: json.RawMessage(`{"id": "silver"}`)},
+		},
+		Certificates: []*Certificate{
+			{ID: "old-cert"},
+		},
+	}
+
+	changes := func(plan *Plan) []string {
+		var out []string
+		for _, c := range plan.Changes {
+			out = append(out, c.Op+" "+c.Kind+" "+c.ID)
+		}
+		return out
+	}
+
+	plan := NewPlan(desired, current, false)
+	assert.Equal(t, []string{
+		"create certificate cert",
+		"create api new",
+		"update api changed",
+		"update api converted",
+		"update policy gold",
+	}, changes(plan))
+
+	plan = NewPlan(desired, current, true)
+	assert.Equal(t, []string{
+		"create certificate cert",
+		"create api new",
+		"update api changed",
+		"update api converted",
+		"update policy gold",
+		"delete policy silver",
+		"delete api removed",
+		"delete certificate old-cert",
+	}, changes(plan))
+
+	var out bytes.Buffer
+	plan.Write(&out)
+	assert.Contains(t, out.String(), "+ api new (new.json)\n")
+	assert.Contains(t, out.String(), "- policy silver\n")
+	assert.Contains(t, out.String(), "Plan: 2 to create, 3 to update, 3 to delete.\n")
+
+	assert.True(t, NewPlan(current, current, true).Empty())
+}
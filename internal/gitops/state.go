@@ -0,0 +1,198 @@
+// Package gitops reconciles the APIs, policies and certificates of a Gateway
+// with the declarative state kept in a directory tree, typically a git checkout.
+package gitops
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/TykTechnologies/tyk/apidef/oas"
+	"github.com/TykTechnologies/tyk/certs"
+)
+
+// Kinds of resources.
+const (
+	KindAPI         = "api"
+	KindPolicy      = "policy"
+	KindCertificate = "certificate"
+)
+
+// State is a set of APIs, policies and certificates, either read from a directory or from a Gateway.
+type State struct {
+	APIs         []*API
+	Policies     []*Policy
+	Certificates []*Certificate
+}
+
+// API is a classic API definition or a Tyk OAS API definition.
+type API struct {
+	ID string
+	// OAS is set for Tyk OAS API definitions, Definition is then the OAS document.
+	OAS        bool
+	Definition json.RawMessage
+	// Path is the file the API was read from.
+	Path string
+}
+
+// Policy is a security policy.
+type Policy struct {
+	ID         string
+	Definition json.RawMessage
+	Path       string
+}
+
+// Certificate is a PEM encoded certificate, along with its private key if it has one.
+// Its ID is the one the Gateway certificate store gives it.
+type Certificate struct {
+	ID   string
+	PEM  []byte
+	Path string
+}
+
+// Load reads the state kept in the directory tree at dir. JSON files are classic API
+// definitions, Tyk OAS API definitions or policies, told apart by their content, and
+// `.pem`, `.crt` and `.cer` files are certificates. Hidden files and directories are ignored.
+func Load(dir string) (*State, error) {
+	state := &State{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(d.Name(), ".") && path != dir {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			rel = path
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			return state.loadJSON(path, rel)
+		case ".pem", ".crt", ".cer":
+			return state.loadCertificate(path, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	state.sort()
+	return state, nil
+}
+
+func (s *State) loadJSON(path, rel string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: %w", rel, err)
+	}
+
+	switch {
+	case doc["openapi"] != nil:
+		var oasObj oas.OAS
+		if err := json.Unmarshal(data, &oasObj); err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+
+		var id string
+		if ext := oasObj.GetTykExtension(); ext != nil {
+			id = ext.Info.ID
+		}
+		s.APIs = append(s.APIs, &API{ID: id, OAS: true, Definition: data, Path: rel})
+	case doc["proxy"] != nil || doc["api_id"] != nil:
+		var def struct {
+			APIID string `json:"api_id"`
+		}
+		if err := json.Unmarshal(data, &def); err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		s.APIs = append(s.APIs, &API{ID: def.APIID, Definition: data, Path: rel})
+	case doc["access_rights"] != nil || doc["rate"] != nil || doc["quota_max"] != nil:
+		var pol struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(data, &pol); err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		s.Policies = append(s.Policies, &Policy{ID: pol.ID, Definition: data, Path: rel})
+	default:
+		return fmt.Errorf("%s: not an API definition or a policy", rel)
+	}
+
+	return nil
+}
+
+func (s *State) loadCertificate(path, rel string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	id, err := CertificateID(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", rel, err)
+	}
+
+	s.Certificates = append(s.Certificates, &Certificate{ID: id, PEM: data, Path: rel})
+	return nil
+}
+
+// CertificateID returns the ID the Gateway certificate store gives to a PEM encoded certificate.
+func CertificateID(data []byte) (string, error) {
+	id, _, err := certs.GetCertIDAndChainPEM(data, "")
+	return id, err
+}
+
+func (s *State) sort() {
+	sort.Slice(s.APIs, func(i, j int) bool { return s.APIs[i].ID < s.APIs[j].ID })
+	sort.Slice(s.Policies, func(i, j int) bool { return s.Policies[i].ID < s.Policies[j].ID })
+	sort.Slice(s.Certificates, func(i, j int) bool { return s.Certificates[i].ID < s.Certificates[j].ID })
+}
+
+func (s *State) api(id string) *API {
+	for _, api := range s.APIs {
+		if api.ID == id {
+			return api
+		}
+	}
+	return nil
+}
+
+func (s *State) policy(id string) *Policy {
+	for _, pol := range s.Policies {
+		if pol.ID == id {
+			return pol
+		}
+	}
+	return nil
+}
+
+func (s *State) certificate(id string) *Certificate {
+	for _, cert := range s.Certificates {
+		if cert.ID == id {
+			return cert
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package gitops
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TykTechnologies/tyk/apidef/oas/lint"
+	"github.com/TykTechnologies/tyk/internal/crypto"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.CopyFS(dir, os.DirFS("testdata")))
+
+	certPEM, _, _, _ := crypto.GenServerCertificate()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "server.pem"), certPEM, 0600))
+
+	// hidden directories, such as .git, are skipped
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "config.json"), []byte(`{}`), 0600))
+
+	state, err := Load(dir)
+	require.NoError(t, err)
+
+	require.Len(t, state.APIs, 2)
+	assert.Equal(t, "classic-api", state.APIs[0].ID)
+	assert.False(t, state.APIs[0].OAS)
+	assert.Equal(t, filepath.Join("apis", "classic-api.json"), state.APIs[0].Path)
+	assert.Equal(t, "oas-api", state.APIs[1].ID)
+	assert.True(t, state.APIs[1].OAS)
+
+	require.Len(t, state.Policies, 1)
+	assert.Equal(t, "gold", state.Policies[0].ID)
+
+	certID, err := CertificateID(certPEM)
+	require.NoError(t, err)
+	require.Len(t, state.Certificates, 1)
+	assert.Equal(t, certID, state.Certificates[0].ID)
+	assert.Equal(t, certPEM, state.Certificates[0].PEM)
+
+	errs, warnings := Validate(state)
+	assert.Empty(t, errs)
+	require.NotEmpty(t, warnings)
+	for _, f := range warnings {
+		assert.Equal(t, filepath.Join("apis", "oas-api.json"), f.File)
+		assert.Equal(t, lint.SeverityWarning, f.Severity)
+	}
+
+	t.Run("unknown document", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "unknown.json"), []byte(`{"foo": "bar"}`), 0600))
+		defer os.Remove(filepath.Join(dir, "unknown.json"))
+
+		_, err := Load(dir)
+		assert.ErrorContains(t, err, "unknown.json: not an API definition or a policy")
+	})
+}
+
+func TestValidate(t *testing.T) {
+	state, err := Load("testdata")
+	require.NoError(t, err)
+
+	classic := *state.APIs[0]
+	classic.Path = "copy.json"
+
+	noName := classic
+	noName.ID = "no-name"
+	noName.Path = "no-name.json"
+	noName.Definition = []byte(`{"api_id": "no-name", "proxy": {"listen_path": "/no-name/", "target_url": "http://upstream.example.com"}, "version_data": {"not_versioned": true, "versions": {}}}`)
+
+	insecure := *state.APIs[1]
+	insecure.ID = "insecure"
+	insecure.Path = "insecure.json"
+	insecure.Definition = []byte(`{"openapi": "3.0.3", "info": {"title": "insecure", "version": "1.0.0"}, "paths": {},
+		"x-tyk-api-gateway": {"info": {"id": "insecure", "name": "insecure", "state": {"active": true}},
+		"upstream": {"url": "https://upstream.example.com", "tlsTransport": {"insecureSkipVerify": true}},
+		"server": {"listenPath": {"value": "/insecure/"}}}}`)
+
+	state.APIs = append(state.APIs, &classic, &noName, &insecure)
+	state.Policies = append(state.Policies, &Policy{Path: "anonymous.json", Definition: []byte(`{"rate": 1}`)})
+
+	errs, _ := Validate(state)
+	require.Len(t, errs, 5)
+	assert.ErrorContains(t, errs[0], `copy.json: API ID "classic-api" is already used by apis/classic-api.json`)
+	assert.ErrorContains(t, errs[1], `copy.json: listen path "/classic/" is already used by apis/classic-api.json`)
+	assert.ErrorContains(t, errs[2], "no-name.json: (root): name is required")
+	assert.ErrorContains(t, errs[3], "anonymous.json: policy ID is required")
+	assert.ErrorContains(t, errs[4], "insecure.json: insecure-upstream-tls: the upstream certificate isn't verified")
+}
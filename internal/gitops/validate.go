@@ -0,0 +1,150 @@
+package gitops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/TykTechnologies/tyk/apidef"
+	"github.com/TykTechnologies/tyk/apidef/oas"
+	"github.com/TykTechnologies/tyk/apidef/oas/lint"
+	"github.com/TykTechnologies/tyk/internal/service/gojsonschema"
+)
+
+// Validate checks the state can be applied: classic API definitions are linted against the
+// API definition schema, Tyk OAS API definitions against the OAS schema and the lint rules, and
+// both are checked with the API definition validators. IDs must be set and unique, as must listen
+// paths. It returns every problem found, an empty slice means the state is valid, along with the
+// lint findings that are only warnings.
+func Validate(state *State) ([]error, []lint.Finding) {
+	var errs []error
+	fail := func(path string, err error) {
+		errs = append(errs, fmt.Errorf("%s: %w", path, err))
+	}
+
+	ids := map[string]string{}
+	listenPaths := map[string]string{}
+
+	var lintDefs []*lint.Definition
+	for _, api := range state.APIs {
+		def, oasObj, err := validateAPI(api)
+		if err != nil {
+			fail(api.Path, err)
+			continue
+		}
+
+		if oasObj != nil {
+			lintDefs = append(lintDefs, &lint.Definition{File: api.Path, API: oasObj})
+		}
+
+		if other, ok := ids[api.ID]; ok {
+			fail(api.Path, fmt.Errorf("API ID %q is already used by %s", api.ID, other))
+		}
+		ids[api.ID] = api.Path
+
+		listenPath := def.Domain + def.Proxy.ListenPath
+		if other, ok := listenPaths[listenPath]; ok {
+			fail(api.Path, fmt.Errorf("listen path %q is already used by %s", listenPath, other))
+		}
+		listenPaths[listenPath] = api.Path
+	}
+
+	policyIDs := map[string]string{}
+	for _, pol := range state.Policies {
+		if pol.ID == "" {
+			fail(pol.Path, errors.New("policy ID is required"))
+			continue
+		}
+
+		if other, ok := policyIDs[pol.ID]; ok {
+			fail(pol.Path, fmt.Errorf("policy ID %q is already used by %s", pol.ID, other))
+		}
+		policyIDs[pol.ID] = pol.Path
+	}
+
+	certIDs := map[string]string{}
+	for _, cert := range state.Certificates {
+		if other, ok := certIDs[cert.ID]; ok {
+			fail(cert.Path, fmt.Errorf("certificate is a duplicate of %s", other))
+		}
+		certIDs[cert.ID] = cert.Path
+	}
+
+	var warnings []lint.Finding
+	for _, f := range lintAPIs(lintDefs) {
+		if f.Severity.AtLeast(lint.SeverityError) {
+			fail(f.File, fmt.Errorf("%s: %s (%s)", f.Rule, f.Message, f.Pointer))
+			continue
+		}
+		warnings = append(warnings, f)
+	}
+
+	return errs, warnings
+}
+
+// lintAPIs lints the Tyk OAS API definitions with the default severities of the rules. Listen
+// path collisions are left to Validate, which checks them across classic APIs too.
+func lintAPIs(defs []*lint.Definition) []lint.Finding {
+	linter, err := lint.New(lint.Config{Rules: map[string]lint.Severity{"listen-path-collision": lint.SeverityOff}})
+	if err != nil {
+		return nil
+	}
+	return linter.Lint(defs)
+}
+
+// validateAPI validates the API definition, the Tyk OAS one is returned too for Tyk OAS APIs.
+func validateAPI(api *API) (*apidef.APIDefinition, *oas.OAS, error) {
+	var (
+		def    apidef.APIDefinition
+		oasObj *oas.OAS
+	)
+
+	if api.OAS {
+		oasObj = &oas.OAS{}
+		if err := json.Unmarshal(api.Definition, oasObj); err != nil {
+			return nil, nil, err
+		}
+
+		if oasObj.GetTykExtension() == nil {
+			return nil, nil, apidef.ErrPayloadWithoutTykExtension
+		}
+
+		if err := oas.ValidateOASObject(api.Definition, oasObj.OpenAPI); err != nil {
+			return nil, nil, err
+		}
+
+		if err := oasObj.Validate(context.Background()); err != nil {
+			return nil, nil, err
+		}
+
+		oasObj.ExtractTo(&def)
+	} else {
+		result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader([]byte(apidef.Schema)), gojsonschema.NewBytesLoader(api.Definition))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !result.Valid() {
+			var errs []error
+			for _, resErr := range result.Errors() {
+				errs = append(errs, errors.New(resErr.String()))
+			}
+			return nil, nil, errors.Join(errs...)
+		}
+
+		if err := json.Unmarshal(api.Definition, &def); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if api.ID == "" {
+		return nil, nil, errors.New("API ID is required")
+	}
+
+	if result := apidef.Validate(&def, apidef.DefaultValidationRuleSet); !result.IsValid {
+		return nil, nil, errors.Join(result.Errors...)
+	}
+
+	return &def, oasObj, nil
+}
@@ -0,0 +1,79 @@
+package gitops
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watch calls fn whenever files change in the directory tree at dir, until ctx is done.
+// Changes are debounced, so a checkout touching many files triggers a single call.
+func Watch(ctx context.Context, dir string, debounce time.Duration, fn func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := watchTree(watcher, dir); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					// errors are ignored, the directory may already be gone
+					_ = watchTree(watcher, event.Name)
+				}
+			}
+
+			if event.Has(fsnotify.Chmod) {
+				continue
+			}
+			timer.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+		case <-timer.C:
+			fn()
+		}
+	}
+}
+
+// watchTree adds dir and its subdirectories, except hidden ones, to the watcher as fsnotify isn't recursive.
+func watchTree(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if strings.HasPrefix(d.Name(), ".") && path != dir {
+			return filepath.SkipDir
+		}
+
+		return watcher.Add(path)
+	})
+}
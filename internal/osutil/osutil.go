@@ -63,6 +63,17 @@ func (r *Root) WriteFile(filePath string, data []byte, perm fs.FileMode) error {
 	return os.WriteFile(fullPath, data, perm)
 }
 
+// ReadFile reads the file which locates inside of root directory.
+func (r *Root) ReadFile(filePath string) ([]byte, error) {
+	fullPath, err := r.Ensure(filePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(fullPath)
+}
+
 // Remove file which is inside root path.
 func (r *Root) Remove(filePath string) error {
 	fullPath, err := r.Ensure(filePath)
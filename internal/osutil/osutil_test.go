@@ -104,6 +104,29 @@ func TestWriteFile(t *testing.T) {
 	assert.Equal(t, content, readContent)
 }
 
+func TestReadFile(t *testing.T) {
+	tempDir := setupTestDir(t)
+	root, err := osutil.NewRoot(tempDir)
+	assert.NoError(t, err)
+
+	t.Run("SuccessfulRead", func(t *testing.T) {
+		fileName := "read_me.txt"
+		content := []byte("some data")
+		err := os.WriteFile(filepath.Join(tempDir, fileName), content, 0644)
+		assert.NoError(t, err)
+
+		readContent, err := root.ReadFile(fileName)
+		assert.NoError(t, err)
+		assert.Equal(t, content, readContent)
+	})
+
+	t.Run("PathTraversalAttack", func(t *testing.T) {
+		_, err := root.ReadFile("../../../etc/passwd")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "attempts to escape root directory")
+	})
+}
+
 func TestRemove(t *testing.T) {
 	tempDir := setupTestDir(t)
 	root, err := osutil.NewRoot(tempDir)
@@ -17,14 +17,16 @@ var (
 	NewClientMock     = redismock.NewClientMock
 	NewPool           = goredis.NewPool
 
-	Nil       = redis.Nil
-	ErrClosed = redis.ErrClosed
+	Nil         = redis.Nil
+	ErrClosed   = redis.ErrClosed
+	TxFailedErr = redis.TxFailedErr
 )
 
 type (
 	UniversalClient  = redis.UniversalClient
 	UniversalOptions = redis.UniversalOptions
 	Pipeliner        = redis.Pipeliner
+	Tx               = redis.Tx
 
 	Client        = redis.Client
 	ClusterClient = redis.ClusterClient
@@ -35,6 +37,10 @@ type (
 	Message      = redis.Message
 	Subscription = redis.Subscription
 
+	XAddArgs = redis.XAddArgs
+	XMessage = redis.XMessage
+
+	Cmder          = redis.Cmder
 	IntCmd         = redis.IntCmd
 	StringCmd      = redis.StringCmd
 	StringSliceCmd = redis.StringSliceCmd
@@ -0,0 +1,94 @@
+// Package revision keeps the history of the API definitions and policies changed through the Control API.
+package revision
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Kinds of resources with a history.
+const (
+	KindAPI    = "api"
+	KindPolicy = "policy"
+)
+
+// Storage names.
+const (
+	StorageFile  = "file"
+	StorageRedis = "redis"
+)
+
+const (
+	// DefaultKeep is the number of revisions kept for each resource when no limit is configured.
+	DefaultKeep = 10
+	// DefaultKeyPrefix prefixes the Redis keys revisions are stored under.
+	DefaultKeyPrefix = "tyk-revisions:"
+)
+
+// ErrNotFound is returned when a revision isn't kept.
+var ErrNotFound = errors.New("revision not found")
+
+// Revision is a stored version of a resource. Definition is the stored API definition or
+// policy, OAS is the OpenAPI document of OAS API definitions.
+type Revision struct {
+	Number    int       `json:"revision"`
+	CreatedAt time.Time `json:"created_at"`
+	Action    string    `json:"action"`
+	// RolledBackFrom is the revision restored by a rollback.
+	RolledBackFrom int             `json:"rolled_back_from,omitempty"`
+	Definition     json.RawMessage `json:"definition,omitempty"`
+	OAS            json.RawMessage `json:"oas,omitempty"`
+}
+
+// Summary returns the revision without its content.
+func (r *Revision) Summary() *Revision {
+	return &Revision{
+		Number:         r.Number,
+		CreatedAt:      r.CreatedAt,
+		Action:         r.Action,
+		RolledBackFrom: r.RolledBackFrom,
+	}
+}
+
+// Content returns the document revisions are compared on, the OpenAPI document of OAS API definitions.
+func (r *Revision) Content() json.RawMessage {
+	if len(r.OAS) > 0 {
+		return r.OAS
+	}
+	return r.Definition
+}
+
+// Store keeps the last revisions of resources.
+type Store interface {
+	// Add stores rev as the next revision of the resource, its number is set, and drops the
+	// revisions past the limit.
+	Add(kind, id string, rev *Revision) error
+	// List returns the kept revisions of the resource, newest first.
+	List(kind, id string) ([]*Revision, error)
+}
+
+// Get returns a kept revision of the resource.
+func Get(s Store, kind, id string, number int) (*Revision, error) {
+	revs, err := s.List(kind, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rev := range revs {
+		if rev.Number == number {
+			return rev, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Previous returns the kept revision preceding the given one, nil when there is none.
+func Previous(revs []*Revision, number int) *Revision {
+	for _, rev := range revs {
+		if rev.Number < number {
+			return rev
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package revision
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/TykTechnologies/tyk/internal/osutil"
+	"github.com/TykTechnologies/tyk/internal/redis"
+)
+
+// FileStore keeps the revisions of each resource in a JSON file of a directory.
+type FileStore struct {
+	mu   sync.Mutex
+	root *osutil.Root
+	keep int
+}
+
+// NewFileStore returns a store keeping keep revisions of each resource in the directory at path,
+// it's created when missing.
+func NewFileStore(path string, keep int) (*FileStore, error) {
+	if path == "" {
+		return nil, errors.New("revisions path is required")
+	}
+
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, err
+	}
+
+	root, err := osutil.NewRoot(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if keep <= 0 {
+		keep = DefaultKeep
+	}
+	return &FileStore{root: root, keep: keep}, nil
+}
+
+// Add stores the revision.
+func (s *FileStore) Add(kind, id string, rev *Revision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	revs, err := s.read(kind, id)
+	if err != nil {
+		return err
+	}
+
+	rev.Number = 1
+	if len(revs) > 0 {
+		rev.Number = revs[0].Number + 1
+	}
+
+	revs = append([]*Revision{rev}, revs...)
+	if len(revs) > s.keep {
+		revs = revs[:s.keep]
+	}
+
+	data, err := json.Marshal(revs)
+	if err != nil {
+		return err
+	}
+	return s.root.WriteFile(fileName(kind, id), data, 0600)
+}
+
+// List returns the revisions of the resource.
+func (s *FileStore) List(kind, id string) ([]*Revision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.read(kind, id)
+}
+
+func (s *FileStore) read(kind, id string) ([]*Revision, error) {
+	data, err := s.root.ReadFile(fileName(kind, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return []*Revision{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var revs []*Revision
+	if err := json.Unmarshal(data, &revs); err != nil {
+		return nil, err
+	}
+	return revs, nil
+}
+
+func fileName(kind, id string) string {
+	return kind + "-" + id + ".json"
+}
+
+// RedisStore keeps the revisions of each resource in a Redis list, newest first.
+type RedisStore struct {
+	client    func() (redis.UniversalClient, error)
+	keyPrefix string
+	keep      int
+}
+
+// NewRedisStore returns a store keeping keep revisions of each resource, client returns the Redis client to use.
+func NewRedisStore(client func() (redis.UniversalClient, error), keyPrefix string, keep int) *RedisStore {
+	if keyPrefix == "" {
+		keyPrefix = DefaultKeyPrefix
+	}
+	if keep <= 0 {
+		keep = DefaultKeep
+	}
+	return &RedisStore{client: client, keyPrefix: keyPrefix, keep: keep}
+}
+
+// Add stores the revision, its number comes from a counter kept next to the list.
+func (s *RedisStore) Add(kind, id string, rev *Revision) error {
+	client, err := s.client()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	key := s.key(kind, id)
+
+	number, err := client.Incr(ctx, key+":seq").Result()
+	if err != nil {
+		return err
+	}
+	rev.Number = int(number)
+
+	data, err := json.Marshal(rev)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, key, data)
+		pipe.LTrim(ctx, key, 0, int64(s.keep-1))
+		return nil
+	})
+	return err
+}
+
+// List returns the revisions of the resource.
+func (s *RedisStore) List(kind, id string) ([]*Revision, error) {
+	client, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := client.LRange(context.Background(), s.key(kind, id), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	revs := make([]*Revision, 0, len(values))
+	for _, value := range values {
+		var rev Revision
+		if err := json.Unmarshal([]byte(value), &rev); err != nil {
+			continue
+		}
+		revs = append(revs, &rev)
+	}
+	return revs, nil
+}
+
+func (s *RedisStore) key(kind, id string) string {
+	return s.keyPrefix + kind + ":" + id
+}
@@ -0,0 +1,61 @@
+package revision
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	_, err := NewFileStore("", 3)
+	assert.Error(t, err)
+
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "revisions"), 3)
+	require.NoError(t, err)
+
+	revs, err := store.List(KindAPI, "api")
+	require.NoError(t, err)
+	assert.Empty(t, revs)
+
+	for i := 1; i <= 5; i++ {
+		rev := &Revision{Action: "modified", Definition: json.RawMessage(fmt.Sprintf(`{"name":"v%d"}`, i))}
+		require.NoError(t, store.Add(KindAPI, "api", rev))
+		assert.Equal(t, i, rev.Number)
+	}
+	require.NoError(t, store.Add(KindPolicy, "api", &Revision{Action: "added"}))
+
+	revs, err = store.List(KindAPI, "api")
+	require.NoError(t, err)
+	require.Len(t, revs, 3, "only the last revisions are kept")
+	assert.Equal(t, 5, revs[0].Number)
+	assert.Equal(t, 3, revs[2].Number)
+
+	rev, err := Get(store, KindAPI, "api", 4)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"v4"}`, string(rev.Content()))
+
+	_, err = Get(store, KindAPI, "api", 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.Equal(t, 3, Previous(revs, 4).Number)
+	assert.Nil(t, Previous(revs, 3))
+
+	_, err = store.List(KindAPI, "../escape")
+	assert.NoError(t, err, "the file name stays in the directory")
+}
+
+func TestRevisionContent(t *testing.T) {
+	rev := &Revision{Definition: json.RawMessage(`{"api_id":"1"}`)}
+	assert.Equal(t, rev.Definition, rev.Content())
+
+	rev.OAS = json.RawMessage(`{"openapi":"3.0.3"}`)
+	assert.Equal(t, rev.OAS, rev.Content())
+
+	summary := rev.Summary()
+	assert.Nil(t, summary.Definition)
+	assert.Nil(t, summary.OAS)
+}
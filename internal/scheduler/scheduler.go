@@ -21,6 +21,8 @@ type Job struct {
 	Name     string
 	Run      func() error
 	Interval time.Duration
+	// Quiet logs the successful runs at debug level, for jobs running frequently.
+	Quiet bool
 }
 
 // NewJob creates and returns a new Job with the specified name, task function, and interval.
@@ -76,6 +78,8 @@ func (s *Scheduler) Start(ctx context.Context, job *Job) {
 			logger.Info("job scheduler stopping")
 		case err != nil:
 			logger.WithError(err).Errorf("job run error")
+		case job.Quiet:
+			logger.Debug("job run successful")
 		default:
 			logger.Info("job run successful")
 		}
@@ -6,14 +6,15 @@ import (
 	"testing"
 	"time"
 
-	logrus "github.com/sirupsen/logrus/hooks/test"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/TykTechnologies/tyk/internal/scheduler"
 )
 
 func TestScheduler_Break(t *testing.T) {
-	logger, _ := logrus.NewNullLogger()
+	logger, _ := logtest.NewNullLogger()
 
 	s := scheduler.NewScheduler(logger)
 
@@ -29,7 +30,7 @@ func TestScheduler_Break(t *testing.T) {
 }
 
 func TestScheduler_Close(t *testing.T) {
-	logger, _ := logrus.NewNullLogger()
+	logger, _ := logtest.NewNullLogger()
 
 	s := scheduler.NewScheduler(logger)
 	defer s.Close()
@@ -43,8 +44,35 @@ func TestScheduler_Close(t *testing.T) {
 	assert.NotNil(t, s)
 }
 
+func TestScheduler_Job_Quiet(t *testing.T) {
+	for _, quiet := range []bool{false, true} {
+		logger, hook := logtest.NewNullLogger()
+		logger.SetLevel(logrus.DebugLevel)
+
+		runs := 0
+		job := scheduler.NewJob("test", func() error {
+			runs++
+			if runs > 1 {
+				return scheduler.Break
+			}
+			return nil
+		}, 1)
+		job.Quiet = quiet
+
+		scheduler.NewScheduler(logger).Start(context.Background(), job)
+
+		entry := hook.Entries[0]
+		assert.Equal(t, "job run successful", entry.Message)
+		if quiet {
+			assert.Equal(t, logrus.DebugLevel, entry.Level)
+		} else {
+			assert.Equal(t, logrus.InfoLevel, entry.Level)
+		}
+	}
+}
+
 func TestScheduler_Job_Errors(t *testing.T) {
-	logger, _ := logrus.NewNullLogger()
+	logger, _ := logtest.NewNullLogger()
 
 	testcases := []struct {
 		name string
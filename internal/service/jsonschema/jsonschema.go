@@ -0,0 +1,16 @@
+package jsonschema
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+type (
+	Compiler        = jsonschema.Compiler
+	Schema          = jsonschema.Schema
+	ValidationError = jsonschema.ValidationError
+)
+
+var (
+	NewCompiler = jsonschema.NewCompiler
+	Draft2020   = jsonschema.Draft2020
+)
@@ -0,0 +1,257 @@
+package transcoding
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// ErrNoServices is returned when the descriptors don't define any service.
+var ErrNoServices = errors.New("no gRPC services found in the descriptors")
+
+// Binding is an HTTP binding of a gRPC method, from its google.api.http annotation.
+type Binding struct {
+	// Method is the gRPC method.
+	Method protoreflect.MethodDescriptor
+	// HTTPMethod is the HTTP method of the binding.
+	HTTPMethod string
+	// Pattern is the path template of the binding, e.g. /v1/{name=shelves/*}.
+	Pattern string
+	// Body is the field of the request message bound to the request body,
+	// * for the whole message or empty for no body.
+	Body string
+	// ResponseBody is the field of the response message returned as the response body,
+	// empty for the whole message.
+	ResponseBody string
+}
+
+// FullMethod returns the gRPC path of the method, e.g. /library.v1.Library/GetBook.
+func (b Binding) FullMethod() string {
+	return "/" + string(b.Method.Parent().FullName()) + "/" + string(b.Method.Name())
+}
+
+// ParseDescriptorSet parses a serialised FileDescriptorSet. The dependencies not included
+// in the set are resolved from the well-known types.
+func ParseDescriptorSet(data []byte) (*protoregistry.Files, error) {
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid FileDescriptorSet: %w", err)
+	}
+
+	if len(set.File) == 0 {
+		return nil, errors.New("invalid FileDescriptorSet: no files")
+	}
+
+	return NewFiles(&set)
+}
+
+// NewFiles builds the registry of the files of a FileDescriptorSet, in dependency order.
+func NewFiles(set *descriptorpb.FileDescriptorSet) (*protoregistry.Files, error) {
+	pending := make(map[string]*descriptorpb.FileDescriptorProto, len(set.File))
+	for _, fd := range set.File {
+		pending[fd.GetName()] = fd
+	}
+
+	files := new(protoregistry.Files)
+	resolver := &fallbackResolver{files}
+
+	var register func(name string, seen map[string]bool) error
+	register = func(name string, seen map[string]bool) error {
+		if _, err := files.FindFileByPath(name); err == nil {
+			return nil
+		}
+
+		fdp, ok := pending[name]
+		if !ok {
+			global, err := protoregistry.GlobalFiles.FindFileByPath(name)
+			if err != nil {
+				return fmt.Errorf("missing dependency %s", name)
+			}
+			return files.RegisterFile(global)
+		}
+
+		if seen[name] {
+			return fmt.Errorf("import cycle on %s", name)
+		}
+		seen[name] = true
+
+		for _, dep := range fdp.GetDependency() {
+			if err := register(dep, seen); err != nil {
+				return err
+			}
+		}
+
+		fd, err := protodesc.NewFile(fdp, resolver)
+		if err != nil {
+			return err
+		}
+
+		return files.RegisterFile(fd)
+	}
+
+	for _, fd := range set.File {
+		if err := register(fd.GetName(), map[string]bool{}); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// DescriptorSet serialises the files and their dependencies to a FileDescriptorSet,
+// without their source info.
+func DescriptorSet(files []protoreflect.FileDescriptor) ([]byte, error) {
+	var (
+		set  descriptorpb.FileDescriptorSet
+		seen = map[string]bool{}
+		add  func(fd protoreflect.FileDescriptor)
+	)
+
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+
+		fdp := protodesc.ToFileDescriptorProto(fd)
+		fdp.SourceCodeInfo = nil
+		set.File = append(set.File, fdp)
+	}
+
+	for _, fd := range files {
+		add(fd)
+	}
+
+	return proto.Marshal(&set)
+}
+
+// Bindings returns the HTTP bindings of the unary and server streaming methods of the services.
+// The methods without a google.api.http annotation are bound to POST /package.Service/Method.
+func Bindings(files *protoregistry.Files) []Binding {
+	var bindings []Binding
+
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		services := fd.Services()
+		for i := 0; i < services.Len(); i++ {
+			methods := services.Get(i).Methods()
+			for j := 0; j < methods.Len(); j++ {
+				method := methods.Get(j)
+				if method.IsStreamingClient() {
+					continue
+				}
+
+				bindings = append(bindings, methodBindings(method)...)
+			}
+		}
+		return true
+	})
+
+	return bindings
+}
+
+func methodBindings(method protoreflect.MethodDescriptor) []Binding {
+	rule := HTTPRule(method)
+	if rule == nil {
+		binding := Binding{Method: method, HTTPMethod: http.MethodPost, Body: "*"}
+		binding.Pattern = binding.FullMethod()
+		return []Binding{binding}
+	}
+
+	var bindings []Binding
+	if binding, ok := ruleBinding(method, rule); ok {
+		bindings = append(bindings, binding)
+	}
+
+	for _, additional := range rule.GetAdditionalBindings() {
+		if binding, ok := ruleBinding(method, additional); ok {
+			bindings = append(bindings, binding)
+		}
+	}
+
+	return bindings
+}
+
+func ruleBinding(method protoreflect.MethodDescriptor, rule *annotations.HttpRule) (Binding, bool) {
+	binding := Binding{
+		Method:       method,
+		Body:         rule.GetBody(),
+		ResponseBody: rule.GetResponseBody(),
+	}
+
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		binding.HTTPMethod, binding.Pattern = http.MethodGet, pattern.Get
+	case *annotations.HttpRule_Put:
+		binding.HTTPMethod, binding.Pattern = http.MethodPut, pattern.Put
+	case *annotations.HttpRule_Post:
+		binding.HTTPMethod, binding.Pattern = http.MethodPost, pattern.Post
+	case *annotations.HttpRule_Delete:
+		binding.HTTPMethod, binding.Pattern = http.MethodDelete, pattern.Delete
+	case *annotations.HttpRule_Patch:
+		binding.HTTPMethod, binding.Pattern = http.MethodPatch, pattern.Patch
+	case *annotations.HttpRule_Custom:
+		binding.HTTPMethod, binding.Pattern = pattern.Custom.GetKind(), pattern.Custom.GetPath()
+	default:
+		return binding, false
+	}
+
+	return binding, binding.Pattern != ""
+}
+
+// HTTPRule returns the google.api.http annotation of the method, or nil.
+func HTTPRule(method protoreflect.MethodDescriptor) *annotations.HttpRule {
+	opts := method.Options()
+	if opts == nil {
+		return nil
+	}
+
+	// The options are parsed again, so that the annotation is resolved even if the
+	// descriptors were built without the annotation types registered.
+	data, err := proto.Marshal(opts)
+	if err != nil {
+		return nil
+	}
+
+	var parsed descriptorpb.MethodOptions
+	if err := (proto.UnmarshalOptions{Resolver: protoregistry.GlobalTypes}).Unmarshal(data, &parsed); err != nil {
+		return nil
+	}
+
+	if !proto.HasExtension(&parsed, annotations.E_Http) {
+		return nil
+	}
+
+	rule, _ := proto.GetExtension(&parsed, annotations.E_Http).(*annotations.HttpRule)
+	return rule
+}
+
+// fallbackResolver resolves the files of the registry, then the well-known types.
+type fallbackResolver struct {
+	files *protoregistry.Files
+}
+
+func (r *fallbackResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := r.files.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (r *fallbackResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := r.files.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
@@ -0,0 +1,119 @@
+package transcoding
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// HTTPStatusFromCode returns the HTTP status code of a gRPC status code.
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// codeFromHTTPStatus returns the gRPC status code of an upstream response that isn't a gRPC response.
+func codeFromHTTPStatus(status int) codes.Code {
+	switch status {
+	case http.StatusBadRequest:
+		return codes.Internal
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return codes.Unavailable
+	default:
+		return codes.Unknown
+	}
+}
+
+// responseStatus returns the gRPC status of an upstream response, from its trailers
+// or, for a trailers-only response, from its headers.
+func responseStatus(res *http.Response) *statuspb.Status {
+	get := func(key string) string {
+		if value := res.Trailer.Get(key); value != "" {
+			return value
+		}
+		return res.Header.Get(key)
+	}
+
+	code := get("Grpc-Status")
+	if code == "" {
+		return &statuspb.Status{Code: int32(codes.Internal), Message: "missing grpc-status in the upstream response"}
+	}
+
+	st := &statuspb.Status{Code: int32(codes.Unknown)}
+	if parsed, err := strconv.ParseInt(code, 10, 32); err == nil {
+		st.Code = int32(parsed)
+	}
+
+	st.Message = get("Grpc-Message")
+	if unescaped, err := url.PathUnescape(st.Message); err == nil {
+		st.Message = unescaped
+	}
+
+	if details := get("Grpc-Status-Details-Bin"); details != "" {
+		data, err := base64.RawStdEncoding.DecodeString(details)
+		if err != nil {
+			data, err = base64.StdEncoding.DecodeString(details)
+		}
+
+		var detailed statuspb.Status
+		if err == nil && proto.Unmarshal(data, &detailed) == nil {
+			st.Details = detailed.Details
+		}
+	}
+
+	return st
+}
+
+// marshalStatus returns the JSON body of an error status. The details are resolved from
+// the descriptors, then the registered types, and omitted if they can't be resolved.
+func (t *Transcoder) marshalStatus(st *statuspb.Status) []byte {
+	for _, resolver := range []interface {
+		protoregistry.ExtensionTypeResolver
+		protoregistry.MessageTypeResolver
+	}{dynamicpb.NewTypes(t.files), protoregistry.GlobalTypes} {
+		if data, err := (protojson.MarshalOptions{Resolver: resolver}).Marshal(st); err == nil {
+			return data
+		}
+	}
+
+	data, _ := protojson.Marshal(&statuspb.Status{Code: st.Code, Message: st.Message})
+	return data
+}
@@ -0,0 +1,162 @@
+package transcoding
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// template is a parsed google.api.http path template, e.g. /v1/{name=shelves/*}/books:list.
+type template struct {
+	segments []segment
+	verb     string
+}
+
+// segment is a literal, * or ** segment of a path template. The segments of a
+// variable are tagged with its field path.
+type segment struct {
+	literal  string
+	variable string
+}
+
+const (
+	wildcard     = "*"
+	deepWildcard = "**"
+)
+
+// parseTemplate parses a path template. A variable without a pattern matches a single segment.
+func parseTemplate(pattern string) (*template, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, fmt.Errorf("invalid path template %q: must start with /", pattern)
+	}
+
+	t := &template{}
+	rest := pattern[1:]
+
+	// The verb follows the last colon outside of a variable.
+	if i := strings.LastIndex(rest, ":"); i >= 0 && !strings.ContainsAny(rest[i:], "}/") {
+		rest, t.verb = rest[:i], rest[i+1:]
+	}
+
+	for rest != "" {
+		if rest[0] == '{' {
+			end := strings.Index(rest, "}")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path template %q: unclosed variable", pattern)
+			}
+
+			variable, varPattern, found := strings.Cut(rest[1:end], "=")
+			if !found {
+				varPattern = wildcard
+			}
+
+			for _, lit := range strings.Split(varPattern, "/") {
+				t.segments = append(t.segments, segment{literal: lit, variable: variable})
+			}
+
+			rest = rest[end+1:]
+		} else {
+			end := strings.Index(rest, "/")
+			if end < 0 {
+				end = len(rest)
+			}
+			t.segments = append(t.segments, segment{literal: rest[:end]})
+			rest = rest[end:]
+		}
+
+		rest = strings.TrimPrefix(rest, "/")
+	}
+
+	for i, s := range t.segments {
+		if s.literal == deepWildcard && i != len(t.segments)-1 {
+			return nil, fmt.Errorf("invalid path template %q: ** must be the last segment", pattern)
+		}
+	}
+
+	return t, nil
+}
+
+// match matches a request path against the template and returns the values of its variables.
+func (t *template) match(path string) (map[string]string, bool) {
+	path = strings.TrimPrefix(path, "/")
+
+	if t.verb != "" {
+		if !strings.HasSuffix(path, ":"+t.verb) {
+			return nil, false
+		}
+		path = strings.TrimSuffix(path, ":"+t.verb)
+	}
+
+	var parts []string
+	if path != "" {
+		parts = strings.Split(path, "/")
+	}
+
+	values := map[string][]string{}
+	i := 0
+
+	for _, s := range t.segments {
+		if s.literal == deepWildcard {
+			if s.variable != "" {
+				values[s.variable] = append(values[s.variable], parts[i:]...)
+			}
+			i = len(parts)
+			break
+		}
+
+		if i >= len(parts) {
+			return nil, false
+		}
+
+		part := parts[i]
+		if s.literal != wildcard && s.literal != part {
+			return nil, false
+		}
+
+		if s.variable != "" {
+			values[s.variable] = append(values[s.variable], part)
+		}
+
+		i++
+	}
+
+	if i != len(parts) {
+		return nil, false
+	}
+
+	vars := make(map[string]string, len(values))
+	for name, parts := range values {
+		value := strings.Join(parts, "/")
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			value = unescaped
+		}
+		vars[name] = value
+	}
+
+	return vars, true
+}
+
+// OASPath returns the OpenAPI path of a path template, where the variables become path
+// parameters, e.g. /v1/{name=shelves/*} becomes /v1/{name}.
+func OASPath(pattern string) string {
+	var b strings.Builder
+
+	for {
+		start := strings.Index(pattern, "{")
+		if start < 0 {
+			b.WriteString(pattern)
+			return b.String()
+		}
+
+		end := strings.Index(pattern[start:], "}")
+		if end < 0 {
+			b.WriteString(pattern)
+			return b.String()
+		}
+
+		name, _, _ := strings.Cut(pattern[start+1:start+end], "=")
+		b.WriteString(pattern[:start])
+		b.WriteString("{" + name + "}")
+		pattern = pattern[start+end+1:]
+	}
+}
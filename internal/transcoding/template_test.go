@@ -0,0 +1,52 @@
+package transcoding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate_Match(t *testing.T) {
+	tcs := []struct {
+		pattern  string
+		path     string
+		expected map[string]string
+	}{
+		{"/v1/books", "/v1/books", map[string]string{}},
+		{"/v1/books", "/v1/books/1", nil},
+		{"/v1/books/{id}", "/v1/books/1", map[string]string{"id": "1"}},
+		{"/v1/books/{id}", "/v1/books", nil},
+		{"/v1/{name=shelves/*/books/*}", "/v1/shelves/1/books/2", map[string]string{"name": "shelves/1/books/2"}},
+		{"/v1/{name=shelves/*/books/*}", "/v1/shelves/1/authors/2", nil},
+		{"/v1/{book.name=books/*}", "/v1/books/a%20b", map[string]string{"book.name": "books/a b"}},
+		{"/v1/files/{path=**}", "/v1/files/a/b/c.txt", map[string]string{"path": "a/b/c.txt"}},
+		{"/v1/books/*:publish", "/v1/books/1:publish", map[string]string{}},
+		{"/v1/{name=books/*}:publish", "/v1/books/1:publish", map[string]string{"name": "books/1"}},
+		{"/v1/{name=books/*}:publish", "/v1/books/1", nil},
+	}
+
+	for _, tc := range tcs {
+		tmpl, err := parseTemplate(tc.pattern)
+		require.NoError(t, err, tc.pattern)
+
+		vars, ok := tmpl.match(tc.path)
+		assert.Equal(t, tc.expected != nil, ok, "%s %s", tc.pattern, tc.path)
+		if tc.expected != nil {
+			assert.Equal(t, tc.expected, vars, "%s %s", tc.pattern, tc.path)
+		}
+	}
+}
+
+func TestParseTemplate_Errors(t *testing.T) {
+	for _, pattern := range []string{"v1/books", "/v1/{name", "/v1/{path=**}/books"} {
+		_, err := parseTemplate(pattern)
+		assert.Error(t, err, pattern)
+	}
+}
+
+func TestOASPath(t *testing.T) {
+	assert.Equal(t, "/v1/{name}", OASPath("/v1/{name=shelves/*/books/*}"))
+	assert.Equal(t, "/v1/{parent}/books:batchGet", OASPath("/v1/{parent=shelves/*}/books:batchGet"))
+	assert.Equal(t, "/v1/books", OASPath("/v1/books"))
+}
@@ -0,0 +1,530 @@
+// Package transcoding proxies HTTP/JSON requests to gRPC upstreams, following the
+// google.api.http annotations of the service descriptors.
+package transcoding
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	// StreamFormatNDJSON streams the messages of server streaming methods as newline delimited JSON.
+	StreamFormatNDJSON = "ndjson"
+	// StreamFormatSSE streams the messages of server streaming methods as server-sent events.
+	StreamFormatSSE = "sse"
+
+	// maxMessageSize is the maximum size of an upstream message, the gRPC default.
+	maxMessageSize = 4 << 20
+
+	contentTypeJSON   = "application/json"
+	contentTypeNDJSON = "application/x-ndjson"
+	contentTypeSSE    = "text/event-stream"
+	contentTypeGRPC   = "application/grpc"
+)
+
+var errCompressedMessage = errors.New("compressed gRPC messages are not supported")
+
+// Transcoder translates HTTP/JSON requests to gRPC calls and their responses back to JSON.
+type Transcoder struct {
+	files        *protoregistry.Files
+	routes       []route
+	streamFormat string
+}
+
+type route struct {
+	Binding
+	template *template
+}
+
+// New builds a transcoder from a serialised FileDescriptorSet.
+func New(descriptorSet []byte, streamFormat string) (*Transcoder, error) {
+	files, err := ParseDescriptorSet(descriptorSet)
+	if err != nil {
+		return nil, err
+	}
+
+	switch streamFormat {
+	case "":
+		streamFormat = StreamFormatNDJSON
+	case StreamFormatNDJSON, StreamFormatSSE:
+	default:
+		return nil, fmt.Errorf("unsupported stream format %q", streamFormat)
+	}
+
+	t := &Transcoder{files: files, streamFormat: streamFormat}
+	for _, binding := range Bindings(files) {
+		tmpl, err := parseTemplate(binding.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", binding.FullMethod(), err)
+		}
+
+		t.routes = append(t.routes, route{Binding: binding, template: tmpl})
+	}
+
+	if len(t.routes) == 0 {
+		return nil, ErrNoServices
+	}
+
+	return t, nil
+}
+
+// match returns the route of the request and the values of its path variables.
+func (t *Transcoder) match(method, path string) (*route, map[string]string, bool) {
+	for i := range t.routes {
+		r := &t.routes[i]
+		if r.HTTPMethod != method {
+			continue
+		}
+
+		if vars, ok := r.template.match(path); ok {
+			return r, vars, true
+		}
+	}
+
+	return nil, nil, false
+}
+
+// RoundTrip sends the request to the gRPC upstream with the round tripper and returns the
+// transcoded response. The round tripper must speak HTTP/2 to the upstream. The path of the
+// request, relative to the listen path of the API, is matched against the methods. Requests that
+// don't match a method, or can't be transcoded, get an error response without calling the upstream.
+func (t *Transcoder) RoundTrip(rt http.RoundTripper, req *http.Request, path string) (*http.Response, error) {
+	r, vars, ok := t.match(req.Method, path)
+	if !ok {
+		return t.errorResponse(req, &statuspb.Status{Code: int32(codes.NotFound), Message: "Not Found"}), nil
+	}
+
+	input, err := r.input(req, vars)
+	if err != nil {
+		return t.errorResponse(req, &statuspb.Status{Code: int32(codes.InvalidArgument), Message: err.Error()}), nil
+	}
+
+	payload, err := proto.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	frame := make([]byte, 5+len(payload))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(payload)))
+	copy(frame[5:], payload)
+
+	u := *req.URL
+	u.Path, u.RawPath, u.RawQuery = r.FullMethod(), "", ""
+
+	grpcReq, err := http.NewRequestWithContext(req.Context(), http.MethodPost, u.String(), bytes.NewReader(frame))
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range req.Header {
+		switch key {
+		case "Content-Length", "Content-Type", "Accept", "Accept-Encoding", "Connection", "Te":
+			continue
+		}
+		grpcReq.Header[key] = values
+	}
+
+	grpcReq.Host = req.Host
+	grpcReq.Header.Set("Content-Type", contentTypeGRPC)
+	grpcReq.Header.Set("Te", "trailers")
+
+	res, err := rt.RoundTrip(grpcReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return t.errorResponse(req, &statuspb.Status{
+			Code:    int32(codeFromHTTPStatus(res.StatusCode)),
+			Message: fmt.Sprintf("upstream responded with HTTP status %d", res.StatusCode),
+		}), nil
+	}
+
+	if r.Method.IsStreamingServer() {
+		return t.streamResponse(req, res, r), nil
+	}
+
+	return t.unaryResponse(req, res, r), nil
+}
+
+// input builds the request message from the body, the query parameters and the path variables.
+func (r *route) input(req *http.Request, vars map[string]string) (*dynamicpb.Message, error) {
+	input := dynamicpb.NewMessage(r.Method.Input())
+	unmarshal := protojson.UnmarshalOptions{DiscardUnknown: true}
+
+	if r.Body != "" && req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(body)) > 0 {
+			if r.Body != "*" {
+				field := input.Descriptor().Fields().ByName(protoreflect.Name(r.Body))
+				if field == nil {
+					return nil, fmt.Errorf("unknown body field %q", r.Body)
+				}
+
+				body, err = json.Marshal(map[string]json.RawMessage{field.JSONName(): body})
+				if err != nil {
+					return nil, fmt.Errorf("invalid request body: %w", err)
+				}
+			}
+
+			if err := unmarshal.Unmarshal(body, input); err != nil {
+				return nil, fmt.Errorf("invalid request body: %w", err)
+			}
+		}
+	}
+
+	// The query parameters are ignored when the whole message is bound to the body.
+	if r.Body != "*" {
+		for key, values := range req.URL.Query() {
+			if err := setField(input, key, values); err != nil && !errors.Is(err, errUnknownField) {
+				return nil, err
+			}
+		}
+	}
+
+	for key, value := range vars {
+		if err := setField(input, key, []string{value}); err != nil {
+			return nil, err
+		}
+	}
+
+	return input, nil
+}
+
+var errUnknownField = errors.New("unknown field")
+
+// setField sets the field at a dotted path of the message, e.g. book.author.name, from its
+// string values. The fields are looked up by name, then by JSON name.
+func setField(msg protoreflect.Message, path string, values []string) error {
+	names := strings.Split(path, ".")
+
+	for i, name := range names {
+		fields := msg.Descriptor().Fields()
+		fd := fields.ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = fields.ByJSONName(name)
+		}
+		if fd == nil {
+			return fmt.Errorf("%w %q", errUnknownField, path)
+		}
+
+		if i < len(names)-1 {
+			if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+				return fmt.Errorf("%w %q", errUnknownField, path)
+			}
+			msg = msg.Mutable(fd).Message()
+			continue
+		}
+
+		if fd.IsMap() {
+			return fmt.Errorf("map field %q can't be set from a parameter", path)
+		}
+
+		if fd.IsList() {
+			list := msg.Mutable(fd).List()
+			for _, value := range values {
+				v, err := parseValue(fd, list.NewElement, value)
+				if err != nil {
+					return err
+				}
+				list.Append(v)
+			}
+			return nil
+		}
+
+		if len(values) == 0 {
+			return nil
+		}
+
+		v, err := parseValue(fd, func() protoreflect.Value { return msg.NewField(fd) }, values[0])
+		if err != nil {
+			return err
+		}
+		msg.Set(fd, v)
+	}
+
+	return nil
+}
+
+// parseValue parses the string value of a field. Messages are parsed from their JSON string
+// representation, which covers the well-known types such as timestamps and wrappers.
+func parseValue(fd protoreflect.FieldDescriptor, newValue func() protoreflect.Value, value string) (protoreflect.Value, error) {
+	invalid := func(err error) (protoreflect.Value, error) {
+		return protoreflect.Value{}, fmt.Errorf("invalid value %q for field %s: %w", value, fd.Name(), err)
+	}
+
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(value), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfBool(b), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfInt32(int32(n)), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfInt64(n), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfUint32(uint32(n)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfUint64(n), nil
+	case protoreflect.FloatKind:
+		f, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfFloat32(float32(f)), nil
+	case protoreflect.DoubleKind:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfFloat64(f), nil
+	case protoreflect.BytesKind:
+		b, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			if b, err = base64.URLEncoding.DecodeString(value); err != nil {
+				return invalid(err)
+			}
+		}
+		return protoreflect.ValueOfBytes(b), nil
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(value)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		v := newValue()
+		data, _ := json.Marshal(value)
+		if err := protojson.Unmarshal(data, v.Message().Interface()); err != nil {
+			return invalid(err)
+		}
+		return v, nil
+	}
+
+	return invalid(fmt.Errorf("unsupported kind %s", fd.Kind()))
+}
+
+// unaryResponse transcodes the response message to JSON, or the gRPC status to an error response.
+func (t *Transcoder) unaryResponse(req *http.Request, res *http.Response, r *route) *http.Response {
+	defer res.Body.Close()
+
+	payload, err := readMessage(res.Body)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return t.errorResponse(req, &statuspb.Status{Code: int32(codes.Internal), Message: err.Error()})
+	}
+
+	// The trailers are only available once the body is consumed.
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if st := responseStatus(res); st.Code != int32(codes.OK) {
+		return t.errorResponse(req, st)
+	}
+
+	body, err := r.marshalOutput(payload)
+	if err != nil {
+		return t.errorResponse(req, &statuspb.Status{Code: int32(codes.Internal), Message: err.Error()})
+	}
+
+	return newResponse(req, res.Header, http.StatusOK, contentTypeJSON, body)
+}
+
+// streamResponse transcodes the messages of a server stream as they are received, followed by
+// an error if the stream ends with an error status.
+func (t *Transcoder) streamResponse(req *http.Request, res *http.Response, r *route) *http.Response {
+	pr, pw := io.Pipe()
+
+	go func() {
+		defer res.Body.Close()
+
+		for {
+			payload, err := readMessage(res.Body)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+
+			body, err := r.marshalOutput(payload)
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+
+			if _, err := pw.Write(t.streamEvent("result", body)); err != nil {
+				return
+			}
+		}
+
+		if st := responseStatus(res); st.Code != int32(codes.OK) {
+			_, _ = pw.Write(t.streamEvent("error", t.marshalStatus(st)))
+		}
+
+		pw.Close()
+	}()
+
+	contentType := contentTypeNDJSON
+	if t.streamFormat == StreamFormatSSE {
+		contentType = contentTypeSSE
+	}
+
+	out := newResponse(req, res.Header, http.StatusOK, contentType, nil)
+	out.Body = pr
+	out.ContentLength = -1
+	out.Header.Del("Content-Length")
+
+	return out
+}
+
+// streamEvent formats a message of a stream. NDJSON lines wrap the message in a result or
+// error field, server-sent events use the event type instead.
+func (t *Transcoder) streamEvent(kind string, body []byte) []byte {
+	var b bytes.Buffer
+
+	if t.streamFormat == StreamFormatSSE {
+		if kind != "result" {
+			b.WriteString("event: " + kind + "\n")
+		}
+		b.WriteString("data: ")
+		b.Write(body)
+		b.WriteString("\n\n")
+		return b.Bytes()
+	}
+
+	b.WriteString(`{"` + kind + `":`)
+	b.Write(body)
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+// marshalOutput decodes a response message and marshals it, or its response body field, to JSON.
+func (r *route) marshalOutput(payload []byte) ([]byte, error) {
+	output := dynamicpb.NewMessage(r.Method.Output())
+	if err := proto.Unmarshal(payload, output); err != nil {
+		return nil, fmt.Errorf("invalid upstream message: %w", err)
+	}
+
+	if r.ResponseBody == "" {
+		return protojson.Marshal(output)
+	}
+
+	field := output.Descriptor().Fields().ByName(protoreflect.Name(r.ResponseBody))
+	if field == nil {
+		return nil, fmt.Errorf("unknown response body field %q", r.ResponseBody)
+	}
+
+	data, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(output)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields[field.JSONName()], nil
+}
+
+// readMessage reads a length-prefixed gRPC message.
+func readMessage(r io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, errors.New("truncated gRPC message")
+		}
+		return nil, err
+	}
+
+	if header[0] != 0 {
+		return nil, errCompressedMessage
+	}
+
+	length := binary.BigEndian.Uint32(header[1:])
+	if length > maxMessageSize {
+		return nil, fmt.Errorf("gRPC message of %d bytes exceeds the limit of %d bytes", length, maxMessageSize)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, errors.New("truncated gRPC message")
+	}
+
+	return payload, nil
+}
+
+// errorResponse returns the JSON error response of a gRPC status.
+func (t *Transcoder) errorResponse(req *http.Request, st *statuspb.Status) *http.Response {
+	return newResponse(req, nil, HTTPStatusFromCode(codes.Code(st.Code)), contentTypeJSON, t.marshalStatus(st))
+}
+
+// newResponse builds a response, keeping the upstream headers that aren't gRPC specific.
+func newResponse(req *http.Request, upstream http.Header, status int, contentType string, body []byte) *http.Response {
+	header := http.Header{}
+	for key, values := range upstream {
+		if strings.HasPrefix(key, "Grpc-") || key == "Content-Type" || key == "Content-Length" || key == "Trailer" {
+			continue
+		}
+		header[key] = values
+	}
+
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,314 @@
+package transcoding
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func httpRule(rule *annotations.HttpRule) *descriptorpb.MethodOptions {
+	opts := &descriptorpb.MethodOptions{}
+	proto.SetExtension(opts, annotations.E_Http, rule)
+	return opts
+}
+
+func field(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string, repeated bool) *descriptorpb.FieldDescriptorProto {
+	label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	if repeated {
+		label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	}
+
+	fd := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Type:   typ.Enum(),
+		Label:  label.Enum(),
+	}
+	if typeName != "" {
+		fd.TypeName = proto.String(typeName)
+	}
+
+	return fd
+}
+
+// libraryDescriptor returns the FileDescriptorSet of a library service.
+func libraryDescriptor(t *testing.T) []byte {
+	t.Helper()
+
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING
+	msg := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("library.proto"),
+		Package:    proto.String("library.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/api/annotations.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("Book"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("name", 1, str, "", false),
+				field("title", 2, str, "", false),
+				field("page_count", 3, descriptorpb.FieldDescriptorProto_TYPE_INT32, "", false),
+				field("tags", 4, str, "", true),
+			}},
+			{Name: proto.String("GetBookRequest"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("name", 1, str, "", false),
+			}},
+			{Name: proto.String("CreateBookRequest"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("parent", 1, str, "", false),
+				field("book", 2, msg, ".library.v1.Book", false),
+			}},
+			{Name: proto.String("ListBooksRequest"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("parent", 1, str, "", false),
+				field("page_size", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32, "", false),
+			}},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Library"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{
+					Name: proto.String("GetBook"), InputType: proto.String(".library.v1.GetBookRequest"), OutputType: proto.String(".library.v1.Book"),
+					Options: httpRule(&annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: "/v1/{name=shelves/*/books/*}"}}),
+				},
+				{
+					Name: proto.String("CreateBook"), InputType: proto.String(".library.v1.CreateBookRequest"), OutputType: proto.String(".library.v1.Book"),
+					Options: httpRule(&annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: "/v1/{parent=shelves/*}/books"}, Body: "book"}),
+				},
+				{
+					Name: proto.String("ListBooks"), InputType: proto.String(".library.v1.ListBooksRequest"), OutputType: proto.String(".library.v1.Book"),
+					ServerStreaming: proto.Bool(true),
+					Options:         httpRule(&annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: "/v1/{parent=shelves/*}/books"}}),
+				},
+				{
+					Name: proto.String("Ping"), InputType: proto.String(".library.v1.Book"), OutputType: proto.String(".library.v1.Book"),
+				},
+				{
+					Name: proto.String("Upload"), InputType: proto.String(".library.v1.Book"), OutputType: proto.String(".library.v1.Book"),
+					ClientStreaming: proto.Bool(true),
+				},
+			},
+		}},
+	}
+
+	data, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{fdp}})
+	require.NoError(t, err)
+
+	return data
+}
+
+func frame(t *testing.T, messages ...string) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	for _, msg := range messages {
+		var header [5]byte
+		binary.BigEndian.PutUint32(header[1:], uint32(len(msg)))
+		b.Write(header[:])
+		b.WriteString(msg)
+	}
+
+	return b.Bytes()
+}
+
+// upstream returns a gRPC upstream that records the requests and responds with
+// the JSON messages and the status.
+func upstream(t *testing.T, tr *Transcoder, received *map[string]string, status string, messages ...string) http.RoundTripper {
+	t.Helper()
+
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, contentTypeGRPC, r.Header.Get("Content-Type"))
+		assert.Equal(t, "trailers", r.Header.Get("Te"))
+
+		route := tr.routeOf(r.URL.Path)
+		require.NotNil(t, route, r.URL.Path)
+
+		payload, err := readMessage(r.Body)
+		require.NoError(t, err)
+
+		input := dynamicpb.NewMessage(route.Method.Input())
+		require.NoError(t, proto.Unmarshal(payload, input))
+		data, err := protojson.Marshal(input)
+		require.NoError(t, err)
+		(*received)[r.URL.Path] = string(data)
+
+		var encoded []string
+		for _, msg := range messages {
+			output := dynamicpb.NewMessage(route.Method.Output())
+			require.NoError(t, protojson.Unmarshal([]byte(msg), output))
+			payload, err := proto.Marshal(output)
+			require.NoError(t, err)
+			encoded = append(encoded, string(payload))
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {contentTypeGRPC}, "X-Upstream": {"library"}},
+			Body:       io.NopCloser(bytes.NewReader(frame(t, encoded...))),
+			Trailer:    http.Header{"Grpc-Status": {status}, "Grpc-Message": {"book%20not%20found"}},
+		}, nil
+	})
+}
+
+func (t *Transcoder) routeOf(fullMethod string) *route {
+	for i := range t.routes {
+		if t.routes[i].FullMethod() == fullMethod {
+			return &t.routes[i]
+		}
+	}
+	return nil
+}
+
+func TestTranscoder(t *testing.T) {
+	tr, err := New(libraryDescriptor(t), "")
+	require.NoError(t, err)
+	assert.Len(t, tr.routes, 4, "client streaming methods are not transcoded")
+
+	roundTrip := func(t *testing.T, rt http.RoundTripper, method, target, body string) (*http.Response, string) {
+		t.Helper()
+
+		req := httptest.NewRequest(method, "http://upstream"+target, strings.NewReader(body))
+		res, err := tr.RoundTrip(rt, req, req.URL.Path)
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		return res, string(data)
+	}
+
+	t.Run("unary with path variables", func(t *testing.T) {
+		received := map[string]string{}
+		rt := upstream(t, tr, &received, "0", `{"name": "shelves/1/books/2", "title": "Dune", "pageCount": 412}`)
+
+		res, body := roundTrip(t, rt, http.MethodGet, "/v1/shelves/1/books/2", "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, contentTypeJSON, res.Header.Get("Content-Type"))
+		assert.Equal(t, "library", res.Header.Get("X-Upstream"))
+		assert.JSONEq(t, `{"name": "shelves/1/books/2", "title": "Dune", "pageCount": 412}`, body)
+		assert.JSONEq(t, `{"name": "shelves/1/books/2"}`, received["/library.v1.Library/GetBook"])
+	})
+
+	t.Run("body field and query parameters", func(t *testing.T) {
+		received := map[string]string{}
+		rt := upstream(t, tr, &received, "0", `{"title": "Dune"}`)
+
+		res, _ := roundTrip(t, rt, http.MethodPost, "/v1/shelves/1/books?book.tags=a&book.tags=b&unknown=1", `{"title": "Dune", "page_count": 412}`)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.JSONEq(t, `{"parent": "shelves/1", "book": {"title": "Dune", "pageCount": 412, "tags": ["a", "b"]}}`, received["/library.v1.Library/CreateBook"])
+	})
+
+	t.Run("method without annotation", func(t *testing.T) {
+		received := map[string]string{}
+		rt := upstream(t, tr, &received, "0", `{}`)
+
+		res, body := roundTrip(t, rt, http.MethodPost, "/library.v1.Library/Ping", `{"title": "ping"}`)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.JSONEq(t, `{}`, body)
+		assert.JSONEq(t, `{"title": "ping"}`, received["/library.v1.Library/Ping"])
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		received := map[string]string{}
+		rt := upstream(t, tr, &received, "5")
+
+		res, body := roundTrip(t, rt, http.MethodGet, "/v1/shelves/1/books/3", "")
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		assert.JSONEq(t, `{"code": 5, "message": "book not found"}`, body)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		res, body := roundTrip(t, nil, http.MethodPost, "/v1/shelves/1/books", `{"title": 1`)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Contains(t, body, `"code":3`)
+
+		res, _ = roundTrip(t, nil, http.MethodGet, "/v1/shelves/1/books?page_size=ten", "")
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("no matching method", func(t *testing.T) {
+		res, _ := roundTrip(t, nil, http.MethodDelete, "/v1/shelves/1/books/2", "")
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("server streaming as ndjson", func(t *testing.T) {
+		received := map[string]string{}
+		rt := upstream(t, tr, &received, "14", `{"title": "Dune"}`, `{"title": "Emma"}`)
+
+		res, body := roundTrip(t, rt, http.MethodGet, "/v1/shelves/1/books?page_size=2", "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, contentTypeNDJSON, res.Header.Get("Content-Type"))
+		assert.Equal(t, int64(-1), res.ContentLength)
+		assert.JSONEq(t, `{"parent": "shelves/1", "pageSize": 2}`, received["/library.v1.Library/ListBooks"])
+
+		lines := strings.Split(strings.TrimSpace(body), "\n")
+		require.Len(t, lines, 3)
+		assert.JSONEq(t, `{"result": {"title": "Dune"}}`, lines[0])
+		assert.JSONEq(t, `{"result": {"title": "Emma"}}`, lines[1])
+		assert.JSONEq(t, `{"error": {"code": 14, "message": "book not found"}}`, lines[2])
+	})
+
+	t.Run("server streaming as sse", func(t *testing.T) {
+		sse, err := New(libraryDescriptor(t), StreamFormatSSE)
+		require.NoError(t, err)
+
+		received := map[string]string{}
+		req := httptest.NewRequest(http.MethodGet, "http://upstream/v1/shelves/1/books", nil)
+		res, err := sse.RoundTrip(upstream(t, sse, &received, "0", `{"title": "Dune"}`), req, req.URL.Path)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, contentTypeSSE, res.Header.Get("Content-Type"))
+		require.True(t, strings.HasPrefix(string(body), "data: "))
+		require.True(t, strings.HasSuffix(string(body), "\n\n"))
+		assert.JSONEq(t, `{"title": "Dune"}`, strings.TrimPrefix(strings.TrimSpace(string(body)), "data: "))
+	})
+}
+
+func TestNew_Errors(t *testing.T) {
+	_, err := New([]byte("not a descriptor"), "")
+	assert.Error(t, err)
+
+	_, err = New(libraryDescriptor(t), "xml")
+	assert.Error(t, err)
+}
+
+func TestHTTPStatusFromCode(t *testing.T) {
+	tcs := map[codes.Code]int{
+		codes.OK:                 http.StatusOK,
+		codes.Canceled:           499,
+		codes.InvalidArgument:    http.StatusBadRequest,
+		codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+		codes.NotFound:           http.StatusNotFound,
+		codes.AlreadyExists:      http.StatusConflict,
+		codes.PermissionDenied:   http.StatusForbidden,
+		codes.Unauthenticated:    http.StatusUnauthorized,
+		codes.ResourceExhausted:  http.StatusTooManyRequests,
+		codes.FailedPrecondition: http.StatusBadRequest,
+		codes.Unimplemented:      http.StatusNotImplemented,
+		codes.Unavailable:        http.StatusServiceUnavailable,
+		codes.DataLoss:           http.StatusInternalServerError,
+	}
+
+	for code, expected := range tcs {
+		assert.Equal(t, expected, HTTPStatusFromCode(code), code.String())
+	}
+}
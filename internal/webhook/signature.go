@@ -0,0 +1,93 @@
+// Package webhook implements signing of webhook deliveries according to the
+// Standard Webhooks specification (https://www.standardwebhooks.com).
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers set on signed webhook deliveries.
+const (
+	HeaderID        = "webhook-id"
+	HeaderTimestamp = "webhook-timestamp"
+	HeaderSignature = "webhook-signature"
+)
+
+const (
+	// secretPrefix marks a base64 encoded secret.
+	secretPrefix = "whsec_"
+	// signatureVersion is the version prefix of an HMAC-SHA256 signature.
+	signatureVersion = "v1"
+)
+
+// ErrInvalidSecret is returned when a `whsec_` prefixed secret isn't valid base64.
+var ErrInvalidSecret = errors.New("invalid webhook signing secret")
+
+// Sign returns the `v1,<signature>` value for the webhook-signature header,
+// an HMAC-SHA256 over "<id>.<timestamp>.<body>". Secrets prefixed with
+// `whsec_` are base64 decoded, other secrets are used as is.
+func Sign(secret, id string, timestamp time.Time, body []byte) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id))
+	mac.Write([]byte{'.'})
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+
+	return signatureVersion + "," + base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// SignRequest sets the webhook-id, webhook-timestamp and webhook-signature
+// headers on req for the given message id and body.
+func SignRequest(req *http.Request, secret, id string, timestamp time.Time, body []byte) error {
+	signature, err := Sign(secret, id, timestamp, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set(HeaderID, id)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp.Unix(), 10))
+	req.Header.Set(HeaderSignature, signature)
+	return nil
+}
+
+// Verify reports whether any signature in the space delimited header value
+// matches the expected signature for the message.
+func Verify(secret, id string, timestamp time.Time, body []byte, header string) (bool, error) {
+	expected, err := Sign(secret, id, timestamp, body)
+	if err != nil {
+		return false, err
+	}
+
+	for _, signature := range strings.Fields(header) {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	if !strings.HasPrefix(secret, secretPrefix) {
+		return []byte(secret), nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, secretPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSecret, err)
+	}
+	return key, nil
+}
@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	// Test vector from the Standard Webhooks reference implementations.
+	const (
+		secret    = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+		id        = "msg_p5jXN8AQM9LWM0D4loKWxJek"
+		payload   = `{"test": 2432232314}`
+		signature = "v1,g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE="
+	)
+	timestamp := time.Unix(1614265330, 0)
+
+	got, err := Sign(secret, id, timestamp, []byte(payload))
+	require.NoError(t, err)
+	assert.Equal(t, signature, got)
+
+	ok, err := Verify(secret, id, timestamp, []byte(payload), "v1,bm90LXRoZS1zaWduYXR1cmU= "+signature)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = Verify(secret, id, timestamp.Add(time.Second), []byte(payload), signature)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestSign_InvalidSecret(t *testing.T) {
+	_, err := Sign("whsec_%%%", "id", time.Now(), nil)
+	assert.ErrorIs(t, err, ErrInvalidSecret)
+}
+
+func TestSignRequest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "http://example.com", nil)
+	require.NoError(t, err)
+
+	timestamp := time.Unix(1614265330, 0)
+	require.NoError(t, SignRequest(req, "secret", "msg_1", timestamp, []byte("{}")))
+
+	assert.Equal(t, "msg_1", req.Header.Get(HeaderID))
+	assert.Equal(t, "1614265330", req.Header.Get(HeaderTimestamp))
+
+	expected, err := Sign("secret", "msg_1", timestamp, []byte("{}"))
+	require.NoError(t, err)
+	assert.Equal(t, expected, req.Header.Get(HeaderSignature))
+}
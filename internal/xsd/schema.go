@@ -0,0 +1,503 @@
+// Package xsd validates XML documents against XML schemas.
+//
+// It supports the subset of XML Schema 1.0 used by the types of WSDL documents: global and local
+// elements, named and anonymous complex and simple types, sequence, choice and all groups, element
+// references, occurrence bounds, attributes, complex and simple content extensions, restrictions
+// with the common facets, lists and unions. Imports and includes of external schemas aren't
+// resolved, and elements and types are matched by local name.
+package xsd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Namespace is the namespace of the XML schema definitions.
+const Namespace = "http://www.w3.org/2001/XMLSchema"
+
+// namespaceInstance is the namespace of the XML schema instance attributes, e.g. xsi:nil.
+const namespaceInstance = "http://www.w3.org/2001/XMLSchema-instance"
+
+const unbounded = -1
+
+// ErrNoSchema is returned when a document has no XML schema.
+var ErrNoSchema = errors.New("no XML schema found")
+
+// Schema is a set of compiled XML schemas.
+type Schema struct {
+	elements     map[string]*element
+	complexTypes map[string]*complexType
+	simpleTypes  map[string]*simpleType
+	attributes   map[string]*attribute
+	groups       map[string]*particle
+}
+
+type qname struct {
+	space string
+	local string
+}
+
+func (q qname) builtin() bool {
+	return q.space == Namespace
+}
+
+type element struct {
+	name     string
+	ref      string
+	typeName qname
+	complex  *complexType
+	simple   *simpleType
+	nillable bool
+}
+
+type particle struct {
+	kind    string
+	element *element
+	// group is the name of the referenced model group.
+	group    string
+	children []*particle
+	min, max int
+}
+
+type complexType struct {
+	particle     *particle
+	attributes   []*attribute
+	anyAttribute bool
+	mixed        bool
+
+	// base is the base type of a complex or simple content extension.
+	base qname
+	// simpleContent is set when the content of the type is a text of the base type.
+	simpleContent bool
+}
+
+type simpleType struct {
+	base   qname
+	inline *simpleType
+	facets facets
+
+	list     bool
+	itemType qname
+	item     *simpleType
+
+	union   bool
+	members []qname
+	inlines []*simpleType
+}
+
+type attribute struct {
+	name     string
+	ref      string
+	typeName qname
+	simple   *simpleType
+	required bool
+}
+
+// Parse compiles the XML schemas of a document. The document is either an XML schema, or a
+// document containing XML schemas such as the types of a WSDL document.
+func Parse(data []byte) (*Schema, error) {
+	root, err := parseNode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Schema{
+		elements:     map[string]*element{},
+		complexTypes: map[string]*complexType{},
+		simpleTypes:  map[string]*simpleType{},
+		attributes:   map[string]*attribute{},
+		groups:       map[string]*particle{},
+	}
+
+	found := false
+	var walk func(n *node) error
+	walk = func(n *node) error {
+		if n.is("schema") {
+			found = true
+			return s.parseSchema(n)
+		}
+
+		for _, child := range n.children {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := walk(root); err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, ErrNoSchema
+	}
+
+	return s, nil
+}
+
+// Elements returns the names of the global elements.
+func (s *Schema) Elements() []string {
+	names := make([]string, 0, len(s.elements))
+	for name := range s.elements {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (s *Schema) parseSchema(n *node) error {
+	for _, child := range n.children {
+		var err error
+		switch {
+		case child.is("element"):
+			var el *element
+			if el, err = s.parseElement(child); err == nil {
+				s.elements[el.name] = el
+			}
+		case child.is("complexType"):
+			var ct *complexType
+			if ct, err = s.parseComplexType(child); err == nil {
+				s.complexTypes[child.attr("name")] = ct
+			}
+		case child.is("simpleType"):
+			var st *simpleType
+			if st, err = s.parseSimpleType(child); err == nil {
+				s.simpleTypes[child.attr("name")] = st
+			}
+		case child.is("attribute"):
+			var attr *attribute
+			if attr, err = s.parseAttribute(child); err == nil {
+				s.attributes[attr.name] = attr
+			}
+		case child.is("group"):
+			for _, group := range child.children {
+				if group.is("sequence") || group.is("choice") || group.is("all") {
+					var p *particle
+					if p, err = s.parseParticle(group); err == nil {
+						s.groups[child.attr("name")] = p
+					}
+				}
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) parseElement(n *node) (*element, error) {
+	el := &element{
+		name:     n.attr("name"),
+		ref:      n.qname("ref").local,
+		typeName: n.qname("type"),
+		nillable: n.attr("nillable") == "true",
+	}
+
+	if el.name == "" && el.ref == "" {
+		return nil, errors.New("element without name")
+	}
+
+	for _, child := range n.children {
+		var err error
+		switch {
+		case child.is("complexType"):
+			el.complex, err = s.parseComplexType(child)
+		case child.is("simpleType"):
+			el.simple, err = s.parseSimpleType(child)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("element %s: %w", el.name, err)
+		}
+	}
+
+	return el, nil
+}
+
+func (s *Schema) parseComplexType(n *node) (*complexType, error) {
+	ct := &complexType{mixed: n.attr("mixed") == "true"}
+
+	for _, child := range n.children {
+		switch {
+		case child.is("complexContent"), child.is("simpleContent"):
+			ct.simpleContent = child.is("simpleContent")
+			if child.attr("mixed") == "true" {
+				ct.mixed = true
+			}
+
+			for _, derivation := range child.children {
+				if !derivation.is("extension") && !derivation.is("restriction") {
+					continue
+				}
+
+				// A restriction of a complex type redefines its content, only extensions inherit it.
+				if derivation.is("extension") || ct.simpleContent {
+					ct.base = derivation.qname("base")
+				}
+
+				for _, content := range derivation.children {
+					if err := s.parseContent(ct, content); err != nil {
+						return nil, err
+					}
+				}
+			}
+		default:
+			if err := s.parseContent(ct, child); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return ct, nil
+}
+
+// parseContent parses a particle or an attribute of a complex type.
+func (s *Schema) parseContent(ct *complexType, n *node) error {
+	switch {
+	case n.is("sequence"), n.is("choice"), n.is("all"), n.is("group"):
+		p, err := s.parseParticle(n)
+		if err != nil {
+			return err
+		}
+		ct.particle = p
+	case n.is("attribute"):
+		attr, err := s.parseAttribute(n)
+		if err != nil {
+			return err
+		}
+		ct.attributes = append(ct.attributes, attr)
+	case n.is("anyAttribute"):
+		ct.anyAttribute = true
+	}
+
+	return nil
+}
+
+func (s *Schema) parseParticle(n *node) (*particle, error) {
+	p := &particle{kind: n.name.Local, min: 1, max: 1}
+
+	if value := n.attr("minOccurs"); value != "" {
+		min, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid minOccurs %q", value)
+		}
+		p.min = min
+	}
+
+	if value := n.attr("maxOccurs"); value == "unbounded" {
+		p.max = unbounded
+	} else if value != "" {
+		max, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maxOccurs %q", value)
+		}
+		p.max = max
+	}
+
+	switch p.kind {
+	case "element":
+		el, err := s.parseElement(n)
+		if err != nil {
+			return nil, err
+		}
+		p.element = el
+	case "group":
+		p.group = n.qname("ref").local
+	case "sequence", "choice", "all":
+		for _, child := range n.children {
+			if !child.is("element") && !child.is("sequence") && !child.is("choice") && !child.is("all") && !child.is("any") && !child.is("group") {
+				continue
+			}
+
+			c, err := s.parseParticle(child)
+			if err != nil {
+				return nil, err
+			}
+			p.children = append(p.children, c)
+		}
+	}
+
+	return p, nil
+}
+
+func (s *Schema) parseSimpleType(n *node) (*simpleType, error) {
+	st := &simpleType{}
+
+	for _, child := range n.children {
+		switch {
+		case child.is("restriction"):
+			st.base = child.qname("base")
+			for _, facet := range child.children {
+				if facet.is("simpleType") {
+					inline, err := s.parseSimpleType(facet)
+					if err != nil {
+						return nil, err
+					}
+					st.inline = inline
+					continue
+				}
+
+				if err := st.facets.add(facet.name.Local, facet.attr("value")); err != nil {
+					return nil, err
+				}
+			}
+		case child.is("list"):
+			st.list = true
+			st.itemType = child.qname("itemType")
+			for _, item := range child.children {
+				if item.is("simpleType") {
+					inline, err := s.parseSimpleType(item)
+					if err != nil {
+						return nil, err
+					}
+					st.item = inline
+				}
+			}
+		case child.is("union"):
+			st.union = true
+			for _, member := range strings.Fields(child.attr("memberTypes")) {
+				st.members = append(st.members, child.resolve(member))
+			}
+			for _, member := range child.children {
+				if member.is("simpleType") {
+					inline, err := s.parseSimpleType(member)
+					if err != nil {
+						return nil, err
+					}
+					st.inlines = append(st.inlines, inline)
+				}
+			}
+		}
+	}
+
+	return st, nil
+}
+
+func (s *Schema) parseAttribute(n *node) (*attribute, error) {
+	attr := &attribute{
+		name:     n.attr("name"),
+		ref:      n.qname("ref").local,
+		typeName: n.qname("type"),
+		required: n.attr("use") == "required",
+	}
+
+	if attr.name == "" && attr.ref == "" {
+		return nil, errors.New("attribute without name")
+	}
+
+	for _, child := range n.children {
+		if child.is("simpleType") {
+			st, err := s.parseSimpleType(child)
+			if err != nil {
+				return nil, err
+			}
+			attr.simple = st
+		}
+	}
+
+	return attr, nil
+}
+
+// node is an element of an XML document.
+type node struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*node
+	text     strings.Builder
+	// namespaces are the namespaces in scope by prefix, the default namespace has an empty prefix.
+	namespaces map[string]string
+}
+
+func parseNode(data []byte) (*node, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var root *node
+	var stack []*node
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XML: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			n := &node{name: t.Name, attrs: t.Attr, namespaces: map[string]string{}}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+				for prefix, space := range parent.namespaces {
+					n.namespaces[prefix] = space
+				}
+			}
+
+			for _, attr := range t.Attr {
+				switch {
+				case attr.Name.Space == "xmlns":
+					n.namespaces[attr.Name.Local] = attr.Value
+				case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+					n.namespaces[""] = attr.Value
+				}
+			}
+
+			if root == nil {
+				root = n
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("invalid XML: no root element")
+	}
+
+	return root, nil
+}
+
+// is returns true if the node is the XML schema element with the local name.
+func (n *node) is(local string) bool {
+	return n.name.Space == Namespace && n.name.Local == local
+}
+
+func (n *node) attr(local string) string {
+	for _, attr := range n.attrs {
+		if attr.Name.Space == "" && attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func (n *node) qname(local string) qname {
+	value := n.attr(local)
+	if value == "" {
+		return qname{}
+	}
+	return n.resolve(value)
+}
+
+// resolve resolves the namespace of a prefixed name with the namespaces in scope.
+func (n *node) resolve(value string) qname {
+	prefix, local, found := strings.Cut(value, ":")
+	if !found {
+		return qname{space: n.namespaces[""], local: value}
+	}
+	return qname{space: n.namespaces[prefix], local: local}
+}
@@ -0,0 +1,733 @@
+package xsd
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// The namespaces of the SOAP 1.1 and 1.2 envelopes.
+const (
+	NamespaceSOAP11 = "http://schemas.xmlsoap.org/soap/envelope/"
+	NamespaceSOAP12 = "http://www.w3.org/2003/05/soap-envelope"
+)
+
+// Error is a validation error of an element of a document.
+type Error struct {
+	// Path is the path of the element, e.g. /GetPrice/Item.
+	Path   string
+	Reason string
+}
+
+func (e Error) Error() string {
+	return e.Path + ": " + e.Reason
+}
+
+// Errors are the validation errors of a document.
+type Errors []Error
+
+func (e Errors) Error() string {
+	reasons := make([]string, 0, len(e))
+	for _, err := range e {
+		reasons = append(reasons, err.Error())
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Validate validates an XML document against the global element of the schema with the name of
+// its root element. The payload of a SOAP envelope, the children of its body, is validated instead
+// of the envelope. When element isn't empty, the root element or the payload must have this name.
+//
+// The returned error is Errors when the document is well-formed but not valid.
+func (s *Schema) Validate(data []byte, element string) error {
+	root, err := parseNode(data)
+	if err != nil {
+		return err
+	}
+
+	roots := []*node{root}
+	if root.name.Local == "Envelope" && (root.name.Space == NamespaceSOAP11 || root.name.Space == NamespaceSOAP12) {
+		roots = nil
+		for _, child := range root.children {
+			if child.name.Local == "Body" && child.name.Space == root.name.Space {
+				roots = child.children
+			}
+		}
+	}
+
+	v := &validator{schema: s}
+	for _, n := range roots {
+		path := "/" + n.name.Local
+		if element != "" && n.name.Local != element {
+			v.fail(path, "expected element %s", element)
+			continue
+		}
+
+		el, ok := s.elements[n.name.Local]
+		if !ok {
+			v.fail(path, "element %s is not declared", n.name.Local)
+			continue
+		}
+
+		v.element(el, n, path)
+	}
+
+	if element != "" && len(roots) == 0 {
+		v.fail("/", "missing element %s", element)
+	}
+
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+
+	return nil
+}
+
+type validator struct {
+	schema *Schema
+	errs   Errors
+
+	// expected and at describe the first content model mismatch of the element being validated.
+	expected *particle
+	at       int
+}
+
+func (v *validator) fail(path, format string, args ...interface{}) {
+	v.errs = append(v.errs, Error{Path: path, Reason: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) element(el *element, n *node, path string) {
+	if el.ref != "" {
+		ref, ok := v.schema.elements[el.ref]
+		if !ok {
+			v.fail(path, "element %s is not declared", el.ref)
+			return
+		}
+		el = ref
+	}
+
+	for _, attr := range n.attrs {
+		if attr.Name.Space == namespaceInstance && attr.Name.Local == "nil" && attr.Value == "true" {
+			if !el.nillable {
+				v.fail(path, "element is not nillable")
+			} else if len(n.children) > 0 || strings.TrimSpace(n.text.String()) != "" {
+				v.fail(path, "nil element must be empty")
+			}
+			return
+		}
+	}
+
+	switch {
+	case el.complex != nil:
+		v.complex(el.complex, n, path)
+	case el.simple != nil:
+		v.simpleElement(n, path, func(value string) error { return v.simple(el.simple, value) })
+	case el.typeName.local != "":
+		v.typed(el.typeName, n, path)
+	}
+}
+
+// typed validates an element against a named type.
+func (v *validator) typed(name qname, n *node, path string) {
+	if name.builtin() {
+		if name.local == "anyType" {
+			return
+		}
+		v.simpleElement(n, path, func(value string) error { return builtin(name.local, value) })
+		return
+	}
+
+	if ct, ok := v.schema.complexTypes[name.local]; ok {
+		v.complex(ct, n, path)
+		return
+	}
+
+	if st, ok := v.schema.simpleTypes[name.local]; ok {
+		v.simpleElement(n, path, func(value string) error { return v.simple(st, value) })
+		return
+	}
+
+	v.fail(path, "type %s is not declared", name.local)
+}
+
+func (v *validator) simpleElement(n *node, path string, check func(string) error) {
+	if len(n.children) > 0 {
+		v.fail(path, "element %s is not allowed", n.children[0].name.Local)
+		return
+	}
+
+	v.attributes(nil, false, n, path)
+
+	if err := check(n.text.String()); err != nil {
+		v.fail(path, "%s", err)
+	}
+}
+
+func (v *validator) complex(ct *complexType, n *node, path string) {
+	attributes, anyAttribute, p, text, mixed := v.flatten(ct, 0)
+
+	v.attributes(attributes, anyAttribute, n, path)
+
+	if text != nil {
+		if len(n.children) > 0 {
+			v.fail(path, "element %s is not allowed", n.children[0].name.Local)
+			return
+		}
+		if err := text(n.text.String()); err != nil {
+			v.fail(path, "%s", err)
+		}
+		return
+	}
+
+	if !mixed && strings.TrimSpace(n.text.String()) != "" {
+		v.fail(path, "text content is not allowed")
+	}
+
+	if p == nil {
+		if len(n.children) > 0 {
+			v.fail(path, "element %s is not allowed", n.children[0].name.Local)
+		}
+		return
+	}
+
+	// The mismatch of the parent element is restored after the content of this element is matched.
+	expected, at := v.expected, v.at
+	defer func() { v.expected, v.at = expected, at }()
+
+	v.expected, v.at = nil, 0
+	i, ok := v.match(p, n.children, 0, path)
+
+	switch {
+	case !ok && v.expected != nil && v.at < len(n.children):
+		v.fail(path, "unexpected element %s, expected %s", n.children[v.at].name.Local, v.describe(v.expected))
+	case !ok && v.expected != nil:
+		v.fail(path, "missing element %s", v.describe(v.expected))
+	case !ok:
+		v.fail(path, "content doesn't match the schema")
+	case i < len(n.children):
+		v.fail(path, "unexpected element %s", n.children[i].name.Local)
+	}
+}
+
+// flatten returns the attributes and content of a complex type including its base types. The
+// content is either a particle, or a check of the text of a simple content.
+func (v *validator) flatten(ct *complexType, depth int) (attributes []*attribute, anyAttribute bool, p *particle, text func(string) error, mixed bool) {
+	attributes, anyAttribute, p, mixed = ct.attributes, ct.anyAttribute, ct.particle, ct.mixed
+
+	if ct.base.local == "" || depth > 32 {
+		return
+	}
+
+	if ct.base.builtin() {
+		if ct.simpleContent {
+			name := ct.base.local
+			text = func(value string) error { return builtin(name, value) }
+		}
+		return
+	}
+
+	if st, ok := v.schema.simpleTypes[ct.base.local]; ok {
+		text = func(value string) error { return v.simple(st, value) }
+		return
+	}
+
+	base, ok := v.schema.complexTypes[ct.base.local]
+	if !ok {
+		return
+	}
+
+	baseAttributes, baseAny, baseParticle, baseText, baseMixed := v.flatten(base, depth+1)
+	attributes = append(append([]*attribute{}, baseAttributes...), attributes...)
+	anyAttribute = anyAttribute || baseAny
+	mixed = mixed || baseMixed
+	text = baseText
+
+	switch {
+	case ct.simpleContent:
+	case baseParticle != nil && p != nil:
+		p = &particle{kind: "sequence", children: []*particle{baseParticle, p}, min: 1, max: 1}
+	case baseParticle != nil:
+		p = baseParticle
+	}
+
+	return
+}
+
+func (v *validator) attributes(attributes []*attribute, anyAttribute bool, n *node, path string) {
+	declared := map[string]*attribute{}
+	for _, attr := range attributes {
+		if attr.ref != "" {
+			ref, ok := v.schema.attributes[attr.ref]
+			if !ok {
+				continue
+			}
+			required := attr.required
+			attr = ref
+			if required {
+				attr = &attribute{name: ref.name, typeName: ref.typeName, simple: ref.simple, required: true}
+			}
+		}
+		declared[attr.name] = attr
+	}
+
+	present := map[string]bool{}
+	for _, attr := range n.attrs {
+		if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") || attr.Name.Space == namespaceInstance {
+			continue
+		}
+
+		present[attr.Name.Local] = true
+
+		decl, ok := declared[attr.Name.Local]
+		if !ok {
+			if !anyAttribute {
+				v.fail(path, "attribute %s is not allowed", attr.Name.Local)
+			}
+			continue
+		}
+
+		var err error
+		switch {
+		case decl.simple != nil:
+			err = v.simple(decl.simple, attr.Value)
+		case decl.typeName.local != "":
+			err = v.simpleNamed(decl.typeName, attr.Value)
+		}
+
+		if err != nil {
+			v.fail(path, "attribute %s: %s", attr.Name.Local, err)
+		}
+	}
+
+	for name, attr := range declared {
+		if attr.required && !present[name] {
+			v.fail(path, "missing attribute %s", name)
+		}
+	}
+}
+
+// match matches the children from i against the occurrences of a particle, it returns the index
+// after the matched children.
+func (v *validator) match(p *particle, children []*node, i int, path string) (int, bool) {
+	count := 0
+	for p.max == unbounded || count < p.max {
+		j, ok := v.matchOnce(p, children, i, path)
+		if !ok {
+			break
+		}
+
+		if j == i {
+			// An empty match satisfies the remaining occurrences.
+			count = p.min
+			break
+		}
+
+		i = j
+		count++
+	}
+
+	if count < p.min {
+		if v.expected == nil || i >= v.at {
+			v.expected, v.at = p, i
+		}
+		return i, false
+	}
+
+	return i, true
+}
+
+func (v *validator) matchOnce(p *particle, children []*node, i int, path string) (int, bool) {
+	switch p.kind {
+	case "element":
+		name := p.element.name
+		if p.element.ref != "" {
+			name = p.element.ref
+		}
+
+		if i >= len(children) || children[i].name.Local != name {
+			return i, false
+		}
+
+		v.element(p.element, children[i], path+"/"+name)
+		return i + 1, true
+
+	case "any":
+		if i >= len(children) {
+			return i, false
+		}
+		return i + 1, true
+
+	case "group":
+		group, ok := v.schema.groups[p.group]
+		if !ok {
+			return i, false
+		}
+		return v.match(group, children, i, path)
+
+	case "sequence":
+		for _, child := range p.children {
+			j, ok := v.match(child, children, i, path)
+			if !ok {
+				return i, false
+			}
+			i = j
+		}
+		return i, true
+
+	case "choice":
+		empty := false
+		for _, child := range p.children {
+			errs := len(v.errs)
+			j, ok := v.match(child, children, i, path)
+			if ok && j > i {
+				return j, true
+			}
+
+			v.errs = v.errs[:errs]
+			empty = empty || ok
+		}
+		return i, empty
+
+	case "all":
+		used := make([]bool, len(p.children))
+		for i < len(children) {
+			matched := false
+			for k, child := range p.children {
+				if used[k] {
+					continue
+				}
+				if j, ok := v.matchOnce(child, children, i, path); ok && j > i {
+					used[k], matched, i = true, true, j
+					break
+				}
+			}
+			if !matched {
+				break
+			}
+		}
+
+		for k, child := range p.children {
+			if !used[k] && child.min > 0 {
+				if v.expected == nil || i >= v.at {
+					v.expected, v.at = child, i
+				}
+				return i, false
+			}
+		}
+		return i, true
+	}
+
+	return i, false
+}
+
+// describe returns the names of the elements a particle starts with.
+func (v *validator) describe(p *particle) string {
+	var names []string
+	var walk func(p *particle, depth int)
+	walk = func(p *particle, depth int) {
+		if depth > 32 {
+			return
+		}
+
+		switch p.kind {
+		case "element":
+			name := p.element.name
+			if p.element.ref != "" {
+				name = p.element.ref
+			}
+			names = append(names, name)
+		case "any":
+			names = append(names, "any element")
+		case "group":
+			if group, ok := v.schema.groups[p.group]; ok {
+				walk(group, depth+1)
+			}
+		case "sequence":
+			for _, child := range p.children {
+				walk(child, depth+1)
+				if child.min > 0 {
+					return
+				}
+			}
+		case "choice", "all":
+			for _, child := range p.children {
+				walk(child, depth+1)
+			}
+		}
+	}
+	walk(p, 0)
+
+	return strings.Join(names, " or ")
+}
+
+// simpleNamed checks a value against a named simple type.
+func (v *validator) simpleNamed(name qname, value string) error {
+	if name.builtin() {
+		return builtin(name.local, value)
+	}
+
+	st, ok := v.schema.simpleTypes[name.local]
+	if !ok {
+		return fmt.Errorf("type %s is not declared", name.local)
+	}
+
+	return v.simple(st, value)
+}
+
+func (v *validator) simple(st *simpleType, value string) error {
+	switch {
+	case st.list:
+		for _, item := range strings.Fields(value) {
+			var err error
+			if st.item != nil {
+				err = v.simple(st.item, item)
+			} else {
+				err = v.simpleNamed(st.itemType, item)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case st.union:
+		for _, member := range st.members {
+			if v.simpleNamed(member, value) == nil {
+				return nil
+			}
+		}
+		for _, member := range st.inlines {
+			if v.simple(member, value) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("value %q doesn't match any member type", value)
+	}
+
+	var err error
+	switch {
+	case st.inline != nil:
+		err = v.simple(st.inline, value)
+	case st.base.local != "":
+		err = v.simpleNamed(st.base, value)
+	}
+	if err != nil {
+		return err
+	}
+
+	return st.facets.check(value)
+}
+
+type facets struct {
+	enumeration []string
+	patterns    []*regexp.Regexp
+	length      *int
+	minLength   *int
+	maxLength   *int
+
+	minInclusive, maxInclusive *float64
+	minExclusive, maxExclusive *float64
+}
+
+func (f *facets) add(name, value string) error {
+	var err error
+	switch name {
+	case "enumeration":
+		f.enumeration = append(f.enumeration, value)
+	case "pattern":
+		var re *regexp.Regexp
+		if re, err = regexp.Compile("^(?:" + value + ")$"); err == nil {
+			f.patterns = append(f.patterns, re)
+		}
+	case "length":
+		f.length, err = intFacet(value)
+	case "minLength":
+		f.minLength, err = intFacet(value)
+	case "maxLength":
+		f.maxLength, err = intFacet(value)
+	case "minInclusive":
+		f.minInclusive, err = floatFacet(value)
+	case "maxInclusive":
+		f.maxInclusive, err = floatFacet(value)
+	case "minExclusive":
+		f.minExclusive, err = floatFacet(value)
+	case "maxExclusive":
+		f.maxExclusive, err = floatFacet(value)
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid %s facet %q: %w", name, value, err)
+	}
+
+	return nil
+}
+
+func (f *facets) check(value string) error {
+	if len(f.enumeration) > 0 {
+		found := false
+		for _, enum := range f.enumeration {
+			if value == enum || strings.TrimSpace(value) == enum {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("value %q is not one of %s", value, strings.Join(f.enumeration, ", "))
+		}
+	}
+
+	for _, re := range f.patterns {
+		if !re.MatchString(value) {
+			return fmt.Errorf("value %q doesn't match the pattern %s", value, re)
+		}
+	}
+
+	length := utf8.RuneCountInString(value)
+	switch {
+	case f.length != nil && length != *f.length:
+		return fmt.Errorf("length of %q must be %d", value, *f.length)
+	case f.minLength != nil && length < *f.minLength:
+		return fmt.Errorf("length of %q must be at least %d", value, *f.minLength)
+	case f.maxLength != nil && length > *f.maxLength:
+		return fmt.Errorf("length of %q must be at most %d", value, *f.maxLength)
+	}
+
+	if f.minInclusive == nil && f.maxInclusive == nil && f.minExclusive == nil && f.maxExclusive == nil {
+		return nil
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return fmt.Errorf("value %q is not a number", value)
+	}
+
+	switch {
+	case f.minInclusive != nil && n < *f.minInclusive:
+		return fmt.Errorf("value %s must be at least %v", value, *f.minInclusive)
+	case f.maxInclusive != nil && n > *f.maxInclusive:
+		return fmt.Errorf("value %s must be at most %v", value, *f.maxInclusive)
+	case f.minExclusive != nil && n <= *f.minExclusive:
+		return fmt.Errorf("value %s must be greater than %v", value, *f.minExclusive)
+	case f.maxExclusive != nil && n >= *f.maxExclusive:
+		return fmt.Errorf("value %s must be less than %v", value, *f.maxExclusive)
+	}
+
+	return nil
+}
+
+func intFacet(value string) (*int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func floatFacet(value string) (*float64, error) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+var (
+	decimalPattern  = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+	integerPattern  = regexp.MustCompile(`^[+-]?\d+$`)
+	durationPattern = regexp.MustCompile(`^-?P(\d+Y)?(\d+M)?(\d+D)?(T(\d+H)?(\d+M)?(\d+(\.\d+)?S)?)?$`)
+)
+
+// integerBounds are the bounds of the built-in integer types, nil is unbounded.
+var integerBounds = map[string][2]*big.Int{
+	"integer":            {nil, nil},
+	"long":               {big.NewInt(math.MinInt64), big.NewInt(math.MaxInt64)},
+	"int":                {big.NewInt(math.MinInt32), big.NewInt(math.MaxInt32)},
+	"short":              {big.NewInt(math.MinInt16), big.NewInt(math.MaxInt16)},
+	"byte":               {big.NewInt(math.MinInt8), big.NewInt(math.MaxInt8)},
+	"nonNegativeInteger": {big.NewInt(0), nil},
+	"positiveInteger":    {big.NewInt(1), nil},
+	"nonPositiveInteger": {nil, big.NewInt(0)},
+	"negativeInteger":    {nil, big.NewInt(-1)},
+	"unsignedLong":       {big.NewInt(0), new(big.Int).SetUint64(math.MaxUint64)},
+	"unsignedInt":        {big.NewInt(0), big.NewInt(math.MaxUint32)},
+	"unsignedShort":      {big.NewInt(0), big.NewInt(math.MaxUint16)},
+	"unsignedByte":       {big.NewInt(0), big.NewInt(math.MaxUint8)},
+}
+
+var errInvalid = errors.New("invalid value")
+
+// builtin checks a value against a built-in type, unknown types accept any value.
+func builtin(name, value string) error {
+	collapsed := strings.TrimSpace(value)
+
+	var err error
+	switch name {
+	case "boolean":
+		switch collapsed {
+		case "true", "false", "1", "0":
+		default:
+			err = errInvalid
+		}
+	case "decimal":
+		if !decimalPattern.MatchString(collapsed) {
+			err = errInvalid
+		}
+	case "float", "double":
+		switch collapsed {
+		case "INF", "-INF", "NaN":
+		default:
+			_, err = strconv.ParseFloat(collapsed, 64)
+		}
+	case "date":
+		err = parseTime(collapsed, "2006-01-02")
+	case "dateTime":
+		err = parseTime(collapsed, "2006-01-02T15:04:05.999999999")
+	case "time":
+		err = parseTime(collapsed, "15:04:05.999999999")
+	case "duration":
+		if !durationPattern.MatchString(collapsed) || strings.HasSuffix(collapsed, "T") {
+			err = errInvalid
+		}
+	case "base64Binary":
+		_, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value), ""))
+	case "hexBinary":
+		_, err = hex.DecodeString(collapsed)
+	default:
+		bounds, ok := integerBounds[name]
+		if !ok {
+			return nil
+		}
+
+		n, valid := new(big.Int), integerPattern.MatchString(collapsed)
+		if valid {
+			_, valid = n.SetString(strings.TrimPrefix(collapsed, "+"), 10)
+		}
+		if !valid || (bounds[0] != nil && n.Cmp(bounds[0]) < 0) || (bounds[1] != nil && n.Cmp(bounds[1]) > 0) {
+			err = errInvalid
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("value %q is not a valid %s", value, name)
+	}
+
+	return nil
+}
+
+// parseTime parses a date or time with an optional time zone.
+func parseTime(value, layout string) error {
+	for _, zone := range []string{"", "Z07:00"} {
+		if _, err := time.Parse(layout+zone, value); err == nil {
+			return nil
+		}
+	}
+	return errInvalid
+}
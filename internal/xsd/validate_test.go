@@ -0,0 +1,142 @@
+package xsd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `<?xml version="1.0"?>
+<wsdl:types xmlns:wsdl="http://schemas.xmlsoap.org/wsdl/" xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:tns="http://example.com/stock">
+  <xs:schema targetNamespace="http://example.com/stock" elementFormDefault="qualified">
+    <xs:simpleType name="Symbol">
+      <xs:restriction base="xs:string">
+        <xs:pattern value="[A-Z]{1,5}"/>
+      </xs:restriction>
+    </xs:simpleType>
+    <xs:complexType name="Base">
+      <xs:sequence>
+        <xs:element name="Symbol" type="tns:Symbol"/>
+      </xs:sequence>
+      <xs:attribute name="version" type="xs:int" use="required"/>
+    </xs:complexType>
+    <xs:element name="GetPrice">
+      <xs:complexType>
+        <xs:complexContent>
+          <xs:extension base="tns:Base">
+            <xs:sequence>
+              <xs:element name="Quantity" minOccurs="0">
+                <xs:simpleType>
+                  <xs:restriction base="xs:positiveInteger">
+                    <xs:maxInclusive value="100"/>
+                  </xs:restriction>
+                </xs:simpleType>
+              </xs:element>
+              <xs:choice>
+                <xs:element name="Date" type="xs:date"/>
+                <xs:element name="Latest" type="xs:boolean"/>
+              </xs:choice>
+              <xs:element name="Tag" type="xs:string" minOccurs="0" maxOccurs="2"/>
+            </xs:sequence>
+          </xs:extension>
+        </xs:complexContent>
+      </xs:complexType>
+    </xs:element>
+    <xs:element name="Currency">
+      <xs:simpleType>
+        <xs:restriction base="xs:string">
+          <xs:enumeration value="EUR"/>
+          <xs:enumeration value="USD"/>
+        </xs:restriction>
+      </xs:simpleType>
+    </xs:element>
+  </xs:schema>
+</wsdl:types>`
+
+func TestParse(t *testing.T) {
+	schema, err := Parse([]byte(testSchema))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"GetPrice", "Currency"}, schema.Elements())
+
+	_, err = Parse([]byte(`<definitions/>`))
+	assert.ErrorIs(t, err, ErrNoSchema)
+
+	_, err = Parse([]byte(`<definitions>`))
+	assert.ErrorContains(t, err, "invalid XML")
+}
+
+func TestSchema_Validate(t *testing.T) {
+	schema, err := Parse([]byte(testSchema))
+	require.NoError(t, err)
+
+	cases := []struct {
+		name    string
+		doc     string
+		element string
+		err     string
+	}{
+		{
+			name: "valid",
+			doc:  `<GetPrice xmlns="http://example.com/stock" version="1"><Symbol>TYK</Symbol><Quantity>10</Quantity><Date>2024-01-02</Date><Tag>a</Tag><Tag>b</Tag></GetPrice>`,
+		},
+		{
+			name:    "SOAP envelope",
+			doc:     `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Header/><soap:Body><GetPrice version="2"><Symbol>TYK</Symbol><Latest>true</Latest></GetPrice></soap:Body></soap:Envelope>`,
+			element: "GetPrice",
+		},
+		{
+			name: "invalid simple values",
+			doc:  `<GetPrice version="x"><Symbol>tyk</Symbol><Quantity>101</Quantity><Latest>yes</Latest></GetPrice>`,
+			err: `/GetPrice: attribute version: value "x" is not a valid int; ` +
+				`/GetPrice/Symbol: value "tyk" doesn't match the pattern ^(?:[A-Z]{1,5})$; ` +
+				`/GetPrice/Quantity: value 101 must be at most 100; ` +
+				`/GetPrice/Latest: value "yes" is not a valid boolean`,
+		},
+		{
+			name: "missing element",
+			doc:  `<GetPrice version="1"><Symbol>TYK</Symbol><Quantity>1</Quantity></GetPrice>`,
+			err:  `/GetPrice: missing element Date or Latest`,
+		},
+		{
+			name: "unexpected element",
+			doc:  `<GetPrice version="1"><Symbol>TYK</Symbol><Latest>1</Latest><Tag>a</Tag><Tag>b</Tag><Tag>c</Tag></GetPrice>`,
+			err:  `/GetPrice: unexpected element Tag`,
+		},
+		{
+			name: "missing attribute",
+			doc:  `<GetPrice><Symbol>TYK</Symbol><Latest>1</Latest></GetPrice>`,
+			err:  `/GetPrice: missing attribute version`,
+		},
+		{
+			name: "enumeration",
+			doc:  `<Currency>GBP</Currency>`,
+			err:  `/Currency: value "GBP" is not one of EUR, USD`,
+		},
+		{
+			name: "undeclared element",
+			doc:  `<GetQuote/>`,
+			err:  `/GetQuote: element GetQuote is not declared`,
+		},
+		{
+			name:    "unexpected root element",
+			doc:     `<Currency>EUR</Currency>`,
+			element: "GetPrice",
+			err:     `/Currency: expected element GetPrice`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tc.doc), tc.element)
+			if tc.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var errs Errors
+			require.ErrorAs(t, err, &errs)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
@@ -39,6 +39,11 @@ var (
 	emergencyModeCallback       func()
 	emergencyModeLoadedCallback func()
 
+	// emergencyModeChangeCallback is notified when emergency mode is entered or exited.
+	emergencyModeChangeCallback func(enabled bool)
+	emergencyModeNotified       bool
+	emergencyModeChangeMu       sync.Mutex
+
 	killChan = make(chan int)
 	killed   bool
 	id       string
@@ -190,9 +195,38 @@ func Reset() {
 	values.Reset()
 }
 
+// SetEmergencyModeChangeCallback registers fn to be called when the gateway
+// enters or exits emergency mode. It must not block.
+func SetEmergencyModeChangeCallback(fn func(enabled bool)) {
+	emergencyModeChangeMu.Lock()
+	defer emergencyModeChangeMu.Unlock()
+	emergencyModeChangeCallback = fn
+}
+
+// notifyEmergencyMode reports an emergency mode transition once per change. The
+// emergency mode that is assumed on startup until the first login isn't reported.
+func notifyEmergencyMode(enabled bool) {
+	emergencyModeChangeMu.Lock()
+	if emergencyModeNotified == enabled {
+		emergencyModeChangeMu.Unlock()
+		return
+	}
+	emergencyModeNotified = enabled
+	callback := emergencyModeChangeCallback
+	emergencyModeChangeMu.Unlock()
+
+	if callback != nil {
+		callback(enabled)
+	}
+}
+
 func ResetEmergencyMode() {
 	values.SetEmergencyMode(false)
 	values.SetEmergencyModeLoaded(false)
+
+	emergencyModeChangeMu.Lock()
+	emergencyModeNotified = false
+	emergencyModeChangeMu.Unlock()
 }
 
 func EmitErrorEvent(jobName string, funcName string, err error) {
@@ -394,6 +428,7 @@ func loginBase() bool {
 			Log.Warning("[RPC Store] --> Detected cold start, attempting to load from cache")
 			Log.Warning("[RPC Store] ----> Found APIs... beginning emergency load")
 			values.SetEmergencyModeLoaded(true)
+			notifyEmergencyMode(true)
 			if emergencyModeLoadedCallback != nil {
 				go emergencyModeLoadedCallback()
 			}
@@ -503,6 +538,7 @@ func recoverOp(fn func() error) func() error {
 			if n == 0 {
 				// we failed at our first call so we are in emergency mode now
 				values.SetEmergencyMode(true)
+				notifyEmergencyMode(true)
 			}
 			n++
 			return err
@@ -510,6 +546,7 @@ func recoverOp(fn func() error) func() error {
 		if values.GetEmergencyMode() {
 			values.SetEmergencyMode(false)
 			values.SetEmergencyModeLoaded(false)
+			notifyEmergencyMode(false)
 			if emergencyModeCallback != nil {
 				emergencyModeCallback()
 			}
@@ -637,4 +674,5 @@ func SetLoadCounts(t *testing.T, value int) {
 // EnableEmergencyMode sets the emergency mode state for production use
 func EnableEmergencyMode(enabled bool) {
 	values.SetEmergencyMode(enabled)
+	notifyEmergencyMode(enabled)
 }
@@ -47,6 +47,26 @@ func TestRecoveryFromEmergencyMode(t *testing.T) {
 	}
 }
 
+func TestEmergencyModeChangeCallback(t *testing.T) {
+	defer ResetEmergencyMode()
+
+	var changes []bool
+	SetEmergencyModeChangeCallback(func(enabled bool) {
+		changes = append(changes, enabled)
+	})
+	defer SetEmergencyModeChangeCallback(nil)
+
+	// only transitions are reported
+	EnableEmergencyMode(true)
+	EnableEmergencyMode(true)
+	EnableEmergencyMode(false)
+	EnableEmergencyMode(false)
+
+	if len(changes) != 2 || !changes[0] || changes[1] {
+		t.Fatalf("expected an enter and an exit notification, got %v", changes)
+	}
+}
+
 func TestClientIsConnected(t *testing.T) {
 	t.Parallel()
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -348,6 +349,28 @@ func (r *RedisCluster) GetRawKey(keyName string) (string, error) {
 	return value, nil
 }
 
+// GetRawMultiKey gets multiple keys by their full name, the value of a missing key is empty.
+func (r *RedisCluster) GetRawMultiKey(keys []string) ([]string, error) {
+	storage, err := r.kv()
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	values, err := storage.GetMulti(context.Background(), keys)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, len(values))
+	for i, val := range values {
+		if val != nil {
+			result[i] = fmt.Sprint(val)
+		}
+	}
+	return result, nil
+}
+
 func (r *RedisCluster) GetExp(keyName string) (int64, error) {
 	return r.GetKeyTTL(keyName)
 }
@@ -1086,3 +1109,182 @@ func (r *RedisCluster) ScanKeys(pattern string) ([]string, error) {
 
 	return storage.Keys(context.Background(), pattern)
 }
+
+// ErrInvalidCursor is returned when a scan cursor wasn't returned by ScanKeysPage.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ScanKeysPage returns a page of the keys matching the pattern, along with the cursor of the next
+// page, empty once every key was returned. An empty cursor starts from the first page. Count is a
+// hint of the number of keys to return, as with the Redis SCAN command a page may hold more or
+// fewer keys. In cluster mode the masters are scanned one after the other.
+func (r *RedisCluster) ScanKeysPage(pattern, cursor string, count int64) ([]string, string, error) {
+	var node int
+	var scanCursor uint64
+	if cursor != "" {
+		if _, err := fmt.Sscanf(cursor, "%d-%d", &node, &scanCursor); err != nil || node < 0 {
+			return nil, "", ErrInvalidCursor
+		}
+	}
+
+	ctx := context.Background()
+	nodes, err := r.scanNodes(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if node >= len(nodes) {
+		return nil, "", ErrInvalidCursor
+	}
+
+	keys, next, err := nodes[node].Scan(ctx, scanCursor, r.KeyPrefix+pattern, count).Result()
+	if err != nil {
+		return nil, "", err
+	}
+
+	for i, v := range keys {
+		keys[i] = r.cleanKey(v)
+	}
+
+	if next == 0 {
+		node++
+		if node == len(nodes) {
+			return keys, "", nil
+		}
+	}
+
+	return keys, fmt.Sprintf("%d-%d", node, next), nil
+}
+
+// scanNodes returns the clients to scan, the masters sorted by address in cluster mode.
+func (r *RedisCluster) scanNodes(ctx context.Context) ([]redis.UniversalClient, error) {
+	client, err := r.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, ok := client.(*redis.ClusterClient)
+	if !ok {
+		return []redis.UniversalClient{client}, nil
+	}
+
+	var mu sync.Mutex
+	masters := map[string]*redis.Client{}
+	err = cluster.ForEachMaster(ctx, func(_ context.Context, node *redis.Client) error {
+		mu.Lock()
+		masters[node.Options().Addr] = node
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]string, 0, len(masters))
+	for addr := range masters {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	nodes := make([]redis.UniversalClient, 0, len(addrs))
+	for _, addr := range addrs {
+		nodes = append(nodes, masters[addr])
+	}
+	return nodes, nil
+}
+
+// BatchOp is a write of a batch: the key, a full key name, is deleted when Delete is set
+// and set to Value with a TTL in seconds otherwise.
+type BatchOp struct {
+	Key    string
+	Value  string
+	TTL    int64
+	Delete bool
+}
+
+// WriteBatch sends the operations in a single pipeline and returns the error of each operation.
+// When atomic is set they're sent as a MULTI/EXEC transaction, so either all of them or none are
+// applied and the returned error is set if the transaction failed. In cluster mode a transaction
+// only spans the keys of a single hash slot.
+func (r *RedisCluster) WriteBatch(ops []BatchOp, atomic bool) ([]error, error) {
+	client, err := r.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	var pipe redis.Pipeliner
+	if atomic {
+		pipe = client.TxPipeline()
+	} else {
+		pipe = client.Pipeline()
+	}
+
+	cmds := make([]redis.Cmder, len(ops))
+	for i, op := range ops {
+		if op.Delete {
+			cmds[i] = pipe.Del(ctx, op.Key)
+		} else {
+			cmds[i] = pipe.Set(ctx, op.Key, op.Value, time.Duration(op.TTL)*time.Second)
+		}
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && atomic {
+		return nil, err
+	}
+
+	errs := make([]error, len(ops))
+	for i, cmd := range cmds {
+		errs[i] = cmd.Err()
+	}
+	return errs, nil
+}
+
+// ErrKeyChanged is returned by conditional writes when the key doesn't match, or changed during the write.
+var ErrKeyChanged = errors.New("key changed")
+
+// SetRawKeyIf sets the key, with a TTL in seconds, if match accepts its current value, empty when
+// it doesn't exist. The key is watched, so it's written only if it didn't change since it was read.
+func (r *RedisCluster) SetRawKeyIf(keyName, value string, ttl int64, match func(current string) bool) error {
+	return r.writeRawKeyIf(keyName, match, func(ctx context.Context, pipe redis.Pipeliner) {
+		pipe.Set(ctx, keyName, value, time.Duration(ttl)*time.Second)
+	})
+}
+
+// DeleteRawKeyIf deletes the key if match accepts its current value, see SetRawKeyIf.
+func (r *RedisCluster) DeleteRawKeyIf(keyName string, match func(current string) bool) error {
+	return r.writeRawKeyIf(keyName, match, func(ctx context.Context, pipe redis.Pipeliner) {
+		pipe.Del(ctx, keyName)
+	})
+}
+
+func (r *RedisCluster) writeRawKeyIf(keyName string, match func(current string) bool, write func(context.Context, redis.Pipeliner)) error {
+	client, err := r.Client()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	err = client.Watch(ctx, func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, keyName).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+
+		if !match(current) {
+			return ErrKeyChanged
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			write(ctx, pipe)
+			return nil
+		})
+		return err
+	}, keyName)
+
+	if errors.Is(err, redis.TxFailedErr) {
+		return ErrKeyChanged
+	}
+	return err
+}
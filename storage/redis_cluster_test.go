@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
@@ -2004,6 +2005,98 @@ func TestScanKeys(t *testing.T) {
 	})
 }
 
+func TestScanKeysPage(t *testing.T) {
+	storage := &RedisCluster{ConnectionHandler: rc, KeyPrefix: "scan-page-test:"}
+	defer storage.DeleteScanMatch("scan-page-test:*")
+
+	want := map[string]bool{}
+	for i := 0; i < 25; i++ {
+		key := "key" + strconv.Itoa(i)
+		assert.NoError(t, storage.SetKey(key, "value", 0))
+		want[key] = true
+	}
+
+	got := map[string]bool{}
+	cursor := ""
+	for pages := 0; ; pages++ {
+		assert.Less(t, pages, 100, "scan never ended")
+
+		keys, next, err := storage.ScanKeysPage("*", cursor, 5)
+		assert.NoError(t, err)
+		for _, key := range keys {
+			got[key] = true
+		}
+
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	assert.Equal(t, want, got)
+
+	values, err := storage.GetRawMultiKey([]string{"scan-page-test:key0", "scan-page-test:missing"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"value", ""}, values)
+
+	_, _, err = storage.ScanKeysPage("*", "not-a-cursor", 5)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	_, _, err = storage.ScanKeysPage("*", "100-0", 5)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestWriteBatch(t *testing.T) {
+	storage := &RedisCluster{ConnectionHandler: rc, KeyPrefix: "write-batch-test:"}
+	defer storage.DeleteScanMatch("write-batch-test:*")
+
+	assert.NoError(t, storage.SetKey("deleted", "value", 0))
+
+	for _, atomic := range []bool{false, true} {
+		errs, err := storage.WriteBatch([]BatchOp{
+			{Key: "write-batch-test:created", Value: "value", TTL: 60},
+			{Key: "write-batch-test:deleted", Delete: true},
+		}, atomic)
+		assert.NoError(t, err)
+		assert.Equal(t, []error{nil, nil}, errs)
+
+		value, err := storage.GetKey("created")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+
+		ttl, err := storage.GetKeyTTL("created")
+		assert.NoError(t, err)
+		assert.Greater(t, ttl, int64(0))
+
+		_, err = storage.GetKey("deleted")
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+	}
+}
+
+func TestWriteRawKeyIf(t *testing.T) {
+	storage := &RedisCluster{ConnectionHandler: rc, KeyPrefix: "write-if-test:"}
+	defer storage.DeleteScanMatch("write-if-test:*")
+
+	const key = "write-if-test:key"
+	equals := func(expected string) func(string) bool {
+		return func(current string) bool { return current == expected }
+	}
+
+	assert.NoError(t, storage.SetRawKeyIf(key, "v1", 60, equals("")))
+	assert.ErrorIs(t, storage.SetRawKeyIf(key, "v2", 60, equals("")), ErrKeyChanged)
+	assert.NoError(t, storage.SetRawKeyIf(key, "v2", 60, equals("v1")))
+
+	value, err := storage.GetRawKey(key)
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", value)
+
+	assert.ErrorIs(t, storage.DeleteRawKeyIf(key, equals("v1")), ErrKeyChanged)
+	assert.NoError(t, storage.DeleteRawKeyIf(key, equals("v2")))
+
+	_, err = storage.GetRawKey(key)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
 func TestGetKeyPrefix(t *testing.T) {
 	t.Run("with prefix", func(t *testing.T) {
 		prefix := "prefix:"
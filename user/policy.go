@@ -36,6 +36,9 @@ type Policy struct {
 
 	// Smoothing contains rate limit smoothing settings.
 	Smoothing *apidef.RateLimitSmoothing `json:"smoothing" bson:"smoothing"`
+
+	// Schedule contains the activation schedule and maintenance windows of the policy.
+	Schedule *apidef.ActivationSchedule `json:"schedule,omitempty" bson:"schedule,omitempty"`
 }
 
 func (p *Policy) APILimit() APILimit {